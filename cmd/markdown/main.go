@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+)
+
+// 文章 Markdown 批量导入导出：
+//
+//	go run cmd/markdown/main.go import -author <用户名或ID> -file posts.zip
+//	go run cmd/markdown/main.go export -author <用户名或ID> -out posts.zip
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "import" && os.Args[1] != "export") {
+		fmt.Fprintln(os.Stderr, "用法: markdown import|export -author <用户名或ID> [-file posts.zip] [-out posts.zip]")
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	author := flags.String("author", "", "文章作者的用户名或ID")
+	file := flags.String("file", "", "导入的 zip 压缩包")
+	out := flags.String("out", "articles.zip", "导出的 zip 文件路径")
+	flags.Parse(os.Args[2:])
+	if *author == "" || (command == "import" && *file == "") {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// 初始化日志
+	utils.InitLogger()
+
+	// 加载配置
+	if err := config.Init(); err != nil {
+		log.Fatalf("❌ 加载配置失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		log.Fatalf("❌ 数据库连接失败: %v", err)
+	}
+
+	// 初始化Redis
+	if err := database.InitRedis(); err != nil {
+		log.Fatalf("❌ Redis连接失败: %v", err)
+	}
+
+	user, err := findAuthor(*author)
+	if err != nil {
+		log.Fatalf("❌ 找不到用户 %s: %v", *author, err)
+	}
+
+	markdownService := service.NewArticleMarkdownService()
+	if command == "export" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("❌ 创建文件失败: %v", err)
+		}
+		count, err := markdownService.Export(user.ID, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Fatalf("❌ 导出文章失败: %v", err)
+		}
+		log.Printf("✅ 已导出 %s 的 %d 篇文章到 %s", user.Username, count, *out)
+		return
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("❌ 打开文件失败: %v", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		log.Fatalf("❌ 读取文件失败: %v", err)
+	}
+
+	// 命令行由运维执行，按管理员权限导入，不存在的分类会被创建
+	result, err := markdownService.Import(user.ID, models.RoleAdmin, f, info.Size())
+	if err != nil {
+		log.Fatalf("❌ 导入文章失败: %v", err)
+	}
+	for _, item := range result.Items {
+		if item.Status == models.ArticleImportImported && item.Message == "" {
+			continue
+		}
+		log.Printf("[%s] %s %s", item.Status, item.File, item.Message)
+	}
+	log.Printf("✅ 导入完成：成功 %d 篇，跳过 %d 篇，失败 %d 篇", result.Imported, result.Skipped, result.Failed)
+}
+
+// findAuthor 按ID或用户名查找作者
+func findAuthor(value string) (*models.User, error) {
+	var user models.User
+	query := database.DB.Where("username = ?", value)
+	if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+		query = database.DB.Where("id = ? OR username = ?", id, value)
+	}
+	if err := query.First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
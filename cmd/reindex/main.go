@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+)
+
+// 全量重建搜索索引：go run cmd/reindex/main.go [-type article,work,doc,user]
+func main() {
+	typeList := flag.String("type", "", "只重建指定类型，逗号分隔：article,work,doc,user；为空时重建全部")
+	flag.Parse()
+
+	// 初始化日志
+	utils.InitLogger()
+
+	// 加载配置
+	if err := config.Init(); err != nil {
+		log.Fatalf("❌ 加载配置失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		log.Fatalf("❌ 数据库连接失败: %v", err)
+	}
+
+	// 初始化Redis
+	if err := database.InitRedis(); err != nil {
+		log.Fatalf("❌ Redis连接失败: %v", err)
+	}
+
+	var types []string
+	for _, t := range strings.Split(*typeList, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	start := time.Now()
+	log.Println("开始重建搜索索引...")
+	count, err := service.NewSearchService().Reindex(types...)
+	if err != nil {
+		log.Fatalf("❌ 重建搜索索引失败（已收录 %d 条）: %v", count, err)
+	}
+	log.Printf("✅ 搜索索引重建完成，共收录 %d 条内容，耗时 %s", count, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+)
+
+// 重新渲染文章和已发布文档的 HTML 与目录：go run cmd/render/main.go
+func main() {
+	// 初始化日志
+	utils.InitLogger()
+
+	// 加载配置
+	if err := config.Init(); err != nil {
+		log.Fatalf("❌ 加载配置失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		log.Fatalf("❌ 数据库连接失败: %v", err)
+	}
+
+	// 初始化Redis
+	if err := database.InitRedis(); err != nil {
+		log.Fatalf("❌ Redis连接失败: %v", err)
+	}
+
+	start := time.Now()
+	log.Println("开始重新渲染 Markdown...")
+	articles, docs, err := service.NewMarkdownRenderService().RenderAll()
+	if err != nil {
+		log.Fatalf("❌ 重新渲染失败（已完成 %d 篇文章、%d 篇文档）: %v", articles, docs, err)
+	}
+	log.Printf("✅ 重新渲染完成，共 %d 篇文章、%d 篇文档，耗时 %s", articles, docs, time.Since(start).Round(time.Millisecond))
+}
@@ -43,6 +43,14 @@ func main() {
 	sched.RegisterTask("hot_works", scheduler.NewHotWorksTask(), 3*time.Minute)
 	// 注册榜单生成任务（每天检查一次，在周日、月初、年初生成榜单）
 	sched.RegisterTask("article_rank", scheduler.NewArticleRankTask(), 24*time.Hour)
+	// 执行冷静期已结束的账号注销，清理过期的数据导出文件
+	sched.RegisterTask("account_cleanup", scheduler.NewAccountCleanupTask(), time.Hour)
+	// 发布已到达发布时间的定时文章
+	sched.RegisterTask("article_publish", scheduler.NewArticlePublishTask(), time.Minute)
+	// 预计算每篇文章的相关文章
+	sched.RegisterTask("related_articles", scheduler.NewRelatedArticlesTask(), time.Hour)
+	// 将 Redis 中缓冲的浏览量写入数据库并累计每日浏览历史
+	sched.RegisterTask("view_flush", scheduler.NewViewFlushTask(), time.Minute)
 
 	log.Println("========================================")
 	log.Println("✅ 定时任务调度器启动成功")
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+)
+
+// 导入 WordPress 导出的 WXR 文件：
+//
+//	go run cmd/wordpress/main.go -file wordpress.xml [-dry-run] [-author-map alice=admin,bob=bob2]
+func main() {
+	file := flag.String("file", "", "WordPress 导出的 XML 文件")
+	dryRun := flag.Bool("dry-run", false, "只统计将要创建的内容，不写入数据库")
+	authorMapValue := flag.String("author-map", "", "作者映射：wp登录名=本站用户名，多个用逗号分隔")
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	authorMap, err := service.ParseWordPressAuthorMap(*authorMapValue)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	// 初始化日志
+	utils.InitLogger()
+
+	// 加载配置
+	if err := config.Init(); err != nil {
+		log.Fatalf("❌ 加载配置失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := database.Init(); err != nil {
+		log.Fatalf("❌ 数据库连接失败: %v", err)
+	}
+
+	// 初始化Redis
+	if err := database.InitRedis(); err != nil {
+		log.Fatalf("❌ Redis连接失败: %v", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("❌ 打开文件失败: %v", err)
+	}
+	defer f.Close()
+
+	report, err := service.NewWordPressImportService().Import(f, &models.WordPressImportOptions{
+		DryRun:    *dryRun,
+		AuthorMap: authorMap,
+	})
+	if err != nil {
+		log.Fatalf("❌ 导入失败: %v", err)
+	}
+
+	action := "已创建"
+	if report.DryRun {
+		action = "将创建"
+		log.Printf("试运行，不会写入数据库。来源站点: %s", report.Site)
+	}
+	for _, author := range report.Authors {
+		note := ""
+		if author.Placeholder {
+			note = "（占位账号）"
+		}
+		if author.Conflict != "" {
+			note += "，与本站用户 " + author.Conflict + " 冲突，需在 -author-map 中指定"
+		}
+		log.Printf("作者 %s -> %s%s", author.Login, author.Username, note)
+	}
+	for _, row := range []struct {
+		name  string
+		count models.WordPressImportCount
+	}{
+		{"用户", report.Users},
+		{"分类", report.Categories},
+		{"标签", report.Tags},
+		{"文章", report.Articles},
+		{"评论", report.Comments},
+	} {
+		log.Printf("%s: %s %d，已存在 %d，跳过 %d", row.name, action, row.count.Created, row.count.Existing, row.count.Skipped)
+	}
+	for _, warning := range report.Warnings {
+		log.Printf("⚠️ %s", warning)
+	}
+	log.Println("✅ WordPress 导入完成")
+}
@@ -17,6 +17,9 @@ type Config struct {
 	Upload     UploadConfig     `mapstructure:"upload"`
 	Pagination PaginationConfig `mapstructure:"pagination"`
 	Cache      CacheConfig      `mapstructure:"cache"`
+	Mail       MailConfig       `mapstructure:"mail"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	Account    AccountConfig    `mapstructure:"account"`
 }
 
 type AdminConfig struct {
@@ -25,8 +28,9 @@ type AdminConfig struct {
 }
 
 type ServerConfig struct {
-	Port int    `mapstructure:"port"`
-	Mode string `mapstructure:"mode"`
+	Port      int    `mapstructure:"port"`
+	Mode      string `mapstructure:"mode"`
+	PublicURL string `mapstructure:"publicURL"` // 站点对外访问地址，用于生成邮件等外部链接，例如 https://blog.example.com
 }
 
 type DatabaseConfig struct {
@@ -51,9 +55,10 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expireHours"`
-	AdminSecret string `mapstructure:"adminSecret"` // 管理员专用secret
+	Secret              string `mapstructure:"secret"`
+	ExpireHours         int    `mapstructure:"expireHours"`         // 刷新令牌有效期（小时）
+	AccessExpireMinutes int    `mapstructure:"accessExpireMinutes"` // 访问令牌有效期（分钟）
+	AdminSecret         string `mapstructure:"adminSecret"`         // 管理员专用secret
 }
 
 type UploadConfig struct {
@@ -81,6 +86,65 @@ type CacheConfig struct {
 	UserExpire    int `mapstructure:"userExpire"`
 }
 
+type MailConfig struct {
+	Transport string         `mapstructure:"transport"` // smtp, file, memory；为空时不发送邮件
+	From      string         `mapstructure:"from"`      // 发件人地址
+	FromName  string         `mapstructure:"fromName"`  // 发件人名称
+	FileDir   string         `mapstructure:"fileDir"`   // file 模式下邮件保存目录
+	SMTP      SMTPMailConfig `mapstructure:"smtp"`
+}
+
+type SMTPMailConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	TLS      bool   `mapstructure:"tls"` // true 使用隐式 TLS（465 端口），否则在服务器支持时使用 STARTTLS
+}
+
+// AccountConfig 个人数据导出和账号注销，未配置的项使用默认值
+type AccountConfig struct {
+	ExportDir         string `mapstructure:"exportDir"`         // 导出压缩包保存目录，默认 ./storage/exports，不要放在可公开访问的目录下
+	ExportExpireHours int    `mapstructure:"exportExpireHours"` // 导出文件保留时长，默认 72 小时
+	DeletionGraceDays int    `mapstructure:"deletionGraceDays"` // 注销冷静期，默认 14 天
+}
+
+type AuthConfig struct {
+	Providers  []OAuthProviderConfig `mapstructure:"providers"`  // 第三方登录（OAuth2 / OIDC）
+	LoginGuard LoginGuardConfig      `mapstructure:"loginGuard"` // 登录防暴力破解
+	Captcha    CaptchaConfig         `mapstructure:"captcha"`    // 人机验证，登录失败次数过多时要求通过
+}
+
+// LoginGuardConfig 登录失败限制，未配置的项使用默认值
+type LoginGuardConfig struct {
+	ChallengeAfter int `mapstructure:"challengeAfter"` // 同一用户名失败次数达到后开始退避并要求人机验证，默认 3
+	MaxAttempts    int `mapstructure:"maxAttempts"`    // 同一用户名失败次数达到后临时锁定，默认 10
+	IPMaxAttempts  int `mapstructure:"ipMaxAttempts"`  // 同一 IP 失败次数达到后临时锁定，默认 50
+	LockoutMinutes int `mapstructure:"lockoutMinutes"` // 临时锁定时长，默认 15 分钟
+}
+
+type CaptchaConfig struct {
+	Provider  string `mapstructure:"provider"` // turnstile, hcaptcha, recaptcha；留空则不启用
+	SiteKey   string `mapstructure:"siteKey"`
+	SecretKey string `mapstructure:"secretKey"`
+	VerifyURL string `mapstructure:"verifyURL"` // 可选，覆盖校验地址
+}
+
+type OAuthProviderConfig struct {
+	Name         string   `mapstructure:"name"`        // 唯一标识，出现在回调地址中，例如 github
+	Type         string   `mapstructure:"type"`        // github, oidc
+	DisplayName  string   `mapstructure:"displayName"` // 登录按钮上显示的名称
+	ClientID     string   `mapstructure:"clientID"`
+	ClientSecret string   `mapstructure:"clientSecret"`
+	Issuer       string   `mapstructure:"issuer"`      // oidc：签发者地址，用于自动发现端点
+	Scopes       []string `mapstructure:"scopes"`      // 为空时使用各类型的默认值
+	AuthURL      string   `mapstructure:"authURL"`     // 可选，覆盖授权端点（GitHub Enterprise 等）
+	TokenURL     string   `mapstructure:"tokenURL"`    // 可选，覆盖令牌端点
+	APIURL       string   `mapstructure:"apiURL"`      // github：API 地址，默认 https://api.github.com
+	RedirectURL  string   `mapstructure:"redirectURL"` // 可选，默认 {server.publicURL}/api/auth/oauth/{name}/callback
+	AllowSignup  bool     `mapstructure:"allowSignup"` // 未绑定的第三方账号是否自动注册
+}
+
 var AppConfig *Config
 
 func Init() error {
@@ -181,6 +245,7 @@ func bindEnvVars() {
 	// Server 配置
 	viper.BindEnv("server.port", "SERVER_PORT")
 	viper.BindEnv("server.mode", "SERVER_MODE")
+	viper.BindEnv("server.publicURL", "SERVER_PUBLIC_URL")
 
 	// Admin 配置
 	viper.BindEnv("admin.port", "ADMIN_PORT")
@@ -209,6 +274,7 @@ func bindEnvVars() {
 	viper.BindEnv("jwt.secret", "JWT_SECRET")
 	viper.BindEnv("jwt.adminSecret", "JWT_ADMIN_SECRET")
 	viper.BindEnv("jwt.expireHours", "JWT_EXPIRE_HOURS")
+	viper.BindEnv("jwt.accessExpireMinutes", "JWT_ACCESS_EXPIRE_MINUTES")
 
 	// Upload 配置
 	viper.BindEnv("upload.storageType", "UPLOAD_STORAGE_TYPE")
@@ -224,4 +290,23 @@ func bindEnvVars() {
 	// Cache 配置
 	viper.BindEnv("cache.articleExpire", "CACHE_ARTICLE_EXPIRE")
 	viper.BindEnv("cache.userExpire", "CACHE_USER_EXPIRE")
+
+	// Mail 配置
+	viper.BindEnv("mail.transport", "MAIL_TRANSPORT")
+	viper.BindEnv("mail.from", "MAIL_FROM")
+	viper.BindEnv("mail.fromName", "MAIL_FROM_NAME")
+	viper.BindEnv("mail.fileDir", "MAIL_FILE_DIR")
+	viper.BindEnv("mail.smtp.host", "MAIL_SMTP_HOST")
+	viper.BindEnv("mail.smtp.port", "MAIL_SMTP_PORT")
+	viper.BindEnv("mail.smtp.username", "MAIL_SMTP_USERNAME")
+	viper.BindEnv("mail.smtp.password", "MAIL_SMTP_PASSWORD")
+	viper.BindEnv("mail.smtp.tls", "MAIL_SMTP_TLS")
+
+	// 账号数据配置
+	viper.BindEnv("account.exportDir", "ACCOUNT_EXPORT_DIR")
+
+	// 人机验证配置
+	viper.BindEnv("auth.captcha.provider", "CAPTCHA_PROVIDER")
+	viper.BindEnv("auth.captcha.siteKey", "CAPTCHA_SITE_KEY")
+	viper.BindEnv("auth.captcha.secretKey", "CAPTCHA_SECRET_KEY")
 }
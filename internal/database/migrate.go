@@ -15,11 +15,24 @@ import (
 func MigrateDB() error {
 	log.Println("开始数据库迁移...")
 
+	// 文章发布时间字段由本次迁移新增时，已有的私有文章也需要回填
+	legacyArticles := DB.Migrator().HasTable(&models.Article{}) && !DB.Migrator().HasColumn(&models.Article{}, "PublishAt")
+
 	// 自动迁移所有表
 	if err := autoMigrate(); err != nil {
 		return fmt.Errorf("自动迁移失败: %w", err)
 	}
 
+	// 为旧数据回填 slug，之后才能创建唯一索引
+	if err := backfillSlugs(); err != nil {
+		return fmt.Errorf("回填 slug 失败: %w", err)
+	}
+
+	// 为已公开的旧数据回填发布时间
+	if err := backfillPublishedAt(legacyArticles); err != nil {
+		return fmt.Errorf("回填发布时间失败: %w", err)
+	}
+
 	// 创建索引
 	if err := createIndexes(); err != nil {
 		return fmt.Errorf("创建索引失败: %w", err)
@@ -33,6 +46,10 @@ func MigrateDB() error {
 
 	log.Println("数据库迁移完成！")
 
+	if err := SeedRoles(); err != nil {
+		return fmt.Errorf("初始化角色失败: %w", err)
+	}
+
 	// 检查数据库健康状态
 	if err := CreateAdmin(); err != nil {
 		return fmt.Errorf("数据库健康检查失败: %w", err)
@@ -65,10 +82,63 @@ func createIndexes() error {
 		}
 	}
 
+	// 文章和作品的 slug 在同一作者下唯一
+	for _, table := range []string{"articles", "works"} {
+		indexName := "idx_" + table + "_author_slug"
+		DB.Raw("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?", table, indexName).Scan(&count)
+		if count == 0 {
+			if err := DB.Exec(fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s(author_id, slug)", indexName, table)).Error; err != nil {
+				log.Printf("警告: 创建 slug 唯一索引失败: %v", err)
+			}
+		}
+	}
+
 	log.Println("索引创建完成")
 	return nil
 }
 
+// backfillSlugs 没有 slug 的旧文章和作品使用 ID 作为 slug，保证唯一且不影响已有链接
+func backfillSlugs() error {
+	for _, table := range []string{"articles", "works"} {
+		result := DB.Exec(fmt.Sprintf("UPDATE %s SET slug = CAST(id AS CHAR) WHERE slug IS NULL OR slug = ''", table))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("已为 %s 表回填 %d 条 slug", table, result.RowsAffected)
+		}
+	}
+	return nil
+}
+
+// backfillPublishedAt 发布时间字段加入前已公开的作品和文章以创建时间作为发布时间，
+// 文章重新公开时据此判断不是首次发布，不再通知关注者。
+// legacyArticles 为 true 时私有文章可能曾经公开过，一并回填；之后新建的私有文章从未发布，不能回填
+func backfillPublishedAt(legacyArticles bool) error {
+	articleStatuses := []int{models.ArticleStatusPublished}
+	if legacyArticles {
+		articleStatuses = append(articleStatuses, models.ArticleStatusPrivate)
+	}
+	backfills := []struct {
+		table string
+		sql   string
+		args  []interface{}
+	}{
+		{"works", "UPDATE works SET published_at = created_at WHERE status = 1 AND published_at IS NULL", nil},
+		{"articles", "UPDATE articles SET publish_at = created_at WHERE status IN ? AND publish_at IS NULL", []interface{}{articleStatuses}},
+	}
+	for _, backfill := range backfills {
+		result := DB.Exec(backfill.sql, backfill.args...)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("已为 %s 表回填 %d 条发布时间", backfill.table, result.RowsAffected)
+		}
+	}
+	return nil
+}
+
 // createForeignKeys 创建外键约束
 func createForeignKeys() error {
 	log.Println("创建外键约束...")
@@ -435,10 +505,38 @@ func AfterDeleteComment(db *gorm.DB, comment *models.Comment) error {
 	return nil
 }
 
+// SeedRoles 写入缺失的内置角色；已存在的角色保留后台调整过的权限
+func SeedRoles() error {
+	for _, builtin := range models.BuiltinRoles {
+		var count int64
+		if err := DB.Model(&models.Role{}).Where("name = ?", builtin.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		role := models.Role{
+			Name:        builtin.Name,
+			DisplayName: builtin.DisplayName,
+			Description: builtin.Description,
+			BuiltIn:     true,
+		}
+		for _, permission := range builtin.Permissions {
+			role.Permissions = append(role.Permissions, models.RolePermission{Permission: permission})
+		}
+		if err := DB.Create(&role).Error; err != nil {
+			return err
+		}
+		log.Printf("已创建内置角色: %s", builtin.Name)
+	}
+	return nil
+}
+
 func CreateAdmin() error {
 	log.Println("开始创建管理员...")
 	var user *models.User
-	res := DB.Model(&models.User{}).Where("role = ?", "admin").First(&user)
+	res := DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).First(&user)
 	if res.Error != nil && errors.Is(res.Error, gorm.ErrRecordNotFound) {
 		log.Println("管理员不存在，开始创建...")
 		// 密码加密：
@@ -452,7 +550,7 @@ func CreateAdmin() error {
 		user = &models.User{
 			Username: "admin",
 			Password: hashedPassword,
-			Role:     "admin",
+			Role:     models.RoleAdmin,
 		}
 		res = DB.Model(&models.User{}).Create(&user)
 		if res.Error != nil {
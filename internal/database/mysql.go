@@ -89,6 +89,25 @@ func autoMigrate() error {
 		&models.Doc{},
 		&models.DocVersion{},
 		&models.ShareLink{},
+		&models.UserSession{},
+		&models.UserTwoFactor{},
+		&models.UserRecoveryCode{},
+		&models.UserIdentity{},
+		&models.PersonalAccessToken{},
+		&models.AuditLog{},
+		&models.Role{},
+		&models.RolePermission{},
+		&models.AccountExport{},
+		&models.AccountDeletion{},
+		&models.ArticleRevision{},
+		&models.Series{},
+		&models.SeriesArticle{},
+		&models.SlugRedirect{},
+		&models.SearchDocument{},
+		&models.SearchPosting{},
+		&models.ImportRecord{},
+		&models.ViewStat{},
+		&models.ViewFlushBatch{},
 		// 日志表
 		&models.VisitLog{},
 		&models.VisitLogSummary{},
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessTokenHandler struct {
+	service *service.AccessTokenService
+}
+
+func NewAccessTokenHandler() *AccessTokenHandler {
+	return &AccessTokenHandler{service: service.NewAccessTokenService()}
+}
+
+// Scopes 可选的权限范围
+// GET /api/profile/tokens/scopes
+func (h *AccessTokenHandler) Scopes(c *gin.Context) {
+	utils.Success(c, models.AccessTokenScopes)
+}
+
+// List 当前用户的个人访问令牌
+// GET /api/profile/tokens
+func (h *AccessTokenHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	tokens, err := h.service.List(userID.(uint))
+	if err != nil {
+		accessTokenError(c, err)
+		return
+	}
+
+	list := make([]*models.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		list[i] = token.ToResponse()
+	}
+	utils.Success(c, list)
+}
+
+// Create 创建个人访问令牌，明文只返回这一次
+// POST /api/profile/tokens
+func (h *AccessTokenHandler) Create(c *gin.Context) {
+	var req models.PersonalAccessTokenCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	token, err := h.service.Create(userID.(uint), &req)
+	if err != nil {
+		accessTokenError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "令牌已创建，请立即复制保存，关闭后将无法再次查看", token)
+}
+
+// Revoke 删除个人访问令牌
+// DELETE /api/profile/tokens/:id
+func (h *AccessTokenHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的令牌ID")
+		return
+	}
+
+	if err := h.service.Revoke(userID.(uint), uint(id)); err != nil {
+		accessTokenError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "令牌已删除", nil)
+}
+
+func accessTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAccessTokenNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrAccessTokenScope), errors.Is(err, service.ErrAccessTokenLimit):
+		utils.BadRequest(c, err.Error())
+	default:
+		authError(c, err)
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountDataHandler 个人数据导出与账号注销
+type AccountDataHandler struct {
+	exportService   *service.AccountExportService
+	deletionService *service.AccountDeletionService
+	userService     *service.UserService
+}
+
+func NewAccountDataHandler() *AccountDataHandler {
+	return &AccountDataHandler{
+		exportService:   service.NewAccountExportService(),
+		deletionService: service.NewAccountDeletionService(),
+		userService:     service.NewUserService(),
+	}
+}
+
+// Exports 最近的导出任务
+// GET /api/profile/exports
+func (h *AccountDataHandler) Exports(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	exports, err := h.exportService.List(userID.(uint))
+	if err != nil {
+		accountDataError(c, err)
+		return
+	}
+	utils.Success(c, exports)
+}
+
+// RequestExport 创建导出任务，压缩包在后台生成
+// POST /api/profile/exports
+func (h *AccountDataHandler) RequestExport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	export, err := h.exportService.Request(userID.(uint))
+	if err != nil {
+		accountDataError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "正在生成导出文件，完成后可在此下载", export)
+}
+
+// DownloadExport 下载导出的压缩包
+// GET /api/profile/exports/:id/download
+func (h *AccountDataHandler) DownloadExport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的导出ID")
+		return
+	}
+
+	export, err := h.exportService.File(userID.(uint), uint(id))
+	if err != nil {
+		accountDataError(c, err)
+		return
+	}
+	c.FileAttachment(export.FilePath, fmt.Sprintf("inkspace-export-%s.zip", export.CreatedAt.Format("20060102")))
+}
+
+// DeletionStatus 当前的注销申请，没有时 data 为 null
+// GET /api/profile/deletion
+func (h *AccountDataHandler) DeletionStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	deletion, err := h.deletionService.Status(userID.(uint))
+	if err != nil {
+		accountDataError(c, err)
+		return
+	}
+	utils.Success(c, deletion)
+}
+
+// ScheduleDeletion 申请注销账号，冷静期结束后执行
+// POST /api/profile/deletion
+func (h *AccountDataHandler) ScheduleDeletion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	var req models.AccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.userService.GetUserByID(userID.(uint))
+	if err != nil {
+		utils.NotFound(c, "用户不存在")
+		return
+	}
+
+	deletion, err := h.deletionService.Schedule(user, &req)
+	if err != nil {
+		accountDataError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "注销申请已提交，冷静期内可随时撤销", deletion)
+}
+
+// CancelDeletion 撤销注销申请
+// DELETE /api/profile/deletion
+func (h *AccountDataHandler) CancelDeletion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.deletionService.Cancel(userID.(uint)); err != nil {
+		accountDataError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "已撤销注销申请", nil)
+}
+
+func accountDataError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrExportNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrExportInProgress), errors.Is(err, service.ErrExportTooFrequent),
+		errors.Is(err, service.ErrExportNotReady), errors.Is(err, service.ErrDeletionScheduled),
+		errors.Is(err, service.ErrDeletionNotScheduled), errors.Is(err, service.ErrDeletionConfirm),
+		errors.Is(err, service.ErrDeletionPassword), errors.Is(err, service.ErrDeletionBackendAccount):
+		utils.BadRequest(c, err.Error())
+	default:
+		authError(c, err)
+	}
+}
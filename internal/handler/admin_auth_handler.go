@@ -9,12 +9,16 @@ import (
 )
 
 type AdminAuthHandler struct {
-	service *service.UserService
+	service          *service.UserService
+	tokenService     *service.TokenService
+	twoFactorService *service.TwoFactorService
 }
 
 func NewAdminAuthHandler() *AdminAuthHandler {
 	return &AdminAuthHandler{
-		service: service.NewUserService(),
+		service:          service.NewUserService(),
+		tokenService:     service.NewTokenService(),
+		twoFactorService: service.NewTwoFactorService(),
 	}
 }
 
@@ -28,28 +32,66 @@ func (h *AdminAuthHandler) AdminLogin(c *gin.Context) {
 	}
 
 	// 先验证用户
-	_, user, err := h.service.Login(&req)
+	user, err := h.service.Authenticate(c.Request.Context(), &req, c.ClientIP())
 	if err != nil {
-		utils.Error(c, 401, err.Error())
+		loginError(c, 401, err)
 		return
 	}
 
-	// 验证是否是管理员
-	if user.Role != "admin" {
+	// 验证是否可以访问管理后台
+	if !service.NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess) {
 		utils.Error(c, 403, "该账号不是管理员")
 		return
 	}
 
-	// 生成管理员专用Token（使用独立的secret）
-	adminToken, err := utils.GenerateAdminToken(user.ID, user.Username, user.Role)
+	// 管理员必须通过两步验证，这里只会返回登录挑战（未绑定时要求先绑定验证器）
+	tokens, challenge, err := h.twoFactorService.BeginLogin(service.TokenScopeAdmin, user, clientInfo(c))
 	if err != nil {
 		utils.InternalServerError(c, "生成Token失败")
 		return
 	}
 
+	loginSuccess(c, tokens, challenge, user)
+}
+
+// AdminTwoFactorVerify 管理员登录第二步：提交验证码或恢复码
+// POST /api/admin/auth/2fa/verify
+func (h *AdminAuthHandler) AdminTwoFactorVerify(c *gin.Context) {
+	verifyTwoFactorLogin(c, h.twoFactorService, service.TokenScopeAdmin)
+}
+
+// AdminTwoFactorSetup 尚未绑定验证器的管理员在登录时获取密钥
+// POST /api/admin/auth/2fa/setup
+func (h *AdminAuthHandler) AdminTwoFactorSetup(c *gin.Context) {
+	setupTwoFactorLogin(c, h.twoFactorService, service.TokenScopeAdmin)
+}
+
+// AdminTwoFactorEnable 管理员完成验证器绑定并登录
+// POST /api/admin/auth/2fa/enable
+func (h *AdminAuthHandler) AdminTwoFactorEnable(c *gin.Context) {
+	enableTwoFactorLogin(c, h.twoFactorService, service.TokenScopeAdmin)
+}
+
+// AdminRefresh 刷新管理员令牌
+// POST /api/admin/auth/refresh
+func (h *AdminAuthHandler) AdminRefresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	tokens, user, err := h.tokenService.Refresh(service.TokenScopeAdmin, req.RefreshToken)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
 	utils.Success(c, gin.H{
-		"token": adminToken,
-		"user":  user.ToResponse(),
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user.ToResponse(),
 	})
 }
 
@@ -63,19 +105,31 @@ func (h *AdminAuthHandler) GetAdminProfile(c *gin.Context) {
 		return
 	}
 
-	// 验证是否是管理员
-	if user.Role != "admin" {
+	// 验证是否可以访问管理后台
+	roles := service.NewRoleService()
+	if !roles.HasPermission(user.Role, models.PermissionAdminAccess) {
 		utils.Error(c, 403, "该账号不是管理员")
 		return
 	}
 
-	utils.Success(c, user.ToResponse())
+	// 附带权限列表，前端据此显示菜单和操作按钮
+	response := user.ToResponse()
+	response.Permissions = roles.Permissions(user.Role)
+	utils.Success(c, response)
 }
 
 // AdminLogout 管理员退出
 // POST /api/admin/auth/logout
 func (h *AdminAuthHandler) AdminLogout(c *gin.Context) {
-	// 由于使用JWT，服务端不需要特殊处理
-	// 客户端清除token即可
+	var req models.LogoutRequest
+	_ = c.ShouldBindJSON(&req)
+
+	// 吊销当前访问令牌和刷新令牌，退出后旧令牌立即失效
+	claims, _ := tokenClaims(c)
+	if err := h.tokenService.Revoke(service.TokenScopeAdmin, claims, req.RefreshToken); err != nil {
+		authError(c, err)
+		return
+	}
+
 	utils.Success(c, nil)
 }
@@ -14,12 +14,20 @@ import (
 )
 
 type ArticleHandler struct {
-	service *service.ArticleService
+	service        *service.ArticleService
+	seriesService  *service.SeriesService
+	slugService    *service.SlugService
+	relatedService *service.RelatedArticleService
+	viewService    *service.ViewService
 }
 
 func NewArticleHandler() *ArticleHandler {
 	return &ArticleHandler{
-		service: service.NewArticleService(),
+		service:        service.NewArticleService(),
+		seriesService:  service.NewSeriesService(),
+		slugService:    service.NewSlugService(),
+		relatedService: service.NewRelatedArticleService(),
+		viewService:    service.NewViewService(),
 	}
 }
 
@@ -123,14 +131,33 @@ func (h *ArticleHandler) GetDetail(c *gin.Context) {
 		return
 	}
 
-	article, err := h.service.GetByID(uint(id))
+	h.respondDetail(c, uint(id))
+}
+
+// GetBySlug 通过作者用户名和 slug 获取文章详情，旧 slug 301 跳转到当前地址
+// GET /api/articles/slug/:username/:slug
+func (h *ArticleHandler) GetBySlug(c *gin.Context) {
+	resolved, err := h.slugService.Resolve(models.SlugTargetArticle, c.Param("username"), c.Param("slug"))
+	if err != nil {
+		slugError(c, err, "文章不存在")
+		return
+	}
+	if resolved.Redirect {
+		redirectToSlug(c, "/api/articles/slug/", resolved)
+		return
+	}
+	h.respondDetail(c, resolved.TargetID)
+}
+
+func (h *ArticleHandler) respondDetail(c *gin.Context, id uint) {
+	article, err := h.service.GetByID(id)
 	if err != nil {
 		utils.NotFound(c, "文章不存在")
 		return
 	}
 
-	// 权限检查：如果是草稿(status=0)或私有(status=2)，只有作者或管理员可以查看
-	if article.Status == 0 || article.Status == 2 {
+	// 权限检查：草稿(status=0)、私有(status=2)和定时发布(status=3)的文章，只有作者或有文章管理权限的用户可以查看
+	if article.Status != models.ArticleStatusPublished {
 		userID, exists := c.Get("user_id")
 		role, _ := c.Get("role")
 		roleStr := "user"
@@ -138,17 +165,24 @@ func (h *ArticleHandler) GetDetail(c *gin.Context) {
 			roleStr = role.(string)
 		}
 
-		// 如果不是管理员，且不是作者，则无权限查看
-		if roleStr != "admin" && (!exists || userID.(uint) != article.AuthorID) {
+		// 既没有文章管理权限，也不是作者，则无权限查看
+		if !service.NewRoleService().HasPermission(roleStr, models.PermissionArticleManage) && (!exists || userID.(uint) != article.AuthorID) {
 			utils.Error(c, 403, "无权限查看此文章")
 			return
 		}
 	}
 
-	// Increment view count
-	go h.service.IncrementViewCount(uint(id))
+	// 只统计已发布文章的浏览量，同一访客在去重窗口内只计一次
+	if article.Status == models.ArticleStatusPublished && recordView(c, h.viewService, models.ViewTargetArticle, id) {
+		article.ViewCount++
+	}
 
-	utils.Success(c, article.ToResponse())
+	resp := article.ToResponse()
+	// 所属系列及前后篇导航
+	if series, err := h.seriesService.ArticleSeries(article.ID, true); err == nil {
+		resp.Series = series
+	}
+	utils.Success(c, resp)
 }
 
 // GetEdit 获取文章详情用于编辑（需要认证，且只允许作者或管理员访问）
@@ -173,12 +207,11 @@ func (h *ArticleHandler) GetEdit(c *gin.Context) {
 		roleStr = role.(string)
 	}
 
-	// 查询文章，但使用WHERE条件确保权限（非管理员只能查询自己的文章）
+	// 查询文章，但使用WHERE条件确保权限（没有文章管理权限时只能查询自己的文章）
 	var article models.Article
 	query := database.DB.Where("id = ?", uint(id))
 
-	// 非管理员只能查询自己的文章
-	if roleStr != "admin" {
+	if !service.NewRoleService().HasPermission(roleStr, models.PermissionArticleManage) {
 		query = query.Where("author_id = ?", userID.(uint))
 	}
 
@@ -192,7 +225,11 @@ func (h *ArticleHandler) GetEdit(c *gin.Context) {
 	}
 
 	// 返回文章数据（不增加浏览量）
-	utils.Success(c, article.ToResponse())
+	resp := article.ToResponse()
+	if series, err := h.seriesService.ArticleSeries(article.ID, false); err == nil {
+		resp.Series = series
+	}
+	utils.Success(c, resp)
 }
 
 func (h *ArticleHandler) GetList(c *gin.Context) {
@@ -213,6 +250,17 @@ func (h *ArticleHandler) GetList(c *gin.Context) {
 	// 如果是 /api/admin/articles，则显示所有状态
 	if c.Request.URL.Path == "/api/admin/articles" {
 		query.ShowAll = true
+	} else {
+		// 前台请求：只有作者本人可以查看自己的草稿、私有和定时发布文章
+		query.ShowAll = false
+		currentUserID := uint(0)
+		if uid, exists := c.Get("user_id"); exists {
+			currentUserID = uid.(uint)
+		}
+		if query.AuthorID == 0 || query.AuthorID != currentUserID {
+			status := models.ArticleStatusPublished
+			query.Status = &status
+		}
 	}
 
 	articles, total, err := h.service.GetList(&query)
@@ -226,8 +274,11 @@ func (h *ArticleHandler) GetList(c *gin.Context) {
 		resp := article.ToResponse()
 		// Don't return full content in list
 		resp.Content = ""
+		resp.ContentHTML = ""
+		resp.TOC = nil
 		articleResponses[i] = resp
 	}
+	h.seriesService.AttachSeries(articleResponses)
 
 	utils.PageResponse(c, articleResponses, total, query.Page, query.PageSize)
 }
@@ -296,8 +347,11 @@ func (h *ArticleHandler) GetUserArticles(c *gin.Context) {
 		resp := article.ToResponse()
 		// Don't return full content in list
 		resp.Content = ""
+		resp.ContentHTML = ""
+		resp.TOC = nil
 		articleResponses[i] = resp
 	}
+	h.seriesService.AttachSeries(articleResponses)
 
 	utils.PageResponse(c, articleResponses, total, query.Page, query.PageSize)
 }
@@ -325,6 +379,49 @@ func (h *ArticleHandler) GetRecommended(c *gin.Context) {
 		resp := article.ToResponse()
 		// Don't return full content in list
 		resp.Content = ""
+		resp.ContentHTML = ""
+		resp.TOC = nil
+		articleResponses[i] = resp
+	}
+
+	utils.Success(c, articleResponses)
+}
+
+// GetRelated 获取相关文章，未发布的文章只有作者或有文章管理权限的用户可以查看
+// GET /api/articles/:id/related?limit=6
+func (h *ArticleHandler) GetRelated(c *gin.Context) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "6"))
+	if err != nil || limit <= 0 {
+		limit = 6
+	}
+	if limit > 10 {
+		limit = 10
+	}
+
+	var viewerID uint
+	if userID, exists := c.Get("user_id"); exists {
+		viewerID = userID.(uint)
+	}
+	articles, err := h.relatedService.Get(id, viewerID, c.GetString("role"), limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFound(c, "文章不存在")
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	articleResponses := make([]*models.ArticleResponse, len(articles))
+	for i, article := range articles {
+		resp := article.ToResponse()
+		resp.Content = ""
+		resp.ContentHTML = ""
+		resp.TOC = nil
 		articleResponses[i] = resp
 	}
 
@@ -379,6 +476,8 @@ func (h *ArticleHandler) GetHotArticles(c *gin.Context) {
 		resp := article.ToResponse()
 		// Don't return full content in list
 		resp.Content = ""
+		resp.ContentHTML = ""
+		resp.TOC = nil
 		articleResponses[i] = resp
 	}
 
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxMarkdownArchiveSize 导入压缩包的大小上限
+const maxMarkdownArchiveSize = 100 << 20
+
+// ArticleMarkdownHandler 文章的 Markdown 批量导入导出
+type ArticleMarkdownHandler struct {
+	service *service.ArticleMarkdownService
+}
+
+func NewArticleMarkdownHandler() *ArticleMarkdownHandler {
+	return &ArticleMarkdownHandler{
+		service: service.NewArticleMarkdownService(),
+	}
+}
+
+// Import 上传 zip 压缩包批量导入文章，支持 Hugo、Hexo、Jekyll 的 YAML/TOML 元数据头
+// POST /api/articles/import (multipart: file)
+func (h *ArticleMarkdownHandler) Import(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请选择要导入的 zip 文件")
+		return
+	}
+	if !strings.EqualFold(filepath.Ext(header.Filename), ".zip") {
+		utils.BadRequest(c, "只支持 zip 格式的压缩包")
+		return
+	}
+	if header.Size > maxMarkdownArchiveSize {
+		utils.BadRequest(c, fmt.Sprintf("压缩包大小不能超过 %d MB", maxMarkdownArchiveSize>>20))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.InternalServerError(c, "读取上传文件失败")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Import(userID.(uint), c.GetString("role"), file, header.Size)
+	if err != nil {
+		if errors.Is(err, service.ErrMarkdownArchiveInvalid) ||
+			errors.Is(err, service.ErrMarkdownArchiveEmpty) ||
+			errors.Is(err, service.ErrMarkdownArchiveTooMany) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.SuccessWithMessage(c, fmt.Sprintf("导入完成：成功 %d 篇，跳过 %d 篇，失败 %d 篇", result.Imported, result.Skipped, result.Failed), result)
+}
+
+// Export 下载当前用户全部文章的 Markdown 压缩包，格式与导入一致
+// GET /api/articles/export
+func (h *ArticleMarkdownHandler) Export(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := h.service.Export(userID.(uint), &buf); err != nil {
+		utils.InternalServerError(c, "导出文章失败")
+		return
+	}
+	filename := fmt.Sprintf("inkspace-articles-%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
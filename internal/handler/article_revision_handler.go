@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArticleRevisionHandler 文章版本历史：列表、查看、比较和恢复
+type ArticleRevisionHandler struct {
+	service *service.ArticleRevisionService
+}
+
+func NewArticleRevisionHandler() *ArticleRevisionHandler {
+	return &ArticleRevisionHandler{
+		service: service.NewArticleRevisionService(),
+	}
+}
+
+// List 获取文章版本列表
+// GET /api/articles/:id/revisions
+func (h *ArticleRevisionHandler) List(c *gin.Context) {
+	articleID, userID, role, ok := revisionContext(c)
+	if !ok {
+		return
+	}
+	revisions, err := h.service.List(articleID, userID, role)
+	if err != nil {
+		articleRevisionError(c, err)
+		return
+	}
+	responses := make([]*models.ArticleRevisionResponse, len(revisions))
+	for i := range revisions {
+		responses[i] = revisions[i].ToResponse()
+	}
+	utils.Success(c, responses)
+}
+
+// Get 获取指定版本的完整内容
+// GET /api/articles/:id/revisions/:version
+func (h *ArticleRevisionHandler) Get(c *gin.Context) {
+	articleID, userID, role, ok := revisionContext(c)
+	if !ok {
+		return
+	}
+	version, ok := pathVersion(c)
+	if !ok {
+		return
+	}
+	revision, err := h.service.Get(articleID, version, userID, role)
+	if err != nil {
+		articleRevisionError(c, err)
+		return
+	}
+	utils.Success(c, revision.ToResponse())
+}
+
+// Diff 比较两个版本
+// GET /api/articles/:id/revisions/diff?from=1&to=2&mode=line|word
+func (h *ArticleRevisionHandler) Diff(c *gin.Context) {
+	articleID, userID, role, ok := revisionContext(c)
+	if !ok {
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from <= 0 {
+		utils.BadRequest(c, "无效的起始版本")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to <= 0 {
+		utils.BadRequest(c, "无效的目标版本")
+		return
+	}
+	mode := c.DefaultQuery("mode", service.DiffModeLine)
+	if mode != service.DiffModeLine && mode != service.DiffModeWord {
+		utils.BadRequest(c, "比较方式只能是 line 或 word")
+		return
+	}
+
+	result, err := h.service.Diff(articleID, from, to, mode, userID, role)
+	if err != nil {
+		articleRevisionError(c, err)
+		return
+	}
+	utils.Success(c, result)
+}
+
+// Restore 恢复到指定版本
+// POST /api/articles/:id/revisions/:version/restore
+func (h *ArticleRevisionHandler) Restore(c *gin.Context) {
+	articleID, userID, role, ok := revisionContext(c)
+	if !ok {
+		return
+	}
+	version, ok := pathVersion(c)
+	if !ok {
+		return
+	}
+	article, err := h.service.Restore(articleID, version, userID, role)
+	if err != nil {
+		articleRevisionError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "已恢复到 v"+strconv.Itoa(version), article.ToResponse())
+}
+
+func revisionContext(c *gin.Context) (uint, uint, string, bool) {
+	articleID, ok := pathUint(c, "id")
+	if !ok {
+		return 0, 0, "", false
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, "", false
+	}
+	roleStr := "user"
+	if role, exists := c.Get("role"); exists && role != nil {
+		roleStr = role.(string)
+	}
+	return articleID, userID, roleStr, true
+}
+
+func articleRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrArticleNotEditable), errors.Is(err, service.ErrArticleRevisionNotFound):
+		utils.NotFound(c, err.Error())
+	default:
+		utils.InternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+)
+
+func authError(c *gin.Context, err error) {
+	var blocked *service.LoginBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		loginError(c, 429, err)
+	case errors.Is(err, service.ErrRefreshTokenInvalid), errors.Is(err, service.ErrSessionRevoked):
+		utils.Unauthorized(c, err.Error())
+	case errors.Is(err, service.ErrSessionNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrTwoFactorChallengeInvalid):
+		utils.Unauthorized(c, err.Error())
+	case errors.Is(err, service.ErrTwoFactorCodeInvalid), errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrTwoFactorAlreadyEnabled), errors.Is(err, service.ErrTwoFactorSetupMissing),
+		errors.Is(err, service.ErrTwoFactorRequired):
+		utils.BadRequest(c, err.Error())
+	default:
+		zap.L().Error("auth request failed", zap.Error(err))
+		utils.InternalServerError(c, "服务暂时不可用")
+	}
+}
+
+// loginError 密码登录失败的响应：锁定期间返回 429 和重试时间，需要人机验证时附带组件信息，
+// 其他错误沿用各登录入口原有的错误码
+func loginError(c *gin.Context, code int, err error) {
+	var blocked *service.LoginBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		seconds := int(blocked.RetryAfter.Seconds() + 0.999)
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		utils.ErrorWithData(c, 429, err.Error(), gin.H{"retry_after": seconds})
+	case errors.Is(err, service.ErrCaptchaRequired), errors.Is(err, service.ErrCaptchaInvalid):
+		utils.ErrorWithData(c, code, err.Error(), gin.H{
+			"captcha_required": true,
+			"captcha":          service.NewLoginGuardService().CaptchaInfo(),
+		})
+	default:
+		utils.Error(c, code, err.Error())
+	}
+}
+
+// tokenClaims 读取认证中间件解析出的访问令牌声明
+func tokenClaims(c *gin.Context) (*utils.Claims, bool) {
+	value, exists := c.Get("token_claims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*utils.Claims)
+	return claims, ok
+}
+
+// clientInfo 记录登录设备信息，用于会话列表展示
+func clientInfo(c *gin.Context) *models.ClientInfo {
+	return &models.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// loginSuccess 登录成功的统一响应；需要两步验证时只返回挑战，不包含令牌
+func loginSuccess(c *gin.Context, tokens *models.TokenPair, challenge *models.TwoFactorChallenge, user *models.User) {
+	if challenge != nil {
+		utils.Success(c, gin.H{"two_factor": challenge})
+		return
+	}
+	utils.Success(c, gin.H{
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user.ToResponse(),
+	})
+}
@@ -56,6 +56,7 @@ func (h *DocHandler) List(c *gin.Context) {
 		responses[i].Summary = service.ContentSummary(docs[i].Content)
 		responses[i].Content = ""
 		responses[i].ContentHTML = ""
+		responses[i].TOC = nil
 	}
 	utils.Success(c, responses)
 }
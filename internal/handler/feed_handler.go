@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+	"github.com/iceymoss/inkspace/pkg/feed"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedHandler 文章订阅源（RSS 2.0 / Atom / JSON Feed）
+type FeedHandler struct {
+	service *service.FeedService
+}
+
+func NewFeedHandler() *FeedHandler {
+	return &FeedHandler{
+		service: service.NewFeedService(),
+	}
+}
+
+// Site 全站最新文章
+// GET /api/feed/:format
+func (h *FeedHandler) Site(c *gin.Context) {
+	h.serve(c, service.FeedScopeSite, 0, c.Param("format"))
+}
+
+// SiteAlias 根路径下的常用订阅地址，如 /feed.xml、/atom.xml、/feed.json
+func (h *FeedHandler) SiteAlias(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.serve(c, service.FeedScopeSite, 0, string(format))
+	}
+}
+
+// Author 作者的文章
+// GET /api/users/:id/feed/:format
+func (h *FeedHandler) Author(c *gin.Context) {
+	h.serveScoped(c, service.FeedScopeAuthor)
+}
+
+// Category 分类下的文章
+// GET /api/categories/:id/feed/:format
+func (h *FeedHandler) Category(c *gin.Context) {
+	h.serveScoped(c, service.FeedScopeCategory)
+}
+
+// Tag 标签下的文章
+// GET /api/tags/:id/feed/:format
+func (h *FeedHandler) Tag(c *gin.Context) {
+	h.serveScoped(c, service.FeedScopeTag)
+}
+
+func (h *FeedHandler) serveScoped(c *gin.Context, scope string) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	h.serve(c, scope, id, c.Param("format"))
+}
+
+func (h *FeedHandler) serve(c *gin.Context, scope string, id uint, formatName string) {
+	format, ok := feed.ParseFormat(strings.ToLower(formatName))
+	if !ok {
+		utils.BadRequest(c, "不支持的订阅格式，可选 rss、atom、json")
+		return
+	}
+
+	doc, err := h.service.Build(scope, id, format, feedBaseURL(c))
+	if err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.Header("ETag", doc.ETag)
+	c.Header("Last-Modified", doc.LastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+	if feedNotModified(c.Request, doc) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, doc.ContentType, doc.Body)
+}
+
+// feedNotModified 条件请求判断：优先比较 If-None-Match，没有时再比较 If-Modified-Since
+func feedNotModified(r *http.Request, doc *service.FeedDocument) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == doc.ETag {
+				return true
+			}
+		}
+		return false
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil {
+			return !doc.LastModified.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
+
+// feedBaseURL 站点对外地址；未配置 server.publicURL 时根据请求推断
+func feedBaseURL(c *gin.Context) string {
+	if config.AppConfig != nil && config.AppConfig.Server.PublicURL != "" {
+		return strings.TrimRight(config.AppConfig.Server.PublicURL, "/")
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	return scheme + "://" + c.Request.Host
+}
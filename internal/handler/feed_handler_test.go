@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/service"
+)
+
+func TestFeedNotModified(t *testing.T) {
+	modified := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	doc := &service.FeedDocument{ETag: `"abc"`, LastModified: modified}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"无条件请求", nil, false},
+		{"ETag 匹配", map[string]string{"If-None-Match": `"abc"`}, true},
+		{"弱 ETag 匹配", map[string]string{"If-None-Match": `"xyz", W/"abc"`}, true},
+		{"ETag 不匹配时忽略时间", map[string]string{"If-None-Match": `"xyz"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, false},
+		{"未修改", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+		{"已修改", map[string]string{"If-Modified-Since": modified.Add(-time.Minute).Format(http.TimeFormat)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/feed/rss", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			if got := feedNotModified(req, doc); got != tt.want {
+				t.Fatalf("feedNotModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginGuardHandler struct {
+	service *service.LoginGuardService
+}
+
+func NewLoginGuardHandler() *LoginGuardHandler {
+	return &LoginGuardHandler{service: service.NewLoginGuardService()}
+}
+
+// List 当前被退避或锁定的用户名和 IP
+// GET /api/admin/login-locks
+func (h *LoginGuardHandler) List(c *gin.Context) {
+	locks, err := h.service.Locks()
+	if err != nil {
+		utils.InternalServerError(c, "获取登录锁定列表失败")
+		return
+	}
+
+	utils.Success(c, locks)
+}
+
+// Unlock 解除用户名或 IP 的登录锁定
+// POST /api/admin/login-locks/unlock
+func (h *LoginGuardHandler) Unlock(c *gin.Context) {
+	var req models.LoginUnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	setAuditTarget(c, req.Type, req.Value)
+	if err := h.service.Unlock(req.Type, req.Value); err != nil {
+		if errors.Is(err, service.ErrLoginLockType) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, "解除锁定失败")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已解除锁定", nil)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthBindingCookie 将授权流程绑定到发起的浏览器，只在回调路径下发送
+const (
+	oauthBindingCookie = "oauth_binding"
+	oauthBindingPath   = "/api/auth/oauth/"
+)
+
+type OAuthHandler struct {
+	service *service.OAuthService
+}
+
+func NewOAuthHandler() *OAuthHandler {
+	return &OAuthHandler{service: service.NewOAuthService()}
+}
+
+// Providers 已启用的第三方登录方式
+// GET /api/auth/oauth/providers
+func (h *OAuthHandler) Providers(c *gin.Context) {
+	utils.Success(c, h.service.Providers())
+}
+
+// Start 跳转到第三方授权页
+// GET /api/auth/oauth/:provider/start?redirect=/path
+func (h *OAuthHandler) Start(c *gin.Context) {
+	authURL, binding, err := h.service.StartLogin(c.Request.Context(), c.Param("provider"), c.Query("redirect"))
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+	setOAuthBinding(c, binding, int(service.OAuthStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback 第三方授权回调，处理完成后跳转回前端回调页
+// GET /api/auth/oauth/:provider/callback
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	binding, _ := c.Cookie(oauthBindingCookie)
+	target := h.service.Callback(c.Request.Context(), c.Param("provider"), c.Query("code"), c.Query("state"), binding, c.Query("error"))
+	setOAuthBinding(c, "", -1)
+	c.Redirect(http.StatusFound, target)
+}
+
+// Exchange 前端回调页用一次性 code 换取登录令牌
+// POST /api/auth/oauth/exchange
+func (h *OAuthHandler) Exchange(c *gin.Context) {
+	var req models.OAuthExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	tokens, challenge, user, err := h.service.Exchange(req.Code, clientInfo(c))
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+
+	loginSuccess(c, tokens, challenge, user)
+}
+
+// Identities 当前用户绑定的第三方账号
+// GET /api/profile/identities
+func (h *OAuthHandler) Identities(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	identities, err := h.service.ListIdentities(userID.(uint))
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+
+	utils.Success(c, identities)
+}
+
+// Link 返回绑定第三方账号的授权地址，由前端跳转
+// POST /api/profile/identities/:provider/link
+func (h *OAuthHandler) Link(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	authURL, binding, err := h.service.StartLink(c.Request.Context(), c.Param("provider"), userID.(uint))
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+	setOAuthBinding(c, binding, int(service.OAuthStateTTL.Seconds()))
+
+	utils.Success(c, gin.H{"url": authURL})
+}
+
+// Unlink 解绑第三方账号
+// DELETE /api/profile/identities/:id
+func (h *OAuthHandler) Unlink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的绑定ID")
+		return
+	}
+
+	if err := h.service.Unlink(userID.(uint), uint(id)); err != nil {
+		oauthError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已解绑", nil)
+}
+
+// setOAuthBinding 写入或清除（maxAge < 0）授权流程的浏览器绑定 Cookie。
+// SameSite=Lax 保证从身份提供方跳转回来的顶层 GET 请求会带上它
+func setOAuthBinding(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthBindingCookie, value, maxAge, oauthBindingPath, "", secure, true)
+}
+
+func oauthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOAuthProviderNotFound), errors.Is(err, service.ErrOAuthIdentityNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrOAuthStateInvalid):
+		utils.Unauthorized(c, err.Error())
+	case errors.Is(err, service.ErrOAuthLastLoginMethod):
+		utils.BadRequest(c, err.Error())
+	default:
+		authError(c, err)
+	}
+}
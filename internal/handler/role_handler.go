@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	service *service.RoleService
+}
+
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{service: service.NewRoleService()}
+}
+
+// Permissions 可分配的权限点
+// GET /api/admin/permissions
+func (h *RoleHandler) Permissions(c *gin.Context) {
+	utils.Success(c, models.Permissions)
+}
+
+// List 角色列表
+// GET /api/admin/roles
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.service.List()
+	if err != nil {
+		utils.InternalServerError(c, "获取角色列表失败")
+		return
+	}
+
+	utils.Success(c, roles)
+}
+
+// Create 新建角色
+// POST /api/admin/roles
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req models.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	role, err := h.service.Create(&req, c.GetString("role"))
+	if err != nil {
+		roleError(c, err)
+		return
+	}
+
+	utils.Success(c, role)
+}
+
+// Update 修改角色
+// PUT /api/admin/roles/:id
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的角色ID")
+		return
+	}
+
+	var req models.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	role, err := h.service.Update(uint(id), &req, c.GetString("role"))
+	if err != nil {
+		roleError(c, err)
+		return
+	}
+
+	utils.Success(c, role)
+}
+
+// Delete 删除角色
+// DELETE /api/admin/roles/:id
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的角色ID")
+		return
+	}
+
+	if err := h.service.Delete(uint(id), c.GetString("role")); err != nil {
+		roleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+func roleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		utils.Error(c, 404, err.Error())
+	case errors.Is(err, service.ErrRoleEscalation):
+		utils.Error(c, 403, err.Error())
+	case errors.Is(err, service.ErrRoleExists), errors.Is(err, service.ErrRoleName),
+		errors.Is(err, service.ErrRoleNotEditable), errors.Is(err, service.ErrRoleBuiltIn),
+		errors.Is(err, service.ErrRoleInUse), errors.Is(err, service.ErrPermissionInvalid):
+		utils.Error(c, 400, err.Error())
+	default:
+		utils.InternalServerError(c, "角色操作失败")
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 文章、作品、文档和用户的统一全文搜索
+type SearchHandler struct {
+	service *service.SearchService
+}
+
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{
+		service: service.NewSearchService(),
+	}
+}
+
+// Search 统一搜索，返回高亮结果和分面统计
+// GET /api/search?q=&type=&category_id=&tag_id=&work_type=&author_id=
+func (h *SearchHandler) Search(c *gin.Context) {
+	var query models.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	query.Keyword = strings.TrimSpace(query.Keyword)
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+
+	resp, err := h.service.Search(&query)
+	if err != nil {
+		if errors.Is(err, service.ErrSearchQueryEmpty) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.Success(c, resp)
+}
+
+// Reindex 在后台重建搜索索引（管理员），任务开始后立即返回 202，结果记录在日志中
+// POST /api/admin/search/reindex?type=article,work
+func (h *SearchHandler) Reindex(c *gin.Context) {
+	var types []string
+	for _, t := range strings.Split(c.Query("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	if err := h.service.StartReindex(types...); err != nil {
+		switch {
+		case errors.Is(err, service.ErrSearchTypeInvalid):
+			utils.BadRequest(c, err.Error())
+		case errors.Is(err, service.ErrSearchReindexing):
+			utils.Error(c, http.StatusConflict, err.Error())
+		default:
+			utils.InternalServerError(c, err.Error())
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, utils.Response{Code: 0, Message: "索引重建已开始", Data: gin.H{"types": types}})
+}
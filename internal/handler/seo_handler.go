@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/pkg/seo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SEOHandler 为前台 SPA 页面生成标题、描述、Open Graph 和结构化数据
+type SEOHandler struct {
+	service *service.SEOService
+}
+
+func NewSEOHandler() *SEOHandler {
+	return &SEOHandler{
+		service: service.NewSEOService(),
+	}
+}
+
+// PageMeta 供 serveSPA 在返回 index.html 前改写 <head>
+func (h *SEOHandler) PageMeta(c *gin.Context, requestPath string) *seo.Meta {
+	return h.service.PageMeta(requestPath, feedBaseURL(c))
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesHandler 文章系列：公开浏览和作者管理
+type SeriesHandler struct {
+	service *service.SeriesService
+}
+
+func NewSeriesHandler() *SeriesHandler {
+	return &SeriesHandler{
+		service: service.NewSeriesService(),
+	}
+}
+
+// List 公开系列列表，包含连载进度
+// GET /api/series?owner_id=
+func (h *SeriesHandler) List(c *gin.Context) {
+	var query models.SeriesListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+
+	viewerID, _ := optionalViewer(c)
+	list, total, err := h.service.List(&query, viewerID)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.PageResponse(c, list, total, query.Page, query.PageSize)
+}
+
+// My 当前用户的系列（包含尚未发布文章的系列）
+// GET /api/series/my
+func (h *SeriesHandler) My(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+	query := &models.SeriesListQuery{Page: 1, PageSize: 200, OwnerID: userID.(uint)}
+	list, _, err := h.service.List(query, userID.(uint))
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.Success(c, list)
+}
+
+// Get 系列详情及按顺序排列的文章
+// GET /api/series/:id
+func (h *SeriesHandler) Get(c *gin.Context) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	viewerID, role := optionalViewer(c)
+	series, err := h.service.Get(id, viewerID, role)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+	utils.Success(c, series)
+}
+
+func (h *SeriesHandler) Create(c *gin.Context) {
+	var req models.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+	series, err := h.service.Create(&req, userID.(uint))
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+	utils.Success(c, series.ToResponse())
+}
+
+func (h *SeriesHandler) Update(c *gin.Context) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	var req models.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	userID, role, ok := seriesEditor(c)
+	if !ok {
+		return
+	}
+	series, err := h.service.Update(id, &req, userID, role)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+	utils.Success(c, series.ToResponse())
+}
+
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	userID, role, ok := seriesEditor(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(id, userID, role); err != nil {
+		seriesError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// SetArticles 按顺序设置系列中的文章
+// PUT /api/series/:id/articles
+func (h *SeriesHandler) SetArticles(c *gin.Context) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	var req models.SeriesArticlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	userID, role, ok := seriesEditor(c)
+	if !ok {
+		return
+	}
+	if err := h.service.SetArticles(id, req.ArticleIDs, userID, role); err != nil {
+		seriesError(c, err)
+		return
+	}
+	series, err := h.service.Get(id, userID, role)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+	utils.Success(c, series)
+}
+
+func seriesEditor(c *gin.Context) (uint, string, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, "", false
+	}
+	_, role := optionalViewer(c)
+	return userID, role, true
+}
+
+// optionalViewer 返回当前登录用户（可能未登录）及其角色
+func optionalViewer(c *gin.Context) (uint, string) {
+	viewerID := uint(0)
+	if uid, exists := c.Get("user_id"); exists {
+		viewerID = uid.(uint)
+	}
+	role := "user"
+	if value, exists := c.Get("role"); exists && value != nil {
+		role = value.(string)
+	}
+	return viewerID, role
+}
+
+func seriesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSeriesNotFound):
+		utils.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrSeriesArticleInvalid), errors.Is(err, service.ErrSeriesArticleTaken):
+		utils.BadRequest(c, err.Error())
+	default:
+		utils.InternalServerError(c, err.Error())
+	}
+}
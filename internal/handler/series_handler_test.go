@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iceymoss/inkspace/internal/service"
+)
+
+func TestSeriesError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "not found", err: service.ErrSeriesNotFound, code: http.StatusNotFound},
+		{name: "invalid article", err: service.ErrSeriesArticleInvalid, code: http.StatusBadRequest},
+		{name: "wrapped taken", err: fmt.Errorf("set: %w", service.ErrSeriesArticleTaken), code: http.StatusBadRequest},
+		{name: "unexpected", err: errors.New("db down"), code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			context, _ := gin.CreateTestContext(recorder)
+
+			seriesError(context, tt.err)
+
+			var body struct {
+				Code int `json:"code"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != tt.code {
+				t.Fatalf("seriesError(%v) code = %d, want %d", tt.err, body.Code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	service *service.SessionService
+}
+
+func NewSessionHandler() *SessionHandler {
+	return &SessionHandler{service: service.NewSessionService()}
+}
+
+// List 当前用户的登录会话
+// GET /api/profile/sessions
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	sessions, err := h.service.List(userID.(uint))
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	currentSessionID := ""
+	if claims, ok := tokenClaims(c); ok {
+		currentSessionID = claims.SessionID
+	}
+	list := make([]*models.UserSessionResponse, len(sessions))
+	for i, session := range sessions {
+		list[i] = session.ToResponse(currentSessionID)
+	}
+
+	utils.Success(c, list)
+}
+
+// Revoke 远程注销某个会话
+// DELETE /api/profile/sessions/:id
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的会话ID")
+		return
+	}
+
+	if err := h.service.Revoke(userID.(uint), uint(id)); err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已注销该会话", nil)
+}
+
+// RevokeOthers 在其他所有设备上退出登录
+// DELETE /api/profile/sessions
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	claims, ok := tokenClaims(c)
+	if !ok || claims.SessionID == "" {
+		utils.BadRequest(c, "当前登录不支持会话管理，请重新登录后再试")
+		return
+	}
+
+	count, err := h.service.RevokeOthers(userID.(uint), claims.SessionID)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"revoked": count})
+}
@@ -59,11 +59,14 @@ func (h *SettingHandler) UpdateSetting(c *gin.Context) {
 		return
 	}
 
+	before, _ := h.service.Values([]string{req.Key})
 	setting, err := h.service.Set(&req)
+	setAuditTarget(c, "settings", req.Key)
 	if err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
+	setAuditChange(c, before, map[string]string{setting.Key: setting.Value})
 
 	utils.Success(c, setting.ToResponse())
 }
@@ -76,10 +79,18 @@ func (h *SettingHandler) BatchUpdateSettings(c *gin.Context) {
 		return
 	}
 
+	keys := make([]string, 0, len(req))
+	for key := range req {
+		keys = append(keys, key)
+	}
+	before, _ := h.service.Values(keys)
+	setAuditTarget(c, "settings", "")
 	if err := h.service.BatchSet(req); err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
+	after, _ := h.service.Values(keys)
+	setAuditChange(c, before, after)
 
 	utils.SuccessWithMessage(c, "配置更新成功", nil)
 }
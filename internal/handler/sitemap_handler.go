@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+	"github.com/iceymoss/inkspace/pkg/sitemap"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapHandler 站点地图与 robots.txt
+type SitemapHandler struct {
+	service *service.SitemapService
+}
+
+func NewSitemapHandler() *SitemapHandler {
+	return &SitemapHandler{
+		service: service.NewSitemapService(),
+	}
+}
+
+// Index 站点地图索引
+// GET /sitemap.xml
+func (h *SitemapHandler) Index(c *gin.Context) {
+	doc, err := h.service.Index(feedBaseURL(c))
+	h.serve(c, doc, err)
+}
+
+// Section 按分类拆分的站点地图，如 /sitemaps/articles-1.xml
+// GET /sitemaps/:name
+func (h *SitemapHandler) Section(c *gin.Context) {
+	section, page, ok := parseSitemapName(c.Param("name"))
+	if !ok {
+		utils.NotFound(c, service.ErrSitemapNotFound.Error())
+		return
+	}
+	doc, err := h.service.Section(section, page, feedBaseURL(c))
+	h.serve(c, doc, err)
+}
+
+// Robots 动态 robots.txt，内容可在后台 SEO 设置中修改
+// GET /robots.txt
+func (h *SitemapHandler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, h.service.RobotsTxt(feedBaseURL(c)))
+}
+
+func (h *SitemapHandler) serve(c *gin.Context, doc *service.SitemapDocument, err error) {
+	if err != nil {
+		if errors.Is(err, service.ErrSitemapNotFound) {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.Header("Last-Modified", doc.LastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, sitemap.ContentType, doc.Body)
+}
+
+// parseSitemapName 解析 {section}-{page}.xml
+func parseSitemapName(name string) (string, int, bool) {
+	name, ok := strings.CutSuffix(name, ".xml")
+	if !ok {
+		return "", 0, false
+	}
+	i := strings.LastIndex(name, "-")
+	if i <= 0 {
+		return "", 0, false
+	}
+	page, err := strconv.Atoi(name[i+1:])
+	if err != nil || page < 1 {
+		return "", 0, false
+	}
+	return name[:i], page, true
+}
@@ -0,0 +1,26 @@
+package handler
+
+import "testing"
+
+func TestParseSitemapName(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		page    int
+		ok      bool
+	}{
+		{"articles-1.xml", "articles", 1, true},
+		{"docs-12.xml", "docs", 12, true},
+		{"articles-0.xml", "", 0, false},
+		{"articles.xml", "", 0, false},
+		{"-1.xml", "", 0, false},
+		{"articles-1.txt", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		section, page, ok := parseSitemapName(tt.name)
+		if section != tt.section || page != tt.page || ok != tt.ok {
+			t.Errorf("parseSitemapName(%q) = %q, %d, %v", tt.name, section, page, ok)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// slugPermalinkPrefixes 前台固定链接前缀与内容类型的对应关系，如 /blog/:username/:slug
+var slugPermalinkPrefixes = map[string]string{
+	"blog":  models.SlugTargetArticle,
+	"works": models.SlugTargetWork,
+}
+
+// SlugPermalinkRedirect 供前台 SPA 路由使用：旧 slug 的固定链接返回当前地址
+func SlugPermalinkRedirect(requestPath string) (string, bool) {
+	parts := strings.Split(strings.Trim(requestPath, "/"), "/")
+	if len(parts) != 3 {
+		return "", false
+	}
+	targetType, ok := slugPermalinkPrefixes[parts[0]]
+	if !ok {
+		return "", false
+	}
+	resolved, err := service.NewSlugService().Resolve(targetType, parts[1], parts[2])
+	if err != nil || !resolved.Redirect {
+		return "", false
+	}
+	return slugPath("/"+parts[0]+"/", resolved), true
+}
+
+// redirectToSlug 301 跳转到规范 slug 地址，保留查询参数
+func redirectToSlug(c *gin.Context, prefix string, resolved *models.SlugResolution) {
+	location := slugPath(prefix, resolved)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
+
+func slugPath(prefix string, resolved *models.SlugResolution) string {
+	return prefix + url.PathEscape(resolved.Username) + "/" + url.PathEscape(resolved.Slug)
+}
+
+func slugError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, service.ErrSlugNotFound) {
+		utils.NotFound(c, notFound)
+		return
+	}
+	utils.InternalServerError(c, err.Error())
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimelineHandler 个性化首页时间线
+type TimelineHandler struct {
+	service *service.TimelineService
+}
+
+func NewTimelineHandler() *TimelineHandler {
+	return &TimelineHandler{
+		service: service.NewTimelineService(),
+	}
+}
+
+// Get 关注作者的新文章和作品，按兴趣混合排序，游标分页
+// GET /api/feed?cursor=&page_size=
+func (h *TimelineHandler) Get(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	var query models.TimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	if query.PageSize <= 0 || query.PageSize > 50 {
+		query.PageSize = 20
+	}
+
+	resp, err := h.service.Get(userID.(uint), &query)
+	if err != nil {
+		if errors.Is(err, service.ErrTimelineCursorInvalid) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.Success(c, resp)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	service     *service.TwoFactorService
+	userService *service.UserService
+}
+
+func NewTwoFactorHandler() *TwoFactorHandler {
+	return &TwoFactorHandler{
+		service:     service.NewTwoFactorService(),
+		userService: service.NewUserService(),
+	}
+}
+
+// Status 两步验证状态
+// GET /api/profile/2fa
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.service.Status(user)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, status)
+}
+
+// Setup 生成验证器密钥和二维码地址
+// POST /api/profile/2fa/setup
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	setup, err := h.service.Setup(user)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, setup)
+}
+
+// Enable 校验验证码并开启两步验证，恢复码只在此时返回一次
+// POST /api/profile/2fa/enable
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	recoveryCodes, err := h.service.Enable(userID.(uint), req.Code)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"recovery_codes": recoveryCodes})
+}
+
+// Disable 关闭两步验证
+// POST /api/profile/2fa/disable
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req models.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Disable(user, req.Password, req.Code); err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已关闭两步验证", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// POST /api/profile/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	recoveryCodes, err := h.service.RegenerateRecoveryCodes(userID.(uint), req.Code)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"recovery_codes": recoveryCodes})
+}
+
+// VerifyLogin 用户端登录第二步：提交验证码或恢复码
+// POST /api/auth/2fa/verify
+func (h *TwoFactorHandler) VerifyLogin(c *gin.Context) {
+	verifyTwoFactorLogin(c, h.service, service.TokenScopeUser)
+}
+
+// SetupLogin 必须开启两步验证的账号在登录时获取验证器密钥
+// POST /api/auth/2fa/setup
+func (h *TwoFactorHandler) SetupLogin(c *gin.Context) {
+	setupTwoFactorLogin(c, h.service, service.TokenScopeUser)
+}
+
+// EnableLogin 登录时完成验证器绑定
+// POST /api/auth/2fa/enable
+func (h *TwoFactorHandler) EnableLogin(c *gin.Context) {
+	enableTwoFactorLogin(c, h.service, service.TokenScopeUser)
+}
+
+func (h *TwoFactorHandler) currentUser(c *gin.Context) (*models.User, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return nil, false
+	}
+	user, err := h.userService.GetUserByID(userID.(uint))
+	if err != nil {
+		utils.NotFound(c, "用户不存在")
+		return nil, false
+	}
+	return user, true
+}
+
+func verifyTwoFactorLogin(c *gin.Context, twoFactorService *service.TwoFactorService, scope string) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	tokens, user, err := twoFactorService.CompleteLogin(scope, req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	loginSuccess(c, tokens, nil, user)
+}
+
+func setupTwoFactorLogin(c *gin.Context, twoFactorService *service.TwoFactorService, scope string) {
+	var req models.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	setup, err := twoFactorService.SetupForChallenge(scope, req.ChallengeToken)
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, setup)
+}
+
+func enableTwoFactorLogin(c *gin.Context, twoFactorService *service.TwoFactorService, scope string) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	tokens, user, recoveryCodes, err := twoFactorService.EnableForChallenge(scope, req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"token":          tokens.Token,
+		"refresh_token":  tokens.RefreshToken,
+		"expires_in":     tokens.ExpiresIn,
+		"user":           user.ToResponse(),
+		"recovery_codes": recoveryCodes,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/iceymoss/inkspace/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type UserHandler struct {
@@ -45,18 +47,54 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, user, err := h.service.Login(&req)
+	tokens, challenge, user, err := h.service.Login(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
-		utils.Error(c, 400, err.Error())
+		loginError(c, 400, err)
+		return
+	}
+
+	loginSuccess(c, tokens, challenge, user)
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对
+// POST /api/auth/refresh
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	tokens, user, err := service.NewTokenService().Refresh(service.TokenScopeUser, req.RefreshToken)
+	if err != nil {
+		authError(c, err)
 		return
 	}
 
 	utils.Success(c, gin.H{
-		"token": token,
-		"user":  user.ToResponse(),
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user.ToResponse(),
 	})
 }
 
+// Logout 退出登录，吊销当前访问令牌和刷新令牌
+// POST /api/logout
+func (h *UserHandler) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	// 请求体可选，未携带刷新令牌时只吊销访问令牌
+	_ = c.ShouldBindJSON(&req)
+
+	claims, _ := tokenClaims(c)
+	if err := service.NewTokenService().Revoke(service.TokenScopeUser, claims, req.RefreshToken); err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, nil)
+}
+
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -115,7 +153,19 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	utils.Success(c, nil)
+	// 修改密码会注销所有会话，为当前客户端重新签发令牌，避免被立即登出
+	user, err := h.service.GetUserByID(userID.(uint))
+	if err != nil {
+		utils.NotFound(c, "用户不存在")
+		return
+	}
+	tokens, err := service.NewTokenService().StartSession(service.TokenScopeUser, user, clientInfo(c))
+	if err != nil {
+		authError(c, err)
+		return
+	}
+
+	utils.Success(c, tokens)
 }
 
 func (h *UserHandler) GetUserList(c *gin.Context) {
@@ -205,7 +255,8 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateUserStatus(uint(id), req.Status); err != nil {
+	actorID, _ := c.Get("user_id")
+	if err := h.service.UpdateUserStatus(actorID.(uint), c.GetString("role"), uint(id), req.Status); err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
@@ -222,15 +273,14 @@ func (h *UserHandler) UpdateUserRole(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Role string `json:"role" binding:"required,oneof=admin user"`
-	}
+	var req models.UserRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	if err := h.service.UpdateUserRole(uint(id), req.Role); err != nil {
+	actorID, _ := c.Get("user_id")
+	if err := h.service.UpdateUserRole(actorID.(uint), c.GetString("role"), uint(id), req.Role); err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
@@ -247,7 +297,8 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteUser(uint(id)); err != nil {
+	actorID, _ := c.Get("user_id")
+	if err := h.service.DeleteUser(actorID.(uint), c.GetString("role"), uint(id)); err != nil {
 		utils.Error(c, 400, err.Error())
 		return
 	}
@@ -273,3 +324,91 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 	// 只返回公开信息，不包含Email、Role、Status等敏感信息
 	utils.Success(c, user.ToPublicResponse())
 }
+
+// VerifyEmail 验证邮箱
+// POST /api/email/verify
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req models.EmailVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := service.NewAccountEmailService().VerifyEmail(req.Token)
+	if err != nil {
+		accountEmailError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "邮箱验证成功", user.ToResponse())
+}
+
+// ResendVerification 重新发送验证邮件
+// POST /api/profile/email/verification
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	user, err := h.service.GetUserByID(userID.(uint))
+	if err != nil {
+		utils.NotFound(c, "用户不存在")
+		return
+	}
+
+	if err := service.NewAccountEmailService().SendVerification(user); err != nil {
+		accountEmailError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "验证邮件已发送", nil)
+}
+
+// ForgotPassword 发送重置密码邮件
+// POST /api/password/forgot
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req models.PasswordForgotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := service.NewAccountEmailService().RequestPasswordReset(req.Email); err != nil {
+		accountEmailError(c, err)
+		return
+	}
+
+	// 无论邮箱是否注册都返回相同结果
+	utils.SuccessWithMessage(c, "如果该邮箱已注册，重置密码邮件将很快送达", nil)
+}
+
+// ResetPassword 通过邮件链接重置密码
+// POST /api/password/reset
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req models.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := service.NewAccountEmailService().ResetPassword(req.Token, req.Password); err != nil {
+		accountEmailError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "密码已重置，请使用新密码登录", nil)
+}
+
+func accountEmailError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrActionTokenInvalid), errors.Is(err, service.ErrEmailAlreadyVerified):
+		utils.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrMailTooFrequent):
+		utils.Error(c, 429, err.Error())
+	default:
+		zap.L().Error("account email request failed", zap.Error(err))
+		utils.InternalServerError(c, "服务暂时不可用")
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ViewHandler 浏览趋势
+type ViewHandler struct {
+	service *service.ViewService
+}
+
+func NewViewHandler() *ViewHandler {
+	return &ViewHandler{service: service.NewViewService()}
+}
+
+// ArticleTrend 文章最近若干天的浏览趋势
+// GET /api/articles/:id/views?days=30
+func (h *ViewHandler) ArticleTrend(c *gin.Context) {
+	h.trend(c, models.ViewTargetArticle)
+}
+
+// WorkTrend 作品最近若干天的浏览趋势
+// GET /api/works/:id/views?days=30
+func (h *ViewHandler) WorkTrend(c *gin.Context) {
+	h.trend(c, models.ViewTargetWork)
+}
+
+func (h *ViewHandler) trend(c *gin.Context, targetType string) {
+	id, ok := pathUint(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	days, _ := strconv.Atoi(c.Query("days"))
+	roleStr := "user"
+	if role, exists := c.Get("role"); exists && role != nil {
+		roleStr = role.(string)
+	}
+
+	trend, err := h.service.Trend(targetType, id, userID, roleStr, days)
+	if err != nil {
+		if errors.Is(err, service.ErrViewTargetNotFound) {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.Success(c, trend)
+}
+
+// recordView 记录一次浏览，返回是否计入浏览量；统计失败不影响详情页的响应
+func recordView(c *gin.Context, views *service.ViewService, targetType string, id uint) bool {
+	var userID uint
+	if value, exists := c.Get("user_id"); exists {
+		userID, _ = value.(uint)
+	}
+	counted, err := views.Record(targetType, id, userID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		log.Printf("记录 %s %d 浏览量失败: %v", targetType, id, err)
+		return false
+	}
+	return counted
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWXRFileSize WordPress 导出文件的大小上限
+const maxWXRFileSize = 100 << 20
+
+// WordPressImportHandler 导入 WordPress 站点（管理员）
+type WordPressImportHandler struct {
+	service *service.WordPressImportService
+}
+
+func NewWordPressImportHandler() *WordPressImportHandler {
+	return &WordPressImportHandler{
+		service: service.NewWordPressImportService(),
+	}
+}
+
+// Import 上传 WordPress 导出的 WXR 文件导入文章、评论、分类和标签；dry_run=true 时只返回将要创建的内容
+// POST /api/admin/import/wordpress (multipart: file, dry_run, author_map=wp登录名=本站用户名,...)
+func (h *WordPressImportHandler) Import(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请选择 WordPress 导出的 XML 文件")
+		return
+	}
+	if header.Size > maxWXRFileSize {
+		utils.BadRequest(c, fmt.Sprintf("文件大小不能超过 %d MB", maxWXRFileSize>>20))
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.PostForm("dry_run"))
+	authorMap, err := service.ParseWordPressAuthorMap(c.PostForm("author_map"))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.InternalServerError(c, "读取上传文件失败")
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(file, &models.WordPressImportOptions{DryRun: dryRun, AuthorMap: authorMap})
+	if err != nil {
+		if errors.Is(err, service.ErrWXRInvalid) || errors.Is(err, service.ErrWordPressAuthorNotFound) ||
+			errors.Is(err, service.ErrWordPressAuthorConflict) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+	utils.Success(c, report)
+}
@@ -16,12 +16,16 @@ import (
 )
 
 type WorkHandler struct {
-	service *service.WorkService
+	service     *service.WorkService
+	slugService *service.SlugService
+	viewService *service.ViewService
 }
 
 func NewWorkHandler() *WorkHandler {
 	return &WorkHandler{
-		service: service.NewWorkService(),
+		service:     service.NewWorkService(),
+		slugService: service.NewSlugService(),
+		viewService: service.NewViewService(),
 	}
 }
 
@@ -127,7 +131,26 @@ func (h *WorkHandler) GetDetail(c *gin.Context) {
 		return
 	}
 
-	work, err := h.service.GetByID(uint(id))
+	h.respondDetail(c, uint(id))
+}
+
+// GetBySlug 通过作者用户名和 slug 获取作品详情，旧 slug 301 跳转到当前地址
+// GET /api/works/slug/:username/:slug
+func (h *WorkHandler) GetBySlug(c *gin.Context) {
+	resolved, err := h.slugService.Resolve(models.SlugTargetWork, c.Param("username"), c.Param("slug"))
+	if err != nil {
+		slugError(c, err, "作品不存在")
+		return
+	}
+	if resolved.Redirect {
+		redirectToSlug(c, "/api/works/slug/", resolved)
+		return
+	}
+	h.respondDetail(c, resolved.TargetID)
+}
+
+func (h *WorkHandler) respondDetail(c *gin.Context, id uint) {
+	work, err := h.service.GetByID(id)
 	if err != nil {
 		utils.NotFound(c, "作品不存在")
 		return
@@ -142,13 +165,15 @@ func (h *WorkHandler) GetDetail(c *gin.Context) {
 	}
 
 	// 权限检查：
-	// - 草稿（status=0）：只有作者或管理员可以查看
-	// - 待审核（status=2）：只有作者或管理员可以查看
-	// - 审核不通过（status=3）：只有作者或管理员可以查看
+	// - 草稿（status=0）：只有作者或有作品管理/审核权限的用户可以查看
+	// - 待审核（status=2）：同上
+	// - 审核不通过（status=3）：同上
 	// - 已发布（status=1）：所有人可以查看
 	if work.Status != 1 {
-		// 如果不是管理员，且不是作者，则无权限查看
-		if roleStr != "admin" && (!userExists || userID.(uint) != work.AuthorID) {
+		// 既没有作品管理或审核权限，也不是作者，则无权限查看
+		roles := service.NewRoleService()
+		canReview := roles.HasPermission(roleStr, models.PermissionWorkManage) || roles.HasPermission(roleStr, models.PermissionWorkAudit)
+		if !canReview && (!userExists || userID.(uint) != work.AuthorID) {
 			utils.NotFound(c, "作品不存在")
 			return
 		}
@@ -158,9 +183,8 @@ func (h *WorkHandler) GetDetail(c *gin.Context) {
 	// 只有已发布的作品才增加浏览量
 	skipView := c.Query("skip_view") == "true"
 	if !skipView && work.Status == 1 {
-		// 同步增加浏览量，确保返回的数据包含最新的浏览量
-		if err := h.service.IncrementViewCount(uint(id)); err == nil {
-			// 更新返回数据中的浏览量（+1）
+		// 计入浏览量时同步更新返回数据，重复访问不计数
+		if recordView(c, h.viewService, models.ViewTargetWork, id) {
 			work.ViewCount++
 		}
 	}
@@ -190,12 +214,11 @@ func (h *WorkHandler) GetEdit(c *gin.Context) {
 		roleStr = role.(string)
 	}
 
-	// 查询作品，但使用WHERE条件确保权限（非管理员只能查询自己的作品）
+	// 查询作品，但使用WHERE条件确保权限（没有作品管理权限时只能查询自己的作品）
 	var work models.Work
 	query := database.DB.Where("id = ?", uint(id))
 
-	// 非管理员只能查询自己的作品
-	if roleStr != "admin" {
+	if !service.NewRoleService().HasPermission(roleStr, models.PermissionWorkManage) {
 		query = query.Where("author_id = ?", userID.(uint))
 	}
 
@@ -3,6 +3,8 @@ package middleware
 import (
 	"strings"
 
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
 	"github.com/iceymoss/inkspace/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -35,8 +37,16 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 验证是否是管理员
-		if claims.Role != "admin" {
+		// 校验令牌是否已被吊销
+		if err := service.NewTokenService().Validate(claims); err != nil {
+			utils.Error(c, 401, "登录已失效，请重新登录")
+			c.Abort()
+			return
+		}
+		touchSession(c, claims)
+
+		// 验证是否可以访问管理后台，具体操作再由 RequirePermission 校验
+		if !service.NewRoleService().HasPermission(claims.Role, models.PermissionAdminAccess) {
 			utils.Error(c, 403, "需要管理员权限")
 			c.Abort()
 			return
@@ -46,7 +56,24 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("token_claims", claims)
+
+		c.Next()
+	}
+}
 
+// RequirePermission 校验当前角色是否拥有全部指定权限，需放在 AdminAuthMiddleware 之后
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles := service.NewRoleService()
+		role := c.GetString("role")
+		for _, permission := range permissions {
+			if !roles.HasPermission(role, permission) {
+				utils.Error(c, 403, "没有权限: "+permission)
+				c.Abort()
+				return
+			}
+		}
 		c.Next()
 	}
 }
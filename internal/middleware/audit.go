@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditBodyLimit 只解析响应开头部分，足够读取 code 和 data.id
+const auditBodyLimit = 64 << 10
+
+// Audit 记录管理后台的每个写操作：操作人、动作、对象、变更前后差异、IP 和请求 ID。
+// 需放在 AdminAuthMiddleware 之后；对象 ID 取自路由参数，新建时取自响应中的 data.id。
+// 处理函数可以通过上下文的 audit_target_type / audit_target_id 覆盖对象，
+// 通过 audit_before / audit_after 提供路由无法推导的变更（如批量修改配置）。
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		audit := service.NewAuditService()
+		action, targetType := auditAction(c.Request.Method, c.FullPath())
+		targetID := ""
+		if len(c.Params) > 0 {
+			targetID = c.Params[0].Value
+		}
+		before := audit.Snapshot(targetType, targetID)
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		var response struct {
+			Code int `json:"code"`
+			Data struct {
+				ID json.Number `json:"id"`
+			} `json:"data"`
+		}
+		status := 0
+		if err := json.Unmarshal(writer.body.Bytes(), &response); err == nil {
+			status = response.Code
+		} else if c.Writer.Status() >= http.StatusBadRequest {
+			status = c.Writer.Status()
+		}
+		if targetID == "" && status == 0 {
+			targetID = response.Data.ID.String()
+		}
+
+		if value, ok := c.Get("audit_target_type"); ok {
+			targetType = value.(string)
+		}
+		if value, ok := c.Get("audit_target_id"); ok {
+			targetID = value.(string)
+		}
+		var after interface{}
+		if value, ok := c.Get("audit_before"); ok {
+			before = value
+			after, _ = c.Get("audit_after")
+		} else if status == 0 {
+			after = audit.Snapshot(targetType, targetID)
+		} else {
+			after = before // 操作失败，没有变更
+		}
+
+		entry := &models.AuditLog{
+			ActorName:  c.GetString("username"),
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   targetID,
+			IP:         c.ClientIP(),
+			RequestID:  c.GetString("request_id"),
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Status:     status,
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			id := userID.(uint)
+			entry.ActorID = &id
+		}
+		audit.RecordChange(entry, before, after)
+	}
+}
+
+// auditAction 由路由推导动作和对象类型：
+// POST /api/admin/roles -> roles.create，PUT /api/admin/users/:id/status -> users.status.update，
+// DELETE /api/admin/tags/:id -> tags.delete，POST /api/admin/login-locks/unlock -> login-locks.unlock
+func auditAction(method, fullPath string) (action, targetType string) {
+	path := strings.TrimPrefix(fullPath, "/api/")
+	path = strings.TrimPrefix(path, "admin/")
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return strings.ToLower(method), ""
+	}
+
+	action = strings.Join(segments, ".")
+	switch method {
+	case http.MethodPost:
+		if len(segments) == 1 {
+			action += ".create"
+		}
+	case http.MethodPut, http.MethodPatch:
+		action += ".update"
+	case http.MethodDelete:
+		action += ".delete"
+	}
+	return action, segments[0]
+}
+
+// auditResponseWriter 在写出响应的同时保留开头部分用于解析结果
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if remaining := auditBodyLimit - w.body.Len(); remaining > 0 {
+		w.body.Write(data[:min(len(data), remaining)])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	if remaining := auditBodyLimit - w.body.Len(); remaining > 0 {
+		w.body.WriteString(s[:min(len(s), remaining)])
+	}
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"log"
+	"net/http"
 	"strings"
 
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/service"
 	"github.com/iceymoss/inkspace/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +26,17 @@ func AuthMiddleware() gin.HandlerFunc {
 			token = strings.TrimPrefix(token, "Bearer ")
 		}
 
+		// 个人访问令牌
+		if strings.HasPrefix(token, service.PersonalAccessTokenPrefix) {
+			if !setAccessToken(c, token) {
+				utils.Unauthorized(c, "访问令牌无效或已过期")
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
 		claims, err := utils.ParseToken(token)
 		if err != nil {
 			utils.Unauthorized(c, "Token无效")
@@ -29,17 +44,25 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 校验令牌是否已被吊销（退出登录、修改密码、封禁、角色变更）
+		if err := service.NewTokenService().Validate(claims); err != nil {
+			utils.Unauthorized(c, "登录已失效，请重新登录")
+			c.Abort()
+			return
+		}
+		touchSession(c, claims)
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("token_claims", claims)
 		c.Next()
 	}
 }
 
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
+		if !service.NewRoleService().HasPermission(c.GetString("role"), models.PermissionAdminAccess) {
 			utils.Forbidden(c, "需要管理员权限")
 			c.Abort()
 			return
@@ -49,8 +72,8 @@ func AdminMiddleware() gin.HandlerFunc {
 }
 
 // OptionalAuthMiddleware 可选认证中间件
-// 如果有token就解析，没有token也允许通过
-func OptionalAuthMiddleware() gin.HandlerFunc {
+// 如果有token就解析，没有token也允许通过；个人访问令牌需要拥有 scope，否则按未登录处理
+func OptionalAuthMiddleware(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
 		if token == "" {
@@ -64,6 +87,15 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 			token = strings.TrimPrefix(token, "Bearer ")
 		}
 
+		// 个人访问令牌无效或缺少权限范围时同样按未登录处理
+		if strings.HasPrefix(token, service.PersonalAccessTokenPrefix) {
+			if accessToken, user, err := service.NewAccessTokenService().Authenticate(token, c.ClientIP()); err == nil {
+				setOptionalAccessToken(c, accessToken, user, scope)
+			}
+			c.Next()
+			return
+		}
+
 		claims, err := utils.ParseToken(token)
 		if err != nil {
 			// Token无效，继续处理，但不设置user_id
@@ -71,10 +103,106 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Token已被吊销，按未登录处理
+		if err := service.NewTokenService().Validate(claims); err != nil {
+			c.Next()
+			return
+		}
+
 		// Token有效，设置用户信息
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("token_claims", claims)
+		c.Next()
+	}
+}
+
+// setAccessToken 校验个人访问令牌并写入用户信息
+func setAccessToken(c *gin.Context, raw string) bool {
+	token, user, err := service.NewAccessTokenService().Authenticate(raw, c.ClientIP())
+	if err != nil {
+		return false
+	}
+	setTokenUser(c, token, user)
+	return true
+}
+
+// setOptionalAccessToken 令牌拥有 scope 时才写入用户信息，
+// 避免只有上传等权限的令牌借可选认证读取作者的草稿和私有内容
+func setOptionalAccessToken(c *gin.Context, token *models.PersonalAccessToken, user *models.User, scope string) bool {
+	if !token.HasScope(scope) {
+		return false
+	}
+	setTokenUser(c, token, user)
+	return true
+}
+
+func setTokenUser(c *gin.Context, token *models.PersonalAccessToken, user *models.User) {
+	c.Set("user_id", user.ID)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	c.Set("access_token", token)
+}
+
+// RequireScope 使用个人访问令牌时校验权限范围；登录会话不受限制
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, ok := accessToken(c); ok && !token.HasScope(scope) {
+			utils.Forbidden(c, "访问令牌缺少权限: "+scope)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireResourceScope 按请求方法校验资源权限：GET/HEAD 需要 resource:read，其余需要 resource:write
+func RequireResourceScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = resource + ":read"
+		}
+		if token, ok := accessToken(c); ok && !token.HasScope(scope) {
+			utils.Forbidden(c, "访问令牌缺少权限: "+scope)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SessionOnly 账号安全相关接口只允许登录会话访问，个人访问令牌一律拒绝
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := accessToken(c); ok {
+			utils.Forbidden(c, "个人访问令牌无权访问该接口")
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+func accessToken(c *gin.Context) (*models.PersonalAccessToken, bool) {
+	value, exists := c.Get("access_token")
+	if !exists {
+		return nil, false
+	}
+	token, ok := value.(*models.PersonalAccessToken)
+	return token, ok
+}
+
+// touchSession 异步刷新会话的最近活跃时间，不阻塞请求
+func touchSession(c *gin.Context, claims *utils.Claims) {
+	sessionID, ip := claims.SessionID, c.ClientIP()
+	if sessionID == "" {
+		return
+	}
+	go func() {
+		if err := service.NewSessionService().Touch(sessionID, ip); err != nil {
+			log.Printf("更新会话活跃时间失败: %s, 错误: %v", sessionID, err)
+		}
+	}()
+}
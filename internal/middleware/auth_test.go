@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestOptionalAccessTokenRequiresReadScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := &models.User{ID: 7, Username: "author", Role: models.RoleUser}
+	// 作者 7 的私有文章：只有识别为作者本人时才返回
+	privateArticle := func(c *gin.Context) {
+		if userID, ok := c.Get("user_id"); ok && userID.(uint) == user.ID {
+			c.Status(http.StatusOK)
+			return
+		}
+		c.Status(http.StatusNotFound)
+	}
+
+	tests := []struct {
+		scopes string
+		want   int
+	}{
+		{models.ScopeUpload, http.StatusNotFound},
+		{models.ScopeWorksRead, http.StatusNotFound},
+		{models.ScopeArticlesRead, http.StatusOK},
+		{models.ScopeArticlesWrite, http.StatusOK},
+	}
+	for _, tt := range tests {
+		token := &models.PersonalAccessToken{UserID: user.ID, Scopes: tt.scopes}
+		router := gin.New()
+		router.GET("/api/articles/:id", func(c *gin.Context) {
+			setOptionalAccessToken(c, token, user, models.ScopeArticlesRead)
+		}, privateArticle)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/articles/1", nil))
+
+		if recorder.Code != tt.want {
+			t.Errorf("scopes %q: status = %d, want %d", tt.scopes, recorder.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// 数据导出任务状态
+const (
+	AccountExportPending    = "pending"
+	AccountExportProcessing = "processing"
+	AccountExportReady      = "ready"
+	AccountExportFailed     = "failed"
+)
+
+// 注销账号时对已发表评论的处理方式
+const (
+	CommentModeAnonymize = "anonymize" // 保留评论内容，去除作者信息
+	CommentModeDelete    = "delete"    // 删除评论；已有回复的评论清空内容以保留讨论结构
+)
+
+// AccountExport 个人数据导出任务，压缩包保存在服务器本地，过期后删除
+type AccountExport struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	UserID      uint       `gorm:"index;not null" json:"user_id"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	FilePath    string     `gorm:"size:255" json:"-"`
+	FileSize    int64      `gorm:"default:0" json:"file_size"`
+	Error       string     `gorm:"size:255" json:"error"`
+	CompletedAt *time.Time `gorm:"type:datetime(3)" json:"completed_at"`
+	ExpiresAt   *time.Time `gorm:"type:datetime(3);index" json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AccountDeletion 账号注销申请，冷静期结束后由定时任务执行；冷静期内可撤销
+type AccountDeletion struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	CommentMode string    `gorm:"size:20;not null" json:"comment_mode"`
+	ScheduledAt time.Time `gorm:"type:datetime(3);index" json:"scheduled_at"` // 到期执行时间
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AccountDeletionRequest 申请注销；设置过密码的账号需要密码，仅第三方登录的账号输入用户名确认
+type AccountDeletionRequest struct {
+	Password    string `json:"password"`
+	Confirm     string `json:"confirm"`
+	CommentMode string `json:"comment_mode" binding:"required,oneof=anonymize delete"`
+}
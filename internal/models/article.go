@@ -1,19 +1,30 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// 文章状态
+const (
+	ArticleStatusDraft     = 0 // 草稿
+	ArticleStatusPublished = 1 // 已发布
+	ArticleStatusPrivate   = 2 // 私有
+	ArticleStatusScheduled = 3 // 定时发布（到达 PublishAt 后由调度器发布）
+)
+
 type Article struct {
 	ID            uint           `gorm:"primarykey" json:"id"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 	Title         string         `gorm:"size:200;not null;index:idx_title" json:"title" binding:"required"`
+	Slug          string         `gorm:"size:200" json:"slug"` // 同一作者下唯一，唯一索引在迁移时回填后创建
 	Content       string         `gorm:"type:longtext;not null" json:"content" binding:"required"`
 	ContentHTML   string         `gorm:"type:longtext" json:"content_html"`
+	ContentTOC    string         `gorm:"type:text" json:"-"` // 目录 JSON，与 ContentHTML 一起由 Content 渲染生成
 	Summary       string         `gorm:"size:500" json:"summary"`
 	Cover         string         `gorm:"size:255" json:"cover"`
 	CategoryID    uint           `gorm:"index:idx_category_id" json:"category_id"`
@@ -27,16 +38,17 @@ type Article struct {
 	FavoriteCount int            `gorm:"default:0;not null" json:"favorite_count"` // 收藏数
 	WordCount     int            `gorm:"default:0;not null" json:"word_count"`
 	ReadingTime   int            `gorm:"default:0;not null" json:"reading_time"`                                // 阅读时间（分钟）
-	Status        int            `gorm:"default:1;index:idx_status;index:idx_top_status_created" json:"status"` // 0: draft, 1: published, 2: private
+	Status        int            `gorm:"default:1;index:idx_status;index:idx_top_status_created" json:"status"` // 0: draft, 1: published, 2: private, 3: scheduled
 	IsTop         bool           `gorm:"default:false;index:idx_top_status_created" json:"is_top"`
 	IsRecommend   bool           `gorm:"default:false" json:"is_recommend"`
 	IsOriginal    bool           `gorm:"default:true" json:"is_original"`
 	SourceURL     string         `gorm:"size:255" json:"source_url"`
-	PublishAt     *time.Time     `gorm:"type:datetime(3)" json:"publish_at"`
+	PublishAt     *time.Time     `gorm:"type:datetime(3);index:idx_publish_at" json:"publish_at"`
 }
 
 type ArticleRequest struct {
 	Title       string `json:"title" binding:"required,max=200"`
+	Slug        string `json:"slug" binding:"max=200"` // 为空时创建根据标题生成，更新时保持不变
 	Content     string `json:"content" binding:"required"`
 	Summary     string `json:"summary" binding:"max=500"`
 	Cover       string `json:"cover"`
@@ -45,6 +57,8 @@ type ArticleRequest struct {
 	Status      int    `json:"status"`
 	IsTop       bool   `json:"is_top"`
 	IsRecommend bool   `json:"is_recommend"`
+	// PublishAt 定时发布时间，status=3 时必填且须晚于当前时间
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 type ArticleListQuery struct {
@@ -62,36 +76,43 @@ type ArticleListQuery struct {
 }
 
 type ArticleResponse struct {
-	ID            uint              `json:"id"`
-	Title         string            `json:"title"`
-	Content       string            `json:"content"`
-	Summary       string            `json:"summary"`
-	Cover         string            `json:"cover"`
-	CategoryID    uint              `json:"category_id"`
-	Category      *CategoryResponse `json:"category,omitempty"`
-	Tags          []TagResponse     `json:"tags,omitempty"`
-	AuthorID      uint              `json:"author_id"`
-	Author        *UserResponse     `json:"author,omitempty"`
-	ViewCount     int               `json:"view_count"`
-	LikeCount     int               `json:"like_count"`
-	CommentCount  int               `json:"comment_count"`
-	FavoriteCount int               `json:"favorite_count"`
-	WordCount     int               `json:"word_count"`
-	ReadingTime   int               `json:"reading_time"`
-	Status        int               `json:"status"`
-	IsTop         bool              `json:"is_top"`
-	IsRecommend   bool              `json:"is_recommend"`
-	IsOriginal    bool              `json:"is_original"`
-	SourceURL     string            `json:"source_url"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
+	ID            uint               `json:"id"`
+	Title         string             `json:"title"`
+	Slug          string             `json:"slug"`
+	Content       string             `json:"content"`
+	ContentHTML   string             `json:"content_html,omitempty"`
+	TOC           json.RawMessage    `json:"toc,omitempty"`
+	Summary       string             `json:"summary"`
+	Cover         string             `json:"cover"`
+	CategoryID    uint               `json:"category_id"`
+	Category      *CategoryResponse  `json:"category,omitempty"`
+	Tags          []TagResponse      `json:"tags,omitempty"`
+	AuthorID      uint               `json:"author_id"`
+	Author        *UserResponse      `json:"author,omitempty"`
+	Series        *ArticleSeriesInfo `json:"series,omitempty"`
+	ViewCount     int                `json:"view_count"`
+	LikeCount     int                `json:"like_count"`
+	CommentCount  int                `json:"comment_count"`
+	FavoriteCount int                `json:"favorite_count"`
+	WordCount     int                `json:"word_count"`
+	ReadingTime   int                `json:"reading_time"`
+	Status        int                `json:"status"`
+	IsTop         bool               `json:"is_top"`
+	IsRecommend   bool               `json:"is_recommend"`
+	IsOriginal    bool               `json:"is_original"`
+	SourceURL     string             `json:"source_url"`
+	PublishAt     *time.Time         `json:"publish_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
 }
 
 func (a *Article) ToResponse() *ArticleResponse {
 	resp := &ArticleResponse{
 		ID:            a.ID,
 		Title:         a.Title,
+		Slug:          a.Slug,
 		Content:       a.Content,
+		ContentHTML:   a.ContentHTML,
 		Summary:       a.Summary,
 		Cover:         a.Cover,
 		CategoryID:    a.CategoryID,
@@ -107,10 +128,15 @@ func (a *Article) ToResponse() *ArticleResponse {
 		IsRecommend:   a.IsRecommend,
 		IsOriginal:    a.IsOriginal,
 		SourceURL:     a.SourceURL,
+		PublishAt:     a.PublishAt,
 		CreatedAt:     a.CreatedAt,
 		UpdatedAt:     a.UpdatedAt,
 	}
 
+	if a.ContentTOC != "" {
+		resp.TOC = json.RawMessage(a.ContentTOC)
+	}
+
 	if a.Category != nil {
 		resp.Category = a.Category.ToResponse()
 	}
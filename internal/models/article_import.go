@@ -0,0 +1,26 @@
+package models
+
+// ArticleImportItem 压缩包中一个 Markdown 文件的导入结果
+type ArticleImportItem struct {
+	File      string `json:"file"`
+	ArticleID uint   `json:"article_id,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Status    string `json:"status"` // imported、skipped、failed
+	Message   string `json:"message,omitempty"`
+	Images    int    `json:"images,omitempty"` // 改写为上传地址的图片数
+}
+
+// ArticleImportResult Markdown 批量导入结果
+type ArticleImportResult struct {
+	Imported int                  `json:"imported"`
+	Skipped  int                  `json:"skipped"`
+	Failed   int                  `json:"failed"`
+	Items    []*ArticleImportItem `json:"items"`
+}
+
+// 单个文件的导入状态
+const (
+	ArticleImportImported = "imported"
+	ArticleImportSkipped  = "skipped"
+	ArticleImportFailed   = "failed"
+)
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// ArticleRevision 文章修订版本，每次修改文章内容时保存一份快照
+type ArticleRevision struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ArticleID  uint      `gorm:"not null;uniqueIndex:idx_article_revision" json:"article_id"`
+	Version    int       `gorm:"not null;uniqueIndex:idx_article_revision" json:"version"`
+	Title      string    `gorm:"size:200" json:"title"`
+	Summary    string    `gorm:"size:500" json:"summary"`
+	Cover      string    `gorm:"size:255" json:"cover"`
+	Content    string    `gorm:"type:longtext" json:"content"`
+	EditorID   *uint     `gorm:"index" json:"editor_id"`     // 编辑者，账号注销后为空
+	EditorName string    `gorm:"size:50" json:"editor_name"` // 编辑时的昵称快照
+	Remark     string    `gorm:"size:100" json:"remark"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ArticleRevisionResponse struct {
+	Version    int       `json:"version"`
+	Title      string    `json:"title"`
+	Summary    string    `json:"summary"`
+	Cover      string    `json:"cover"`
+	Content    string    `json:"content,omitempty"`
+	EditorID   *uint     `json:"editor_id"`
+	EditorName string    `json:"editor_name"`
+	Remark     string    `json:"remark"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r *ArticleRevision) ToResponse() *ArticleRevisionResponse {
+	return &ArticleRevisionResponse{
+		Version: r.Version, Title: r.Title, Summary: r.Summary, Cover: r.Cover, Content: r.Content,
+		EditorID: r.EditorID, EditorName: r.EditorName, Remark: r.Remark, CreatedAt: r.CreatedAt,
+	}
+}
+
+// ArticleDiffSegment 差异片段，Type 取值 equal/insert/delete
+type ArticleDiffSegment struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ArticleDiffLine 行级差异，OldLine/NewLine 为行号，新增行没有 OldLine，删除行没有 NewLine
+type ArticleDiffLine struct {
+	Type    string `json:"type"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+	Text    string `json:"text"`
+}
+
+// ArticleRevisionDiff 两个版本之间的差异，Mode 为 line 时返回 Lines，为 word 时返回 Segments
+type ArticleRevisionDiff struct {
+	From     int                  `json:"from"`
+	To       int                  `json:"to"`
+	Mode     string               `json:"mode"`
+	Title    []ArticleDiffSegment `json:"title"`
+	Summary  []ArticleDiffSegment `json:"summary"`
+	Lines    []ArticleDiffLine    `json:"lines,omitempty"`
+	Segments []ArticleDiffSegment `json:"segments,omitempty"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计动作；管理后台请求的动作由路由推导，如 users.status.update、settings.batch.update
+const (
+	AuditActionLoginLockout = "auth.lockout" // 登录失败次数过多被临时锁定
+)
+
+// AuditLog 审计日志，只追加不修改；系统触发的记录 ActorID 为空
+type AuditLog struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ActorID    *uint     `gorm:"index" json:"actor_id"`
+	ActorName  string    `gorm:"size:50" json:"actor_name"`
+	Action     string    `gorm:"size:64;not null;index" json:"action"`
+	TargetType string    `gorm:"size:32;index:idx_audit_logs_target" json:"target_type"`
+	TargetID   string    `gorm:"size:100;index:idx_audit_logs_target" json:"target_id"`
+	IP         string    `gorm:"size:50" json:"ip"`
+	RequestID  string    `gorm:"size:64;index" json:"request_id"`
+	Method     string    `gorm:"size:10" json:"method"`
+	Path       string    `gorm:"size:255" json:"path"`
+	Status     int       `gorm:"index" json:"status"`            // 响应业务码，0 为成功
+	Changes    string    `gorm:"type:mediumtext" json:"changes"` // JSON: {"字段": {"before": 旧值, "after": 新值}}
+	Detail     string    `gorm:"type:text" json:"detail"`        // JSON
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// AuditChange 单个字段的变更
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditLogResponse struct {
+	ID         uint                   `json:"id"`
+	ActorID    *uint                  `json:"actor_id"`
+	ActorName  string                 `json:"actor_name"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"`
+	TargetID   string                 `json:"target_id"`
+	IP         string                 `json:"ip"`
+	RequestID  string                 `json:"request_id"`
+	Method     string                 `json:"method"`
+	Path       string                 `json:"path"`
+	Status     int                    `json:"status"`
+	Changes    map[string]AuditChange `json:"changes,omitempty"`
+	Detail     json.RawMessage        `json:"detail,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+func (l *AuditLog) ToResponse() *AuditLogResponse {
+	resp := &AuditLogResponse{
+		ID:         l.ID,
+		ActorID:    l.ActorID,
+		ActorName:  l.ActorName,
+		Action:     l.Action,
+		TargetType: l.TargetType,
+		TargetID:   l.TargetID,
+		IP:         l.IP,
+		RequestID:  l.RequestID,
+		Method:     l.Method,
+		Path:       l.Path,
+		Status:     l.Status,
+		CreatedAt:  l.CreatedAt,
+	}
+	if l.Changes != "" {
+		_ = json.Unmarshal([]byte(l.Changes), &resp.Changes)
+	}
+	if l.Detail != "" && json.Valid([]byte(l.Detail)) {
+		resp.Detail = json.RawMessage(l.Detail)
+	}
+	return resp
+}
+
+// AuditLogQuery 审计日志查询参数
+type AuditLogQuery struct {
+	Page       int    `form:"page,default=1"`
+	PageSize   int    `form:"page_size,default=20"`
+	ActorID    *uint  `form:"actor_id"`
+	Actor      string `form:"actor"`       // 操作人用户名，模糊匹配
+	Action     string `form:"action"`      // 前缀匹配，如 users 匹配 users.*
+	TargetType string `form:"target_type"` // 对象类型，如 users、works、settings
+	TargetID   string `form:"target_id"`
+	RequestID  string `form:"request_id"`
+	Result     string `form:"result"`     // success, failure
+	StartDate  string `form:"start_date"` // 2006-01-02
+	EndDate    string `form:"end_date"`   // 2006-01-02，包含当天
+}
+
+// CaptchaInfo 前端渲染人机验证组件所需的信息
+type CaptchaInfo struct {
+	Provider string `json:"provider"` // turnstile, hcaptcha, recaptcha
+	SiteKey  string `json:"site_key"`
+}
+
+// LoginLock 登录退避或锁定状态
+type LoginLock struct {
+	Type      string    `json:"type"` // user, ip
+	Value     string    `json:"value"`
+	Reason    string    `json:"reason"` // backoff, lockout
+	Failures  int64     `json:"failures"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type LoginUnlockRequest struct {
+	Type  string `json:"type" binding:"required,oneof=user ip"`
+	Value string `json:"value" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// TokenPair 登录/刷新后下发的令牌对
+type TokenPair struct {
+	Token        string `json:"token"`         // 短期访问令牌
+	RefreshToken string `json:"refresh_token"` // 一次性刷新令牌，使用后轮换
+	ExpiresIn    int64  `json:"expires_in"`    // 访问令牌剩余有效期（秒）
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
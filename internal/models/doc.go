@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -20,6 +21,7 @@ type Doc struct {
 	Title       string         `gorm:"size:200;not null" json:"title"`
 	Content     string         `gorm:"type:longtext" json:"content"`
 	ContentHTML string         `gorm:"type:longtext" json:"content_html"`
+	ContentTOC  string         `gorm:"type:text" json:"-"` // 目录 JSON，发布时与 ContentHTML 一起生成
 	Status      int            `gorm:"index;default:0;not null" json:"status"`
 	WordCount   int            `gorm:"default:0" json:"word_count"`
 	ViewCount   int            `gorm:"default:0" json:"view_count"`
@@ -68,21 +70,22 @@ type DocListQuery struct {
 }
 
 type DocResponse struct {
-	ID          uint       `json:"id"`
-	WorkspaceID uint       `json:"workspace_id"`
-	CatalogID   *uint      `json:"catalog_id"`
-	ArticleID   *uint      `json:"article_id"`
-	Title       string     `json:"title"`
-	Summary     string     `json:"summary,omitempty"`
-	Content     string     `json:"content"`
-	ContentHTML string     `json:"content_html"`
-	Status      int        `json:"status"`
-	WordCount   int        `json:"word_count"`
-	ViewCount   int        `json:"view_count"`
-	Sort        int        `json:"sort"`
-	PublishedAt *time.Time `json:"published_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uint            `json:"id"`
+	WorkspaceID uint            `json:"workspace_id"`
+	CatalogID   *uint           `json:"catalog_id"`
+	ArticleID   *uint           `json:"article_id"`
+	Title       string          `json:"title"`
+	Summary     string          `json:"summary,omitempty"`
+	Content     string          `json:"content"`
+	ContentHTML string          `json:"content_html"`
+	TOC         json.RawMessage `json:"toc,omitempty"`
+	Status      int             `json:"status"`
+	WordCount   int             `json:"word_count"`
+	ViewCount   int             `json:"view_count"`
+	Sort        int             `json:"sort"`
+	PublishedAt *time.Time      `json:"published_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
 }
 
 type DocSearchResponse struct {
@@ -97,10 +100,14 @@ type DocSearchResponse struct {
 }
 
 func (d *Doc) ToResponse() *DocResponse {
-	return &DocResponse{
+	resp := &DocResponse{
 		ID: d.ID, WorkspaceID: d.WorkspaceID, CatalogID: d.CatalogID, ArticleID: d.ArticleID, Title: d.Title,
 		Content: d.Content, ContentHTML: d.ContentHTML, Status: d.Status, WordCount: d.WordCount,
 		ViewCount: d.ViewCount, Sort: d.Sort, PublishedAt: d.PublishedAt,
 		CreatedAt: d.CreatedAt, UpdatedAt: d.UpdatedAt,
 	}
+	if d.ContentTOC != "" {
+		resp.TOC = json.RawMessage(d.ContentTOC)
+	}
+	return resp
 }
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// 导入记录的来源
+const (
+	ImportSourceWordPress = "wordpress"
+)
+
+// 导入记录的内容类型
+const (
+	ImportTypeAuthor  = "author"
+	ImportTypePost    = "post"
+	ImportTypeComment = "comment"
+)
+
+// ImportRecord 外部站点的内容与本站记录的对应关系，重复导入同一站点时据此跳过已导入的内容
+type ImportRecord struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	Source     string    `gorm:"size:20;not null;uniqueIndex:idx_import_record,priority:1" json:"source"`
+	Site       string    `gorm:"size:191;not null;uniqueIndex:idx_import_record,priority:2" json:"site"` // 来源站点地址
+	SourceType string    `gorm:"size:20;not null;uniqueIndex:idx_import_record,priority:3" json:"source_type"`
+	SourceID   string    `gorm:"size:64;not null;uniqueIndex:idx_import_record,priority:4" json:"source_id"`
+	TargetID   uint      `gorm:"not null;index" json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WordPressImportOptions WordPress 导入选项
+type WordPressImportOptions struct {
+	DryRun    bool              // 只统计将要创建的内容，不写入数据库
+	AuthorMap map[string]string // WordPress 登录名 -> 本站用户名，未指定的按导入记录匹配或创建占位账号
+}
+
+// WordPressImportCount 某类内容的导入统计；试运行时 Created 为将要创建的数量
+type WordPressImportCount struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"` // 之前导入过或本站已有同名记录
+	Skipped  int `json:"skipped"`  // 回收站、垃圾评论、pingback 等不导入的内容
+}
+
+// WordPressAuthorMapping WordPress 作者对应的本站用户
+type WordPressAuthorMapping struct {
+	Login       string `json:"login"`
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	Placeholder bool   `json:"placeholder"`        // 本次新建的占位账号，可通过找回密码设置密码
+	Conflict    string `json:"conflict,omitempty"` // 邮箱或用户名相同的本站用户，试运行时报告，需在作者映射中指定
+}
+
+// WordPressImportReport WordPress 导入结果
+type WordPressImportReport struct {
+	DryRun     bool                      `json:"dry_run"`
+	Site       string                    `json:"site"`
+	Authors    []*WordPressAuthorMapping `json:"authors"`
+	Users      WordPressImportCount      `json:"users"`
+	Categories WordPressImportCount      `json:"categories"`
+	Tags       WordPressImportCount      `json:"tags"`
+	Articles   WordPressImportCount      `json:"articles"`
+	Comments   WordPressImportCount      `json:"comments"`
+	Warnings   []string                  `json:"warnings,omitempty"`
+}
@@ -15,7 +15,7 @@ type Notification struct {
 
 	UserID     uint   `gorm:"not null;index" json:"user_id"`             // 接收通知的用户
 	FromUserID *uint  `gorm:"index" json:"from_user_id,omitempty"`       // 触发通知的用户（NULL表示系统通知）
-	Type       string `gorm:"type:varchar(50);not null" json:"type"`     // comment/like/favorite/follow/reply
+	Type       string `gorm:"type:varchar(50);not null" json:"type"`     // comment/like/favorite/follow/reply/article_publish
 	Content    string `gorm:"type:text" json:"content"`                  // 通知内容
 	ArticleID  *uint  `gorm:"index" json:"article_id,omitempty"`         // 相关文章ID
 	WorkID     *uint  `gorm:"index" json:"work_id,omitempty"`            // 相关作品ID
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 个人访问令牌权限范围：resource:read / resource:write，write 包含 read
+const (
+	ScopeProfileRead        = "profile:read"
+	ScopeProfileWrite       = "profile:write"
+	ScopeArticlesRead       = "articles:read"
+	ScopeArticlesWrite      = "articles:write"
+	ScopeWorksRead          = "works:read"
+	ScopeWorksWrite         = "works:write"
+	ScopeCommentsWrite      = "comments:write"
+	ScopeSocialRead         = "social:read"
+	ScopeSocialWrite        = "social:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	ScopeDocsRead           = "docs:read"
+	ScopeDocsWrite          = "docs:write"
+	ScopeUpload             = "upload"
+)
+
+// AccessTokenScope 可选权限范围及说明，供前端展示
+type AccessTokenScope struct {
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+}
+
+var AccessTokenScopes = []AccessTokenScope{
+	{ScopeProfileRead, "读取个人资料"},
+	{ScopeProfileWrite, "修改个人资料和外观设置"},
+	{ScopeArticlesRead, "读取自己的文章（含草稿）"},
+	{ScopeArticlesWrite, "创建、修改、删除文章和标签"},
+	{ScopeWorksRead, "读取自己的作品和配额"},
+	{ScopeWorksWrite, "创建、修改、删除作品"},
+	{ScopeCommentsWrite, "发表和删除评论"},
+	{ScopeSocialRead, "读取关注、收藏列表"},
+	{ScopeSocialWrite, "点赞、关注、收藏"},
+	{ScopeNotificationsRead, "读取通知"},
+	{ScopeNotificationsWrite, "标记和删除通知"},
+	{ScopeDocsRead, "读取知识库文档"},
+	{ScopeDocsWrite, "编辑知识库文档和分享链接"},
+	{ScopeUpload, "上传图片和附件"},
+}
+
+// ValidAccessTokenScope 是否为已定义的权限范围
+func ValidAccessTokenScope(scope string) bool {
+	for _, item := range AccessTokenScopes {
+		if item.Scope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken 个人访问令牌，用于脚本和 CI 调用 API；只保存令牌摘要
+type PersonalAccessToken struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	Name       string     `gorm:"size:50;not null" json:"name"`
+	TokenHash  string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"` // 令牌前几位，便于用户辨认
+	Scopes     string     `gorm:"size:500;not null" json:"-"`     // 逗号分隔
+	ExpiresAt  *time.Time `gorm:"type:datetime(3)" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"type:datetime(3)" json:"last_used_at"`
+	LastUsedIP string     `gorm:"size:50" json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (t *PersonalAccessToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+// HasScope 判断令牌是否拥有指定权限，resource:write 同时授予 resource:read
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, granted := range t.ScopeList() {
+		if granted == scope {
+			return true
+		}
+		if resource, ok := strings.CutSuffix(scope, ":read"); ok && granted == resource+":write" {
+			return true
+		}
+	}
+	return false
+}
+
+type PersonalAccessTokenCreateRequest struct {
+	Name          string   `json:"name" binding:"required,max=50"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"min=0,max=365"` // 0 表示永不过期
+}
+
+type PersonalAccessTokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Token      string     `json:"token,omitempty"` // 仅创建时返回一次明文
+}
+
+func (t *PersonalAccessToken) ToResponse() *PersonalAccessTokenResponse {
+	return &PersonalAccessTokenResponse{
+		ID: t.ID, Name: t.Name, Prefix: t.Prefix, Scopes: t.ScopeList(),
+		ExpiresAt: t.ExpiresAt, LastUsedAt: t.LastUsedAt, LastUsedIP: t.LastUsedIP, CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestPersonalAccessTokenHasScope(t *testing.T) {
+	token := &PersonalAccessToken{Scopes: ScopeArticlesWrite + "," + ScopeDocsRead + "," + ScopeUpload}
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{ScopeArticlesWrite, true},
+		{ScopeArticlesRead, true}, // write 包含 read
+		{ScopeDocsRead, true},
+		{ScopeDocsWrite, false}, // read 不包含 write
+		{ScopeUpload, true},
+		{ScopeWorksRead, false},
+		{ScopeProfileRead, false},
+	}
+	for _, test := range tests {
+		if got := token.HasScope(test.scope); got != test.want {
+			t.Errorf("HasScope(%q) = %v, want %v", test.scope, got, test.want)
+		}
+	}
+
+	if (&PersonalAccessToken{}).HasScope(ScopeUpload) {
+		t.Error("token without scopes should not grant anything")
+	}
+}
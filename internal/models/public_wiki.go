@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type PublicWikiStatsResponse struct {
 	PublicDocCount int64 `json:"public_doc_count"`
@@ -49,12 +52,13 @@ type PublicWorkspaceTreeResponse struct {
 }
 
 type PublicDocResponse struct {
-	ID          uint       `json:"id"`
-	WorkspaceID uint       `json:"workspace_id"`
-	CatalogID   *uint      `json:"catalog_id"`
-	Title       string     `json:"title"`
-	ContentHTML string     `json:"content_html"`
-	ViewCount   int        `json:"view_count"`
-	PublishedAt *time.Time `json:"published_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uint            `json:"id"`
+	WorkspaceID uint            `json:"workspace_id"`
+	CatalogID   *uint           `json:"catalog_id"`
+	Title       string          `json:"title"`
+	ContentHTML string          `json:"content_html"`
+	TOC         json.RawMessage `json:"toc,omitempty"`
+	ViewCount   int             `json:"view_count"`
+	PublishedAt *time.Time      `json:"published_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
 }
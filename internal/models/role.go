@@ -0,0 +1,167 @@
+package models
+
+import "time"
+
+// 内置角色；admin 拥有全部权限且不可修改，user 为普通注册用户
+const (
+	RoleAdmin     = "admin"
+	RoleEditor    = "editor"
+	RoleModerator = "moderator"
+	RoleAuditor   = "auditor"
+	RoleUser      = "user"
+)
+
+// 权限点，命名为 资源.动作
+const (
+	PermissionAdminAccess     = "admin.access"     // 登录管理后台
+	PermissionUserRead        = "user.read"        // 查看用户和登录锁定
+	PermissionUserManage      = "user.manage"      // 启用、禁用、删除用户，解除登录锁定
+	PermissionRoleManage      = "role.manage"      // 分配用户角色，维护角色权限
+	PermissionArticleManage   = "article.manage"   // 编辑、删除、推荐任意文章
+	PermissionWorkManage      = "work.manage"      // 编辑、删除、推荐任意作品
+	PermissionWorkAudit       = "work.audit"       // 审核作品上下线
+	PermissionCommentModerate = "comment.moderate" // 审核和删除评论
+	PermissionTaxonomyManage  = "taxonomy.manage"  // 维护分类和标签
+	PermissionLinkManage      = "link.manage"      // 维护友情链接
+	PermissionSettingsWrite   = "settings.write"   // 修改系统配置
+	PermissionAdManage        = "ad.manage"        // 维护广告位和广告
+	PermissionAuditRead       = "audit.read"       // 查看审计日志
+	PermissionSearchManage    = "search.manage"    // 重建搜索索引
+)
+
+// PermissionInfo 权限点及说明，供前端展示
+type PermissionInfo struct {
+	Permission  string `json:"permission"`
+	Description string `json:"description"`
+}
+
+var Permissions = []PermissionInfo{
+	{PermissionAdminAccess, "登录管理后台"},
+	{PermissionUserRead, "查看用户和登录锁定"},
+	{PermissionUserManage, "启用、禁用、删除用户，解除登录锁定"},
+	{PermissionRoleManage, "分配用户角色，维护角色权限"},
+	{PermissionArticleManage, "编辑、删除、推荐任意文章"},
+	{PermissionWorkManage, "编辑、删除、推荐任意作品"},
+	{PermissionWorkAudit, "审核作品上下线"},
+	{PermissionCommentModerate, "审核和删除评论"},
+	{PermissionTaxonomyManage, "维护分类和标签"},
+	{PermissionLinkManage, "维护友情链接"},
+	{PermissionSettingsWrite, "修改系统配置"},
+	{PermissionAdManage, "维护广告位和广告"},
+	{PermissionAuditRead, "查看审计日志"},
+	{PermissionSearchManage, "重建搜索索引"},
+}
+
+// ValidPermission 是否为已定义的权限点
+func ValidPermission(permission string) bool {
+	for _, item := range Permissions {
+		if item.Permission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AllPermissions 全部权限点，即 admin 角色的权限
+func AllPermissions() []string {
+	list := make([]string, len(Permissions))
+	for i, item := range Permissions {
+		list[i] = item.Permission
+	}
+	return list
+}
+
+// Role 角色；用户通过 User.Role 关联角色名
+type Role struct {
+	ID          uint             `gorm:"primarykey" json:"id"`
+	Name        string           `gorm:"size:20;uniqueIndex;not null" json:"name"`
+	DisplayName string           `gorm:"size:50;not null" json:"display_name"`
+	Description string           `gorm:"size:255" json:"description"`
+	BuiltIn     bool             `gorm:"default:false" json:"built_in"` // 内置角色不可删除
+	Permissions []RolePermission `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// RolePermission 角色拥有的权限点
+type RolePermission struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	RoleID     uint   `gorm:"not null;uniqueIndex:idx_role_permission" json:"role_id"`
+	Permission string `gorm:"size:64;not null;uniqueIndex:idx_role_permission" json:"permission"`
+}
+
+// PermissionList 角色的权限点列表，admin 始终拥有全部权限
+func (r *Role) PermissionList() []string {
+	if r.Name == RoleAdmin {
+		return AllPermissions()
+	}
+	list := make([]string, 0, len(r.Permissions))
+	for _, item := range r.Permissions {
+		list = append(list, item.Permission)
+	}
+	return list
+}
+
+// Editable 权限是否可修改：admin 固定拥有全部权限，user 固定没有后台权限
+func (r *Role) Editable() bool {
+	return r.Name != RoleAdmin && r.Name != RoleUser
+}
+
+// BuiltinRole 内置角色的初始定义，仅在角色不存在时写入，之后可在后台调整
+type BuiltinRole struct {
+	Name        string
+	DisplayName string
+	Description string
+	Permissions []string
+}
+
+var BuiltinRoles = []BuiltinRole{
+	{RoleAdmin, "管理员", "拥有全部权限", nil},
+	{RoleEditor, "编辑", "管理文章、作品、分类和标签", []string{
+		PermissionAdminAccess, PermissionArticleManage, PermissionWorkManage, PermissionWorkAudit, PermissionTaxonomyManage,
+	}},
+	{RoleModerator, "版主", "审核评论和作品，处理违规用户", []string{
+		PermissionAdminAccess, PermissionCommentModerate, PermissionWorkAudit, PermissionUserRead, PermissionUserManage,
+	}},
+	{RoleAuditor, "审计员", "只读访问后台，查看审计日志", []string{
+		PermissionAdminAccess, PermissionUserRead, PermissionAuditRead,
+	}},
+	{RoleUser, "普通用户", "前台注册用户", nil},
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=20"`
+	DisplayName string   `json:"display_name" binding:"required,max=50"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	Description string    `json:"description"`
+	BuiltIn     bool      `json:"built_in"`
+	Editable    bool      `json:"editable"`
+	Permissions []string  `json:"permissions"`
+	UserCount   int64     `json:"user_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (r *Role) ToResponse(userCount int64) *RoleResponse {
+	return &RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		DisplayName: r.DisplayName,
+		Description: r.Description,
+		BuiltIn:     r.BuiltIn,
+		Editable:    r.Editable(),
+		Permissions: r.PermissionList(),
+		UserCount:   userCount,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+type UserRoleRequest struct {
+	Role string `json:"role" binding:"required,max=20"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// 搜索索引中的内容类型
+const (
+	SearchTypeArticle = "article"
+	SearchTypeWork    = "work"
+	SearchTypeDoc     = "doc"
+	SearchTypeUser    = "user"
+)
+
+// SearchTypes 全部可检索的内容类型
+var SearchTypes = []string{SearchTypeArticle, SearchTypeWork, SearchTypeDoc, SearchTypeUser}
+
+// SearchDocument 搜索索引中的一条内容，只收录公开可见的数据，由 SearchService 与源数据同步
+type SearchDocument struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	Type            string    `gorm:"size:20;not null;uniqueIndex:idx_search_target,priority:1" json:"type"`
+	TargetID        uint      `gorm:"not null;uniqueIndex:idx_search_target,priority:2" json:"target_id"`
+	Title           string    `gorm:"size:255" json:"title"`
+	Content         string    `gorm:"type:longtext" json:"content"` // 纯文本，用于生成高亮摘要
+	Cover           string    `gorm:"size:255" json:"cover"`
+	Slug            string    `gorm:"size:200" json:"slug"`
+	AuthorID        uint      `gorm:"index" json:"author_id"`
+	CategoryID      uint      `gorm:"index" json:"category_id"`
+	TagIDs          string    `gorm:"size:500" json:"tag_ids"`          // 形如 ",1,5,"，为空表示没有标签
+	Subtype         string    `gorm:"size:50" json:"subtype"`           // 作品类型：project、photography
+	Length          int       `gorm:"not null;default:0" json:"length"` // 加权后的词数，用于 BM25 长度归一化
+	SourceUpdatedAt time.Time `json:"source_updated_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SearchPosting 倒排索引：词在某条内容中出现的加权次数
+type SearchPosting struct {
+	Term       string `gorm:"primaryKey;size:64" json:"term"`
+	DocumentID uint   `gorm:"primaryKey;index" json:"document_id"`
+	TF         int    `gorm:"not null" json:"tf"`
+}
+
+// SearchQuery 统一搜索的查询参数
+type SearchQuery struct {
+	Keyword    string `form:"q" binding:"required,max=100"`
+	Type       string `form:"type" binding:"omitempty,oneof=article work doc user"`
+	CategoryID uint   `form:"category_id"`
+	TagID      uint   `form:"tag_id"`
+	WorkType   string `form:"work_type"`
+	AuthorID   uint   `form:"author_id"`
+	Page       int    `form:"page,default=1"`
+	PageSize   int    `form:"page_size,default=20"`
+}
+
+// SearchAuthor 搜索结果中的作者信息
+type SearchAuthor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar"`
+}
+
+// SearchHit 单条搜索结果；Title 和 Snippet 为已转义的 HTML，命中部分用 <mark> 标出
+type SearchHit struct {
+	Type      string        `json:"type"`
+	ID        uint          `json:"id"`
+	Title     string        `json:"title"`
+	Snippet   string        `json:"snippet"`
+	URL       string        `json:"url"`
+	Cover     string        `json:"cover"`
+	Author    *SearchAuthor `json:"author,omitempty"`
+	Score     float64       `json:"score"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SearchFacet 分面统计中的一个取值
+type SearchFacet struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets 各维度的分面统计；每个维度按除自身外的其他筛选条件计数
+type SearchFacets struct {
+	Types      []SearchFacet `json:"types"`
+	Categories []SearchFacet `json:"categories"`
+	Tags       []SearchFacet `json:"tags"`
+	Authors    []SearchFacet `json:"authors"`
+	WorkTypes  []SearchFacet `json:"work_types"`
+}
+
+// SearchResponse 统一搜索结果
+type SearchResponse struct {
+	List     []*SearchHit `json:"list"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Facets   SearchFacets `json:"facets"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Series 文章系列（合集），用于组织多篇连载文章
+type Series struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	OwnerID     uint           `gorm:"index;not null" json:"owner_id"`
+	Owner       *User          `gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE" json:"owner,omitempty"`
+	Title       string         `gorm:"size:100;not null" json:"title"`
+	Description string         `gorm:"size:500" json:"description"`
+	Cover       string         `gorm:"size:255" json:"cover"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// SeriesArticle 系列成员，一篇文章最多属于一个系列，Sort 越小越靠前
+type SeriesArticle struct {
+	ID        uint `gorm:"primarykey" json:"id"`
+	SeriesID  uint `gorm:"not null;index:idx_series_sort,priority:1" json:"series_id"`
+	ArticleID uint `gorm:"not null;uniqueIndex" json:"article_id"`
+	Sort      int  `gorm:"not null;default:0;index:idx_series_sort,priority:2" json:"sort"`
+}
+
+type SeriesRequest struct {
+	Title       string `json:"title" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Cover       string `json:"cover" binding:"max=255"`
+}
+
+// SeriesArticlesRequest 按顺序设置系列包含的文章
+type SeriesArticlesRequest struct {
+	ArticleIDs []uint `json:"article_ids" binding:"max=200"`
+}
+
+type SeriesListQuery struct {
+	Page     int  `form:"page,default=1"`
+	PageSize int  `form:"page_size,default=20"`
+	OwnerID  uint `form:"owner_id"`
+}
+
+// SeriesArticleItem 系列中的文章条目
+type SeriesArticleItem struct {
+	ID        uint       `json:"id"`
+	Title     string     `json:"title"`
+	Summary   string     `json:"summary,omitempty"`
+	Cover     string     `json:"cover,omitempty"`
+	Status    int        `json:"status"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	Position  int        `json:"position"`
+}
+
+// SeriesResponse ArticleCount 为系列内全部文章数，PublishedCount 为已发布的篇数（连载进度）
+type SeriesResponse struct {
+	ID             uint                `json:"id"`
+	Title          string              `json:"title"`
+	Description    string              `json:"description"`
+	Cover          string              `json:"cover"`
+	OwnerID        uint                `json:"owner_id"`
+	Owner          *PublicUserResponse `json:"owner,omitempty"`
+	ArticleCount   int                 `json:"article_count"`
+	PublishedCount int                 `json:"published_count"`
+	Articles       []SeriesArticleItem `json:"articles,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func (s *Series) ToResponse() *SeriesResponse {
+	resp := &SeriesResponse{
+		ID: s.ID, Title: s.Title, Description: s.Description, Cover: s.Cover, OwnerID: s.OwnerID,
+		CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt,
+	}
+	if s.Owner != nil {
+		resp.Owner = s.Owner.ToPublicResponse()
+	}
+	return resp
+}
+
+// ArticleNavItem 系列内相邻文章
+type ArticleNavItem struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// ArticleSeriesInfo 文章所属系列；Position 和 Total 按已发布的文章计算，Prev/Next 只在详情中返回
+type ArticleSeriesInfo struct {
+	ID       uint            `json:"id"`
+	Title    string          `json:"title"`
+	Position int             `json:"position,omitempty"`
+	Total    int             `json:"total,omitempty"`
+	Prev     *ArticleNavItem `json:"prev,omitempty"`
+	Next     *ArticleNavItem `json:"next,omitempty"`
+}
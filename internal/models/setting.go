@@ -59,12 +59,21 @@ const (
 	SettingWorkCommentEnabled    = "work_comment_enabled"       // 是否开放作品评论
 	SettingWorkAudit             = "work_audit"                 // 作品是否需要审核
 	SettingRegisterEnabled       = "register_enabled"           // 是否开放注册
+	SettingEmailVerifyRequired   = "email_verify_required"      // 未验证邮箱的账号是否禁止评论和发布
 	SettingUploadMaxSize         = "upload_max_size"            // 上传文件最大大小
 	SettingCodeTheme             = "code_theme"                 // Markdown 代码高亮主题
 	SettingMarkdownTheme         = "markdown_theme"             // Markdown 主题风格（light/dark）
 	SettingSiteTheme             = "site_theme"                 // 网站整体主题（day/night/holiday/mourning）
 	SettingDefaultGuestUITheme   = "default_guest_ui_theme"     // 无缓存访客的默认 UI 主题
 	SettingDefaultGuestScheme    = "default_guest_color_scheme" // 无缓存访客的默认明暗模式
+	SettingRobotsTxt             = "robots_txt"                 // robots.txt 内容，为空时使用默认规则
+	SettingHotRankFormula        = "hot_rank_formula"           // 热门排序公式（hackernews/reddit）
+	SettingHotRankGravity        = "hot_rank_gravity"           // Hacker News 公式的重力系数
+	SettingHotRankTimeScale      = "hot_rank_time_scale"        // Reddit 公式的时间尺度（小时）
+	SettingHotRankWeightView     = "hot_rank_weight_view"       // 一次浏览折算的热度积分
+	SettingHotRankWeightComment  = "hot_rank_weight_comment"    // 一条评论折算的热度积分
+	SettingHotRankWeightLike     = "hot_rank_weight_like"       // 一次点赞折算的热度积分
+	SettingHotRankWeightFavorite = "hot_rank_weight_favorite"   // 一次收藏折算的热度积分
 )
 
 func (s *Setting) ToResponse() *SettingResponse {
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// 可通过 slug 访问的内容类型
+const (
+	SlugTargetArticle = "article"
+	SlugTargetWork    = "work"
+)
+
+// SlugRedirect 记录内容修改前的 slug，旧链接通过 301 跳转到当前地址
+type SlugRedirect struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	TargetType string    `gorm:"size:20;not null;uniqueIndex:idx_slug_redirect,priority:1" json:"target_type"`
+	AuthorID   uint      `gorm:"not null;uniqueIndex:idx_slug_redirect,priority:2" json:"author_id"`
+	Slug       string    `gorm:"size:200;not null;uniqueIndex:idx_slug_redirect,priority:3" json:"slug"`
+	TargetID   uint      `gorm:"not null;index" json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SlugResolution slug 解析结果；Redirect 为 true 时 Slug 为当前的规范 slug
+type SlugResolution struct {
+	TargetID uint
+	Username string
+	Slug     string
+	Redirect bool
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// 个性化首页时间线中的内容类型
+const (
+	TimelineTypeArticle = "article"
+	TimelineTypeWork    = "work"
+)
+
+// TimelineQuery 时间线分页参数，cursor 为上一页返回的 next_cursor
+type TimelineQuery struct {
+	Cursor   string `form:"cursor"`
+	PageSize int    `form:"page_size,default=20"`
+}
+
+// TimelineItem 时间线中的一条内容
+type TimelineItem struct {
+	Type        string           `json:"type"`
+	PublishedAt time.Time        `json:"published_at"`
+	Article     *ArticleResponse `json:"article,omitempty"`
+	Work        *WorkResponse    `json:"work,omitempty"`
+}
+
+// TimelineResponse 时间线分页结果；has_more 为 false 时没有下一页
+type TimelineResponse struct {
+	List       []*TimelineItem `json:"list"`
+	NextCursor string          `json:"next_cursor"`
+	HasMore    bool            `json:"has_more"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// UserTwoFactor 用户的 TOTP 两步验证配置
+type UserTwoFactor struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	Secret       string     `gorm:"size:64;not null" json:"-"`
+	Enabled      bool       `gorm:"default:false;not null" json:"enabled"`
+	EnabledAt    *time.Time `gorm:"type:datetime(3)" json:"enabled_at"`
+	LastUsedStep int64      `gorm:"default:0;not null" json:"-"` // 最近一次通过校验的时间步，防止验证码重放
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// UserRecoveryCode 两步验证恢复码，只保存摘要，每个只能使用一次
+type UserRecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `gorm:"type:datetime(3)" json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // 验证器中的6位验证码或恢复码
+}
+
+// TwoFactorChallenge 密码校验通过但仍需完成两步验证时返回给客户端
+type TwoFactorChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	SetupRequired  bool   `json:"setup_required"` // 管理员尚未绑定验证器，需要先完成绑定
+	ExpiresIn      int64  `json:"expires_in"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// 地址，前端生成二维码
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
@@ -7,31 +7,33 @@ import (
 )
 
 type User struct {
-	ID            uint           `gorm:"primarykey" json:"id"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
-	Username      string         `gorm:"uniqueIndex;size:50;not null" json:"username" binding:"required,min=3,max=50"`
-	Password      string         `gorm:"size:255;not null" json:"-"`
-	Email         string         `gorm:"uniqueIndex;size:100;not null" json:"email" binding:"required,email"`
-	Nickname      string         `gorm:"size:50" json:"nickname"`
-	Avatar        string         `gorm:"size:255" json:"avatar"`
-	Bio           string         `gorm:"size:500" json:"bio"`
-	Role          string         `gorm:"size:20;default:'user';index:idx_role_status" json:"role"` // admin, user
-	Status        int            `gorm:"default:1;index:idx_role_status" json:"status"`            // 1: active, 0: inactive
-	LastLoginAt   *time.Time     `gorm:"type:datetime(3)" json:"last_login_at"`
-	LastLoginIP    string         `gorm:"size:50" json:"last_login_ip"`
-	ArticleCount   int            `gorm:"default:0;not null" json:"article_count"`
-	WorkCount      int            `gorm:"default:0;not null" json:"work_count"`      // 作品数
-	CommentCount   int            `gorm:"default:0;not null" json:"comment_count"`
-	FollowingCount int            `gorm:"default:0;not null" json:"following_count"` // 关注数
-	FollowerCount  int            `gorm:"default:0;not null" json:"follower_count"`  // 粉丝数
-	FavoriteCount  int            `gorm:"default:0;not null" json:"favorite_count"`  // 收藏数
+	ID              uint           `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	Username        string         `gorm:"uniqueIndex;size:50;not null" json:"username" binding:"required,min=3,max=50"`
+	Password        string         `gorm:"size:255;not null" json:"-"`
+	Email           string         `gorm:"uniqueIndex;size:100;not null" json:"email" binding:"required,email"`
+	EmailVerifiedAt *time.Time     `gorm:"type:datetime(3)" json:"email_verified_at"` // 为空表示邮箱未验证
+	Nickname        string         `gorm:"size:50" json:"nickname"`
+	Avatar          string         `gorm:"size:255" json:"avatar"`
+	Bio             string         `gorm:"size:500" json:"bio"`
+	Role            string         `gorm:"size:20;default:'user';index:idx_role_status" json:"role"` // 角色标识，对应 roles.name
+	Status          int            `gorm:"default:1;index:idx_role_status" json:"status"`            // 1: active, 0: inactive
+	LastLoginAt     *time.Time     `gorm:"type:datetime(3)" json:"last_login_at"`
+	LastLoginIP     string         `gorm:"size:50" json:"last_login_ip"`
+	ArticleCount    int            `gorm:"default:0;not null" json:"article_count"`
+	WorkCount       int            `gorm:"default:0;not null" json:"work_count"` // 作品数
+	CommentCount    int            `gorm:"default:0;not null" json:"comment_count"`
+	FollowingCount  int            `gorm:"default:0;not null" json:"following_count"` // 关注数
+	FollowerCount   int            `gorm:"default:0;not null" json:"follower_count"`  // 粉丝数
+	FavoriteCount   int            `gorm:"default:0;not null" json:"favorite_count"`  // 收藏数
 }
 
 type UserLoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username     string `json:"username" binding:"required"`
+	Password     string `json:"password" binding:"required"`
+	CaptchaToken string `json:"captcha_token"` // 失败次数过多后需要提交人机验证结果
 }
 
 type UserRegisterRequest struct {
@@ -48,6 +50,19 @@ type UserUpdateRequest struct {
 	Avatar   string `json:"avatar"`
 }
 
+type EmailVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type PasswordForgotRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type PasswordResetRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6,max=50"`
+}
+
 type PasswordChangeRequest struct {
 	OldPassword string `json:"old_password" binding:"required,min=6"`
 	NewPassword string `json:"new_password" binding:"required,min=6,max=50"`
@@ -58,6 +73,7 @@ type UserResponse struct {
 	ID             uint      `json:"id"`
 	Username       string    `json:"username"`
 	Email          string    `json:"email"`
+	EmailVerified  bool      `json:"email_verified"`
 	Nickname       string    `json:"nickname"`
 	Avatar         string    `json:"avatar"`
 	Bio            string    `json:"bio"`
@@ -70,6 +86,7 @@ type UserResponse struct {
 	FollowerCount  int       `json:"follower_count"`
 	FavoriteCount  int       `json:"favorite_count"`
 	CreatedAt      time.Time `json:"created_at"`
+	Permissions    []string  `json:"permissions,omitempty"` // 仅管理后台返回
 }
 
 // PublicUserResponse 公开用户响应（用于查看他人主页，不包含敏感信息）
@@ -93,6 +110,7 @@ func (u *User) ToResponse() *UserResponse {
 		ID:             u.ID,
 		Username:       u.Username,
 		Email:          u.Email,
+		EmailVerified:  u.EmailVerifiedAt != nil,
 		Nickname:       u.Nickname,
 		Avatar:         u.Avatar,
 		Bio:            u.Bio,
@@ -132,7 +150,6 @@ type UserListQuery struct {
 	PageSize int    `form:"page_size,default=10"`
 	Username string `form:"username"` // 支持用户名或昵称模糊搜索
 	Email    string `form:"email"`
-	Role     string `form:"role"`   // admin/user
+	Role     string `form:"role"`   // 角色标识
 	Status   *int   `form:"status"` // 1: active, 0: inactive
 }
-
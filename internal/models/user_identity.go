@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// UserIdentity 绑定到本站账号的第三方登录身份（GitHub、OIDC 等）
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Provider  string    `gorm:"uniqueIndex:idx_user_identities_subject;size:50;not null" json:"provider"`
+	Subject   string    `gorm:"uniqueIndex:idx_user_identities_subject;size:191;not null" json:"-"` // 提供方内的用户唯一标识
+	Email     string    `gorm:"size:100" json:"email"`
+	Name      string    `gorm:"size:100" json:"name"`
+	AvatarURL string    `gorm:"size:500" json:"avatar_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OAuthProviderResponse 登录页展示的第三方登录入口
+type OAuthProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+type OAuthExchangeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type OAuthLinkRequest struct {
+	Redirect string `json:"redirect"`
+}
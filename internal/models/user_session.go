@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// UserSession 登录会话：每次登录生成一条，刷新令牌在同一会话内轮换
+type UserSession struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	SessionID    string     `gorm:"uniqueIndex;size:36;not null" json:"-"`
+	UserID       uint       `gorm:"index:idx_user_sessions_user;not null" json:"user_id"`
+	Scope        string     `gorm:"size:10;not null;default:'user'" json:"scope"` // user, admin
+	Device       string     `gorm:"size:100" json:"device"`
+	UserAgent    string     `gorm:"size:500" json:"user_agent"`
+	IP           string     `gorm:"size:50" json:"ip"`
+	LastActiveAt time.Time  `gorm:"type:datetime(3)" json:"last_active_at"`
+	ExpiresAt    time.Time  `gorm:"type:datetime(3);index" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"type:datetime(3);index:idx_user_sessions_user" json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ClientInfo 发起登录的客户端信息
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type UserSessionResponse struct {
+	ID           uint      `json:"id"`
+	Scope        string    `json:"scope"`
+	Device       string    `json:"device"`
+	UserAgent    string    `json:"user_agent"`
+	IP           string    `json:"ip"`
+	Current      bool      `json:"current"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (s *UserSession) ToResponse(currentSessionID string) *UserSessionResponse {
+	return &UserSessionResponse{
+		ID: s.ID, Scope: s.Scope, Device: s.Device, UserAgent: s.UserAgent, IP: s.IP,
+		Current:      currentSessionID != "" && s.SessionID == currentSessionID,
+		LastActiveAt: s.LastActiveAt, ExpiresAt: s.ExpiresAt, CreatedAt: s.CreatedAt,
+	}
+}
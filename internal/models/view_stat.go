@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// 浏览统计的内容类型
+const (
+	ViewTargetArticle = "article"
+	ViewTargetWork    = "work"
+)
+
+// ViewStat 内容每天的浏览量（去重后），由定时任务从 Redis 缓冲中批量写入，用于趋势图
+type ViewStat struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	TargetType string    `gorm:"size:20;not null;uniqueIndex:idx_view_stat_target_date" json:"target_type"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_view_stat_target_date" json:"target_id"`
+	Date       time.Time `gorm:"type:date;not null;uniqueIndex:idx_view_stat_target_date;index" json:"date"`
+	Views      int       `gorm:"default:0;not null" json:"views"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ViewFlushBatch 已写入数据库的浏览量缓冲批次，与浏览量在同一事务中记录，重试时跳过已写入的批次
+type ViewFlushBatch struct {
+	ID        string    `gorm:"primaryKey;size:36" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// ViewTrendPoint 趋势图中的一天
+type ViewTrendPoint struct {
+	Date  string `json:"date"` // 2006-01-02
+	Views int    `json:"views"`
+}
+
+// ViewTrendResponse 内容最近若干天的浏览趋势，没有浏览的日期补 0
+type ViewTrendResponse struct {
+	TargetType string           `json:"target_type"`
+	TargetID   uint             `json:"target_id"`
+	ViewCount  int              `json:"view_count"` // 累计浏览量，包含尚未写入数据库的部分
+	Total      int              `json:"total"`      // 统计区间内的浏览量
+	Points     []ViewTrendPoint `json:"points"`
+}
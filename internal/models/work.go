@@ -13,6 +13,7 @@ type Work struct {
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 	Title         string         `gorm:"size:200;not null" json:"title" binding:"required"`
+	Slug          string         `gorm:"size:200" json:"slug"`                                          // 同一作者下唯一，唯一索引在迁移时回填后创建
 	Type          string         `gorm:"size:50;not null;default:'project';index:idx_type" json:"type"` // project, photography, video, etc.
 	Metadata      string         `gorm:"type:text" json:"metadata"`                                     // JSON: 类型专属元数据
 	DailyQuota    bool           `gorm:"default:false" json:"daily_quota"`                              // 是否受每日配额限制
@@ -33,6 +34,7 @@ type Work struct {
 	Status        int            `gorm:"default:1;index:idx_status_sort" json:"status"` // 0: draft, 1: published, 2: pending, 3: rejected
 	IsRecommend   bool           `gorm:"default:false" json:"is_recommend"`
 	AuditMessage  string         `gorm:"type:text" json:"audit_message"` // 审核消息（审核通过或拒绝的原因）
+	PublishedAt   *time.Time     `gorm:"index" json:"published_at"`      // 首次公开（直接发布或审核通过）的时间
 }
 
 // PhotoItem 单张照片信息（摄影作品中的一张照片）
@@ -61,6 +63,7 @@ type PhotographyMetadata struct {
 
 type WorkRequest struct {
 	Title       string                 `json:"title" binding:"required,max=200"`
+	Slug        string                 `json:"slug" binding:"max=200"` // 为空时创建根据标题生成，更新时保持不变
 	Type        string                 `json:"type" binding:"required,oneof=project photography"`
 	Metadata    map[string]interface{} `json:"metadata"` // 动态元数据
 	DailyQuota  bool                   `json:"daily_quota"`
@@ -80,6 +83,7 @@ type WorkRequest struct {
 type WorkResponse struct {
 	ID            uint                   `json:"id"`
 	Title         string                 `json:"title"`
+	Slug          string                 `json:"slug"`
 	Type          string                 `json:"type"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"` // 动态元数据
 	DailyQuota    bool                   `json:"daily_quota"`
@@ -109,6 +113,7 @@ func (w *Work) ToResponse() *WorkResponse {
 	resp := &WorkResponse{
 		ID:            w.ID,
 		Title:         w.Title,
+		Slug:          w.Slug,
 		Type:          w.Type,
 		DailyQuota:    w.DailyQuota,
 		Description:   w.Description,
@@ -5,6 +5,7 @@ import (
 
 	"github.com/iceymoss/inkspace/internal/handler"
 	"github.com/iceymoss/inkspace/internal/middleware"
+	"github.com/iceymoss/inkspace/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,11 +15,13 @@ func SetupAdminRouter(assets ...fs.FS) *gin.Engine {
 	r := gin.Default()
 
 	// Middleware
+	r.Use(middleware.RequestID())
 	r.Use(middleware.CORSMiddleware())
 
 	// Handlers
 	userHandler := handler.NewUserHandler()
 	articleHandler := handler.NewArticleHandler()
+	articleRevisionHandler := handler.NewArticleRevisionHandler()
 	commentHandler := handler.NewCommentHandler()
 	categoryHandler := handler.NewCategoryHandler()
 	tagHandler := handler.NewTagHandler()
@@ -28,6 +31,11 @@ func SetupAdminRouter(assets ...fs.FS) *gin.Engine {
 	adminAuthHandler := handler.NewAdminAuthHandler()
 	uploadHandler := handler.NewUploadHandler()
 	adHandler := handler.NewAdHandler()
+	loginGuardHandler := handler.NewLoginGuardHandler()
+	roleHandler := handler.NewRoleHandler()
+	auditLogHandler := handler.NewAuditLogHandler()
+	searchHandler := handler.NewSearchHandler()
+	wordPressImportHandler := handler.NewWordPressImportHandler()
 
 	// 注意：管理后台需要完整的handler来处理查询和管理操作
 
@@ -38,11 +46,15 @@ func SetupAdminRouter(assets ...fs.FS) *gin.Engine {
 		adminAuth := api.Group("/admin/auth")
 		{
 			adminAuth.POST("/login", adminAuthHandler.AdminLogin)
+			adminAuth.POST("/refresh", adminAuthHandler.AdminRefresh)
+			adminAuth.POST("/2fa/verify", adminAuthHandler.AdminTwoFactorVerify)
+			adminAuth.POST("/2fa/setup", adminAuthHandler.AdminTwoFactorSetup)
+			adminAuth.POST("/2fa/enable", adminAuthHandler.AdminTwoFactorEnable)
 		}
 
 		// Admin authenticated routes
 		adminAuthRoutes := api.Group("/admin/auth")
-		adminAuthRoutes.Use(middleware.AdminAuthMiddleware())
+		adminAuthRoutes.Use(middleware.AdminAuthMiddleware(), middleware.Audit())
 		{
 			adminAuthRoutes.GET("/profile", adminAuthHandler.GetAdminProfile)
 			adminAuthRoutes.POST("/logout", adminAuthHandler.AdminLogout)
@@ -50,88 +62,125 @@ func SetupAdminRouter(assets ...fs.FS) *gin.Engine {
 
 		// Upload routes (authenticated)
 		upload := api.Group("/upload")
-		upload.Use(middleware.AdminAuthMiddleware())
+		upload.Use(middleware.AdminAuthMiddleware(), middleware.Audit())
 		{
 			upload.POST("/image", uploadHandler.UploadImage)
 			upload.POST("/avatar", uploadHandler.UploadAvatar)
 		}
 
 		// Admin routes (require admin authentication)
+		// 内容列表和详情对所有后台账号开放，写操作按权限点校验；配置类数据的读取同样需要对应权限
 		admin := api.Group("/admin")
-		admin.Use(middleware.AdminAuthMiddleware())
+		admin.Use(middleware.AdminAuthMiddleware(), middleware.Audit())
 		{
+			// Roles and permissions
+			admin.GET("/permissions", roleHandler.Permissions)
+			admin.GET("/roles", roleHandler.List)
+			admin.POST("/roles", middleware.RequirePermission(models.PermissionRoleManage), roleHandler.Create)
+			admin.PUT("/roles/:id", middleware.RequirePermission(models.PermissionRoleManage), roleHandler.Update)
+			admin.DELETE("/roles/:id", middleware.RequirePermission(models.PermissionRoleManage), roleHandler.Delete)
+
 			// Users management
-			admin.GET("/users", userHandler.GetUserList)
-			admin.PUT("/users/:id/status", userHandler.UpdateUserStatus)
-			admin.PUT("/users/:id/role", userHandler.UpdateUserRole)
-			admin.DELETE("/users/:id", userHandler.DeleteUser)
+			admin.GET("/users", middleware.RequirePermission(models.PermissionUserRead), userHandler.GetUserList)
+			admin.PUT("/users/:id/status", middleware.RequirePermission(models.PermissionUserManage), userHandler.UpdateUserStatus)
+			admin.PUT("/users/:id/role", middleware.RequirePermission(models.PermissionRoleManage), userHandler.UpdateUserRole)
+			admin.DELETE("/users/:id", middleware.RequirePermission(models.PermissionUserManage), userHandler.DeleteUser)
+
+			// Login lockouts
+			admin.GET("/login-locks", middleware.RequirePermission(models.PermissionUserRead), loginGuardHandler.List)
+			admin.POST("/login-locks/unlock", middleware.RequirePermission(models.PermissionUserManage), loginGuardHandler.Unlock)
+
+			// Audit logs
+			readAudit := middleware.RequirePermission(models.PermissionAuditRead)
+			admin.GET("/audit-logs", readAudit, auditLogHandler.List)
+			admin.GET("/audit-logs/export", readAudit, auditLogHandler.Export)
 
 			// Articles management
+			manageArticles := middleware.RequirePermission(models.PermissionArticleManage)
 			admin.GET("/articles", articleHandler.GetList)
 			admin.GET("/articles/:id", articleHandler.GetDetail)
-			admin.POST("/articles", articleHandler.Create)
-			admin.PUT("/articles/:id", articleHandler.Update)
-			admin.PUT("/articles/:id/recommend", articleHandler.SetRecommend)
-			admin.DELETE("/articles/:id", articleHandler.Delete)
+			admin.POST("/articles", manageArticles, articleHandler.Create)
+			admin.PUT("/articles/:id", manageArticles, articleHandler.Update)
+			admin.PUT("/articles/:id/recommend", manageArticles, articleHandler.SetRecommend)
+			admin.DELETE("/articles/:id", manageArticles, articleHandler.Delete)
+			admin.GET("/articles/:id/revisions", manageArticles, articleRevisionHandler.List)
+			admin.GET("/articles/:id/revisions/diff", manageArticles, articleRevisionHandler.Diff)
+			admin.GET("/articles/:id/revisions/:version", manageArticles, articleRevisionHandler.Get)
+			admin.POST("/articles/:id/revisions/:version/restore", manageArticles, articleRevisionHandler.Restore)
 
 			// Works management
+			manageWorks := middleware.RequirePermission(models.PermissionWorkManage)
 			admin.GET("/works", workHandler.GetList)
 			admin.GET("/works/:id", workHandler.GetDetail)
-			admin.POST("/works", workHandler.Create)
-			admin.PUT("/works/:id", workHandler.Update)
-			admin.PUT("/works/:id/recommend", workHandler.SetRecommend)
-			admin.PUT("/works/:id/status", workHandler.UpdateWorkStatus)
-			admin.DELETE("/works/:id", workHandler.Delete)
+			admin.POST("/works", manageWorks, workHandler.Create)
+			admin.PUT("/works/:id", manageWorks, workHandler.Update)
+			admin.PUT("/works/:id/recommend", manageWorks, workHandler.SetRecommend)
+			admin.PUT("/works/:id/status", middleware.RequirePermission(models.PermissionWorkAudit), workHandler.UpdateWorkStatus)
+			admin.DELETE("/works/:id", manageWorks, workHandler.Delete)
 
 			// Categories management
+			manageTaxonomy := middleware.RequirePermission(models.PermissionTaxonomyManage)
 			admin.GET("/categories", categoryHandler.GetList)
-			admin.POST("/categories", categoryHandler.Create)
-			admin.PUT("/categories/:id", categoryHandler.Update)
-			admin.DELETE("/categories/:id", categoryHandler.Delete)
+			admin.POST("/categories", manageTaxonomy, categoryHandler.Create)
+			admin.PUT("/categories/:id", manageTaxonomy, categoryHandler.Update)
+			admin.DELETE("/categories/:id", manageTaxonomy, categoryHandler.Delete)
 
 			// Tags management
 			admin.GET("/tags", tagHandler.GetList)
-			admin.POST("/tags", tagHandler.Create)
-			admin.PUT("/tags/:id", tagHandler.Update)
-			admin.DELETE("/tags/:id", tagHandler.Delete)
+			admin.POST("/tags", manageTaxonomy, tagHandler.Create)
+			admin.PUT("/tags/:id", manageTaxonomy, tagHandler.Update)
+			admin.DELETE("/tags/:id", manageTaxonomy, tagHandler.Delete)
 
 			// Comments management
+			moderateComments := middleware.RequirePermission(models.PermissionCommentModerate)
 			admin.GET("/comments", commentHandler.GetList)
-			admin.PUT("/comments/:id/status", commentHandler.UpdateStatus)
-			admin.DELETE("/comments/:id", commentHandler.Delete)
+			admin.PUT("/comments/:id/status", moderateComments, commentHandler.UpdateStatus)
+			admin.DELETE("/comments/:id", moderateComments, commentHandler.Delete)
 
 			// Links management
-			admin.GET("/links", linkHandler.GetList)
-			admin.POST("/links", linkHandler.Create)
-			admin.PUT("/links/:id", linkHandler.Update)
-			admin.DELETE("/links/:id", linkHandler.Delete)
+			manageLinks := middleware.RequirePermission(models.PermissionLinkManage)
+			admin.GET("/links", manageLinks, linkHandler.GetList)
+			admin.POST("/links", manageLinks, linkHandler.Create)
+			admin.PUT("/links/:id", manageLinks, linkHandler.Update)
+			admin.DELETE("/links/:id", manageLinks, linkHandler.Delete)
 
 			// Settings management
-			admin.GET("/settings", settingHandler.GetAllSettings)
-			admin.PUT("/settings", settingHandler.UpdateSetting)
-			admin.PUT("/settings/batch", settingHandler.BatchUpdateSettings)
-			admin.DELETE("/settings/:key", settingHandler.DeleteSetting)
+			writeSettings := middleware.RequirePermission(models.PermissionSettingsWrite)
+			admin.GET("/settings", writeSettings, settingHandler.GetAllSettings)
+			admin.GET("/settings/public", settingHandler.GetPublicSettings) // 站点名称、Logo、代码主题等公开配置
+			admin.PUT("/settings", writeSettings, settingHandler.UpdateSetting)
+			admin.PUT("/settings/batch", writeSettings, settingHandler.BatchUpdateSettings)
+			admin.DELETE("/settings/:key", writeSettings, settingHandler.DeleteSetting)
+
+			// Search index
+			admin.POST("/search/reindex", middleware.RequirePermission(models.PermissionSearchManage), searchHandler.Reindex)
+
+			// WordPress import：会创建文章、评论、分类、标签和用户账号
+			admin.POST("/import/wordpress", middleware.RequirePermission(
+				models.PermissionArticleManage, models.PermissionTaxonomyManage, models.PermissionUserManage,
+			), wordPressImportHandler.Import)
 
 			// Ad Positions management
-			admin.GET("/ad-positions", adHandler.GetPositionList)
-			admin.GET("/ad-positions/:id", adHandler.GetPositionByID)
-			admin.POST("/ad-positions", adHandler.CreatePosition)
-			admin.PUT("/ad-positions/:id", adHandler.UpdatePosition)
-			admin.DELETE("/ad-positions/:id", adHandler.DeletePosition)
+			manageAds := middleware.RequirePermission(models.PermissionAdManage)
+			admin.GET("/ad-positions", manageAds, adHandler.GetPositionList)
+			admin.GET("/ad-positions/:id", manageAds, adHandler.GetPositionByID)
+			admin.POST("/ad-positions", manageAds, adHandler.CreatePosition)
+			admin.PUT("/ad-positions/:id", manageAds, adHandler.UpdatePosition)
+			admin.DELETE("/ad-positions/:id", manageAds, adHandler.DeletePosition)
 
 			// Advertisements management
-			admin.GET("/advertisements", adHandler.GetAdvertisementList)
-			admin.GET("/advertisements/:id", adHandler.GetAdvertisementByID)
-			admin.POST("/advertisements", adHandler.CreateAdvertisement)
-			admin.PUT("/advertisements/:id", adHandler.UpdateAdvertisement)
-			admin.DELETE("/advertisements/:id", adHandler.DeleteAdvertisement)
+			admin.GET("/advertisements", manageAds, adHandler.GetAdvertisementList)
+			admin.GET("/advertisements/:id", manageAds, adHandler.GetAdvertisementByID)
+			admin.POST("/advertisements", manageAds, adHandler.CreateAdvertisement)
+			admin.PUT("/advertisements/:id", manageAds, adHandler.UpdateAdvertisement)
+			admin.DELETE("/advertisements/:id", manageAds, adHandler.DeleteAdvertisement)
 
 			// Ad Placements management
-			admin.GET("/ad-placements", adHandler.GetPlacementList)
-			admin.GET("/ad-placements/:id", adHandler.GetPlacementByID)
-			admin.POST("/ad-placements", adHandler.CreatePlacement)
-			admin.PUT("/ad-placements/:id", adHandler.UpdatePlacement)
-			admin.DELETE("/ad-placements/:id", adHandler.DeletePlacement)
+			admin.GET("/ad-placements", manageAds, adHandler.GetPlacementList)
+			admin.GET("/ad-placements/:id", manageAds, adHandler.GetPlacementByID)
+			admin.POST("/ad-placements", manageAds, adHandler.CreatePlacement)
+			admin.PUT("/ad-placements/:id", manageAds, adHandler.UpdatePlacement)
+			admin.DELETE("/ad-placements/:id", manageAds, adHandler.DeletePlacement)
 		}
 	}
 
@@ -143,7 +192,7 @@ func SetupAdminRouter(assets ...fs.FS) *gin.Engine {
 	// Serve static files (uploads)
 	r.Static("/uploads", "./uploads")
 	if len(assets) > 0 && assets[0] != nil {
-		serveSPA(r, assets[0])
+		serveSPA(r, assets[0], nil, nil)
 	}
 
 	return r
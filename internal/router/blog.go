@@ -5,6 +5,8 @@ import (
 
 	"github.com/iceymoss/inkspace/internal/handler"
 	"github.com/iceymoss/inkspace/internal/middleware"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/feed"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,17 +16,31 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 	r := gin.Default()
 
 	// Middleware
+	r.Use(middleware.RequestID())
 	r.Use(middleware.CORSMiddleware())
 
 	// Handlers
 	userHandler := handler.NewUserHandler()
 	userAppearanceHandler := handler.NewUserAppearanceHandler()
+	sessionHandler := handler.NewSessionHandler()
+	twoFactorHandler := handler.NewTwoFactorHandler()
+	oauthHandler := handler.NewOAuthHandler()
+	accessTokenHandler := handler.NewAccessTokenHandler()
+	accountDataHandler := handler.NewAccountDataHandler()
 	articleHandler := handler.NewArticleHandler()
+	articleRevisionHandler := handler.NewArticleRevisionHandler()
+	articleMarkdownHandler := handler.NewArticleMarkdownHandler()
+	seriesHandler := handler.NewSeriesHandler()
 	commentHandler := handler.NewCommentHandler()
 	categoryHandler := handler.NewCategoryHandler()
 	tagHandler := handler.NewTagHandler()
 	workHandler := handler.NewWorkHandler()
+	viewHandler := handler.NewViewHandler()
+	feedHandler := handler.NewFeedHandler()
+	sitemapHandler := handler.NewSitemapHandler()
+	seoHandler := handler.NewSEOHandler()
 	followHandler := handler.NewFollowHandler()
+	timelineHandler := handler.NewTimelineHandler()
 	favoriteHandler := handler.NewFavoriteHandler()
 	likeHandler := handler.NewLikeHandler()
 	linkHandler := handler.NewLinkHandler()
@@ -37,6 +53,7 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 	docHandler := handler.NewDocHandler()
 	shareHandler := handler.NewShareHandler()
 	publicWikiHandler := handler.NewPublicWikiHandler()
+	searchHandler := handler.NewSearchHandler()
 
 	// API routes
 	api := r.Group("/api")
@@ -47,6 +64,17 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 			// Authentication
 			public.POST("/register", userHandler.Register)
 			public.POST("/login", userHandler.Login)
+			public.POST("/email/verify", userHandler.VerifyEmail)
+			public.POST("/password/forgot", userHandler.ForgotPassword)
+			public.POST("/password/reset", userHandler.ResetPassword)
+			public.POST("/auth/refresh", userHandler.RefreshToken)
+			public.POST("/auth/2fa/verify", twoFactorHandler.VerifyLogin)
+			public.POST("/auth/2fa/setup", twoFactorHandler.SetupLogin)
+			public.POST("/auth/2fa/enable", twoFactorHandler.EnableLogin)
+			public.GET("/auth/oauth/providers", oauthHandler.Providers)
+			public.GET("/auth/oauth/:provider/start", oauthHandler.Start)
+			public.GET("/auth/oauth/:provider/callback", oauthHandler.Callback)
+			public.POST("/auth/oauth/exchange", oauthHandler.Exchange)
 			public.GET("/share/:token", shareHandler.Public)
 			public.GET("/wiki/stats", publicWikiHandler.Stats)
 			public.GET("/wiki/workspaces", publicWikiHandler.Workspaces)
@@ -54,22 +82,32 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 			public.GET("/wiki/docs/:id", publicWikiHandler.Doc)
 
 			// Articles (public read)
-			public.GET("/articles", articleHandler.GetList)
 			public.GET("/articles/recommended", articleHandler.GetRecommended)
 			public.GET("/articles/hot", articleHandler.GetHotArticles)
 
 			// 状态检查API（可选认证）
-			publicWithOptionalAuth := api.Group("")
-			publicWithOptionalAuth.Use(middleware.OptionalAuthMiddleware())
+			// 个人访问令牌需要对应资源的读取权限，否则按未登录处理
+			optionalArticles := api.Group("", middleware.OptionalAuthMiddleware(models.ScopeArticlesRead))
+			optionalWorks := api.Group("", middleware.OptionalAuthMiddleware(models.ScopeWorksRead))
+			optionalSocial := api.Group("", middleware.OptionalAuthMiddleware(models.ScopeSocialRead))
 			{
+				// 文章列表（需要可选认证，以便作者可以按状态查看自己的文章）
+				optionalArticles.GET("/articles", articleHandler.GetList)
 				// 文章详情（需要可选认证，以便作者可以查看自己的私有/草稿文章）
-				publicWithOptionalAuth.GET("/articles/:id", articleHandler.GetDetail)
-				publicWithOptionalAuth.GET("/articles/:id/is-liked", likeHandler.CheckArticleLiked)
-				publicWithOptionalAuth.GET("/articles/:id/is-favorited", favoriteHandler.CheckFavorited)
+				optionalArticles.GET("/articles/:id", articleHandler.GetDetail)
+				optionalArticles.GET("/articles/slug/:username/:slug", articleHandler.GetBySlug)
+				// 相关文章（未发布的源文章只对作者可见）
+				optionalArticles.GET("/articles/:id/related", articleHandler.GetRelated)
+				optionalSocial.GET("/articles/:id/is-liked", likeHandler.CheckArticleLiked)
+				optionalSocial.GET("/articles/:id/is-favorited", favoriteHandler.CheckFavorited)
+				// 文章系列（作者本人可以看到未发布的文章）
+				optionalArticles.GET("/series", seriesHandler.List)
+				optionalArticles.GET("/series/:id", seriesHandler.Get)
 				// 作品详情（需要可选认证，以便作者可以查看自己的待审核/审核不通过的作品）
-				publicWithOptionalAuth.GET("/works/:id", workHandler.GetDetail)
-				publicWithOptionalAuth.GET("/works/:id/liked", likeHandler.CheckWorkLiked)
-				publicWithOptionalAuth.GET("/works/:id/favorited", favoriteHandler.CheckWorkFavorited)
+				optionalWorks.GET("/works/:id", workHandler.GetDetail)
+				optionalWorks.GET("/works/slug/:username/:slug", workHandler.GetBySlug)
+				optionalSocial.GET("/works/:id/liked", likeHandler.CheckWorkLiked)
+				optionalSocial.GET("/works/:id/favorited", favoriteHandler.CheckWorkFavorited)
 			}
 
 			// Comments (public read)
@@ -85,6 +123,15 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 			public.GET("/categories", categoryHandler.GetList)
 			public.GET("/tags", tagHandler.GetList)
 
+			// Full-text search
+			public.GET("/search", searchHandler.Search)
+
+			// Feeds (RSS / Atom / JSON Feed)
+			public.GET("/feed/:format", feedHandler.Site)
+			public.GET("/users/:id/feed/:format", feedHandler.Author)
+			public.GET("/categories/:id/feed/:format", feedHandler.Category)
+			public.GET("/tags/:id/feed/:format", feedHandler.Tag)
+
 			// Works
 			public.GET("/works", workHandler.GetList)
 			public.GET("/works/recommended", workHandler.GetRecommended)
@@ -103,7 +150,7 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 
 		// 可选认证的路由（支持未登录访问，但登录后会有额外信息）
 		publicWithOptionalAuth := api.Group("")
-		publicWithOptionalAuth.Use(middleware.OptionalAuthMiddleware())
+		publicWithOptionalAuth.Use(middleware.OptionalAuthMiddleware(models.ScopeSocialRead))
 		{
 			// 关注统计（支持可选认证，以便显示当前用户的关注状态）
 			publicWithOptionalAuth.GET("/users/:id/follow-stats", followHandler.GetFollowStats)
@@ -124,101 +171,162 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleware())
 		{
+			// 账号安全相关接口只允许登录会话访问，个人访问令牌不可用
+			account := protected.Group("", middleware.SessionOnly())
+			account.POST("/logout", userHandler.Logout)
+			account.PUT("/profile/password", userHandler.ChangePassword)
+			account.POST("/profile/email/verification", userHandler.ResendVerification)
+			account.GET("/profile/sessions", sessionHandler.List)
+			account.DELETE("/profile/sessions", sessionHandler.RevokeOthers)
+			account.DELETE("/profile/sessions/:id", sessionHandler.Revoke)
+			account.GET("/profile/2fa", twoFactorHandler.Status)
+			account.POST("/profile/2fa/setup", twoFactorHandler.Setup)
+			account.POST("/profile/2fa/enable", twoFactorHandler.Enable)
+			account.POST("/profile/2fa/disable", twoFactorHandler.Disable)
+			account.POST("/profile/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			account.GET("/profile/identities", oauthHandler.Identities)
+			account.POST("/profile/identities/:provider/link", oauthHandler.Link)
+			account.DELETE("/profile/identities/:id", oauthHandler.Unlink)
+			account.GET("/profile/tokens", accessTokenHandler.List)
+			account.GET("/profile/tokens/scopes", accessTokenHandler.Scopes)
+			account.POST("/profile/tokens", accessTokenHandler.Create)
+			account.DELETE("/profile/tokens/:id", accessTokenHandler.Revoke)
+			account.GET("/profile/exports", accountDataHandler.Exports)
+			account.POST("/profile/exports", accountDataHandler.RequestExport)
+			account.GET("/profile/exports/:id/download", accountDataHandler.DownloadExport)
+			account.GET("/profile/deletion", accountDataHandler.DeletionStatus)
+			account.POST("/profile/deletion", accountDataHandler.ScheduleDeletion)
+			account.DELETE("/profile/deletion", accountDataHandler.CancelDeletion)
+
+			// 以下路由组在使用个人访问令牌时按权限范围校验（GET 需要 :read，其余需要 :write）
+
 			// User
-			protected.GET("/profile", userHandler.GetProfile)
-			protected.PUT("/profile", userHandler.UpdateProfile)
-			protected.PUT("/profile/password", userHandler.ChangePassword)
-			protected.GET("/profile/appearance", userAppearanceHandler.Get)
-			protected.PUT("/profile/appearance", userAppearanceHandler.Update)
+			profile := protected.Group("", middleware.RequireResourceScope("profile"))
+			profile.GET("/profile", userHandler.GetProfile)
+			profile.PUT("/profile", userHandler.UpdateProfile)
+			profile.GET("/profile/appearance", userAppearanceHandler.Get)
+			profile.PUT("/profile/appearance", userAppearanceHandler.Update)
 
 			// Upload
-			protected.POST("/upload/image", uploadHandler.UploadImage)
-			protected.POST("/upload/avatar", uploadHandler.UploadAvatar)
-			protected.POST("/upload/photo", uploadHandler.UploadPhoto) // 摄影作品原图上传
+			upload := protected.Group("", middleware.RequireScope(models.ScopeUpload))
+			upload.POST("/upload/image", uploadHandler.UploadImage)
+			upload.POST("/upload/avatar", uploadHandler.UploadAvatar)
+			upload.POST("/upload/photo", uploadHandler.UploadPhoto) // 摄影作品原图上传
 
 			// Articles (author can manage their own articles)
-			protected.GET("/articles/:id/edit", articleHandler.GetEdit) // 编辑页专用API，需要权限检查
-			protected.POST("/articles", articleHandler.Create)
-			protected.PUT("/articles/:id", articleHandler.Update)
-			protected.DELETE("/articles/:id", articleHandler.Delete)
+			articles := protected.Group("", middleware.RequireResourceScope("articles"))
+			articles.GET("/articles/:id/edit", articleHandler.GetEdit) // 编辑页专用API，需要权限检查
+			articles.POST("/articles", articleHandler.Create)
+			articles.POST("/articles/import", articleMarkdownHandler.Import) // 上传 Markdown 压缩包批量导入
+			articles.GET("/articles/export", articleMarkdownHandler.Export)
+			articles.PUT("/articles/:id", articleHandler.Update)
+			articles.DELETE("/articles/:id", articleHandler.Delete)
+			articles.GET("/articles/:id/revisions", articleRevisionHandler.List)
+			articles.GET("/articles/:id/revisions/diff", articleRevisionHandler.Diff)
+			articles.GET("/articles/:id/revisions/:version", articleRevisionHandler.Get)
+			articles.POST("/articles/:id/revisions/:version/restore", articleRevisionHandler.Restore)
+			articles.GET("/articles/:id/views", viewHandler.ArticleTrend) // 浏览趋势，作者或管理员可见
+			articles.GET("/series/my", seriesHandler.My)
+			articles.POST("/series", seriesHandler.Create)
+			articles.PUT("/series/:id", seriesHandler.Update)
+			articles.DELETE("/series/:id", seriesHandler.Delete)
+			articles.PUT("/series/:id/articles", seriesHandler.SetArticles)
 			// Markdown image upload (public, but rate limited)
 			public.POST("/upload/markdown-image", uploadHandler.UploadMarkdownImage)
 
 			// Works (author can manage their own works)
-			protected.GET("/works/:id/edit", workHandler.GetEdit) // 编辑页专用API，需要权限检查
-			protected.POST("/works", workHandler.Create)
-			protected.PUT("/works/:id", workHandler.Update)
-			protected.DELETE("/works/:id", workHandler.Delete)
-			protected.GET("/works/quota", workHandler.GetQuotaUsage)
-			protected.GET("/works/my", workHandler.GetMyWorks)
+			works := protected.Group("", middleware.RequireResourceScope("works"))
+			works.GET("/works/:id/edit", workHandler.GetEdit) // 编辑页专用API，需要权限检查
+			works.POST("/works", workHandler.Create)
+			works.PUT("/works/:id", workHandler.Update)
+			works.DELETE("/works/:id", workHandler.Delete)
+			works.GET("/works/quota", workHandler.GetQuotaUsage)
+			works.GET("/works/my", workHandler.GetMyWorks)
+			works.GET("/works/:id/views", viewHandler.WorkTrend) // 浏览趋势，作者或管理员可见
 
 			// Tags (users can create their own tags)
-			protected.POST("/tags", tagHandler.Create)
+			articles.POST("/tags", tagHandler.Create)
 
 			// Comments
-			protected.POST("/comments", commentHandler.Create)
-			protected.DELETE("/comments/:id", commentHandler.Delete)
+			comments := protected.Group("", middleware.RequireScope(models.ScopeCommentsWrite))
+			comments.POST("/comments", commentHandler.Create)
+			comments.DELETE("/comments/:id", commentHandler.Delete)
 
 			// Likes (articles and works require auth, comments are public)
-			protected.POST("/articles/:id/like", likeHandler.LikeArticle)
-			protected.DELETE("/articles/:id/like", likeHandler.UnlikeArticle)
-			protected.POST("/works/:id/like", likeHandler.LikeWork)
+			social := protected.Group("", middleware.RequireResourceScope("social"))
+			social.POST("/articles/:id/like", likeHandler.LikeArticle)
+			social.DELETE("/articles/:id/like", likeHandler.UnlikeArticle)
+			social.POST("/works/:id/like", likeHandler.LikeWork)
 
 			// Follow
-			protected.POST("/users/:id/follow", followHandler.Follow)
-			protected.DELETE("/users/:id/follow", followHandler.Unfollow)
+			social.POST("/users/:id/follow", followHandler.Follow)
+			social.DELETE("/users/:id/follow", followHandler.Unfollow)
 			// 用户关注/粉丝列表（仅本人可访问）
-			protected.GET("/users/:id/following", followHandler.GetFollowingList)
-			protected.GET("/users/:id/followers", followHandler.GetFollowerList)
+			social.GET("/users/:id/following", followHandler.GetFollowingList)
+			social.GET("/users/:id/followers", followHandler.GetFollowerList)
+			// 关注作者的个性化时间线
+			social.GET("/feed", timelineHandler.Get)
 
 			// Favorites
-			protected.POST("/articles/:id/favorite", favoriteHandler.AddFavorite)
-			protected.DELETE("/articles/:id/favorite", favoriteHandler.RemoveFavorite)
-			protected.POST("/works/:id/favorite", favoriteHandler.AddWorkFavorite)
-			protected.DELETE("/works/:id/favorite", favoriteHandler.RemoveWorkFavorite)
-			protected.GET("/favorites", favoriteHandler.GetMyFavorites)
+			social.POST("/articles/:id/favorite", favoriteHandler.AddFavorite)
+			social.DELETE("/articles/:id/favorite", favoriteHandler.RemoveFavorite)
+			social.POST("/works/:id/favorite", favoriteHandler.AddWorkFavorite)
+			social.DELETE("/works/:id/favorite", favoriteHandler.RemoveWorkFavorite)
+			social.GET("/favorites", favoriteHandler.GetMyFavorites)
 			// 用户收藏列表（仅本人可访问）
-			protected.GET("/users/:id/favorites", favoriteHandler.GetUserFavorites)
+			social.GET("/users/:id/favorites", favoriteHandler.GetUserFavorites)
 
 			// Notifications
-			protected.GET("/notifications", notificationHandler.GetNotifications)
-			protected.GET("/notifications/unread-count", notificationHandler.GetUnreadCount)
-			protected.PUT("/notifications/:id/read", notificationHandler.MarkAsRead)
-			protected.PUT("/notifications/read-all", notificationHandler.MarkAllAsRead)
-			protected.DELETE("/notifications/:id", notificationHandler.DeleteNotification)
-			protected.DELETE("/notifications/read-all", notificationHandler.DeleteAllRead)
+			notifications := protected.Group("", middleware.RequireResourceScope("notifications"))
+			notifications.GET("/notifications", notificationHandler.GetNotifications)
+			notifications.GET("/notifications/unread-count", notificationHandler.GetUnreadCount)
+			notifications.PUT("/notifications/:id/read", notificationHandler.MarkAsRead)
+			notifications.PUT("/notifications/read-all", notificationHandler.MarkAllAsRead)
+			notifications.DELETE("/notifications/:id", notificationHandler.DeleteNotification)
+			notifications.DELETE("/notifications/read-all", notificationHandler.DeleteAllRead)
 
 			// Private knowledge base
-			protected.POST("/workspaces", workspaceHandler.Create)
-			protected.GET("/workspaces", workspaceHandler.List)
-			protected.GET("/workspaces/:id", workspaceHandler.Get)
-			protected.PUT("/workspaces/:id", workspaceHandler.Update)
-			protected.DELETE("/workspaces/:id", workspaceHandler.Delete)
-			protected.POST("/workspaces/:id/catalogs", catalogHandler.Create)
-			protected.GET("/workspaces/:id/catalogs", catalogHandler.Tree)
-			protected.PUT("/catalogs/:id", catalogHandler.Update)
-			protected.DELETE("/catalogs/:id", catalogHandler.Delete)
-			protected.PUT("/catalogs/:id/move", catalogHandler.Move)
-			protected.POST("/docs", docHandler.Create)
-			protected.GET("/workspaces/:id/docs", docHandler.List)
-			protected.GET("/docs/:id/edit", docHandler.GetEdit)
-			protected.PUT("/docs/:id", docHandler.Save)
-			protected.PUT("/docs/:id/autosave", docHandler.Autosave)
-			protected.POST("/docs/:id/publish", docHandler.Publish)
-			protected.POST("/docs/:id/publish-to-blog", docHandler.PublishToBlog)
-			protected.DELETE("/docs/:id", docHandler.Delete)
-			protected.PUT("/docs/:id/move", docHandler.Move)
-			protected.GET("/docs/:id/versions", docHandler.Versions)
-			protected.GET("/docs/:id/versions/:version", docHandler.Version)
-			protected.POST("/docs/:id/versions/:version/rollback", docHandler.Rollback)
-			protected.GET("/workspaces/:id/search", docHandler.Search)
-			protected.POST("/docs/:id/shares", shareHandler.Create)
-			protected.GET("/docs/:id/shares", shareHandler.List)
-			protected.PUT("/shares/:id", shareHandler.Update)
-			protected.DELETE("/shares/:id", shareHandler.Delete)
+			docs := protected.Group("", middleware.RequireResourceScope("docs"))
+			docs.POST("/workspaces", workspaceHandler.Create)
+			docs.GET("/workspaces", workspaceHandler.List)
+			docs.GET("/workspaces/:id", workspaceHandler.Get)
+			docs.PUT("/workspaces/:id", workspaceHandler.Update)
+			docs.DELETE("/workspaces/:id", workspaceHandler.Delete)
+			docs.POST("/workspaces/:id/catalogs", catalogHandler.Create)
+			docs.GET("/workspaces/:id/catalogs", catalogHandler.Tree)
+			docs.PUT("/catalogs/:id", catalogHandler.Update)
+			docs.DELETE("/catalogs/:id", catalogHandler.Delete)
+			docs.PUT("/catalogs/:id/move", catalogHandler.Move)
+			docs.POST("/docs", docHandler.Create)
+			docs.GET("/workspaces/:id/docs", docHandler.List)
+			docs.GET("/docs/:id/edit", docHandler.GetEdit)
+			docs.PUT("/docs/:id", docHandler.Save)
+			docs.PUT("/docs/:id/autosave", docHandler.Autosave)
+			docs.POST("/docs/:id/publish", docHandler.Publish)
+			docs.POST("/docs/:id/publish-to-blog", middleware.RequireScope(models.ScopeArticlesWrite), docHandler.PublishToBlog)
+			docs.DELETE("/docs/:id", docHandler.Delete)
+			docs.PUT("/docs/:id/move", docHandler.Move)
+			docs.GET("/docs/:id/versions", docHandler.Versions)
+			docs.GET("/docs/:id/versions/:version", docHandler.Version)
+			docs.POST("/docs/:id/versions/:version/rollback", docHandler.Rollback)
+			docs.GET("/workspaces/:id/search", docHandler.Search)
+			docs.POST("/docs/:id/shares", shareHandler.Create)
+			docs.GET("/docs/:id/shares", shareHandler.List)
+			docs.PUT("/shares/:id", shareHandler.Update)
+			docs.DELETE("/shares/:id", shareHandler.Delete)
 		}
 	}
 
+	// 常用订阅地址
+	r.GET("/feed.xml", feedHandler.SiteAlias(feed.RSS))
+	r.GET("/atom.xml", feedHandler.SiteAlias(feed.Atom))
+	r.GET("/feed.json", feedHandler.SiteAlias(feed.JSON))
+
+	// 站点地图与 robots.txt
+	r.GET("/sitemap.xml", sitemapHandler.Index)
+	r.GET("/sitemaps/:name", sitemapHandler.Section)
+	r.GET("/robots.txt", sitemapHandler.Robots)
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -227,7 +335,7 @@ func SetupUserRouter(assets ...fs.FS) *gin.Engine {
 	// Serve static files (uploads)
 	r.Static("/uploads", "./uploads")
 	if len(assets) > 0 && assets[0] != nil {
-		serveSPA(r, assets[0])
+		serveSPA(r, assets[0], handler.SlugPermalinkRedirect, seoHandler.PageMeta)
 	}
 
 	return r
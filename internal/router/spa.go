@@ -7,10 +7,15 @@ import (
 	"path"
 	"strings"
 
+	"github.com/iceymoss/inkspace/pkg/seo"
+
 	"github.com/gin-gonic/gin"
 )
 
-func serveSPA(r *gin.Engine, assets fs.FS) {
+// serveSPA 提供前端静态资源和 history 路由回退；redirect 非空时可将旧地址 301 跳转到新地址，
+// meta 非空时按路由改写 index.html 的 <head>，让爬虫和链接预览拿到页面标题、描述和封面
+func serveSPA(r *gin.Engine, assets fs.FS, redirect func(requestPath string) (string, bool),
+	meta func(c *gin.Context, requestPath string) *seo.Meta) {
 	fileServer := http.FileServer(http.FS(assets))
 
 	r.NoRoute(func(c *gin.Context) {
@@ -37,11 +42,23 @@ func serveSPA(r *gin.Engine, assets fs.FS) {
 			return
 		}
 
+		if redirect != nil {
+			if location, ok := redirect(requestPath); ok {
+				c.Redirect(http.StatusMovedPermanently, location)
+				return
+			}
+		}
+
 		index, err := fs.ReadFile(assets, "index.html")
 		if err != nil {
 			c.Status(http.StatusNotFound)
 			return
 		}
+		if meta != nil {
+			if m := meta(c, requestPath); m != nil {
+				index = seo.Inject(index, m)
+			}
+		}
 		c.Header("Cache-Control", "no-cache")
 		c.Data(http.StatusOK, "text/html; charset=utf-8", index)
 	})
@@ -3,21 +3,34 @@ package router
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"testing/fstest"
 
+	"github.com/iceymoss/inkspace/pkg/seo"
+
 	"github.com/gin-gonic/gin"
 )
 
 func TestServeSPA(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	assets := fstest.MapFS{
-		"index.html":        {Data: []byte("<html>app</html>")},
+		"index.html":        {Data: []byte("<html><head><title>App</title></head><body>app</body></html>")},
 		"assets/app-123.js": {Data: []byte("console.log('app')")},
 	}
 	r := gin.New()
 	r.GET("/api/known", func(c *gin.Context) { c.Status(http.StatusNoContent) })
-	serveSPA(r, assets)
+	serveSPA(r, assets, func(requestPath string) (string, bool) {
+		if requestPath == "blog/alice/old-slug" {
+			return "/blog/alice/new-slug", true
+		}
+		return "", false
+	}, func(c *gin.Context, requestPath string) *seo.Meta {
+		if requestPath == "blog/alice/new-slug" {
+			return &seo.Meta{Title: "New <Slug>", SiteName: "InkSpace"}
+		}
+		return nil
+	})
 
 	tests := []struct {
 		name         string
@@ -25,13 +38,17 @@ func TestServeSPA(t *testing.T) {
 		accept       string
 		status       int
 		cacheControl string
+		location     string
+		title        string
 	}{
 		{name: "asset", requestPath: "/assets/app-123.js", status: http.StatusOK, cacheControl: "public, max-age=31536000, immutable"},
-		{name: "history fallback", requestPath: "/dashboard/workspaces/1", accept: "text/html", status: http.StatusOK, cacheControl: "no-cache"},
+		{name: "history fallback", requestPath: "/dashboard/workspaces/1", accept: "text/html", status: http.StatusOK, cacheControl: "no-cache", title: "<title>App</title>"},
 		{name: "known API", requestPath: "/api/known", status: http.StatusNoContent},
 		{name: "unknown API", requestPath: "/api/missing", accept: "text/html", status: http.StatusNotFound},
 		{name: "unknown static file", requestPath: "/assets/missing.js", accept: "text/html", status: http.StatusNotFound},
 		{name: "non HTML request", requestPath: "/dashboard", accept: "application/json", status: http.StatusNotFound},
+		{name: "renamed slug", requestPath: "/blog/alice/old-slug", accept: "text/html", status: http.StatusMovedPermanently, location: "/blog/alice/new-slug"},
+		{name: "current slug", requestPath: "/blog/alice/new-slug", accept: "text/html", status: http.StatusOK, cacheControl: "no-cache", title: "<title>New &lt;Slug&gt; - InkSpace</title>"},
 	}
 
 	for _, tt := range tests {
@@ -48,6 +65,12 @@ func TestServeSPA(t *testing.T) {
 			if got := res.Header().Get("Cache-Control"); got != tt.cacheControl {
 				t.Fatalf("Cache-Control = %q, want %q", got, tt.cacheControl)
 			}
+			if got := res.Header().Get("Location"); got != tt.location {
+				t.Fatalf("Location = %q, want %q", got, tt.location)
+			}
+			if tt.title != "" && !strings.Contains(res.Body.String(), tt.title) {
+				t.Fatalf("body = %q, want title %q", res.Body.String(), tt.title)
+			}
 		})
 	}
 }
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/iceymoss/inkspace/internal/service"
+)
+
+// AccountCleanupTask 执行到期的账号注销，清理过期的数据导出文件
+type AccountCleanupTask struct{}
+
+// NewAccountCleanupTask 创建账号清理任务
+func NewAccountCleanupTask() *AccountCleanupTask {
+	return &AccountCleanupTask{}
+}
+
+// Name 返回任务名称
+func (t *AccountCleanupTask) Name() string {
+	return "账号注销与导出清理"
+}
+
+// Run 执行任务
+func (t *AccountCleanupTask) Run(ctx context.Context) error {
+	purged, err := service.NewAccountDeletionService().PurgeDue()
+	if err != nil {
+		return fmt.Errorf("执行账号注销失败: %w", err)
+	}
+	if purged > 0 {
+		log.Printf("✅ 已注销 %d 个账号", purged)
+	}
+
+	if err := service.NewAccountExportService().CleanExpired(); err != nil {
+		return fmt.Errorf("清理导出文件失败: %w", err)
+	}
+	return nil
+}
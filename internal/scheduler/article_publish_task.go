@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/iceymoss/inkspace/internal/service"
+)
+
+// ArticlePublishTask 发布到达 PublishAt 的定时文章
+type ArticlePublishTask struct{}
+
+// NewArticlePublishTask 创建定时发布任务
+func NewArticlePublishTask() *ArticlePublishTask {
+	return &ArticlePublishTask{}
+}
+
+// Name 返回任务名称
+func (t *ArticlePublishTask) Name() string {
+	return "文章定时发布"
+}
+
+// Run 执行任务
+func (t *ArticlePublishTask) Run(ctx context.Context) error {
+	published, err := service.NewArticleService().PublishDue()
+	if err != nil {
+		return fmt.Errorf("发布定时文章失败: %w", err)
+	}
+	if published > 0 {
+		log.Printf("✅ 已发布 %d 篇定时文章", published)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/iceymoss/inkspace/internal/service"
+)
+
+// RelatedArticlesTask 预计算相关文章
+type RelatedArticlesTask struct{}
+
+// NewRelatedArticlesTask 创建相关文章任务
+func NewRelatedArticlesTask() *RelatedArticlesTask {
+	return &RelatedArticlesTask{}
+}
+
+// Name 返回任务名称
+func (t *RelatedArticlesTask) Name() string {
+	return "相关文章计算"
+}
+
+// Run 执行任务
+func (t *RelatedArticlesTask) Run(ctx context.Context) error {
+	count, err := service.NewRelatedArticleService().Compute()
+	if err != nil {
+		return fmt.Errorf("计算相关文章失败: %w", err)
+	}
+	log.Printf("✅ 相关文章计算完成，共 %d 篇文章", count)
+	return nil
+}
@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/iceymoss/inkspace/internal/service"
+)
+
+// ViewFlushTask 将 Redis 中缓冲的浏览量批量写入数据库
+type ViewFlushTask struct{}
+
+// NewViewFlushTask 创建浏览量写入任务
+func NewViewFlushTask() *ViewFlushTask {
+	return &ViewFlushTask{}
+}
+
+// Name 返回任务名称
+func (t *ViewFlushTask) Name() string {
+	return "浏览量写入"
+}
+
+// Run 执行任务
+func (t *ViewFlushTask) Run(ctx context.Context) error {
+	count, err := service.NewViewService().Flush()
+	if err != nil {
+		return fmt.Errorf("写入浏览量失败: %w", err)
+	}
+	if count > 0 {
+		log.Printf("✅ 已写入 %d 次浏览", count)
+	}
+	return nil
+}
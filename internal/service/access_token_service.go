@@ -0,0 +1,175 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// PersonalAccessTokenPrefix 个人访问令牌的固定前缀，认证中间件据此与 JWT 区分
+	PersonalAccessTokenPrefix = "ink_pat_"
+
+	accessTokenLimit         = 20 // 每个用户最多持有的令牌数量
+	accessTokenTouchInterval = time.Minute
+)
+
+var (
+	ErrAccessTokenInvalid  = errors.New("访问令牌无效或已过期")
+	ErrAccessTokenNotFound = errors.New("访问令牌不存在")
+	ErrAccessTokenScope    = errors.New("无效的权限范围")
+	ErrAccessTokenLimit    = fmt.Errorf("最多只能创建 %d 个访问令牌", accessTokenLimit)
+)
+
+type AccessTokenService struct{}
+
+func NewAccessTokenService() *AccessTokenService {
+	return &AccessTokenService{}
+}
+
+// Create 创建个人访问令牌，明文只在返回值中出现一次
+func (s *AccessTokenService) Create(userID uint, req *models.PersonalAccessTokenCreateRequest) (*models.PersonalAccessTokenResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.PersonalAccessToken{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= accessTokenLimit {
+		return nil, ErrAccessTokenLimit
+	}
+
+	raw, err := generateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	token := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: hashAccessToken(raw),
+		Prefix:    raw[:len(PersonalAccessTokenPrefix)+4],
+		Scopes:    strings.Join(scopes, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := database.DB.Create(token).Error; err != nil {
+		return nil, err
+	}
+
+	resp := token.ToResponse()
+	resp.Token = raw
+	return resp, nil
+}
+
+// List 用户的全部访问令牌
+func (s *AccessTokenService) List(userID uint) ([]*models.PersonalAccessToken, error) {
+	var tokens []*models.PersonalAccessToken
+	if err := database.DB.Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Revoke 删除访问令牌，立即失效
+func (s *AccessTokenService) Revoke(userID, id uint) error {
+	result := database.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate 校验请求携带的访问令牌，返回令牌及其所属用户；账号被禁用后令牌同样失效
+func (s *AccessTokenService) Authenticate(raw, ip string) (*models.PersonalAccessToken, *models.User, error) {
+	if !strings.HasPrefix(raw, PersonalAccessTokenPrefix) {
+		return nil, nil, ErrAccessTokenInvalid
+	}
+	var token models.PersonalAccessToken
+	if err := database.DB.Where("token_hash = ?", hashAccessToken(raw)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAccessTokenInvalid
+		}
+		return nil, nil, err
+	}
+	if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+		return nil, nil, ErrAccessTokenInvalid
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "username", "role", "status").First(&user, token.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAccessTokenInvalid
+		}
+		return nil, nil, err
+	}
+	if user.Status != 1 {
+		return nil, nil, ErrAccessTokenInvalid
+	}
+
+	s.touch(&token, ip)
+	return &token, &user, nil
+}
+
+// touch 记录最近使用时间，同一令牌每分钟最多写一次数据库
+func (s *AccessTokenService) touch(token *models.PersonalAccessToken, ip string) {
+	key := fmt.Sprintf("auth:pat:%d:touch", token.ID)
+	if ok, err := database.RDB.SetNX(database.Ctx, key, 1, accessTokenTouchInterval).Result(); err != nil || !ok {
+		return
+	}
+	now := time.Now()
+	database.DB.Model(&models.PersonalAccessToken{}).Where("id = ?", token.ID).
+		UpdateColumns(map[string]interface{}{"last_used_at": now, "last_used_ip": ip})
+}
+
+// normalizeScopes 校验、去重并排序权限范围
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !models.ValidAccessTokenScope(scope) {
+			return nil, fmt.Errorf("%w: %s", ErrAccessTokenScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, ErrAccessTokenScope
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func generateAccessToken() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func hashAccessToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestNormalizeScopes(t *testing.T) {
+	got, err := normalizeScopes([]string{" upload", models.ScopeArticlesWrite, models.ScopeArticlesWrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{models.ScopeArticlesWrite, models.ScopeUpload}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("normalizeScopes() = %v, want %v", got, want)
+	}
+
+	for _, scopes := range [][]string{nil, {"admin"}, {models.ScopeDocsRead, "docs:delete"}} {
+		if _, err := normalizeScopes(scopes); !errors.Is(err, ErrAccessTokenScope) {
+			t.Errorf("normalizeScopes(%v) error = %v, want %v", scopes, err, ErrAccessTokenScope)
+		}
+	}
+}
+
+func TestGenerateAccessToken(t *testing.T) {
+	token, err := generateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, PersonalAccessTokenPrefix) || len(token) != len(PersonalAccessTokenPrefix)+43 {
+		t.Fatalf("generateAccessToken() = %q", token)
+	}
+	if hashAccessToken(token) == hashAccessToken(token+"x") || len(hashAccessToken(token)) != 64 {
+		t.Fatal("hashAccessToken() is not a sha256 hex digest")
+	}
+}
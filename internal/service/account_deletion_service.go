@@ -0,0 +1,492 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/utils"
+	"github.com/iceymoss/inkspace/pkg/mailer"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultDeletionGraceDays = 14
+
+// 注销后评论的展示信息
+const (
+	deletedUserNickname   = "已注销用户"
+	deletedCommentContent = "该评论已删除"
+)
+
+var (
+	ErrDeletionScheduled      = errors.New("已提交注销申请")
+	ErrDeletionNotScheduled   = errors.New("没有待执行的注销申请")
+	ErrDeletionNotDue         = errors.New("注销申请尚在冷静期内")
+	ErrDeletionConfirm        = errors.New("请输入用户名确认注销")
+	ErrDeletionPassword       = errors.New("密码错误")
+	ErrDeletionBackendAccount = errors.New("管理后台账号不能自助注销，请联系管理员调整角色")
+)
+
+func accountDeletionGrace() time.Duration {
+	days := defaultDeletionGraceDays
+	if config.AppConfig != nil && config.AppConfig.Account.DeletionGraceDays > 0 {
+		days = config.AppConfig.Account.DeletionGraceDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// AccountDeletionService 自助注销：提交申请后进入冷静期，到期由定时任务清除账号数据
+type AccountDeletionService struct {
+	mailer mailer.Mailer
+}
+
+func NewAccountDeletionService() *AccountDeletionService {
+	return &AccountDeletionService{mailer: accountMailer()}
+}
+
+// Status 当前的注销申请，没有时返回 nil
+func (s *AccountDeletionService) Status(userID uint) (*models.AccountDeletion, error) {
+	var deletion models.AccountDeletion
+	if err := database.DB.Where("user_id = ?", userID).First(&deletion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+// Schedule 提交注销申请；设置过密码的账号校验密码，仅第三方登录的账号校验用户名
+func (s *AccountDeletionService) Schedule(user *models.User, req *models.AccountDeletionRequest) (*models.AccountDeletion, error) {
+	if NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess) {
+		return nil, ErrDeletionBackendAccount
+	}
+	if user.Password != "" {
+		if !utils.CheckPassword(req.Password, user.Password) {
+			return nil, ErrDeletionPassword
+		}
+	} else if strings.TrimSpace(req.Confirm) != user.Username {
+		return nil, ErrDeletionConfirm
+	}
+
+	existing, err := s.Status(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrDeletionScheduled
+	}
+
+	deletion := &models.AccountDeletion{
+		UserID:      user.ID,
+		CommentMode: req.CommentMode,
+		ScheduledAt: time.Now().Add(accountDeletionGrace()),
+	}
+	if err := database.DB.Create(deletion).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.notify(user, deletion); err != nil {
+		log.Printf("发送注销确认邮件失败: 用户 %d, 错误: %v", user.ID, err)
+	}
+	return deletion, nil
+}
+
+// Cancel 冷静期内撤销注销申请
+func (s *AccountDeletionService) Cancel(userID uint) error {
+	result := database.DB.Where("user_id = ?", userID).Delete(&models.AccountDeletion{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeletionNotScheduled
+	}
+	return nil
+}
+
+// PurgeDue 执行冷静期已结束的注销申请，单个账号失败不影响其他账号
+func (s *AccountDeletionService) PurgeDue() (int, error) {
+	var due []*models.AccountDeletion
+	if err := database.DB.Where("scheduled_at <= ?", time.Now()).Find(&due).Error; err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, deletion := range due {
+		if err := s.Purge(deletion.UserID); err != nil {
+			log.Printf("注销账号失败: 用户 %d, 错误: %v", deletion.UserID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Purge 永久删除账号及其数据。文章、作品、知识库连同他人在其下的评论、点赞、收藏一并删除；
+// 在他人内容下发表的评论按 commentMode 匿名保留或删除；上传记录删除但文件保留，因为可能仍被其他内容引用；
+// 审计日志作为操作记录保留。
+// 事务内锁定并重新读取注销申请和账号，申请已撤销、尚未到期或账号已获得后台权限时不执行
+func (s *AccountDeletionService) Purge(userID uint) error {
+	var exportFiles []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var deletion models.AccountDeletion
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&deletion).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDeletionNotScheduled
+			}
+			return err
+		}
+		if deletion.ScheduledAt.After(time.Now()) {
+			return ErrDeletionNotDue
+		}
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "role").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess) {
+			return ErrDeletionBackendAccount
+		}
+
+		if err := purgeOwnContent(tx, userID); err != nil {
+			return err
+		}
+		if deletion.CommentMode == models.CommentModeDelete {
+			if err := deleteUserComments(tx, userID); err != nil {
+				return err
+			}
+		}
+		if err := anonymizeUserComments(tx, userID); err != nil {
+			return err
+		}
+		// 用户在他人文章中留下的修订记录保留内容，只去除编辑者信息
+		if err := tx.Model(&models.ArticleRevision{}).Where("editor_id = ?", userID).UpdateColumns(map[string]interface{}{
+			"editor_id":   nil,
+			"editor_name": deletedUserNickname,
+		}).Error; err != nil {
+			return err
+		}
+		if err := purgeInteractions(tx, userID); err != nil {
+			return err
+		}
+		if err := purgeKnowledgeBase(tx, userID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AccountExport{}).Where("user_id = ?", userID).Pluck("file_path", &exportFiles).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.VisitLog{}).Where("user_id = ?", userID).Update("user_id", 0).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&models.UserSession{}, &models.UserTwoFactor{}, &models.UserRecoveryCode{}, &models.UserIdentity{},
+			&models.PersonalAccessToken{}, &models.UserAppearance{}, &models.Attachment{},
+			&models.AccountExport{}, &models.AccountDeletion{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&models.User{}, userID).Error
+	})
+	if err != nil {
+		return err
+	}
+	if err := NewTokenService().RevokeAll(userID); err != nil {
+		// 账号删除后令牌也无法再通过校验，这里只记录
+		log.Printf("吊销令牌失败: 用户 %d, 错误: %v", userID, err)
+	}
+	NewSearchService().RemoveAuthor(userID)
+
+	for _, path := range exportFiles {
+		removeExportFile(path)
+	}
+	return nil
+}
+
+// purgeOwnContent 删除用户的文章、作品、私有标签，以及他人在这些内容下的评论、点赞和收藏
+func purgeOwnContent(tx *gorm.DB, userID uint) error {
+	var articleIDs, workIDs []uint
+	if err := tx.Unscoped().Model(&models.Article{}).Where("author_id = ?", userID).Pluck("id", &articleIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.Work{}).Where("author_id = ?", userID).Pluck("id", &workIDs).Error; err != nil {
+		return err
+	}
+
+	if len(articleIDs) > 0 || len(workIDs) > 0 {
+		target := tx.Where("article_id IN ? OR work_id IN ?", nonEmptyIDs(articleIDs), nonEmptyIDs(workIDs))
+
+		var commentIDs []uint
+		if err := tx.Unscoped().Model(&models.Comment{}).Where(target).Pluck("id", &commentIDs).Error; err != nil {
+			return err
+		}
+		if err := decrementGrouped(tx, &models.Comment{}, "user_id", &models.User{}, "comment_count", target); err != nil {
+			return err
+		}
+		if err := decrementGrouped(tx, &models.ArticleFavorite{}, "user_id", &models.User{}, "favorite_count",
+			tx.Where("article_id IN ?", nonEmptyIDs(articleIDs))); err != nil {
+			return err
+		}
+		if err := decrementGrouped(tx, &models.Favorite{}, "user_id", &models.User{}, "favorite_count", target); err != nil {
+			return err
+		}
+		if err := decrementGrouped(tx, &models.Article{}, "category_id", &models.Category{}, "article_count",
+			tx.Where("id IN ?", nonEmptyIDs(articleIDs))); err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where(target).Or("comment_id IN ?", nonEmptyIDs(commentIDs)).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&models.Like{}, &models.Favorite{}} {
+			if err := tx.Unscoped().Where(target).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Where("article_id IN ?", nonEmptyIDs(articleIDs)).Delete(&models.ArticleFavorite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where(target).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Doc{}).Where("article_id IN ?", nonEmptyIDs(articleIDs)).Update("article_id", nil).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(articleIDs) > 0 {
+		// 已删除的文章在删除时已扣减过标签计数
+		var liveArticleIDs []uint
+		if err := tx.Model(&models.Article{}).Where("id IN ?", articleIDs).Pluck("id", &liveArticleIDs).Error; err != nil {
+			return err
+		}
+		if err := decrementGrouped(tx, "article_tags", "tag_id", &models.Tag{}, "article_count",
+			tx.Where("article_id IN ?", nonEmptyIDs(liveArticleIDs))); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN ?", articleIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", articleIDs).Delete(&models.ArticleRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", articleIDs).Delete(&models.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("id IN ?", articleIDs).Delete(&models.Article{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(workIDs) > 0 {
+		if err := tx.Unscoped().Where("id IN ?", workIDs).Delete(&models.Work{}).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Unscoped().Where("owner_id = ?", userID).Delete(&models.Series{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("author_id = ?", userID).Delete(&models.SlugRedirect{}).Error; err != nil {
+		return err
+	}
+
+	var tagIDs []uint
+	if err := tx.Unscoped().Model(&models.Tag{}).Where("user_id = ?", userID).Pluck("id", &tagIDs).Error; err != nil {
+		return err
+	}
+	if len(tagIDs) > 0 {
+		if err := tx.Exec("DELETE FROM article_tags WHERE tag_id IN ?", tagIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("id IN ?", tagIDs).Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUserComments 删除用户在他人内容下的评论；已有回复的评论只清空内容，保留讨论结构
+func deleteUserComments(tx *gorm.DB, userID uint) error {
+	var comments []*models.Comment
+	if err := tx.Unscoped().Where("user_id = ?", userID).Find(&comments).Error; err != nil {
+		return err
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	var repliedIDs []uint
+	if err := tx.Unscoped().Model(&models.Comment{}).Where("parent_id IN ?", ids).Distinct().Pluck("parent_id", &repliedIDs).Error; err != nil {
+		return err
+	}
+	replied := make(map[uint]bool, len(repliedIDs))
+	for _, id := range repliedIDs {
+		replied[id] = true
+	}
+
+	var removable []uint
+	for _, comment := range comments {
+		if replied[comment.ID] {
+			if err := tx.Unscoped().Model(comment).UpdateColumn("content", deletedCommentContent).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		removable = append(removable, comment.ID)
+		if comment.DeletedAt.Valid {
+			continue // 已删除的评论计数已经扣减过
+		}
+		if comment.ArticleID != nil {
+			if err := tx.Model(&models.Article{}).Where("id = ?", *comment.ArticleID).
+				UpdateColumn("comment_count", gorm.Expr("comment_count - ?", 1)).Error; err != nil {
+				return err
+			}
+		}
+		if comment.WorkID != nil {
+			if err := tx.Model(&models.Work{}).Where("id = ?", *comment.WorkID).
+				UpdateColumn("comment_count", gorm.Expr("comment_count - ?", 1)).Error; err != nil {
+				return err
+			}
+		}
+		if comment.ParentID != nil {
+			if err := tx.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+				UpdateColumn("reply_count", gorm.Expr("reply_count - ?", 1)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	if len(removable) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Where("comment_id IN ?", removable).Delete(&models.Notification{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", removable).Delete(&models.Comment{}).Error
+}
+
+// anonymizeUserComments 去除剩余评论的作者信息，评论以“已注销用户”展示
+func anonymizeUserComments(tx *gorm.DB, userID uint) error {
+	return tx.Unscoped().Model(&models.Comment{}).Where("user_id = ?", userID).UpdateColumns(map[string]interface{}{
+		"user_id":    nil,
+		"nickname":   deletedUserNickname,
+		"email":      "",
+		"website":    "",
+		"ip":         "",
+		"user_agent": "",
+	}).Error
+}
+
+// purgeInteractions 删除用户的点赞、收藏、关注和通知，并修正相关计数
+func purgeInteractions(tx *gorm.DB, userID uint) error {
+	byUser := tx.Where("user_id = ?", userID)
+	if err := decrementGrouped(tx, &models.Like{}, "article_id", &models.Article{}, "like_count", byUser); err != nil {
+		return err
+	}
+	if err := decrementGrouped(tx, &models.Like{}, "work_id", &models.Work{}, "like_count", byUser); err != nil {
+		return err
+	}
+	if err := decrementGrouped(tx, &models.ArticleFavorite{}, "article_id", &models.Article{}, "favorite_count", byUser); err != nil {
+		return err
+	}
+	if err := decrementGrouped(tx, &models.Favorite{}, "article_id", &models.Article{}, "favorite_count", byUser); err != nil {
+		return err
+	}
+	if err := decrementGrouped(tx, &models.Favorite{}, "work_id", &models.Work{}, "favorite_count", byUser); err != nil {
+		return err
+	}
+	if err := decrementGrouped(tx, &models.UserFollow{}, "following_id", &models.User{}, "follower_count",
+		tx.Where("follower_id = ?", userID)); err != nil {
+		return err
+	}
+	if err := decrementGrouped(tx, &models.UserFollow{}, "follower_id", &models.User{}, "following_count",
+		tx.Where("following_id = ?", userID)); err != nil {
+		return err
+	}
+
+	for _, model := range []interface{}{&models.Like{}, &models.ArticleFavorite{}, &models.Favorite{}} {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Unscoped().Where("follower_id = ? OR following_id = ?", userID, userID).Delete(&models.UserFollow{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id = ? OR from_user_id = ?", userID, userID).Delete(&models.Notification{}).Error
+}
+
+func purgeKnowledgeBase(tx *gorm.DB, userID uint) error {
+	var docIDs []uint
+	if err := tx.Unscoped().Model(&models.Doc{}).Where("owner_id = ?", userID).Pluck("id", &docIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("owner_id = ? OR doc_id IN ?", userID, nonEmptyIDs(docIDs)).Delete(&models.ShareLink{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("owner_id = ? OR doc_id IN ?", userID, nonEmptyIDs(docIDs)).Delete(&models.DocVersion{}).Error; err != nil {
+		return err
+	}
+	for _, model := range []interface{}{&models.Doc{}, &models.Catalog{}, &models.Workspace{}} {
+		if err := tx.Unscoped().Where("owner_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decrementGrouped 按 groupColumn 统计 source（模型或表名）中符合条件的未删除记录，并从 target 对应记录的 counter 中扣减
+func decrementGrouped(tx *gorm.DB, source interface{}, groupColumn string, target interface{}, counter string, where *gorm.DB) error {
+	query := tx.Model(source)
+	if table, ok := source.(string); ok {
+		query = tx.Table(table)
+	}
+
+	var rows []struct {
+		ID    uint
+		Count int64
+	}
+	if err := query.Select(fmt.Sprintf("%s AS id, COUNT(*) AS count", groupColumn)).
+		Where(where).Where(groupColumn + " IS NOT NULL").
+		Group(groupColumn).Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.ID == 0 {
+			continue
+		}
+		if err := tx.Model(target).Where("id = ?", row.ID).
+			UpdateColumn(counter, gorm.Expr(counter+" - ?", row.Count)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nonEmptyIDs IN 条件不能为空列表，用 0 占位
+func nonEmptyIDs(ids []uint) []uint {
+	if len(ids) == 0 {
+		return []uint{0}
+	}
+	return ids
+}
+
+func (s *AccountDeletionService) notify(user *models.User, deletion *models.AccountDeletion) error {
+	siteName := siteName()
+	link := publicURL("/profile/edit")
+	date := deletion.ScheduledAt.Format("2006-01-02 15:04")
+	text := fmt.Sprintf("您的账号将于 %s 注销，届时文章、作品、知识库等数据将被永久删除且无法恢复。在此之前登录并在账号设置中撤销即可保留账号。", date)
+	return s.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: fmt.Sprintf("【%s】账号注销申请已提交", siteName),
+		Text:    fmt.Sprintf("%s，您好：\n\n%s\n%s\n\n如果这不是您的操作，请立即登录撤销并修改密码。", displayName(user), text, link),
+		HTML:    mailHTML(displayName(user), text, "撤销注销", link),
+	})
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/utils"
+	"github.com/iceymoss/inkspace/pkg/mailer"
+
+	"gorm.io/gorm"
+)
+
+const (
+	emailVerifyTokenTTL   = 24 * time.Hour
+	passwordResetTokenTTL = 30 * time.Minute
+	accountMailInterval   = time.Minute // 同一账号同类邮件的最小发送间隔
+)
+
+var (
+	ErrEmailNotVerified     = errors.New("请先验证邮箱")
+	ErrEmailAlreadyVerified = errors.New("邮箱已验证")
+	ErrMailTooFrequent      = errors.New("邮件发送过于频繁，请稍后再试")
+)
+
+var (
+	defaultMailerOnce sync.Once
+	defaultMailer     mailer.Mailer
+)
+
+// accountMailer 按配置创建全局 Mailer，配置错误时退化为不发送并记录日志
+func accountMailer() mailer.Mailer {
+	defaultMailerOnce.Do(func() {
+		m, err := mailer.NewFromConfig()
+		if err != nil {
+			log.Printf("初始化邮件发送失败，邮件将不会发送: %v", err)
+			m = mailer.NewMemoryMailer()
+		}
+		defaultMailer = m
+	})
+	return defaultMailer
+}
+
+// AccountEmailService 邮箱验证与找回密码
+type AccountEmailService struct {
+	mailer mailer.Mailer
+}
+
+func NewAccountEmailService() *AccountEmailService {
+	return &AccountEmailService{mailer: accountMailer()}
+}
+
+// SendVerification 发送邮箱验证邮件
+func (s *AccountEmailService) SendVerification(user *models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	if err := s.throttle(utils.ActionVerifyEmail, user.ID); err != nil {
+		return err
+	}
+	token, err := utils.GenerateActionToken(utils.ActionVerifyEmail, user.ID, user.Email, emailVerifyTokenTTL)
+	if err != nil {
+		return err
+	}
+	siteName := siteName()
+	link := publicURL("/verify-email?token=" + token)
+	return s.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: fmt.Sprintf("【%s】请验证您的邮箱", siteName),
+		Text: fmt.Sprintf("%s，您好：\n\n请在 24 小时内打开以下链接完成邮箱验证：\n%s\n\n如果这不是您的操作，请忽略本邮件。",
+			displayName(user), link),
+		HTML: mailHTML(displayName(user), "请在 24 小时内点击下方按钮完成邮箱验证。", "验证邮箱", link),
+	})
+}
+
+// VerifyEmail 校验邮件中的令牌并标记邮箱已验证
+func (s *AccountEmailService) VerifyEmail(token string) (*models.User, error) {
+	claims, err := utils.ParseActionToken(token, utils.ActionVerifyEmail)
+	if err != nil {
+		return nil, err
+	}
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrActionTokenInvalid
+		}
+		return nil, err
+	}
+	// 签发后修改过邮箱的，旧链接作废
+	if claims.Fingerprint != utils.ActionFingerprint(user.Email) {
+		return nil, utils.ErrActionTokenInvalid
+	}
+	if user.EmailVerifiedAt != nil {
+		return &user, nil
+	}
+	now := time.Now()
+	if err := database.DB.Model(&user).Update("email_verified_at", now).Error; err != nil {
+		return nil, err
+	}
+	user.EmailVerifiedAt = &now
+	return &user, nil
+}
+
+// RequestPasswordReset 发送重置密码邮件；邮箱不存在时同样返回成功，避免暴露注册信息
+func (s *AccountEmailService) RequestPasswordReset(email string) error {
+	var user models.User
+	if err := database.DB.Where("email = ?", strings.TrimSpace(email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.Status != 1 {
+		return nil
+	}
+	if err := s.throttle(utils.ActionResetPassword, user.ID); err != nil {
+		if errors.Is(err, ErrMailTooFrequent) {
+			return nil
+		}
+		return err
+	}
+
+	// 令牌绑定当前密码哈希，密码修改后链接自动失效
+	token, err := utils.GenerateActionToken(utils.ActionResetPassword, user.ID, user.Password, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	siteName := siteName()
+	link := publicURL("/reset-password?token=" + token)
+	return s.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: fmt.Sprintf("【%s】重置密码", siteName),
+		Text: fmt.Sprintf("%s，您好：\n\n我们收到了重置密码的请求，请在 30 分钟内打开以下链接设置新密码：\n%s\n\n如果这不是您的操作，请忽略本邮件，您的密码不会改变。",
+			displayName(&user), link),
+		HTML: mailHTML(displayName(&user), "我们收到了重置密码的请求，请在 30 分钟内点击下方按钮设置新密码。如果这不是您的操作，请忽略本邮件。", "重置密码", link),
+	})
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码，并注销该用户所有会话
+func (s *AccountEmailService) ResetPassword(token, newPassword string) error {
+	claims, err := utils.ParseActionToken(token, utils.ActionResetPassword)
+	if err != nil {
+		return err
+	}
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrActionTokenInvalid
+		}
+		return err
+	}
+	if claims.Fingerprint != utils.ActionFingerprint(user.Password) {
+		return utils.ErrActionTokenInvalid
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	// 能收到重置邮件说明邮箱可用，顺便标记为已验证
+	updates := map[string]interface{}{"password": hashedPassword}
+	if user.EmailVerifiedAt == nil {
+		updates["email_verified_at"] = time.Now()
+	}
+	if err := database.DB.Model(&user).Updates(updates).Error; err != nil {
+		return err
+	}
+	return NewTokenService().RevokeAll(user.ID)
+}
+
+func (s *AccountEmailService) throttle(purpose string, userID uint) error {
+	key := fmt.Sprintf("mail:throttle:%s:%d", purpose, userID)
+	ok, err := database.RDB.SetNX(database.Ctx, key, 1, accountMailInterval).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrMailTooFrequent
+	}
+	return nil
+}
+
+// EnsureEmailVerified 开启“邮箱验证”开关后，未验证邮箱的账号不能评论和发布内容（后台账号除外）
+func EnsureEmailVerified(userID uint) error {
+	if !NewSettingService().GetBool(models.SettingEmailVerifyRequired, false) {
+		return nil
+	}
+	var user models.User
+	if err := database.DB.Select("id", "role", "email_verified_at").First(&user, userID).Error; err != nil {
+		return err
+	}
+	if NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess) || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return ErrEmailNotVerified
+}
+
+// sendVerificationAsync 注册或修改邮箱后异步发送验证邮件，失败只记录日志
+func sendVerificationAsync(user *models.User) {
+	go func() {
+		if err := NewAccountEmailService().SendVerification(user); err != nil {
+			log.Printf("发送验证邮件失败: 用户%d, 错误: %v", user.ID, err)
+		}
+	}()
+}
+
+func siteName() string {
+	if setting, err := NewSettingService().Get(models.SettingSiteName); err == nil && setting.Value != "" {
+		return setting.Value
+	}
+	return "InkSpace"
+}
+
+func publicURL(path string) string {
+	return strings.TrimRight(config.AppConfig.Server.PublicURL, "/") + path
+}
+
+func displayName(user *models.User) string {
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
+
+func mailHTML(name, message, action, link string) string {
+	return fmt.Sprintf(`<div style="max-width:520px;margin:0 auto;font-family:sans-serif;color:#303133;line-height:1.7">
+<p>%s，您好：</p>
+<p>%s</p>
+<p><a href="%s" style="display:inline-block;padding:10px 24px;background:#409eff;color:#fff;text-decoration:none;border-radius:4px">%s</a></p>
+<p style="color:#909399;font-size:13px">如果按钮无法点击，请复制以下链接到浏览器打开：<br>%s</p>
+</div>`, html.EscapeString(name), html.EscapeString(message), html.EscapeString(link), action, html.EscapeString(link))
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultExportDir         = "./storage/exports"
+	defaultExportExpireHours = 72
+	accountExportInterval    = time.Hour        // 同一账号两次导出的最小间隔
+	accountExportStaleAfter  = 30 * time.Minute // 超过该时间仍未完成的任务视为中断
+)
+
+var (
+	ErrExportInProgress  = errors.New("已有导出任务正在进行，请稍后查看")
+	ErrExportTooFrequent = errors.New("导出过于频繁，请一小时后再试")
+	ErrExportNotFound    = errors.New("导出任务不存在")
+	ErrExportNotReady    = errors.New("导出文件尚未生成或已过期")
+)
+
+// AccountExportService 个人数据导出：把资料、文章、作品、评论、收藏、关注、通知和知识库打包为 zip
+type AccountExportService struct{}
+
+func NewAccountExportService() *AccountExportService {
+	return &AccountExportService{}
+}
+
+func accountExportDir() string {
+	if config.AppConfig != nil && config.AppConfig.Account.ExportDir != "" {
+		return config.AppConfig.Account.ExportDir
+	}
+	return defaultExportDir
+}
+
+func accountExportTTL() time.Duration {
+	if config.AppConfig != nil && config.AppConfig.Account.ExportExpireHours > 0 {
+		return time.Duration(config.AppConfig.Account.ExportExpireHours) * time.Hour
+	}
+	return defaultExportExpireHours * time.Hour
+}
+
+// Request 创建导出任务并在后台生成压缩包
+func (s *AccountExportService) Request(userID uint) (*models.AccountExport, error) {
+	var latest models.AccountExport
+	err := database.DB.Where("user_id = ?", userID).Order("id DESC").First(&latest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		active := latest.Status == models.AccountExportPending || latest.Status == models.AccountExportProcessing
+		if active && time.Since(latest.CreatedAt) < accountExportStaleAfter {
+			return nil, ErrExportInProgress
+		}
+		if latest.Status == models.AccountExportReady && time.Since(latest.CreatedAt) < accountExportInterval {
+			return nil, ErrExportTooFrequent
+		}
+	}
+
+	export := &models.AccountExport{UserID: userID, Status: models.AccountExportPending}
+	if err := database.DB.Create(export).Error; err != nil {
+		return nil, err
+	}
+	go s.process(export.ID)
+	return export, nil
+}
+
+// List 最近的导出任务
+func (s *AccountExportService) List(userID uint) ([]*models.AccountExport, error) {
+	var exports []*models.AccountExport
+	if err := database.DB.Where("user_id = ?", userID).Order("id DESC").Limit(5).Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// File 返回可下载的导出文件路径
+func (s *AccountExportService) File(userID, id uint) (*models.AccountExport, error) {
+	var export models.AccountExport
+	if err := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	if export.Status != models.AccountExportReady || export.ExpiresAt == nil || time.Now().After(*export.ExpiresAt) {
+		return nil, ErrExportNotReady
+	}
+	return &export, nil
+}
+
+// CleanExpired 删除过期的导出文件，并把中断的任务标记为失败
+func (s *AccountExportService) CleanExpired() error {
+	var expired []*models.AccountExport
+	if err := database.DB.Where("expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		return err
+	}
+	for _, export := range expired {
+		removeExportFile(export.FilePath)
+		if err := database.DB.Delete(export).Error; err != nil {
+			return err
+		}
+	}
+	return database.DB.Model(&models.AccountExport{}).
+		Where("status IN ? AND created_at < ?",
+			[]string{models.AccountExportPending, models.AccountExportProcessing}, time.Now().Add(-accountExportStaleAfter)).
+		Updates(map[string]interface{}{"status": models.AccountExportFailed, "error": "导出任务中断，请重新导出"}).Error
+}
+
+func (s *AccountExportService) process(id uint) {
+	var export models.AccountExport
+	if err := database.DB.First(&export, id).Error; err != nil {
+		log.Printf("读取导出任务失败: %d, 错误: %v", id, err)
+		return
+	}
+	database.DB.Model(&export).Update("status", models.AccountExportProcessing)
+
+	path, size, err := s.build(export.UserID)
+	if err != nil {
+		log.Printf("生成导出文件失败: 用户 %d, 错误: %v", export.UserID, err)
+		database.DB.Model(&export).Updates(map[string]interface{}{
+			"status": models.AccountExportFailed,
+			"error":  "生成导出文件失败，请稍后重试",
+		})
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(accountExportTTL())
+	database.DB.Model(&export).Updates(map[string]interface{}{
+		"status":       models.AccountExportReady,
+		"file_path":    path,
+		"file_size":    size,
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	})
+}
+
+func (s *AccountExportService) build(userID uint) (string, int64, error) {
+	dir := accountExportDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", 0, err
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("user-%d-%s.zip", userID, hex.EncodeToString(suffix)))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+	archive := zip.NewWriter(file)
+	err = writeAccountArchive(archive, userID)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		removeExportFile(path)
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+func writeAccountArchive(archive *zip.Writer, userID uint) error {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	var identities []*models.UserIdentity
+	if err := database.DB.Where("user_id = ?", userID).Find(&identities).Error; err != nil {
+		return err
+	}
+	var appearance *models.UserAppearance
+	var record models.UserAppearance
+	if err := database.DB.Where("user_id = ?", userID).First(&record).Error; err == nil {
+		appearance = &record
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err := writeArchiveJSON(archive, "profile.json", map[string]interface{}{
+		"user":       user.ToResponse(),
+		"identities": identities,
+		"appearance": appearance,
+	}); err != nil {
+		return err
+	}
+
+	writers := []func(*zip.Writer, uint) error{
+		exportArticles, exportWorks, exportComments, exportFavorites,
+		exportFollows, exportNotifications, exportWorkspaces,
+	}
+	for _, write := range writers {
+		if err := write(archive, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportArticles 每篇文章一个 Markdown 文件，文件头部为 YAML 元数据
+func exportArticles(archive *zip.Writer, userID uint) error {
+	var articles []*models.Article
+	return database.DB.Preload("Category").Preload("Tags").Where("author_id = ?", userID).
+		FindInBatches(&articles, 100, func(tx *gorm.DB, _ int) error {
+			for _, article := range articles {
+				var meta strings.Builder
+				fmt.Fprintf(&meta, "---\ntitle: %s\n", yamlString(article.Title))
+				fmt.Fprintf(&meta, "date: %s\n", article.CreatedAt.Format(time.RFC3339))
+				fmt.Fprintf(&meta, "updated: %s\n", article.UpdatedAt.Format(time.RFC3339))
+				fmt.Fprintf(&meta, "status: %s\n", articleStatusName(article.Status))
+				if article.Category != nil {
+					fmt.Fprintf(&meta, "category: %s\n", yamlString(article.Category.Name))
+				}
+				if len(article.Tags) > 0 {
+					tags := make([]string, len(article.Tags))
+					for i, tag := range article.Tags {
+						tags[i] = yamlString(tag.Name)
+					}
+					fmt.Fprintf(&meta, "tags: [%s]\n", strings.Join(tags, ", "))
+				}
+				if article.Summary != "" {
+					fmt.Fprintf(&meta, "summary: %s\n", yamlString(article.Summary))
+				}
+				if article.Cover != "" {
+					fmt.Fprintf(&meta, "cover: %s\n", yamlString(article.Cover))
+				}
+				if !article.IsOriginal && article.SourceURL != "" {
+					fmt.Fprintf(&meta, "source: %s\n", yamlString(article.SourceURL))
+				}
+				meta.WriteString("---\n\n")
+
+				name := fmt.Sprintf("articles/%s.md", archiveName(article.ID, article.Title))
+				if err := writeArchiveFile(archive, name, meta.String()+article.Content); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+func exportWorks(archive *zip.Writer, userID uint) error {
+	var works []*models.Work
+	if err := database.DB.Where("author_id = ?", userID).Order("id ASC").Find(&works).Error; err != nil {
+		return err
+	}
+	return writeArchiveJSON(archive, "works.json", works)
+}
+
+func exportComments(archive *zip.Writer, userID uint) error {
+	var comments []*models.Comment
+	if err := database.DB.Where("user_id = ?", userID).Order("id ASC").Find(&comments).Error; err != nil {
+		return err
+	}
+	type exportedComment struct {
+		ID        uint      `json:"id"`
+		ArticleID *uint     `json:"article_id,omitempty"`
+		WorkID    *uint     `json:"work_id,omitempty"`
+		ParentID  *uint     `json:"parent_id,omitempty"`
+		Content   string    `json:"content"`
+		Status    int       `json:"status"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	list := make([]exportedComment, len(comments))
+	for i, comment := range comments {
+		list[i] = exportedComment{comment.ID, comment.ArticleID, comment.WorkID, comment.ParentID,
+			comment.Content, comment.Status, comment.CreatedAt}
+	}
+	return writeArchiveJSON(archive, "comments.json", list)
+}
+
+func exportFavorites(archive *zip.Writer, userID uint) error {
+	type favoriteItem struct {
+		ID        uint      `json:"id"`
+		Title     string    `json:"title"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	var articles []favoriteItem
+	if err := database.DB.Table("article_favorites").
+		Select("articles.id, articles.title, article_favorites.created_at").
+		Joins("JOIN articles ON articles.id = article_favorites.article_id").
+		Where("article_favorites.user_id = ? AND article_favorites.deleted_at IS NULL", userID).
+		Order("article_favorites.id ASC").Scan(&articles).Error; err != nil {
+		return err
+	}
+	var works []favoriteItem
+	if err := database.DB.Table("favorites").
+		Select("works.id, works.title, favorites.created_at").
+		Joins("JOIN works ON works.id = favorites.work_id").
+		Where("favorites.user_id = ? AND favorites.deleted_at IS NULL", userID).
+		Order("favorites.id ASC").Scan(&works).Error; err != nil {
+		return err
+	}
+	return writeArchiveJSON(archive, "favorites.json", map[string]interface{}{
+		"articles": articles,
+		"works":    works,
+	})
+}
+
+func exportFollows(archive *zip.Writer, userID uint) error {
+	type followItem struct {
+		ID        uint      `json:"id"`
+		Username  string    `json:"username"`
+		Nickname  string    `json:"nickname"`
+		CreatedAt time.Time `json:"followed_at"`
+	}
+	query := func(join, where string) ([]followItem, error) {
+		var items []followItem
+		err := database.DB.Table("user_follows").
+			Select("users.id, users.username, users.nickname, user_follows.created_at").
+			Joins("JOIN users ON users.id = user_follows."+join).
+			Where("user_follows."+where+" = ? AND user_follows.deleted_at IS NULL", userID).
+			Order("user_follows.id ASC").Scan(&items).Error
+		return items, err
+	}
+
+	following, err := query("following_id", "follower_id")
+	if err != nil {
+		return err
+	}
+	followers, err := query("follower_id", "following_id")
+	if err != nil {
+		return err
+	}
+	return writeArchiveJSON(archive, "follows.json", map[string]interface{}{
+		"following": following,
+		"followers": followers,
+	})
+}
+
+func exportNotifications(archive *zip.Writer, userID uint) error {
+	var notifications []*models.Notification
+	if err := database.DB.Where("user_id = ?", userID).Order("id ASC").Find(&notifications).Error; err != nil {
+		return err
+	}
+	return writeArchiveJSON(archive, "notifications.json", notifications)
+}
+
+// exportWorkspaces 知识库结构写入 workspaces.json，文档正文按知识库分目录保存为 Markdown
+func exportWorkspaces(archive *zip.Writer, userID uint) error {
+	var workspaces []*models.Workspace
+	if err := database.DB.Where("owner_id = ?", userID).Order("id ASC").Find(&workspaces).Error; err != nil {
+		return err
+	}
+	var catalogs []*models.Catalog
+	if err := database.DB.Where("owner_id = ?", userID).Order("id ASC").Find(&catalogs).Error; err != nil {
+		return err
+	}
+
+	type docItem struct {
+		ID          uint       `json:"id"`
+		WorkspaceID uint       `json:"workspace_id"`
+		CatalogID   *uint      `json:"catalog_id"`
+		Title       string     `json:"title"`
+		Status      int        `json:"status"`
+		File        string     `json:"file"`
+		PublishedAt *time.Time `json:"published_at"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+	}
+	workspaceDirs := make(map[uint]string, len(workspaces))
+	for _, workspace := range workspaces {
+		workspaceDirs[workspace.ID] = "workspaces/" + archiveName(workspace.ID, workspace.Name)
+	}
+
+	var items []docItem
+	var docs []*models.Doc
+	if err := database.DB.Where("owner_id = ?", userID).Order("id ASC").
+		FindInBatches(&docs, 100, func(tx *gorm.DB, _ int) error {
+			for _, doc := range docs {
+				dir, ok := workspaceDirs[doc.WorkspaceID]
+				if !ok {
+					dir = "workspaces/unknown"
+				}
+				name := fmt.Sprintf("%s/%s.md", dir, archiveName(doc.ID, doc.Title))
+				if err := writeArchiveFile(archive, name, doc.Content); err != nil {
+					return err
+				}
+				items = append(items, docItem{doc.ID, doc.WorkspaceID, doc.CatalogID, doc.Title, doc.Status,
+					name, doc.PublishedAt, doc.CreatedAt, doc.UpdatedAt})
+			}
+			return nil
+		}).Error; err != nil {
+		return err
+	}
+
+	return writeArchiveJSON(archive, "workspaces.json", map[string]interface{}{
+		"workspaces": workspaces,
+		"catalogs":   catalogs,
+		"docs":       items,
+	})
+}
+
+func writeArchiveJSON(archive *zip.Writer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeArchiveFile(archive, name, string(data))
+}
+
+func writeArchiveFile(archive *zip.Writer, name, content string) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+// archiveName 生成压缩包内的文件名：ID 加标题，去掉路径分隔符等不安全字符
+func archiveName(id uint, title string) string {
+	var b strings.Builder
+	count := 0
+	for _, r := range strings.TrimSpace(title) {
+		if count >= 50 {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+		count++
+	}
+	name := strings.Trim(b.String(), "-")
+	if name == "" {
+		return fmt.Sprintf("%d", id)
+	}
+	return fmt.Sprintf("%d-%s", id, name)
+}
+
+// yamlString 以双引号输出 YAML 字符串
+func yamlString(value string) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func articleStatusName(status int) string {
+	switch status {
+	case 0:
+		return "draft"
+	case 2:
+		return "private"
+	case 3:
+		return "scheduled"
+	default:
+		return "published"
+	}
+}
+
+func removeExportFile(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("删除导出文件失败: %s, 错误: %v", path, err)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		id    uint
+		title string
+		want  string
+	}{
+		{1, "Hello World", "1-Hello-World"},
+		{2, "../../etc/passwd", "2-etc-passwd"},
+		{3, "  Go 并发：channel 详解  ", "3-Go-并发-channel-详解"},
+		{4, "???", "4"},
+		{5, "", "5"},
+	}
+	for _, test := range tests {
+		if got := archiveName(test.id, test.title); got != test.want {
+			t.Errorf("archiveName(%d, %q) = %q, want %q", test.id, test.title, got, test.want)
+		}
+	}
+}
+
+func TestWriteArchiveJSON(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	if err := writeArchiveJSON(archive, "follows.json", map[string][]string{"following": {"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reader.File) != 1 || reader.File[0].Name != "follows.json" {
+		t.Fatalf("files = %v", reader.File)
+	}
+	f, err := reader.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, _ := io.ReadAll(f)
+	if want := "{\n  \"following\": [\n    \"alice\"\n  ]\n}"; string(data) != want {
+		t.Errorf("content = %q, want %q", data, want)
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	if got := yamlString(`say "hi": yes`); got != `"say \"hi\": yes"` {
+		t.Errorf("yamlString = %s", got)
+	}
+}
+
+func TestArticleStatusName(t *testing.T) {
+	tests := map[int]string{
+		models.ArticleStatusDraft:     "draft",
+		models.ArticleStatusPublished: "published",
+		models.ArticleStatusPrivate:   "private",
+		models.ArticleStatusScheduled: "scheduled",
+	}
+	for status, want := range tests {
+		if got := articleStatusName(status); got != want {
+			t.Errorf("articleStatusName(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
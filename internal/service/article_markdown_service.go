@@ -0,0 +1,557 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/frontmatter"
+	"github.com/iceymoss/inkspace/pkg/uploader"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxMarkdownImportFiles = 1000    // 单个压缩包最多导入的 Markdown 文件数
+	maxMarkdownFileSize    = 2 << 20 // 单个 Markdown 文件大小上限
+	maxMarkdownImageSize   = 5 << 20 // 与图片上传接口的限制一致
+)
+
+var (
+	ErrMarkdownArchiveInvalid = errors.New("无法读取压缩包，请上传 zip 文件")
+	ErrMarkdownArchiveEmpty   = errors.New("压缩包中没有 Markdown 文件")
+	ErrMarkdownArchiveTooMany = errors.New("单次最多导入 1000 篇文章")
+)
+
+var (
+	// ![alt](src "title") 和 ![alt](<src>)，第二个分组为图片地址
+	markdownImagePattern = regexp.MustCompile(`(!\[[^\]]*\]\()(<[^>]+>|[^)\s]+)`)
+	// <img src="...">
+	htmlImagePattern = regexp.MustCompile(`(<img\b[^>]*?\bsrc=["'])([^"']+)`)
+	// Jekyll 的文件名格式：2006-01-02-slug.md
+	datedPostName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+	markdownImageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true}
+)
+
+// ArticleMarkdownService 文章的 Markdown 批量导入导出，兼容 Hugo、Hexo、Jekyll 的元数据头
+type ArticleMarkdownService struct{}
+
+func NewArticleMarkdownService() *ArticleMarkdownService {
+	return &ArticleMarkdownService{}
+}
+
+// Import 导入 zip 压缩包中的 Markdown 文件，每个文件一篇文章。
+// 单个文件失败不影响其他文件，结果中逐个列出；作者已有同名文章时跳过。
+// 没有分类管理权限的角色只能使用已有分类，不存在的分类转为作者自己的标签
+func (s *ArticleMarkdownService) Import(authorID uint, role string, r io.ReaderAt, size int64) (*models.ArticleImportResult, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrMarkdownArchiveInvalid
+	}
+	archive := newMarkdownArchive(reader.File)
+	if len(archive.posts) == 0 {
+		return nil, ErrMarkdownArchiveEmpty
+	}
+	if len(archive.posts) > maxMarkdownImportFiles {
+		return nil, ErrMarkdownArchiveTooMany
+	}
+
+	// 开启邮箱验证且未验证时仍可导入，但只能保存为草稿
+	canPublish := true
+	if err := EnsureEmailVerified(authorID); err != nil {
+		if !errors.Is(err, ErrEmailNotVerified) {
+			return nil, err
+		}
+		canPublish = false
+	}
+
+	importer := &markdownImporter{
+		authorID:          authorID,
+		canPublish:        canPublish,
+		canCreateCategory: NewRoleService().HasPermission(role, models.PermissionTaxonomyManage),
+		archive:           archive,
+		uploader:          (&uploader.UploadProvider{}).NewUploadProvider(),
+		uploaded:          make(map[string]string),
+		categories:        make(map[string]uint),
+		tags:              make(map[string]models.Tag),
+	}
+	result := &models.ArticleImportResult{Items: make([]*models.ArticleImportItem, 0, len(archive.posts))}
+	for _, name := range archive.posts {
+		item := importer.importPost(name)
+		switch item.Status {
+		case models.ArticleImportImported:
+			result.Imported++
+		case models.ArticleImportSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	if result.Imported > 0 {
+		if err := database.DeleteCachePattern("article:*"); err != nil {
+			log.Printf("导入文章后清理缓存失败: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// Export 把作者的全部文章导出为 zip，每篇文章一个带 YAML 元数据头的 Markdown 文件，可以原样再导入。
+// 返回导出的文章数
+func (s *ArticleMarkdownService) Export(authorID uint, w io.Writer) (int, error) {
+	archive := zip.NewWriter(w)
+	count := 0
+	used := make(map[string]bool)
+
+	var articles []*models.Article
+	err := database.DB.Preload("Category").Preload("Tags").Where("author_id = ?", authorID).Order("id ASC").
+		FindInBatches(&articles, 100, func(tx *gorm.DB, _ int) error {
+			for _, article := range articles {
+				data, err := articlePost(article).Marshal()
+				if err != nil {
+					return err
+				}
+				name := article.Slug
+				if name == "" || used[name] {
+					name = archiveName(article.ID, article.Title)
+				}
+				used[name] = true
+				if err := writeArchiveFile(archive, "posts/"+name+".md", string(data)); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, archive.Close()
+}
+
+// articlePost 文章转换为 Markdown 文件。定时发布的文章导出为未来日期，私有文章和草稿导出为 draft
+func articlePost(article *models.Article) *frontmatter.Post {
+	post := &frontmatter.Post{
+		Title:   article.Title,
+		Date:    article.CreatedAt,
+		Updated: article.UpdatedAt,
+		Slug:    article.Slug,
+		Draft:   article.Status != models.ArticleStatusPublished && article.Status != models.ArticleStatusScheduled,
+		Summary: article.Summary,
+		Cover:   article.Cover,
+		Body:    article.Content,
+	}
+	if article.PublishAt != nil {
+		post.Date = *article.PublishAt
+	}
+	if article.Category != nil {
+		post.Categories = []string{article.Category.Name}
+	}
+	for _, tag := range article.Tags {
+		post.Tags = append(post.Tags, tag.Name)
+	}
+	return post
+}
+
+// markdownArchive 压缩包内的文件，路径统一为 / 分隔且不带前导 /
+type markdownArchive struct {
+	files map[string]*zip.File
+	posts []string // Markdown 文件，按路径排序
+}
+
+func newMarkdownArchive(files []*zip.File) *markdownArchive {
+	archive := &markdownArchive{files: make(map[string]*zip.File, len(files))}
+	for _, f := range files {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(f.Name, "\\", "/")), "/")
+		if hiddenArchivePath(name) {
+			continue
+		}
+		archive.files[name] = f
+
+		// Hugo 的 _index.md 是列表页，不是文章
+		ext := strings.ToLower(path.Ext(name))
+		if (ext == ".md" || ext == ".markdown") && path.Base(name) != "_index.md" {
+			archive.posts = append(archive.posts, name)
+		}
+	}
+	sort.Strings(archive.posts)
+	return archive
+}
+
+// hiddenArchivePath macOS 打包时附带的 __MACOSX 目录和以 . 开头的隐藏文件
+func hiddenArchivePath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if part == "__MACOSX" || strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve 在压缩包中查找 Markdown 引用的图片，dir 为 Markdown 文件所在目录。
+// 相对路径相对于 dir；以 / 开头的路径依次尝试站点根目录、Hugo 的 static 和 Hexo 的 source 目录；
+// 都找不到时按路径后缀匹配（站点放在压缩包的子目录中时），取最短的路径。没有找到返回空字符串
+func (a *markdownArchive) resolve(dir, src string) string {
+	if i := strings.IndexAny(src, "?#"); i >= 0 {
+		src = src[:i]
+	}
+	if unescaped, err := url.PathUnescape(src); err == nil {
+		src = unescaped
+	}
+	if src == "" || !markdownImageExts[strings.ToLower(path.Ext(src))] {
+		return ""
+	}
+
+	var candidates []string
+	if strings.HasPrefix(src, "/") {
+		p := strings.TrimPrefix(path.Clean(src), "/")
+		candidates = []string{p, "static/" + p, "source/" + p}
+	} else {
+		candidates = []string{path.Join(dir, src)}
+	}
+	for _, candidate := range candidates {
+		if _, ok := a.files[candidate]; ok {
+			return candidate
+		}
+	}
+
+	suffix := path.Clean("/" + src)
+	best := ""
+	for name := range a.files {
+		if !strings.HasSuffix("/"+name, suffix) {
+			continue
+		}
+		if best == "" || len(name) < len(best) || (len(name) == len(best) && name < best) {
+			best = name
+		}
+	}
+	return best
+}
+
+// rewriteMarkdownImages 替换正文中 Markdown 和 HTML 写法的图片地址。
+// replace 返回空字符串时保留原地址；返回替换的图片数
+func rewriteMarkdownImages(content string, replace func(src string) string) (string, int) {
+	count := 0
+	for _, pattern := range []*regexp.Regexp{markdownImagePattern, htmlImagePattern} {
+		content = pattern.ReplaceAllStringFunc(content, func(match string) string {
+			groups := pattern.FindStringSubmatch(match)
+			src := strings.TrimSuffix(strings.TrimPrefix(groups[2], "<"), ">")
+			target := replace(src)
+			if target == "" {
+				return match
+			}
+			count++
+			return groups[1] + target
+		})
+	}
+	return content, count
+}
+
+// isRemoteImage 外部地址和内联图片不需要上传
+func isRemoteImage(src string) bool {
+	lower := strings.ToLower(src)
+	return strings.Contains(lower, "://") || strings.HasPrefix(lower, "//") || strings.HasPrefix(lower, "data:")
+}
+
+// postFileInfo 从文件路径推断默认的标题、slug、日期和草稿状态，元数据头中有对应字段时以元数据为准。
+// 兼容 Hugo 的 page bundle（目录名/index.md）、Jekyll 的日期前缀文件名和 _drafts 目录
+func postFileInfo(name string) (title, slug string, date time.Time, draft bool) {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	dir := path.Dir(name)
+	if strings.EqualFold(base, "index") && dir != "." {
+		base = path.Base(dir)
+	}
+	if m := datedPostName.FindStringSubmatch(base); m != nil {
+		if t, err := time.ParseInLocation("2006-01-02", m[1], time.Local); err == nil {
+			date = t
+			base = m[2]
+		}
+	}
+	for _, part := range strings.Split(dir, "/") {
+		if part == "_drafts" {
+			draft = true
+		}
+	}
+	return base, base, date, draft
+}
+
+func readArchiveFile(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("文件大小不能超过 %d MB", limit>>20)
+	}
+	return data, nil
+}
+
+// markdownImporter 一次导入过程中的状态，图片、分类和标签在多篇文章间复用
+type markdownImporter struct {
+	authorID          uint
+	canPublish        bool
+	canCreateCategory bool // 分类是全站共享的，只有分类管理权限才能在导入时创建
+	archive           *markdownArchive
+	uploader          uploader.Uploader
+	uploaded          map[string]string // 压缩包内路径 -> 上传后的地址
+	categories        map[string]uint
+	tags              map[string]models.Tag
+}
+
+func (im *markdownImporter) importPost(name string) *models.ArticleImportItem {
+	item := &models.ArticleImportItem{File: name}
+	fail := func(err error) *models.ArticleImportItem {
+		item.Status = models.ArticleImportFailed
+		item.Message = err.Error()
+		return item
+	}
+
+	data, err := readArchiveFile(im.archive.files[name], maxMarkdownFileSize)
+	if err != nil {
+		return fail(err)
+	}
+	post, err := frontmatter.Parse(data)
+	if err != nil {
+		return fail(err)
+	}
+
+	title, slug, date, draft := postFileInfo(name)
+	if post.Title != "" {
+		title = post.Title
+	}
+	if post.Slug != "" {
+		slug = post.Slug
+	}
+	if !post.Date.IsZero() {
+		date = post.Date
+	}
+	draft = draft || post.Draft
+	item.Title = truncateRunes(strings.TrimSpace(title), 200)
+
+	if strings.TrimSpace(post.Body) == "" {
+		item.Status = models.ArticleImportSkipped
+		item.Message = "正文为空"
+		return item
+	}
+	var count int64
+	if err := database.DB.Model(&models.Article{}).
+		Where("author_id = ? AND title = ?", im.authorID, item.Title).
+		Count(&count).Error; err != nil {
+		return fail(err)
+	}
+	if count > 0 {
+		item.Status = models.ArticleImportSkipped
+		item.Message = "已存在同名文章"
+		return item
+	}
+
+	// 上传正文和封面中引用的本地图片
+	dir := path.Dir(name)
+	missing := 0
+	upload := func(src string) string {
+		src = strings.TrimSpace(src)
+		if src == "" || isRemoteImage(src) {
+			return ""
+		}
+		target, err := im.upload(dir, src)
+		if err != nil {
+			missing++
+		}
+		return target
+	}
+	content, images := rewriteMarkdownImages(post.Body, upload)
+	item.Images = images
+	cover := post.Cover
+	if target := upload(cover); target != "" {
+		cover = target
+	}
+
+	// 第一个分类作为文章分类，其余的（如 Hexo 的多级分类）作为标签；
+	// 第一个分类不存在且无权创建时，全部分类都作为标签
+	var messages []string
+	var categoryID uint
+	tagNames := post.Tags
+	if len(post.Categories) > 0 {
+		var found bool
+		if categoryID, found, err = im.category(post.Categories[0]); err != nil {
+			return fail(err)
+		}
+		if found {
+			tagNames = append(tagNames, post.Categories[1:]...)
+		} else {
+			tagNames = append(tagNames, post.Categories...)
+			messages = append(messages, fmt.Sprintf("分类“%s”不存在，已作为标签导入", strings.TrimSpace(post.Categories[0])))
+		}
+	}
+	tags, err := im.findTags(tagNames)
+	if err != nil {
+		return fail(err)
+	}
+
+	now := time.Now()
+	if date.IsZero() {
+		date = now
+	}
+	updated := post.Updated
+	if updated.IsZero() {
+		updated = date
+	}
+
+	status := models.ArticleStatusPublished
+	switch {
+	case draft:
+		status = models.ArticleStatusDraft
+	case !im.canPublish:
+		status = models.ArticleStatusDraft
+		messages = append(messages, "邮箱未验证，已保存为草稿")
+	case date.After(now):
+		// 未来日期的文章与 Hugo 默认行为一致，到时间后再发布
+		status = models.ArticleStatusScheduled
+	}
+	var publishAt *time.Time
+	if status != models.ArticleStatusDraft {
+		publishAt = &date
+	}
+
+	article := &models.Article{
+		CreatedAt:  date,
+		UpdatedAt:  updated,
+		Title:      item.Title,
+		Content:    content,
+		Summary:    truncateRunes(post.Summary, 500),
+		Cover:      cover,
+		CategoryID: categoryID,
+		AuthorID:   im.authorID,
+		Status:     status,
+		PublishAt:  publishAt,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return createImportedArticle(tx, article, slug, tags, "导入")
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	NewSearchService().Sync(models.SearchTypeArticle, article.ID)
+
+	if missing > 0 {
+		messages = append(messages, fmt.Sprintf("%d 张图片未找到或上传失败，保留原地址", missing))
+	}
+	item.ArticleID = article.ID
+	item.Status = models.ArticleImportImported
+	item.Message = strings.Join(messages, "；")
+	return item
+}
+
+// upload 上传压缩包中的图片，同一张图片只上传一次
+func (im *markdownImporter) upload(dir, src string) (string, error) {
+	name := im.archive.resolve(dir, src)
+	if name == "" {
+		return "", fmt.Errorf("图片不存在: %s", src)
+	}
+	if target, ok := im.uploaded[name]; ok {
+		return target, nil
+	}
+
+	data, err := readArchiveFile(im.archive.files[name], maxMarkdownImageSize)
+	if err != nil {
+		return "", err
+	}
+	dstPath := fmt.Sprintf("images/%s/%s%s", time.Now().Format("2006/01/02"), uuid.New().String(), strings.ToLower(path.Ext(name)))
+	target, err := im.uploader.Upload(&uploader.UploadInput{
+		Reader: bytes.NewReader(data),
+		Size:   int64(len(data)),
+		Name:   path.Base(name),
+	}, dstPath)
+	if err != nil {
+		log.Printf("导入文章时上传图片失败: %s, 错误: %v", name, err)
+		return "", err
+	}
+	im.uploaded[name] = target
+	return target, nil
+}
+
+// category 按名称查找分类，不存在时有权限则创建；found 为 false 表示没有对应的分类
+func (im *markdownImporter) category(name string) (id uint, found bool, err error) {
+	name = truncateRunes(strings.TrimSpace(name), 50)
+	if name == "" {
+		return 0, true, nil
+	}
+	if id, ok := im.categories[name]; ok {
+		return id, true, nil
+	}
+
+	var category models.Category
+	err = database.DB.Where("name = ?", name).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !im.canCreateCategory {
+			return 0, false, nil
+		}
+		var slug string
+		if slug, err = uniqueCategorySlug(database.DB, name); err != nil {
+			return 0, false, err
+		}
+		category = models.Category{Name: name, Slug: slug}
+		err = database.DB.Create(&category).Error
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	im.categories[name] = category.ID
+	return category.ID, true, nil
+}
+
+// findTags 按名称查找作者自己的标签或公共标签，优先使用作者自己的；都不存在时创建作者的标签
+func (im *markdownImporter) findTags(names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	seen := make(map[uint]bool)
+	for _, name := range names {
+		name = truncateRunes(strings.TrimSpace(name), 50)
+		if name == "" {
+			continue
+		}
+		tag, ok := im.tags[name]
+		if !ok {
+			err := database.DB.Where("name = ? AND (user_id = ? OR user_id IS NULL)", name, im.authorID).
+				Order("user_id IS NULL").First(&tag).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				authorID := im.authorID
+				tag = models.Tag{Name: name, UserID: &authorID}
+				err = database.DB.Create(&tag).Error
+			}
+			if err != nil {
+				return nil, err
+			}
+			im.tags[name] = tag
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/frontmatter"
+)
+
+func testMarkdownArchive(t *testing.T, names ...string) *markdownArchive {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		if _, err := w.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newMarkdownArchive(r.File)
+}
+
+func TestMarkdownArchivePosts(t *testing.T) {
+	archive := testMarkdownArchive(t,
+		"site/content/posts/b.md",
+		"site/content/posts/_index.md",
+		"site/content/posts/a.markdown",
+		"__MACOSX/site/content/posts/._b.md",
+		"site/.git/README.md",
+		"site/static/images/a.png",
+	)
+	want := []string{"site/content/posts/a.markdown", "site/content/posts/b.md"}
+	if !reflect.DeepEqual(archive.posts, want) {
+		t.Fatalf("posts = %v, want %v", archive.posts, want)
+	}
+}
+
+func TestMarkdownArchiveResolve(t *testing.T) {
+	archive := testMarkdownArchive(t,
+		"content/posts/bundle/index.md",
+		"content/posts/bundle/cover.jpg",
+		"content/posts/hello.md",
+		"static/images/hello.png",
+		"source/_posts/hexo.md",
+		"source/img/hexo.gif",
+		"themes/demo/static/images/hello.png",
+		"static/files/doc.pdf",
+	)
+	tests := []struct {
+		dir, src, want string
+	}{
+		{"content/posts/bundle", "cover.jpg", "content/posts/bundle/cover.jpg"},
+		{"content/posts/bundle", "./cover.jpg?v=1", "content/posts/bundle/cover.jpg"},
+		{"content/posts", "/images/hello.png", "static/images/hello.png"},
+		{"source/_posts", "/img/hexo.gif", "source/img/hexo.gif"},
+		{"source/_posts", "../img/hexo.gif", "source/img/hexo.gif"},
+		{"content/posts", "../../images/hello.png", "static/images/hello.png"},
+		{"content/posts", "/files/doc.pdf", ""},
+		{"content/posts", "missing.png", ""},
+	}
+	for _, tt := range tests {
+		if got := archive.resolve(tt.dir, tt.src); got != tt.want {
+			t.Errorf("resolve(%q, %q) = %q, want %q", tt.dir, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteMarkdownImages(t *testing.T) {
+	content := "![a](img/a.png \"标题\")\n![b](<img/b c.png>)\n![remote](https://example.com/x.png)\n" +
+		"<img class=\"wide\" src='img/a.png'>\n[link](img/a.png)"
+	got, count := rewriteMarkdownImages(content, func(src string) string {
+		if isRemoteImage(src) {
+			return ""
+		}
+		return "/uploads/" + src
+	})
+	want := "![a](/uploads/img/a.png \"标题\")\n![b](/uploads/img/b c.png)\n![remote](https://example.com/x.png)\n" +
+		"<img class=\"wide\" src='/uploads/img/a.png'>\n[link](img/a.png)"
+	if got != want || count != 3 {
+		t.Fatalf("rewrite = %q (%d), want %q", got, count, want)
+	}
+}
+
+func TestPostFileInfo(t *testing.T) {
+	title, slug, date, draft := postFileInfo("_posts/2019-08-01-hello-world.md")
+	if title != "hello-world" || slug != "hello-world" || date.Format("2006-01-02") != "2019-08-01" || draft {
+		t.Fatalf("jekyll = %q %q %v %v", title, slug, date, draft)
+	}
+
+	title, _, date, _ = postFileInfo("content/posts/my-bundle/index.md")
+	if title != "my-bundle" || !date.IsZero() {
+		t.Fatalf("bundle = %q %v", title, date)
+	}
+
+	if _, _, _, draft = postFileInfo("source/_drafts/idea.md"); !draft {
+		t.Fatal("_drafts should be draft")
+	}
+}
+
+func TestArticlePostRoundTrip(t *testing.T) {
+	publishAt := time.Date(2022, 5, 6, 7, 8, 9, 0, time.UTC)
+	article := &models.Article{
+		CreatedAt: publishAt.Add(-time.Hour),
+		UpdatedAt: publishAt.Add(time.Hour),
+		Title:     "导出",
+		Slug:      "export",
+		Content:   "正文\n",
+		Summary:   "摘要",
+		Status:    models.ArticleStatusPrivate,
+		PublishAt: &publishAt,
+		Category:  &models.Category{Name: "后端"},
+		Tags:      []models.Tag{{Name: "Go"}, {Name: "测试"}},
+	}
+	data, err := articlePost(article).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := frontmatter.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Title != "导出" || post.Slug != "export" || !post.Draft || post.Body != "正文\n" {
+		t.Fatalf("post = %+v", post)
+	}
+	if !post.Date.Equal(publishAt) || !post.Updated.Equal(article.UpdatedAt) {
+		t.Fatalf("dates = %v %v", post.Date, post.Updated)
+	}
+	if !reflect.DeepEqual(post.Categories, []string{"后端"}) || !reflect.DeepEqual(post.Tags, []string{"Go", "测试"}) {
+		t.Fatalf("categories = %v tags = %v", post.Categories, post.Tags)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/diff"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 版本差异的比较粒度
+const (
+	DiffModeLine = "line"
+	DiffModeWord = "word"
+)
+
+var (
+	ErrArticleNotEditable      = errors.New("文章不存在或无权限查看")
+	ErrArticleRevisionNotFound = errors.New("版本不存在")
+)
+
+type ArticleRevisionService struct{}
+
+func NewArticleRevisionService() *ArticleRevisionService {
+	return &ArticleRevisionService{}
+}
+
+// List 返回文章的所有版本（不含正文），最新的在前
+func (s *ArticleRevisionService) List(articleID, userID uint, role string) ([]*models.ArticleRevision, error) {
+	if _, err := s.editable(database.DB, articleID, userID, role); err != nil {
+		return nil, err
+	}
+	var revisions []*models.ArticleRevision
+	err := database.DB.Omit("content").Where("article_id = ?", articleID).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// Get 返回指定版本的完整快照
+func (s *ArticleRevisionService) Get(articleID uint, version int, userID uint, role string) (*models.ArticleRevision, error) {
+	if _, err := s.editable(database.DB, articleID, userID, role); err != nil {
+		return nil, err
+	}
+	return findArticleRevision(database.DB, articleID, version)
+}
+
+// Diff 比较同一文章的两个版本
+func (s *ArticleRevisionService) Diff(articleID uint, from, to int, mode string, userID uint, role string) (*models.ArticleRevisionDiff, error) {
+	if _, err := s.editable(database.DB, articleID, userID, role); err != nil {
+		return nil, err
+	}
+	oldRevision, err := findArticleRevision(database.DB, articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	newRevision, err := findArticleRevision(database.DB, articleID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ArticleRevisionDiff{
+		From:    from,
+		To:      to,
+		Mode:    mode,
+		Title:   diffSegments(diff.Words(oldRevision.Title, newRevision.Title)),
+		Summary: diffSegments(diff.Words(oldRevision.Summary, newRevision.Summary)),
+	}
+	if mode == DiffModeWord {
+		result.Segments = diffSegments(diff.Words(oldRevision.Content, newRevision.Content))
+	} else {
+		result.Lines = diffLines(diff.Lines(oldRevision.Content, newRevision.Content))
+	}
+	return result, nil
+}
+
+// Restore 将文章恢复到指定版本，恢复本身也会生成一个新版本
+func (s *ArticleRevisionService) Restore(articleID uint, version int, userID uint, role string) (*models.Article, error) {
+	var article *models.Article
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		article, err = s.editable(tx.Clauses(clause.Locking{Strength: "UPDATE"}), articleID, userID, role)
+		if err != nil {
+			return err
+		}
+		snapshot, err := findArticleRevision(tx, articleID, version)
+		if err != nil {
+			return err
+		}
+		if err := ensureArticleBaseline(tx, article); err != nil {
+			return err
+		}
+
+		article.Title = snapshot.Title
+		article.Summary = snapshot.Summary
+		article.Cover = snapshot.Cover
+		article.Content = snapshot.Content
+		article.ContentHTML, article.ContentTOC, err = renderMarkdownWithTOC(article.Content)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Article{}).Where("id = ?", articleID).Updates(map[string]interface{}{
+			"title": article.Title, "summary": article.Summary, "cover": article.Cover, "content": article.Content,
+			"content_html": article.ContentHTML, "content_toc": article.ContentTOC,
+		}).Error; err != nil {
+			return err
+		}
+		return createArticleRevision(tx, article, userID, fmt.Sprintf("恢复自 v%d", version))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	database.DeleteCache(fmt.Sprintf("article:%d", articleID))
+	database.DeleteCachePattern("article:list:*")
+	NewSearchService().Sync(models.SearchTypeArticle, articleID)
+	invalidateSEOMeta(seoPageArticle, articleID)
+
+	var restored models.Article
+	if err := database.DB.Preload("Category").Preload("Tags").First(&restored, articleID).Error; err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// editable 查询文章，没有文章管理权限时只能访问自己的文章
+func (s *ArticleRevisionService) editable(db *gorm.DB, articleID, userID uint, role string) (*models.Article, error) {
+	query := db.Where("id = ?", articleID)
+	if !NewRoleService().HasPermission(role, models.PermissionArticleManage) {
+		query = query.Where("author_id = ?", userID)
+	}
+	var article models.Article
+	if err := query.First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArticleNotEditable
+		}
+		return nil, err
+	}
+	return &article, nil
+}
+
+func findArticleRevision(db *gorm.DB, articleID uint, version int) (*models.ArticleRevision, error) {
+	var revision models.ArticleRevision
+	if err := db.Where("article_id = ? AND version = ?", articleID, version).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArticleRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ensureArticleBaseline 为尚无版本记录的旧文章补一份修改前的快照，保证第一次修改也能比较和恢复
+func ensureArticleBaseline(tx *gorm.DB, article *models.Article) error {
+	var count int64
+	if err := tx.Model(&models.ArticleRevision{}).Where("article_id = ?", article.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	authorID := article.AuthorID
+	return tx.Create(&models.ArticleRevision{
+		ArticleID: article.ID, Version: 1, Title: article.Title, Summary: article.Summary,
+		Cover: article.Cover, Content: article.Content, EditorID: &authorID,
+		EditorName: editorName(tx, authorID), Remark: "初始版本", CreatedAt: article.UpdatedAt,
+	}).Error
+}
+
+// createArticleRevision 保存文章当前内容为新版本，内容与最新版本相同时不重复保存
+func createArticleRevision(tx *gorm.DB, article *models.Article, editorID uint, remark string) error {
+	var latest models.ArticleRevision
+	err := tx.Where("article_id = ?", article.ID).Order("version DESC").First(&latest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && latest.Title == article.Title && latest.Summary == article.Summary &&
+		latest.Cover == article.Cover && latest.Content == article.Content {
+		return nil
+	}
+	return tx.Create(&models.ArticleRevision{
+		ArticleID: article.ID, Version: latest.Version + 1, Title: article.Title, Summary: article.Summary,
+		Cover: article.Cover, Content: article.Content, EditorID: &editorID,
+		EditorName: editorName(tx, editorID), Remark: remark,
+	}).Error
+}
+
+func editorName(tx *gorm.DB, userID uint) string {
+	var user models.User
+	if err := tx.Select("username", "nickname").First(&user, userID).Error; err != nil {
+		return ""
+	}
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
+
+func diffSegments(edits []diff.Edit) []models.ArticleDiffSegment {
+	segments := make([]models.ArticleDiffSegment, len(edits))
+	for i, edit := range edits {
+		segments[i] = models.ArticleDiffSegment{Type: edit.Op.String(), Text: edit.Text}
+	}
+	return segments
+}
+
+func diffLines(edits []diff.Edit) []models.ArticleDiffLine {
+	lines := make([]models.ArticleDiffLine, 0, len(edits))
+	oldLine, newLine := 0, 0
+	for _, edit := range edits {
+		line := models.ArticleDiffLine{Type: edit.Op.String(), Text: strings.TrimRight(edit.Text, "\r\n")}
+		if edit.Op != diff.Insert {
+			oldLine++
+			line.OldLine = oldLine
+		}
+		if edit.Op != diff.Delete {
+			newLine++
+			line.NewLine = newLine
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+// 定时发布最远可提前的时间
+const maxScheduleAhead = 365 * 24 * time.Hour
+
+var (
+	ErrPublishAtRequired = errors.New("定时发布需要设置发布时间")
+	ErrPublishAtPast     = errors.New("定时发布时间必须晚于当前时间")
+	ErrPublishAtTooFar   = errors.New("定时发布时间不能超过一年")
+)
+
+// resolveArticleStatus 根据请求的状态和发布时间计算最终状态
+// 请求发布但发布时间在未来时按定时发布处理；草稿和私有文章忽略发布时间
+func resolveArticleStatus(status int, publishAt *time.Time, now time.Time) (int, *time.Time, error) {
+	switch status {
+	case models.ArticleStatusScheduled:
+		if publishAt == nil || publishAt.IsZero() {
+			return 0, nil, ErrPublishAtRequired
+		}
+		if !publishAt.After(now) {
+			return 0, nil, ErrPublishAtPast
+		}
+		if publishAt.After(now.Add(maxScheduleAhead)) {
+			return 0, nil, ErrPublishAtTooFar
+		}
+		return status, publishAt, nil
+	case models.ArticleStatusPublished:
+		if publishAt != nil && publishAt.After(now) {
+			return resolveArticleStatus(models.ArticleStatusScheduled, publishAt, now)
+		}
+		return status, nil, nil
+	default:
+		return status, nil, nil
+	}
+}
+
+// PublishDue 发布所有到期的定时文章，返回发布数量
+func (s *ArticleService) PublishDue() (int, error) {
+	var articles []models.Article
+	if err := database.DB.
+		Where("status = ? AND publish_at <= ?", models.ArticleStatusScheduled, time.Now()).
+		Order("publish_at ASC").
+		Find(&articles).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for i := range articles {
+		article := &articles[i]
+		// 以状态为条件更新，避免多个调度实例重复发布
+		result := database.DB.Model(&models.Article{}).
+			Where("id = ? AND status = ?", article.ID, models.ArticleStatusScheduled).
+			Update("status", models.ArticleStatusPublished)
+		if result.Error != nil {
+			return published, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		article.Status = models.ArticleStatusPublished
+		published++
+		database.DeleteCache(fmt.Sprintf("article:%d", article.ID))
+		NewSearchService().Sync(models.SearchTypeArticle, article.ID)
+		invalidateSEOMeta(seoPageArticle, article.ID)
+		s.onPublished(article)
+	}
+
+	if published > 0 {
+		database.DeleteCachePattern("article:list:*")
+	}
+	return published, nil
+}
+
+// onPublished 文章首次公开后在后台通知作者的关注者，并推送到活跃关注者的时间线；
+// 耗时随关注者数量增长，不阻塞发布请求
+func (s *ArticleService) onPublished(article *models.Article) {
+	published := *article
+	go func() {
+		if err := NewNotificationService().CreateArticlePublishNotifications(&published); err != nil {
+			log.Printf("发送文章发布通知失败 (ID: %d): %v", published.ID, err)
+		}
+		publishedAt := published.CreatedAt
+		if published.PublishAt != nil {
+			publishedAt = *published.PublishAt
+		}
+		NewTimelineService().FanOut(models.TimelineTypeArticle, published.ID, published.AuthorID, publishedAt)
+	}()
+}
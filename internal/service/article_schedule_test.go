@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestResolveArticleStatus(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(2 * time.Hour)
+	past := now.Add(-time.Minute)
+	tooFar := now.Add(maxScheduleAhead + time.Hour)
+
+	tests := []struct {
+		name      string
+		status    int
+		publishAt *time.Time
+		want      int
+		wantAt    *time.Time
+		wantErr   error
+	}{
+		{"草稿忽略发布时间", models.ArticleStatusDraft, &future, models.ArticleStatusDraft, nil, nil},
+		{"立即发布", models.ArticleStatusPublished, nil, models.ArticleStatusPublished, nil, nil},
+		{"发布时间已过按立即发布", models.ArticleStatusPublished, &past, models.ArticleStatusPublished, nil, nil},
+		{"发布时间在未来转为定时", models.ArticleStatusPublished, &future, models.ArticleStatusScheduled, &future, nil},
+		{"定时发布", models.ArticleStatusScheduled, &future, models.ArticleStatusScheduled, &future, nil},
+		{"定时发布缺少时间", models.ArticleStatusScheduled, nil, 0, nil, ErrPublishAtRequired},
+		{"定时发布时间已过", models.ArticleStatusScheduled, &past, 0, nil, ErrPublishAtPast},
+		{"定时发布时间过远", models.ArticleStatusScheduled, &tooFar, 0, nil, ErrPublishAtTooFar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotAt, err := resolveArticleStatus(tt.status, tt.publishAt, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+			if (gotAt == nil) != (tt.wantAt == nil) || (gotAt != nil && !gotAt.Equal(*tt.wantAt)) {
+				t.Errorf("publishAt = %v, want %v", gotAt, tt.wantAt)
+			}
+		})
+	}
+}
@@ -22,15 +22,35 @@ func NewArticleService() *ArticleService {
 }
 
 func (s *ArticleService) Create(req *models.ArticleRequest, authorID uint) (*models.Article, error) {
-	// 确保 status 正确设置：0=草稿, 1=已发布, 2=私有
+	// 确保 status 正确设置：0=草稿, 1=已发布, 2=私有, 3=定时发布
 	status := req.Status
-	if status != 0 && status != 1 && status != 2 {
-		status = 1 // 默认已发布
+	if status < models.ArticleStatusDraft || status > models.ArticleStatusScheduled {
+		status = models.ArticleStatusPublished // 默认已发布
+	}
+	now := time.Now()
+	status, publishAt, err := resolveArticleStatus(status, req.PublishAt, now)
+	if err != nil {
+		return nil, err
+	}
+	if status == models.ArticleStatusPublished {
+		publishAt = &now
+	}
+	if status == models.ArticleStatusPublished || status == models.ArticleStatusScheduled {
+		if err := EnsureEmailVerified(authorID); err != nil {
+			return nil, err
+		}
+	}
+
+	contentHTML, contentTOC, err := renderMarkdownWithTOC(req.Content)
+	if err != nil {
+		return nil, err
 	}
 
 	article := &models.Article{
 		Title:       req.Title,
 		Content:     req.Content,
+		ContentHTML: contentHTML,
+		ContentTOC:  contentTOC,
 		Summary:     req.Summary,
 		Cover:       req.Cover,
 		CategoryID:  req.CategoryID,
@@ -38,11 +58,15 @@ func (s *ArticleService) Create(req *models.ArticleRequest, authorID uint) (*mod
 		Status:      status,
 		IsTop:       req.IsTop,
 		IsRecommend: req.IsRecommend,
+		PublishAt:   publishAt,
 	}
 
-	err := database.DB.Transaction(func(tx *gorm.DB) error {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
 		// Create article
-		if err := tx.Create(article).Error; err != nil {
+		if err := createWithSlug(tx, models.SlugTargetArticle, authorID, req.Slug, req.Title, article, &article.Slug); err != nil {
+			return err
+		}
+		if err := createArticleRevision(tx, article, authorID, "创建"); err != nil {
 			return err
 		}
 
@@ -95,6 +119,11 @@ func (s *ArticleService) Create(req *models.ArticleRequest, authorID uint) (*mod
 		return nil, err
 	}
 
+	NewSearchService().Sync(models.SearchTypeArticle, article.ID)
+	if status == models.ArticleStatusPublished {
+		s.onPublished(article)
+	}
+
 	return article, nil
 }
 
@@ -103,8 +132,8 @@ func (s *ArticleService) Update(id uint, req *models.ArticleRequest, userID uint
 	var article models.Article
 	query := database.DB.Where("id = ?", id)
 
-	// 非管理员只能更新自己的文章
-	if role != "admin" {
+	// 没有文章管理权限时只能更新自己的文章
+	if !NewRoleService().HasPermission(role, models.PermissionArticleManage) {
 		query = query.Where("author_id = ?", userID)
 	}
 
@@ -115,6 +144,46 @@ func (s *ArticleService) Update(id uint, req *models.ArticleRequest, userID uint
 		return nil, err
 	}
 
+	// 确保 status 正确设置：0=草稿, 1=已发布, 2=私有, 3=定时发布；无效值保持原状态
+	status := req.Status
+	if status < models.ArticleStatusDraft || status > models.ArticleStatusScheduled {
+		status = article.Status
+	}
+	reqPublishAt := req.PublishAt
+	if status == models.ArticleStatusScheduled && reqPublishAt == nil {
+		// 仅修改内容时沿用已设置的定时发布时间
+		reqPublishAt = article.PublishAt
+	}
+	now := time.Now()
+	status, publishAt, err := resolveArticleStatus(status, reqPublishAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 首次公开：之前从未发布过，或由定时发布提前发布
+	firstPublish := status == models.ArticleStatusPublished &&
+		article.Status != models.ArticleStatusPublished &&
+		(article.PublishAt == nil || article.Status == models.ArticleStatusScheduled)
+	switch {
+	case firstPublish:
+		publishAt = &now
+	case status == models.ArticleStatusScheduled:
+		// 使用校验后的定时发布时间
+	case article.Status == models.ArticleStatusScheduled:
+		// 取消定时发布，清空发布时间，之后发布时重新通知
+		publishAt = nil
+	default:
+		publishAt = article.PublishAt
+	}
+
+	// 草稿转为发布或定时发布时同样需要已验证邮箱
+	wasPublishing := article.Status == models.ArticleStatusPublished || article.Status == models.ArticleStatusScheduled
+	if (status == models.ArticleStatusPublished || status == models.ArticleStatusScheduled) && !wasPublishing {
+		if err := EnsureEmailVerified(userID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 获取旧的标签IDs和分类ID
 	oldTagIDs := make([]uint, len(article.Tags))
 	for i, tag := range article.Tags {
@@ -122,7 +191,12 @@ func (s *ArticleService) Update(id uint, req *models.ArticleRequest, userID uint
 	}
 	oldCategoryID := article.CategoryID
 
-	err := database.DB.Transaction(func(tx *gorm.DB) error {
+	contentHTML, contentTOC, err := renderMarkdownWithTOC(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
 		// 如果分类改变，更新旧分类的文章数
 		if oldCategoryID > 0 && oldCategoryID != req.CategoryID {
 			if err := tx.Model(&models.Category{}).
@@ -148,26 +222,47 @@ func (s *ArticleService) Update(id uint, req *models.ArticleRequest, userID uint
 		updateData := map[string]interface{}{
 			"title":        req.Title,
 			"content":      req.Content,
+			"content_html": contentHTML,
+			"content_toc":  contentTOC,
 			"summary":      req.Summary,
 			"cover":        req.Cover,
 			"category_id":  req.CategoryID,
 			"is_top":       req.IsTop,
 			"is_recommend": req.IsRecommend,
+			"status":       status,
+			"publish_at":   publishAt,
+		}
+
+		// 只有显式修改 slug 时才变更，旧 slug 保留跳转
+		if req.Slug != "" || article.Slug == "" {
+			slug, err := assignSlug(tx, models.SlugTargetArticle, article.AuthorID, article.ID, req.Slug, req.Title)
+			if err != nil {
+				return err
+			}
+			if slug != article.Slug {
+				if err := recordSlugChange(tx, models.SlugTargetArticle, article.AuthorID, article.ID, article.Slug, slug); err != nil {
+					return err
+				}
+				updateData["slug"] = slug
+			}
 		}
 
-		// 确保 status 正确设置：0=草稿, 1=已发布, 2=私有
-		status := req.Status
-		if status == 0 || status == 1 || status == 2 {
-			updateData["status"] = status
+		// 旧文章没有版本记录时，先保存修改前的内容
+		if err := ensureArticleBaseline(tx, &article); err != nil {
+			return err
 		}
 
 		updateQuery := tx.Model(&models.Article{}).Where("id = ?", id)
-		// 非管理员只能更新自己的文章
-		if role != "admin" {
+		// 没有文章管理权限时只能更新自己的文章
+		if !NewRoleService().HasPermission(role, models.PermissionArticleManage) {
 			updateQuery = updateQuery.Where("author_id = ?", userID)
 		}
 
 		if err := updateQuery.Updates(updateData).Error; err != nil {
+			// 检查和写入之间 slug 被并发占用
+			if isSlugConflict(err) {
+				return ErrSlugTaken
+			}
 			return err
 		}
 
@@ -244,7 +339,7 @@ func (s *ArticleService) Update(id uint, req *models.ArticleRequest, userID uint
 			return err
 		}
 
-		return nil
+		return createArticleRevision(tx, &article, userID, "编辑")
 	})
 
 	if err != nil {
@@ -263,6 +358,12 @@ func (s *ArticleService) Update(id uint, req *models.ArticleRequest, userID uint
 		return nil, err
 	}
 
+	NewSearchService().Sync(models.SearchTypeArticle, id)
+	invalidateSEOMeta(seoPageArticle, id)
+	if firstPublish {
+		s.onPublished(&article)
+	}
+
 	return &article, nil
 }
 
@@ -271,8 +372,8 @@ func (s *ArticleService) Delete(id uint, userID uint, role string) error {
 	var article models.Article
 	query := database.DB.Where("id = ?", id)
 
-	// 非管理员只能查询自己的文章
-	if role != "admin" {
+	// 没有文章管理权限时只能查询自己的文章
+	if !NewRoleService().HasPermission(role, models.PermissionArticleManage) {
 		query = query.Where("author_id = ?", userID)
 	}
 
@@ -290,9 +391,9 @@ func (s *ArticleService) Delete(id uint, userID uint, role string) error {
 	}
 
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
-		// 删除文章 - 使用WHERE条件确保权限（非管理员只能删除自己的文章）
+		// 删除文章 - 使用WHERE条件确保权限（没有文章管理权限时只能删除自己的文章）
 		deleteQuery := tx.Where("id = ?", id)
-		if role != "admin" {
+		if !NewRoleService().HasPermission(role, models.PermissionArticleManage) {
 			deleteQuery = deleteQuery.Where("author_id = ?", userID)
 		}
 
@@ -339,6 +440,8 @@ func (s *ArticleService) Delete(id uint, userID uint, role string) error {
 	// Clear cache
 	database.DeleteCache(fmt.Sprintf("article:%d", id))
 	database.DeleteCachePattern("article:list:*")
+	NewSearchService().Sync(models.SearchTypeArticle, id)
+	invalidateSEOMeta(seoPageArticle, id)
 
 	return nil
 }
@@ -447,6 +550,8 @@ func (s *ArticleService) buildOrderBy(query *models.ArticleListQuery) string {
 	switch query.SortBy {
 	case "time":
 		sortField = "created_at"
+	case "publish_at":
+		sortField = "publish_at"
 	case "view_count":
 		sortField = "view_count"
 	case "like_count":
@@ -626,10 +731,6 @@ func (s *ArticleService) getListFromDB(query *models.ArticleListQuery) ([]*model
 	return s.GetList(query)
 }
 
-func (s *ArticleService) IncrementViewCount(id uint) error {
-	return database.DB.Model(&models.Article{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
-}
-
 // GetRecommended 获取推荐文章
 func (s *ArticleService) GetRecommended(limit int) ([]*models.Article, error) {
 	if limit <= 0 {
@@ -748,3 +849,70 @@ func (s *ArticleService) GetHotArticles(limit int) ([]*models.Article, error) {
 
 	return result, nil
 }
+
+// createImportedArticle 在事务中保存从外部导入的文章：分配 slug、记录首个版本、关联标签并更新各项文章数。
+// slug 不合法或已被占用时根据标题重新生成；不发送通知，调用方提交事务后自行同步搜索索引
+func createImportedArticle(tx *gorm.DB, article *models.Article, slug string, tags []models.Tag, remark string) error {
+	contentHTML, contentTOC, err := renderMarkdownWithTOC(article.Content)
+	if err != nil {
+		return err
+	}
+	article.ContentHTML, article.ContentTOC = contentHTML, contentTOC
+
+	if _, err := assignSlug(tx, models.SlugTargetArticle, article.AuthorID, 0, slug, article.Title); err != nil {
+		if !errors.Is(err, ErrSlugInvalid) && !errors.Is(err, ErrSlugTaken) {
+			return err
+		}
+		slug = ""
+	}
+
+	status := article.Status
+	if err := createWithSlug(tx, models.SlugTargetArticle, article.AuthorID, slug, article.Title, article, &article.Slug); err != nil {
+		if !errors.Is(err, ErrSlugTaken) {
+			return err
+		}
+		// 原 slug 在检查后被并发占用，改为根据标题生成
+		if err := createWithSlug(tx, models.SlugTargetArticle, article.AuthorID, "", article.Title, article, &article.Slug); err != nil {
+			return err
+		}
+	}
+	// Status 带有 default:1 标签，创建时零值会被数据库默认值覆盖
+	if status == models.ArticleStatusDraft {
+		if err := tx.Model(article).UpdateColumn("status", models.ArticleStatusDraft).Error; err != nil {
+			return err
+		}
+		article.Status = status
+	}
+	if err := createArticleRevision(tx, article, article.AuthorID, remark); err != nil {
+		return err
+	}
+
+	if len(tags) > 0 {
+		if err := tx.Model(article).Association("Tags").Append(tags); err != nil {
+			return err
+		}
+		tagIDs := make([]uint, len(tags))
+		for i, tag := range tags {
+			tagIDs[i] = tag.ID
+		}
+		if err := tx.Model(&models.Tag{}).
+			Where("id IN ?", tagIDs).
+			UpdateColumn("article_count", gorm.Expr("article_count + ?", 1)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.User{}).
+		Where("id = ?", article.AuthorID).
+		UpdateColumn("article_count", gorm.Expr("article_count + ?", 1)).Error; err != nil {
+		return err
+	}
+	if article.CategoryID > 0 {
+		if err := tx.Model(&models.Category{}).
+			Where("id = ?", article.CategoryID).
+			UpdateColumn("article_count", gorm.Expr("article_count + ?", 1)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// auditExportLimit 单次导出的最大行数，超出部分需缩小筛选范围
+const auditExportLimit = 50000
+
+var ErrAuditDateInvalid = errors.New("日期格式应为 YYYY-MM-DD")
+
+// auditIgnoredFields 不计入变更的字段，每次更新都会变化
+var auditIgnoredFields = map[string]bool{"updated_at": true}
+
+// auditSnapshots 对象类型 -> 读取当前状态，用于计算变更前后的差异；对象不存在时返回 nil
+var auditSnapshots = map[string]func(id string) (interface{}, error){
+	"users":          snapshotModel(func() interface{} { return &models.User{} }),
+	"articles":       snapshotModel(func() interface{} { return &models.Article{} }),
+	"works":          snapshotModel(func() interface{} { return &models.Work{} }),
+	"categories":     snapshotModel(func() interface{} { return &models.Category{} }),
+	"tags":           snapshotModel(func() interface{} { return &models.Tag{} }),
+	"comments":       snapshotModel(func() interface{} { return &models.Comment{} }),
+	"links":          snapshotModel(func() interface{} { return &models.Link{} }),
+	"ad-positions":   snapshotModel(func() interface{} { return &models.AdPosition{} }),
+	"advertisements": snapshotModel(func() interface{} { return &models.Advertisement{} }),
+	"ad-placements":  snapshotModel(func() interface{} { return &models.AdPlacement{} }),
+	"roles":          snapshotRole,
+	"settings":       snapshotSetting,
+}
+
+type AuditService struct{}
+
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+// Record 写入一条审计日志，detail 序列化为 JSON；写入失败只记录日志，不影响业务
+func (s *AuditService) Record(entry *models.AuditLog, detail interface{}) {
+	if detail != nil {
+		if data, err := json.Marshal(detail); err == nil {
+			entry.Detail = string(data)
+		}
+	}
+	if err := database.DB.Create(entry).Error; err != nil {
+		log.Printf("写入审计日志失败: %s %s/%s, 错误: %v", entry.Action, entry.TargetType, entry.TargetID, err)
+	}
+}
+
+// RecordChange 写入审计日志并记录 before/after 之间有变化的字段
+func (s *AuditService) RecordChange(entry *models.AuditLog, before, after interface{}) {
+	if changes := auditDiff(before, after); len(changes) > 0 {
+		if data, err := json.Marshal(changes); err == nil {
+			entry.Changes = string(data)
+		}
+	}
+	s.Record(entry, nil)
+}
+
+// Snapshot 读取对象当前状态；未登记的对象类型或读取失败时返回 nil
+func (s *AuditService) Snapshot(targetType, id string) interface{} {
+	load, ok := auditSnapshots[targetType]
+	if !ok || id == "" {
+		return nil
+	}
+	snapshot, err := load(id)
+	if err != nil {
+		log.Printf("读取审计快照失败: %s/%s, 错误: %v", targetType, id, err)
+		return nil
+	}
+	return snapshot
+}
+
+// List 分页查询审计日志，按时间倒序
+func (s *AuditService) List(query *models.AuditLogQuery) ([]*models.AuditLogResponse, int64, error) {
+	db, err := auditLogFilter(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+	var logs []*models.AuditLog
+	if err := db.Order("id DESC").Offset((query.Page - 1) * query.PageSize).Limit(query.PageSize).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*models.AuditLogResponse, len(logs))
+	for i, entry := range logs {
+		list[i] = entry.ToResponse()
+	}
+	return list, total, nil
+}
+
+// Export 按筛选条件导出 CSV，带 BOM 以便 Excel 正确识别中文
+func (s *AuditService) Export(query *models.AuditLogQuery, w io.Writer) error {
+	db, err := auditLogFilter(query)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"时间", "操作人ID", "操作人", "动作", "对象类型", "对象ID", "结果码", "IP", "请求ID", "方法", "路径", "变更", "详情"}); err != nil {
+		return err
+	}
+
+	var batch []*models.AuditLog
+	result := db.Order("id DESC").Limit(auditExportLimit).FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, entry := range batch {
+			actorID := ""
+			if entry.ActorID != nil {
+				actorID = strconv.FormatUint(uint64(*entry.ActorID), 10)
+			}
+			record := []string{
+				entry.CreatedAt.Format(time.DateTime), actorID, entry.ActorName, entry.Action,
+				entry.TargetType, entry.TargetID, strconv.Itoa(entry.Status), entry.IP, entry.RequestID,
+				entry.Method, entry.Path, entry.Changes, entry.Detail,
+			}
+			for i := range record {
+				record[i] = csvSafe(record[i])
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func auditLogFilter(query *models.AuditLogQuery) (*gorm.DB, error) {
+	db := database.DB.Model(&models.AuditLog{})
+	if query.ActorID != nil {
+		db = db.Where("actor_id = ?", *query.ActorID)
+	}
+	if actor := strings.TrimSpace(query.Actor); actor != "" {
+		db = db.Where("actor_name LIKE ?", "%"+actor+"%")
+	}
+	if action := strings.TrimSpace(query.Action); action != "" {
+		db = db.Where("action LIKE ?", action+"%")
+	}
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+	if query.TargetID != "" {
+		db = db.Where("target_id = ?", query.TargetID)
+	}
+	if query.RequestID != "" {
+		db = db.Where("request_id = ?", query.RequestID)
+	}
+	switch query.Result {
+	case "success":
+		db = db.Where("status = 0")
+	case "failure":
+		db = db.Where("status <> 0")
+	}
+	if query.StartDate != "" {
+		start, err := time.ParseInLocation(time.DateOnly, query.StartDate, time.Local)
+		if err != nil {
+			return nil, ErrAuditDateInvalid
+		}
+		db = db.Where("created_at >= ?", start)
+	}
+	if query.EndDate != "" {
+		end, err := time.ParseInLocation(time.DateOnly, query.EndDate, time.Local)
+		if err != nil {
+			return nil, ErrAuditDateInvalid
+		}
+		db = db.Where("created_at < ?", end.AddDate(0, 0, 1))
+	}
+	return db, nil
+}
+
+// auditDiff 比较两个对象序列化后的顶层字段，返回有变化的字段；新建时 before 为 nil，删除时 after 为 nil
+func auditDiff(before, after interface{}) map[string]models.AuditChange {
+	beforeFields, afterFields := auditFields(before), auditFields(after)
+	changes := make(map[string]models.AuditChange)
+	for key, value := range beforeFields {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if next, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, next) {
+			changes[key] = models.AuditChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok && !auditIgnoredFields[key] {
+			changes[key] = models.AuditChange{After: value}
+		}
+	}
+	return changes
+}
+
+func auditFields(value interface{}) map[string]interface{} {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// 非对象类型整体作为一个字段
+		var raw interface{}
+		_ = json.Unmarshal(data, &raw)
+		return map[string]interface{}{"value": raw}
+	}
+	return fields
+}
+
+// csvSafe 防止以公式字符开头的内容在表格软件中被当作公式执行
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func snapshotModel(newModel func() interface{}) func(id string) (interface{}, error) {
+	return func(id string) (interface{}, error) {
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return nil, nil
+		}
+		model := newModel()
+		if err := database.DB.First(model, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return model, nil
+	}
+}
+
+func snapshotRole(id string) (interface{}, error) {
+	roleID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, nil
+	}
+	role, err := NewRoleService().get(uint(roleID))
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return role.ToResponse(0), nil
+}
+
+func snapshotSetting(key string) (interface{}, error) {
+	setting, err := NewSettingService().Get(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return setting, nil
+}
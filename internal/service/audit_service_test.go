@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestAuditDiff(t *testing.T) {
+	before := &models.Tag{ID: 1, Name: "go", Color: "#000"}
+	after := &models.Tag{ID: 1, Name: "golang", Color: "#000"}
+
+	changes := auditDiff(before, after)
+	if len(changes) != 1 {
+		t.Fatalf("changes = %v, want only name", changes)
+	}
+	if change := changes["name"]; change.Before != "go" || change.After != "golang" {
+		t.Errorf("name change = %+v", change)
+	}
+
+	if changes := auditDiff(nil, after); changes["name"].After != "golang" || changes["name"].Before != nil {
+		t.Errorf("create diff = %v", changes)
+	}
+	if changes := auditDiff(before, (*models.Tag)(nil)); changes["name"].Before != "go" || changes["name"].After != nil {
+		t.Errorf("delete diff = %v", changes)
+	}
+	if changes := auditDiff(map[string]string{"site_name": "a"}, map[string]string{"site_name": "a"}); len(changes) != 0 {
+		t.Errorf("unchanged diff = %v", changes)
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"admin":             "admin",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1":                "'+1",
+		"-1":                "'-1",
+		"@SUM(A1)":          "'@SUM(A1)",
+	}
+	for input, want := range tests {
+		if got := csvSafe(input); got != want {
+			t.Errorf("csvSafe(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
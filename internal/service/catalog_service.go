@@ -81,6 +81,7 @@ func (s *CatalogService) Move(id, ownerID uint, req *models.CatalogMoveRequest)
 
 func (s *CatalogService) Delete(id, ownerID uint) error {
 	var workspaceID uint
+	var docIDs []uint
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		var catalog models.Catalog
 		if err := tx.Where("id = ? AND owner_id = ?", id, ownerID).First(&catalog).Error; err != nil {
@@ -95,7 +96,6 @@ func (s *CatalogService) Delete(id, ownerID uint) error {
 			return err
 		}
 		catalogIDs := catalogDescendantIDs(id, catalogs)
-		var docIDs []uint
 		if err := tx.Model(&models.Doc{}).Where("catalog_id IN ? AND owner_id = ?", catalogIDs, ownerID).Pluck("id", &docIDs).Error; err != nil {
 			return err
 		}
@@ -118,6 +118,9 @@ func (s *CatalogService) Delete(id, ownerID uint) error {
 	})
 	if err == nil {
 		deleteWorkspaceCache(workspaceID)
+		for _, docID := range docIDs {
+			NewSearchService().Sync(models.SearchTypeDoc, docID)
+		}
 	}
 	return err
 }
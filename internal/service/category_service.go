@@ -2,11 +2,12 @@ package service
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 
 	"github.com/iceymoss/inkspace/internal/database"
 	"github.com/iceymoss/inkspace/internal/models"
+
+	"gorm.io/gorm"
 )
 
 type CategoryService struct{}
@@ -30,28 +31,9 @@ func (s *CategoryService) Create(req *models.CategoryRequest) (*models.Category,
 	// - 如果前端传了 slug，则校验唯一性，避免数据库抛出 1062 错误
 	slug := strings.TrimSpace(req.Slug)
 	if slug == "" {
-		baseSlug := generateSlugFromName(req.Name)
-
-		// 确保自动生成的 slug 在数据库中唯一
-		var i int64 = 0
-		for {
-			tmp := baseSlug
-			if i > 0 {
-				tmp = fmt.Sprintf("%s-%d", baseSlug, i)
-			}
-
-			var slugCount int64
-			if err := database.DB.Model(&models.Category{}).
-				Where("slug = ?", tmp).
-				Count(&slugCount).Error; err != nil {
-				return nil, err
-			}
-
-			if slugCount == 0 {
-				slug = tmp
-				break
-			}
-			i++
+		var err error
+		if slug, err = uniqueCategorySlug(database.DB, req.Name); err != nil {
+			return nil, err
 		}
 	} else {
 		// 用户自定义 slug，校验唯一性
@@ -173,8 +155,18 @@ func (s *CategoryService) GetListPaged(page, pageSize int) ([]*models.Category,
 	return categories, total, nil
 }
 
+// uniqueCategorySlug 根据名称生成 slug，并追加序号保证在数据库中唯一
+func uniqueCategorySlug(tx *gorm.DB, name string) (string, error) {
+	return nextFreeSlug(tx.Model(&models.Category{}), generateSlugFromName(name))
+}
+
 // generateSlugFromName 根据分类名称生成一个基础 slug（仅做简单字符清洗和格式化）
 func generateSlugFromName(name string) string {
+	return slugify(name, "category")
+}
+
+// slugify 将文本清洗为只包含小写字母、数字和连字符的 slug，清洗后为空时使用 fallback
+func slugify(name, fallback string) string {
 	s := strings.TrimSpace(strings.ToLower(name))
 	s = strings.ReplaceAll(s, " ", "-")
 	s = strings.ReplaceAll(s, "_", "-")
@@ -196,7 +188,7 @@ func generateSlugFromName(name string) string {
 
 	result := builder.String()
 	if result == "" {
-		result = "category"
+		result = fallback
 	}
 
 	return result
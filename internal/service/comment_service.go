@@ -31,6 +31,10 @@ func (s *CommentService) Create(req *models.CommentRequest, userID uint) (*model
 		return nil, errors.New("不能同时评论文章和作品")
 	}
 
+	if err := EnsureEmailVerified(userID); err != nil {
+		return nil, err
+	}
+
 	// 检查评论功能是否开放
 	settingService := NewSettingService()
 
@@ -222,6 +226,9 @@ func (s *CommentService) Create(req *models.CommentRequest, userID uint) (*model
 	if err != nil {
 		return nil, err
 	}
+	if commentStatus == 1 {
+		markCommentHot(req.ArticleID, req.WorkID)
+	}
 
 	// 发送通知（异步，不阻塞主流程）
 	if req.ArticleID != nil && *req.ArticleID > 0 && article != nil {
@@ -326,8 +333,8 @@ func (s *CommentService) Delete(id uint, userID uint, role string) error {
 		return err
 	}
 
-	// Check permission: 评论作者、文章/作品作者或管理员可以删除
-	if role != "admin" {
+	// Check permission: 评论作者、文章/作品作者或有评论审核权限的用户可以删除
+	if !NewRoleService().HasPermission(role, models.PermissionCommentModerate) {
 		isCommentAuthor := comment.UserID > 0 && comment.UserID == userID
 		isContentAuthor := false
 
@@ -363,10 +370,10 @@ func (s *CommentService) Delete(id uint, userID uint, role string) error {
 
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		// 删除评论 - 使用WHERE条件确保权限
-		// 管理员可以删除任何评论，普通用户只能删除自己的评论或自己内容下的评论
+		// 有评论审核权限的用户可以删除任何评论，普通用户只能删除自己的评论或自己内容下的评论
 		deleteQuery := tx.Where("id = ?", id)
-		if role != "admin" {
-			// 非管理员：只能删除自己的评论，或者自己内容下的评论
+		if !NewRoleService().HasPermission(role, models.PermissionCommentModerate) {
+			// 普通用户：只能删除自己的评论，或者自己内容下的评论
 			// 这里已经在上面检查过权限，所以直接删除
 			// 但为了安全，我们再次确认：如果是评论作者，使用user_id条件
 			if comment.UserID > 0 && comment.UserID == userID {
@@ -421,6 +428,10 @@ func (s *CommentService) Delete(id uint, userID uint, role string) error {
 		return nil
 	})
 
+	if err == nil {
+		markCommentHot(comment.ArticleID, comment.WorkID)
+	}
+
 	return err
 }
 
@@ -538,7 +549,7 @@ func (s *CommentService) UpdateStatus(id uint, status int) error {
 
 	oldStatus := comment.Status
 
-	return database.DB.Transaction(func(tx *gorm.DB) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		// 更新评论状态
 		if err := tx.Model(&models.Comment{}).Where("id = ?", id).Update("status", status).Error; err != nil {
 			return err
@@ -628,4 +639,8 @@ func (s *CommentService) UpdateStatus(id uint, status int) error {
 
 		return nil
 	})
+	if err == nil && oldStatus != status && (oldStatus == 1 || status == 1) {
+		markCommentHot(comment.ArticleID, comment.WorkID)
+	}
+	return err
 }
@@ -13,7 +13,7 @@ func TestRenderMarkdownEscapesRawHTML(t *testing.T) {
 	if strings.Contains(html, "<script>") {
 		t.Fatalf("renderMarkdown() returned executable raw HTML: %s", html)
 	}
-	if !strings.Contains(html, "<h1>") {
+	if !strings.Contains(html, `<h1 id="标题">`) {
 		t.Fatalf("renderMarkdown() did not render Markdown heading: %s", html)
 	}
 }
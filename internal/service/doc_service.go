@@ -1,7 +1,7 @@
 package service
 
 import (
-	"bytes"
+	"encoding/json"
 	"errors"
 	"strings"
 	"time"
@@ -10,7 +10,7 @@ import (
 
 	"github.com/iceymoss/inkspace/internal/database"
 	"github.com/iceymoss/inkspace/internal/models"
-	"github.com/yuin/goldmark"
+	"github.com/iceymoss/inkspace/pkg/markdown"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -90,11 +90,13 @@ func (s *DocService) Save(id, ownerID uint, req *models.DocSaveRequest) (*models
 	if err != nil {
 		return nil, err
 	}
+	NewSearchService().Sync(models.SearchTypeDoc, doc.ID)
 	return s.get(doc.ID, ownerID, database.DB)
 }
 
 func (s *DocService) Autosave(id, ownerID uint, req *models.DocAutosaveRequest) (*models.Doc, error) {
 	var doc models.Doc
+	var unpublished bool
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", id, ownerID).First(&doc).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -103,6 +105,7 @@ func (s *DocService) Autosave(id, ownerID uint, req *models.DocAutosaveRequest)
 			return err
 		}
 		newStatus := statusAfterContentMutation(doc.Status)
+		unpublished = newStatus != doc.Status
 		if doc.Content == req.Content {
 			if newStatus != doc.Status {
 				doc.Status = newStatus
@@ -123,6 +126,9 @@ func (s *DocService) Autosave(id, ownerID uint, req *models.DocAutosaveRequest)
 	if err != nil {
 		return nil, err
 	}
+	if unpublished {
+		NewSearchService().Sync(models.SearchTypeDoc, doc.ID)
+	}
 	return s.get(doc.ID, ownerID, database.DB)
 }
 
@@ -140,11 +146,12 @@ func (s *DocService) Publish(id, ownerID uint, status int) (*models.Doc, error)
 		}
 		updates := map[string]interface{}{"status": status}
 		if status == models.DocStatusPublished {
-			html, err := renderMarkdown(doc.Content)
+			html, toc, err := renderMarkdownWithTOC(doc.Content)
 			if err != nil {
 				return err
 			}
 			updates["content_html"] = html
+			updates["content_toc"] = toc
 		}
 		if status == models.DocStatusPublished && doc.PublishedAt == nil {
 			now := time.Now()
@@ -155,6 +162,7 @@ func (s *DocService) Publish(id, ownerID uint, status int) (*models.Doc, error)
 	if err != nil {
 		return nil, err
 	}
+	NewSearchService().Sync(models.SearchTypeDoc, id)
 	return s.get(id, ownerID, database.DB)
 }
 
@@ -224,6 +232,7 @@ func (s *DocService) Delete(id, ownerID uint) error {
 	})
 	if err == nil {
 		deleteWorkspaceCache(workspaceID)
+		NewSearchService().Sync(models.SearchTypeDoc, id)
 	}
 	return err
 }
@@ -240,6 +249,7 @@ func (s *DocService) Move(id, ownerID uint, req *models.DocMoveRequest) (*models
 		Updates(map[string]interface{}{"catalog_id": req.CatalogID, "sort": req.Sort}).Error; err != nil {
 		return nil, err
 	}
+	NewSearchService().Sync(models.SearchTypeDoc, id)
 	return s.get(id, ownerID, database.DB)
 }
 
@@ -296,6 +306,7 @@ func (s *DocService) Rollback(id, ownerID uint, version int) (*models.Doc, error
 	if err != nil {
 		return nil, err
 	}
+	NewSearchService().Sync(models.SearchTypeDoc, doc.ID)
 	return s.get(doc.ID, ownerID, database.DB)
 }
 
@@ -364,13 +375,29 @@ func createDocVersion(tx *gorm.DB, doc *models.Doc, remark string) error {
 	}).Error
 }
 
+// renderMarkdown 渲染 Markdown 正文，返回清洗后的 HTML
 func renderMarkdown(content string) (string, error) {
-	var output bytes.Buffer
-	// Goldmark's default renderer escapes raw HTML rather than passing it through.
-	if err := goldmark.Convert([]byte(content), &output); err != nil {
+	result, err := markdown.Render(content)
+	if err != nil {
 		return "", err
 	}
-	return output.String(), nil
+	return result.HTML, nil
+}
+
+// renderMarkdownWithTOC 渲染 Markdown 正文，同时返回 JSON 格式的目录，没有标题时目录为空字符串
+func renderMarkdownWithTOC(content string) (string, string, error) {
+	result, err := markdown.Render(content)
+	if err != nil {
+		return "", "", err
+	}
+	if len(result.TOC) == 0 {
+		return result.HTML, "", nil
+	}
+	toc, err := json.Marshal(result.TOC)
+	if err != nil {
+		return "", "", err
+	}
+	return result.HTML, string(toc), nil
 }
 
 func statusAfterContentMutation(status int) int {
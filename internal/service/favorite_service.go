@@ -80,6 +80,7 @@ func (s *FavoriteService) AddFavorite(userID, articleID uint) error {
 	if err == nil {
 		// 清除文章缓存
 		database.DeleteCache(fmt.Sprintf("article:%d", articleID))
+		markHot(models.ViewTargetArticle, articleID)
 	}
 
 	return err
@@ -120,6 +121,10 @@ func (s *FavoriteService) RemoveFavorite(userID, articleID uint) error {
 		return nil
 	})
 
+	if err == nil {
+		markHot(models.ViewTargetArticle, articleID)
+	}
+
 	return err
 }
 
@@ -356,6 +361,7 @@ func (s *FavoriteService) AddWorkFavorite(userID, workID uint) error {
 
 	// 清除作品缓存
 	database.DeleteCache(fmt.Sprintf("work:%d", workID))
+	markHot(models.ViewTargetWork, workID)
 
 	// 发送收藏通知给作品作者
 	if work.AuthorID != userID {
@@ -426,6 +432,7 @@ func (s *FavoriteService) RemoveWorkFavorite(userID, workID uint) error {
 	if err == nil {
 		// 清除作品缓存
 		database.DeleteCache(fmt.Sprintf("work:%d", workID))
+		markHot(models.ViewTargetWork, workID)
 	}
 
 	return err
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/feed"
+
+	"gorm.io/gorm"
+)
+
+const (
+	feedItemLimit = 20
+	// 缓存键以 article:list: 开头，文章发布、修改、删除时随列表缓存一起失效
+	feedCachePrefix = "article:list:feed:"
+	feedCacheTTL    = 10 * time.Minute
+)
+
+// 订阅源范围
+const (
+	FeedScopeSite     = "site"
+	FeedScopeAuthor   = "author"
+	FeedScopeCategory = "category"
+	FeedScopeTag      = "tag"
+)
+
+var ErrFeedNotFound = errors.New("订阅源不存在")
+
+// FeedDocument 生成好的订阅源及条件请求所需的校验信息
+type FeedDocument struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// FeedService 生成站点、作者、分类和标签的 RSS / Atom / JSON Feed 订阅源
+type FeedService struct {
+	articleService *ArticleService
+}
+
+func NewFeedService() *FeedService {
+	return &FeedService{
+		articleService: NewArticleService(),
+	}
+}
+
+// Build 生成订阅源；baseURL 为站点对外地址，用于生成绝对链接
+func (s *FeedService) Build(scope string, id uint, format feed.Format, baseURL string) (*FeedDocument, error) {
+	baseURL = strings.TrimRight(baseURL, "/")
+	cacheKey := fmt.Sprintf("%s%s:%d:%s:%s", feedCachePrefix, scope, id, format, baseURL)
+	if database.RDB != nil {
+		var cached FeedDocument
+		if err := database.GetCache(cacheKey, &cached); err == nil && len(cached.Body) > 0 {
+			return &cached, nil
+		}
+	}
+
+	f, err := s.buildFeed(scope, id, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	f.FeedURL = baseURL + feedPath(scope, id, format)
+	body, err := feed.Render(f, format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum(body)
+	doc := &FeedDocument{
+		Body:         body,
+		ContentType:  format.ContentType(),
+		ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		LastModified: f.Updated.UTC().Truncate(time.Second),
+	}
+	if database.RDB != nil {
+		if err := database.SetCache(cacheKey, doc, feedCacheTTL); err != nil {
+			log.Printf("缓存订阅源失败: %v", err)
+		}
+	}
+	return doc, nil
+}
+
+func (s *FeedService) buildFeed(scope string, id uint, baseURL string) (*feed.Feed, error) {
+	site := siteName()
+	description := ""
+	if setting, err := NewSettingService().Get(models.SettingSiteDescription); err == nil {
+		description = setting.Value
+	}
+
+	status := models.ArticleStatusPublished
+	query := &models.ArticleListQuery{Page: 1, PageSize: feedItemLimit, Status: &status, SortBy: "publish_at"}
+	f := &feed.Feed{
+		Title:       site,
+		Description: description,
+		Link:        baseURL + "/",
+		Language:    "zh-CN",
+		Generator:   "InkSpace",
+	}
+
+	switch scope {
+	case FeedScopeSite:
+		f.ID = baseURL + "/"
+	case FeedScopeAuthor:
+		var user models.User
+		if err := database.DB.First(&user, id).Error; err != nil {
+			return nil, feedLookupError(err)
+		}
+		query.AuthorID = user.ID
+		f.Title = fmt.Sprintf("%s - %s", displayName(&user), site)
+		if user.Bio != "" {
+			f.Description = user.Bio
+		}
+		f.Link = fmt.Sprintf("%s/users/%d", baseURL, user.ID)
+	case FeedScopeCategory:
+		category, err := NewCategoryService().GetByID(id)
+		if err != nil {
+			return nil, feedLookupError(err)
+		}
+		query.CategoryID = category.ID
+		f.Title = fmt.Sprintf("%s - %s", category.Name, site)
+		if category.Description != "" {
+			f.Description = category.Description
+		}
+		f.Link = fmt.Sprintf("%s/blog?category_id=%d", baseURL, category.ID)
+	case FeedScopeTag:
+		tag, err := NewTagService().GetByID(id)
+		if err != nil {
+			return nil, feedLookupError(err)
+		}
+		query.TagID = tag.ID
+		f.Title = fmt.Sprintf("#%s - %s", tag.Name, site)
+		f.Link = fmt.Sprintf("%s/blog?tag_id=%d", baseURL, tag.ID)
+	default:
+		return nil, ErrFeedNotFound
+	}
+	if f.ID == "" {
+		f.ID = f.Link
+	}
+
+	articles, _, err := s.articleService.GetList(query)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Updated = time.Now()
+	if len(articles) > 0 {
+		f.Updated = time.Time{}
+	}
+	host := feedHost(baseURL)
+	for _, article := range articles {
+		item := articleFeedItem(article, baseURL, host)
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+// articleFeedItem 将文章转换为订阅条目；GUID 使用 tag URI，不随标题或 slug 变化
+func articleFeedItem(article *models.Article, baseURL, host string) feed.Item {
+	published := article.CreatedAt
+	if article.PublishAt != nil {
+		published = *article.PublishAt
+	}
+	updated := article.UpdatedAt
+	if updated.Before(published) {
+		updated = published
+	}
+
+	item := feed.Item{
+		ID:        fmt.Sprintf("tag:%s,%s:article:%d", host, article.CreatedAt.UTC().Format("2006-01-02"), article.ID),
+		Title:     article.Title,
+		Link:      fmt.Sprintf("%s/blog/%d", baseURL, article.ID),
+		Summary:   article.Summary,
+		Published: published,
+		Updated:   updated,
+	}
+	if article.Cover != "" {
+		item.Image = absoluteURL(baseURL, article.Cover)
+	}
+	if article.Author != nil {
+		item.AuthorName = displayName(article.Author)
+		item.AuthorURL = fmt.Sprintf("%s/users/%d", baseURL, article.Author.ID)
+		if article.Slug != "" {
+			item.Link = fmt.Sprintf("%s/blog/%s/%s", baseURL, url.PathEscape(article.Author.Username), url.PathEscape(article.Slug))
+		}
+	}
+	if article.Category != nil {
+		item.Categories = append(item.Categories, article.Category.Name)
+	}
+	for _, tag := range article.Tags {
+		item.Categories = append(item.Categories, tag.Name)
+	}
+
+	contentHTML := article.ContentHTML
+	if contentHTML == "" {
+		rendered, err := renderMarkdown(article.Content)
+		if err != nil {
+			log.Printf("渲染文章 %d 失败: %v", article.ID, err)
+		}
+		contentHTML = rendered
+	}
+	item.ContentHTML = sanitizePublicWikiHTML(contentHTML)
+	return item
+}
+
+// feedPath 订阅源的 API 路径
+func feedPath(scope string, id uint, format feed.Format) string {
+	switch scope {
+	case FeedScopeAuthor:
+		return fmt.Sprintf("/api/users/%d/feed/%s", id, format)
+	case FeedScopeCategory:
+		return fmt.Sprintf("/api/categories/%d/feed/%s", id, format)
+	case FeedScopeTag:
+		return fmt.Sprintf("/api/tags/%d/feed/%s", id, format)
+	default:
+		return "/api/feed/" + string(format)
+	}
+}
+
+func feedHost(baseURL string) string {
+	if parsed, err := url.Parse(baseURL); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return "inkspace"
+}
+
+func absoluteURL(baseURL, link string) string {
+	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+		return link
+	}
+	return baseURL + "/" + strings.TrimLeft(link, "/")
+}
+
+func feedLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrFeedNotFound
+	}
+	return err
+}
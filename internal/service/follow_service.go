@@ -78,6 +78,7 @@ func (s *FollowService) Follow(followerID, followingID uint) error {
 
 	// 事务成功后，异步发送关注通知
 	if err == nil {
+		NewTimelineService().Invalidate(followerID)
 		go func() {
 			notifErr := s.notificationService.CreateFollowNotification(followerID, followingID)
 			if notifErr != nil {
@@ -137,6 +138,9 @@ func (s *FollowService) Unfollow(followerID, followingID uint) error {
 		return nil
 	})
 
+	if err == nil {
+		NewTimelineService().Invalidate(followerID)
+	}
 	return err
 }
 
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/ranking"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const (
+	hotRankSize      = 500          // 热门 ZSet 最多保留的内容数
+	hotRankBatchSize = 500          // 每次从数据库读取的内容数
+	hotDirtyPrefix   = "hot:dirty:" // 有新互动、等待重新计算热度的内容 ID 集合
+	hotConfigSuffix  = ":config"    // 与榜单一起保存的排序配置摘要，配置变化后整体重建
+)
+
+// hotRankKeys 各类内容的热门 ZSet
+var hotRankKeys = map[string]string{
+	models.ViewTargetArticle: "hot:articles:zset",
+	models.ViewTargetWork:    "hot:works:zset",
+}
+
+var hotRankSettingKeys = []string{
+	models.SettingHotRankFormula,
+	models.SettingHotRankGravity,
+	models.SettingHotRankTimeScale,
+	models.SettingHotRankWeightView,
+	models.SettingHotRankWeightComment,
+	models.SettingHotRankWeightLike,
+	models.SettingHotRankWeightFavorite,
+}
+
+// HotRankService 热门排序：浏览、评论、点赞、收藏发生时标记内容，
+// 定时任务只重新计算被标记的内容和当前榜单中的内容，不再扫描全表
+type HotRankService struct {
+	settingService *SettingService
+}
+
+func NewHotRankService() *HotRankService {
+	return &HotRankService{settingService: NewSettingService()}
+}
+
+// hotRankRow 计算热度需要的字段
+type hotRankRow struct {
+	ID            uint
+	ViewCount     int
+	CommentCount  int
+	LikeCount     int
+	FavoriteCount int
+	PublishedAt   time.Time
+}
+
+// Config 读取热门排序配置，读取失败时使用默认配置
+func (s *HotRankService) Config() ranking.Config {
+	values, err := s.settingService.Values(hotRankSettingKeys)
+	if err != nil {
+		log.Printf("读取热门排序配置失败，使用默认配置: %v", err)
+	}
+	return hotRankConfig(values)
+}
+
+// Refresh 重新计算被标记的内容和当前榜单成员的热度，返回计算的内容数。
+// 榜单不存在（首次运行或被清空）或排序配置变化时从数据库完整重建一次，
+// 否则榜单外的内容不会按新的公式和权重重新排序
+func (s *HotRankService) Refresh(targetType string) (int, error) {
+	key, ok := hotRankKeys[targetType]
+	if !ok {
+		return 0, ErrViewTargetInvalid
+	}
+	cfg := s.Config()
+	ranker := cfg.Ranker()
+	digest := hotRankConfigDigest(cfg)
+	exists, err := database.RDB.Exists(database.Ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	stored, err := database.RDB.Get(database.Ctx, key+hotConfigSuffix).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	if exists == 0 || stored != digest {
+		return s.rebuild(targetType, key, digest, ranker)
+	}
+
+	// 先移出标记再读取计数，读取期间的新互动会重新标记，留到下次计算
+	dirtyKey := hotDirtyPrefix + targetType
+	dirty, err := database.RDB.SMembers(database.Ctx, dirtyKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(dirty) > 0 {
+		if err := database.RDB.SRem(database.Ctx, dirtyKey, toInterfaces(dirty)...).Err(); err != nil {
+			return 0, err
+		}
+	}
+	restore := func() {
+		if len(dirty) > 0 {
+			database.RDB.SAdd(database.Ctx, dirtyKey, toInterfaces(dirty)...)
+		}
+	}
+
+	// 榜单成员也要重新计算，时间衰减公式的分数会随时间变化
+	members, err := database.RDB.ZRange(database.Ctx, key, 0, -1).Result()
+	if err != nil {
+		restore()
+		return 0, err
+	}
+	ids := parseHotRankIDs(append(dirty, members...))
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	scores := make(map[uint]float64, len(ids))
+	for start := 0; start < len(ids); start += hotRankBatchSize {
+		end := start + hotRankBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var rows []hotRankRow
+		if err := hotRankQuery(targetType).Where("id IN ?", ids[start:end]).Find(&rows).Error; err != nil {
+			restore()
+			return 0, err
+		}
+		for _, row := range rows {
+			scores[row.ID] = hotRankScore(ranker, row, now)
+		}
+	}
+
+	// 已删除或不再公开的内容从榜单中移除
+	pipe := database.RDB.Pipeline()
+	zs := make([]*redis.Z, 0, len(scores))
+	for _, id := range ids {
+		if score, ok := scores[id]; ok {
+			zs = append(zs, &redis.Z{Score: score, Member: strconv.FormatUint(uint64(id), 10)})
+		} else {
+			pipe.ZRem(database.Ctx, key, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	if len(zs) > 0 {
+		pipe.ZAdd(database.Ctx, key, zs...)
+	}
+	pipe.ZRemRangeByRank(database.Ctx, key, 0, -(hotRankSize + 1))
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		restore()
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// rebuild 按主键分批读取全部已发布内容，在临时 key 中重建榜单后替换，同时记录使用的配置摘要
+func (s *HotRankService) rebuild(targetType, key, digest string, ranker ranking.Ranker) (int, error) {
+	tmpKey := key + ":rebuild"
+	if err := database.RDB.Del(database.Ctx, tmpKey).Err(); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	var lastID uint
+	total := 0
+	for {
+		var rows []hotRankRow
+		if err := hotRankQuery(targetType).Where("id > ?", lastID).
+			Order("id").Limit(hotRankBatchSize).Find(&rows).Error; err != nil {
+			return total, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		zs := make([]*redis.Z, len(rows))
+		for i, row := range rows {
+			zs[i] = &redis.Z{Score: hotRankScore(ranker, row, now), Member: strconv.FormatUint(uint64(row.ID), 10)}
+		}
+		pipe := database.RDB.Pipeline()
+		pipe.ZAdd(database.Ctx, tmpKey, zs...)
+		pipe.ZRemRangeByRank(database.Ctx, tmpKey, 0, -(hotRankSize + 1))
+		if _, err := pipe.Exec(database.Ctx); err != nil {
+			return total, err
+		}
+		total += len(rows)
+		lastID = rows[len(rows)-1].ID
+	}
+	pipe := database.RDB.TxPipeline()
+	if total == 0 {
+		pipe.Del(database.Ctx, key)
+	} else {
+		pipe.Rename(database.Ctx, tmpKey, key)
+	}
+	pipe.Set(database.Ctx, key+hotConfigSuffix, digest, 0)
+	_, err := pipe.Exec(database.Ctx)
+	return total, err
+}
+
+// markHot 标记内容的互动计数有变化，等待下次计算热度；标记失败只影响排序的及时性
+func markHot(targetType string, ids ...uint) {
+	if len(ids) == 0 {
+		return
+	}
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	if err := database.RDB.SAdd(database.Ctx, hotDirtyPrefix+targetType, members...).Err(); err != nil {
+		log.Printf("标记 %s 热度变化失败: %v", targetType, err)
+	}
+}
+
+// markCommentHot 标记评论所属的文章或作品
+func markCommentHot(articleID, workID *uint) {
+	if articleID != nil && *articleID > 0 {
+		markHot(models.ViewTargetArticle, *articleID)
+	}
+	if workID != nil && *workID > 0 {
+		markHot(models.ViewTargetWork, *workID)
+	}
+}
+
+// hotRankQuery 已发布内容的计数和发布时间，定时发布的文章按实际发布时间计算
+func hotRankQuery(targetType string) *gorm.DB {
+	if targetType == models.ViewTargetWork {
+		return database.DB.Model(&models.Work{}).
+			Select("id, view_count, comment_count, like_count, favorite_count, created_at AS published_at").
+			Where("status = ?", 1)
+	}
+	return database.DB.Model(&models.Article{}).
+		Select("id, view_count, comment_count, like_count, favorite_count, COALESCE(publish_at, created_at) AS published_at").
+		Where("status = ?", models.ArticleStatusPublished)
+}
+
+func hotRankScore(ranker ranking.Ranker, row hotRankRow, now time.Time) float64 {
+	return ranker.Score(ranking.Counters{
+		Views:     row.ViewCount,
+		Comments:  row.CommentCount,
+		Likes:     row.LikeCount,
+		Favorites: row.FavoriteCount,
+	}, row.PublishedAt, now)
+}
+
+// hotRankConfig 把配置值解析为排序配置，缺失或无效的值使用默认值；权重允许为 0，表示不计入该类互动
+func hotRankConfig(values map[string]string) ranking.Config {
+	cfg := ranking.DefaultConfig()
+	if formula := strings.TrimSpace(values[models.SettingHotRankFormula]); formula != "" {
+		cfg.Formula = formula
+	}
+	if gravity, ok := parseSettingFloat(values[models.SettingHotRankGravity]); ok && gravity > 0 {
+		cfg.Gravity = gravity
+	}
+	if hours, ok := parseSettingFloat(values[models.SettingHotRankTimeScale]); ok && hours > 0 {
+		cfg.TimeScale = time.Duration(hours * float64(time.Hour))
+	}
+	weights := []struct {
+		key    string
+		target *float64
+	}{
+		{models.SettingHotRankWeightView, &cfg.Weights.View},
+		{models.SettingHotRankWeightComment, &cfg.Weights.Comment},
+		{models.SettingHotRankWeightLike, &cfg.Weights.Like},
+		{models.SettingHotRankWeightFavorite, &cfg.Weights.Favorite},
+	}
+	for _, w := range weights {
+		if weight, ok := parseSettingFloat(values[w.key]); ok && weight >= 0 {
+			*w.target = weight
+		}
+	}
+	return cfg
+}
+
+// hotRankConfigDigest 排序配置的摘要，用于判断榜单是否按当前配置计算
+func hotRankConfigDigest(cfg ranking.Config) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%g|%d|%g|%g|%g|%g",
+		strings.ToLower(strings.TrimSpace(cfg.Formula)), cfg.Gravity, cfg.TimeScale,
+		cfg.Weights.View, cfg.Weights.Comment, cfg.Weights.Like, cfg.Weights.Favorite)))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseSettingFloat(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// parseHotRankIDs 解析并去重 Redis 中保存的内容 ID，忽略无效值
+func parseHotRankIDs(values []string) []uint {
+	seen := make(map[uint]bool, len(values))
+	ids := make([]uint, 0, len(values))
+	for _, value := range values {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 || seen[uint(id)] {
+			continue
+		}
+		seen[uint(id)] = true
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/ranking"
+)
+
+func TestHotRankConfig(t *testing.T) {
+	if got := hotRankConfig(nil); !reflect.DeepEqual(got, ranking.DefaultConfig()) {
+		t.Fatalf("未配置时应使用默认配置，得到 %+v", got)
+	}
+
+	cfg := hotRankConfig(map[string]string{
+		models.SettingHotRankFormula:        "reddit",
+		models.SettingHotRankGravity:        "1.5",
+		models.SettingHotRankTimeScale:      "6",
+		models.SettingHotRankWeightView:     "0",
+		models.SettingHotRankWeightComment:  " 10 ",
+		models.SettingHotRankWeightLike:     "-1",
+		models.SettingHotRankWeightFavorite: "abc",
+	})
+	defaults := ranking.DefaultWeights()
+	want := ranking.Config{
+		Formula:   "reddit",
+		Gravity:   1.5,
+		TimeScale: 6 * time.Hour,
+		Weights:   ranking.Weights{View: 0, Comment: 10, Like: defaults.Like, Favorite: defaults.Favorite},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("hotRankConfig = %+v, want %+v", cfg, want)
+	}
+
+	cfg = hotRankConfig(map[string]string{
+		models.SettingHotRankGravity:   "0",
+		models.SettingHotRankTimeScale: "NaN",
+	})
+	if cfg.Gravity != ranking.DefaultGravity || cfg.TimeScale != ranking.DefaultTimeScale {
+		t.Fatalf("无效的公式参数应使用默认值，得到 %+v", cfg)
+	}
+}
+
+func TestHotRankConfigDigest(t *testing.T) {
+	cfg := ranking.DefaultConfig()
+	if hotRankConfigDigest(cfg) != hotRankConfigDigest(ranking.DefaultConfig()) {
+		t.Fatal("相同配置的摘要应相同")
+	}
+	changed := cfg
+	changed.Weights.Favorite++
+	if hotRankConfigDigest(changed) == hotRankConfigDigest(cfg) {
+		t.Fatal("权重变化后摘要应变化")
+	}
+	changed = cfg
+	changed.Formula = ranking.FormulaReddit
+	if hotRankConfigDigest(changed) == hotRankConfigDigest(cfg) {
+		t.Fatal("公式变化后摘要应变化")
+	}
+}
+
+func TestParseHotRankIDs(t *testing.T) {
+	got := parseHotRankIDs([]string{"3", "1", "3", "0", "x", "-2", "7"})
+	if want := []uint{3, 1, 7}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseHotRankIDs = %v, want %v", got, want)
+	}
+}
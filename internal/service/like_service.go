@@ -55,6 +55,7 @@ func (s *LikeService) LikeWork(userID, workID uint) error {
 
 		// 清除作品缓存
 		database.DeleteCache(fmt.Sprintf("work:%d", workID))
+		markHot(models.ViewTargetWork, workID)
 		return nil
 	}
 
@@ -80,6 +81,7 @@ func (s *LikeService) LikeWork(userID, workID uint) error {
 
 	// 清除作品缓存
 	database.DeleteCache(fmt.Sprintf("work:%d", workID))
+	markHot(models.ViewTargetWork, workID)
 
 	// 发送通知给作品作者
 	if work.AuthorID != userID {
@@ -117,6 +119,7 @@ func (s *LikeService) LikeArticle(userID, articleID uint) error {
 
 		// 清除文章缓存
 		database.DeleteCache(fmt.Sprintf("article:%d", articleID))
+		markHot(models.ViewTargetArticle, articleID)
 		return nil
 	}
 
@@ -137,6 +140,7 @@ func (s *LikeService) LikeArticle(userID, articleID uint) error {
 
 	// 清除文章缓存
 	database.DeleteCache(fmt.Sprintf("article:%d", articleID))
+	markHot(models.ViewTargetArticle, articleID)
 
 	// 发送通知给文章作者
 	var article models.Article
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/captcha"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	loginFailureWindow = time.Hour   // 失败计数在最后一次失败后保留的时长
+	loginMaxBackoff    = time.Minute // 退避等待的上限
+	loginLockPrefix    = "auth:login:lock:"
+	loginFailPrefix    = "auth:login:fail:"
+
+	LoginLockUser = "user"
+	LoginLockIP   = "ip"
+
+	loginLockBackoff = "backoff"
+	loginLockLockout = "lockout"
+)
+
+var (
+	ErrCaptchaRequired = errors.New("请完成人机验证")
+	ErrCaptchaInvalid  = errors.New("人机验证失败，请重试")
+	ErrLoginLockType   = errors.New("无效的锁定类型")
+)
+
+// LoginBlockedError 登录处于退避或锁定期间
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	if e.RetryAfter < time.Minute {
+		return fmt.Sprintf("登录失败次数过多，请 %d 秒后再试", int(e.RetryAfter.Seconds()+0.5))
+	}
+	return fmt.Sprintf("登录失败次数过多，请 %d 分钟后再试", int(e.RetryAfter.Minutes()+0.5))
+}
+
+var (
+	loginCaptchaOnce sync.Once
+	loginCaptcha     captcha.Verifier
+)
+
+// loginCaptchaVerifier 按配置创建人机验证校验器，未配置或配置有误时不启用
+func loginCaptchaVerifier() captcha.Verifier {
+	loginCaptchaOnce.Do(func() {
+		if config.AppConfig == nil {
+			return
+		}
+		verifier, err := captcha.New(config.AppConfig.Auth.Captcha, nil)
+		if err != nil {
+			log.Printf("初始化人机验证失败，已禁用: %v", err)
+			return
+		}
+		loginCaptcha = verifier
+	})
+	return loginCaptcha
+}
+
+// LoginGuardService 登录失败限制：按用户名和 IP 分别计数，超过阈值后指数退避、要求人机验证并临时锁定
+type LoginGuardService struct {
+	cfg      config.LoginGuardConfig
+	verifier captcha.Verifier
+	audit    *AuditService
+}
+
+func NewLoginGuardService() *LoginGuardService {
+	var cfg config.LoginGuardConfig
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.Auth.LoginGuard
+	}
+	return &LoginGuardService{cfg: loginGuardDefaults(cfg), verifier: loginCaptchaVerifier(), audit: NewAuditService()}
+}
+
+func loginGuardDefaults(cfg config.LoginGuardConfig) config.LoginGuardConfig {
+	if cfg.ChallengeAfter <= 0 {
+		cfg.ChallengeAfter = 3
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.IPMaxAttempts <= 0 {
+		cfg.IPMaxAttempts = 50
+	}
+	if cfg.LockoutMinutes <= 0 {
+		cfg.LockoutMinutes = 15
+	}
+	return cfg
+}
+
+// CaptchaInfo 需要人机验证时返回给前端的组件信息
+func (s *LoginGuardService) CaptchaInfo() *models.CaptchaInfo {
+	if s.verifier == nil {
+		return nil
+	}
+	return &models.CaptchaInfo{Provider: s.verifier.Provider(), SiteKey: s.verifier.SiteKey()}
+}
+
+// Check 校验密码前调用：处于锁定期返回 *LoginBlockedError，失败次数较多时要求通过人机验证
+func (s *LoginGuardService) Check(ctx context.Context, username, ip, captchaToken string) error {
+	userKey, ipKey := loginKey(loginLockPrefix, LoginLockUser, username), loginKey(loginLockPrefix, LoginLockIP, ip)
+	pipe := database.RDB.Pipeline()
+	userTTL := pipe.PTTL(database.Ctx, userKey)
+	ipTTL := pipe.PTTL(database.Ctx, ipKey)
+	failures := pipe.MGet(database.Ctx, loginKey(loginFailPrefix, LoginLockUser, username), loginKey(loginFailPrefix, LoginLockIP, ip))
+	if _, err := pipe.Exec(database.Ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	if retry := max(userTTL.Val(), ipTTL.Val()); retry > 0 {
+		return &LoginBlockedError{RetryAfter: retry}
+	}
+
+	if s.verifier == nil {
+		return nil
+	}
+	values := failures.Val()
+	userFailures, ipFailures := redisInt(values, 0), redisInt(values, 1)
+	if !s.challengeRequired(userFailures, ipFailures) {
+		return nil
+	}
+	if strings.TrimSpace(captchaToken) == "" {
+		return ErrCaptchaRequired
+	}
+	if err := s.verifier.Verify(ctx, captchaToken, ip); err != nil {
+		if errors.Is(err, captcha.ErrInvalid) || errors.Is(err, captcha.ErrMissingResponse) {
+			return ErrCaptchaInvalid
+		}
+		// 校验服务不可用时放行，避免第三方故障导致所有人无法登录，退避和锁定仍然生效
+		log.Printf("人机验证服务异常: %v", err)
+	}
+	return nil
+}
+
+// challengeRequired 同一 IP 的阈值放宽到用户名的三倍，避免同一出口的正常用户被频繁打扰
+func (s *LoginGuardService) challengeRequired(userFailures, ipFailures int64) bool {
+	return userFailures >= int64(s.cfg.ChallengeAfter) || ipFailures >= int64(s.cfg.ChallengeAfter*3)
+}
+
+// Fail 记录一次失败的登录，按失败次数设置退避或锁定
+func (s *LoginGuardService) Fail(username, ip string) {
+	userFailKey, ipFailKey := loginKey(loginFailPrefix, LoginLockUser, username), loginKey(loginFailPrefix, LoginLockIP, ip)
+	pipe := database.RDB.TxPipeline()
+	userIncr := pipe.Incr(database.Ctx, userFailKey)
+	pipe.Expire(database.Ctx, userFailKey, loginFailureWindow)
+	ipIncr := pipe.Incr(database.Ctx, ipFailKey)
+	pipe.Expire(database.Ctx, ipFailKey, loginFailureWindow)
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		log.Printf("记录登录失败次数失败: %v", err)
+		return
+	}
+
+	lockout := time.Duration(s.cfg.LockoutMinutes) * time.Minute
+	userFailures, ipFailures := userIncr.Val(), ipIncr.Val()
+	if userFailures >= int64(s.cfg.MaxAttempts) {
+		s.lock(LoginLockUser, username, loginLockLockout, lockout, userFailures)
+	} else if delay := loginBackoff(userFailures, s.cfg.ChallengeAfter); delay > 0 {
+		s.lock(LoginLockUser, username, loginLockBackoff, delay, userFailures)
+	}
+	if ipFailures >= int64(s.cfg.IPMaxAttempts) {
+		s.lock(LoginLockIP, ip, loginLockLockout, lockout, ipFailures)
+	}
+}
+
+// Blocked 校验两步验证码前调用：用户名处于退避或锁定期时返回 *LoginBlockedError。
+// 验证码错误同样通过 Fail 计数，重新输入密码获取新的登录挑战不会重置次数
+func (s *LoginGuardService) Blocked(username string) error {
+	ttl, err := database.RDB.PTTL(database.Ctx, loginKey(loginLockPrefix, LoginLockUser, username)).Result()
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		return &LoginBlockedError{RetryAfter: ttl}
+	}
+	return nil
+}
+
+// Succeed 登录成功后清除该用户名的失败记录；IP 计数保留，防止用一个有效账号重置计数
+func (s *LoginGuardService) Succeed(username string) {
+	database.RDB.Del(database.Ctx, loginKey(loginFailPrefix, LoginLockUser, username), loginKey(loginLockPrefix, LoginLockUser, username))
+}
+
+func (s *LoginGuardService) lock(kind, value, reason string, ttl time.Duration, failures int64) {
+	if err := database.RDB.Set(database.Ctx, loginKey(loginLockPrefix, kind, value), reason, ttl).Err(); err != nil {
+		log.Printf("设置登录锁定失败: %s %s, 错误: %v", kind, value, err)
+		return
+	}
+	if reason != loginLockLockout {
+		return
+	}
+	entry := &models.AuditLog{
+		Action:     models.AuditActionLoginLockout,
+		TargetType: kind,
+		TargetID:   normalizeLoginValue(kind, value),
+	}
+	if kind == LoginLockIP {
+		entry.IP = value
+	}
+	s.audit.Record(entry, map[string]interface{}{"failures": failures, "lockout_seconds": int(ttl.Seconds())})
+}
+
+// Locks 当前所有登录锁定（含退避）
+func (s *LoginGuardService) Locks() ([]*models.LoginLock, error) {
+	var keys []string
+	iter := database.RDB.Scan(database.Ctx, 0, loginLockPrefix+"*", 100).Iterator()
+	for iter.Next(database.Ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	locks := make([]*models.LoginLock, 0, len(keys))
+	for _, key := range keys {
+		kind, value, ok := strings.Cut(strings.TrimPrefix(key, loginLockPrefix), ":")
+		if !ok {
+			continue
+		}
+		pipe := database.RDB.Pipeline()
+		reason := pipe.Get(database.Ctx, key)
+		ttl := pipe.PTTL(database.Ctx, key)
+		failures := pipe.Get(database.Ctx, loginKey(loginFailPrefix, kind, value))
+		if _, err := pipe.Exec(database.Ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		if ttl.Val() <= 0 {
+			continue // 扫描期间已过期
+		}
+		count, _ := failures.Int64()
+		locks = append(locks, &models.LoginLock{
+			Type:      kind,
+			Value:     value,
+			Reason:    reason.Val(),
+			Failures:  count,
+			ExpiresAt: time.Now().Add(ttl.Val()),
+		})
+	}
+	return locks, nil
+}
+
+// Unlock 管理员解除锁定并清空失败计数（审计由管理后台的审计中间件记录）
+func (s *LoginGuardService) Unlock(kind, value string) error {
+	if kind != LoginLockUser && kind != LoginLockIP {
+		return ErrLoginLockType
+	}
+	return database.RDB.Del(database.Ctx, loginKey(loginLockPrefix, kind, value), loginKey(loginFailPrefix, kind, value)).Err()
+}
+
+// loginBackoff 达到阈值后每多失败一次等待时间翻倍：1s, 2s, 4s ... 最长 1 分钟
+func loginBackoff(failures int64, challengeAfter int) time.Duration {
+	if failures < int64(challengeAfter) {
+		return 0
+	}
+	shift := failures - int64(challengeAfter)
+	if shift >= 6 {
+		return loginMaxBackoff
+	}
+	delay := time.Second << shift
+	if delay > loginMaxBackoff {
+		return loginMaxBackoff
+	}
+	return delay
+}
+
+// normalizeLoginValue 用户名不区分大小写和首尾空格，避免变换大小写绕过计数
+func normalizeLoginValue(kind, value string) string {
+	if kind == LoginLockUser {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+	return value
+}
+
+func loginKey(prefix, kind, value string) string {
+	return prefix + kind + ":" + normalizeLoginValue(kind, value)
+}
+
+func redisInt(values []interface{}, index int) int64 {
+	if index >= len(values) {
+		return 0
+	}
+	str, ok := values[index].(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(str, 10, 64)
+	return n
+}
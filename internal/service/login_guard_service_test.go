@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{8, 32 * time.Second},
+		{9, time.Minute},
+		{100, time.Minute},
+	}
+	for _, test := range tests {
+		if got := loginBackoff(test.failures, 3); got != test.want {
+			t.Errorf("loginBackoff(%d, 3) = %v, want %v", test.failures, got, test.want)
+		}
+	}
+}
+
+func TestLoginBlockedErrorMessage(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       string
+	}{
+		{1500 * time.Millisecond, "登录失败次数过多，请 2 秒后再试"},
+		{59 * time.Second, "登录失败次数过多，请 59 秒后再试"},
+		{14*time.Minute + 50*time.Second, "登录失败次数过多，请 15 分钟后再试"},
+	}
+	for _, test := range tests {
+		if got := (&LoginBlockedError{RetryAfter: test.retryAfter}).Error(); got != test.want {
+			t.Errorf("LoginBlockedError{%v}.Error() = %q, want %q", test.retryAfter, got, test.want)
+		}
+	}
+}
+
+func TestLoginGuardThresholds(t *testing.T) {
+	s := &LoginGuardService{cfg: loginGuardDefaults(config.LoginGuardConfig{ChallengeAfter: 2})}
+	if s.cfg.MaxAttempts != 10 || s.cfg.IPMaxAttempts != 50 || s.cfg.LockoutMinutes != 15 {
+		t.Fatalf("defaults = %+v", s.cfg)
+	}
+	tests := []struct {
+		user, ip int64
+		want     bool
+	}{
+		{0, 0, false},
+		{1, 5, false},
+		{2, 0, true},
+		{0, 6, true},
+	}
+	for _, test := range tests {
+		if got := s.challengeRequired(test.user, test.ip); got != test.want {
+			t.Errorf("challengeRequired(%d, %d) = %v, want %v", test.user, test.ip, got, test.want)
+		}
+	}
+
+	if loginKey(loginFailPrefix, LoginLockUser, " Alice ") != "auth:login:fail:user:alice" {
+		t.Errorf("username key is not normalized: %s", loginKey(loginFailPrefix, LoginLockUser, " Alice "))
+	}
+	if loginKey(loginLockPrefix, LoginLockIP, "::1") != "auth:login:lock:ip:::1" {
+		t.Errorf("ip key = %s", loginKey(loginLockPrefix, LoginLockIP, "::1"))
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"gorm.io/gorm"
+)
+
+const markdownRenderBatchSize = 200
+
+// MarkdownRenderService 批量重新渲染已保存的正文，用于渲染规则变化后刷新 HTML 和目录
+type MarkdownRenderService struct{}
+
+func NewMarkdownRenderService() *MarkdownRenderService {
+	return &MarkdownRenderService{}
+}
+
+// RenderAll 重新渲染全部文章和已发布的文档，不修改更新时间。
+// 文档只在发布时生成 HTML，草稿保持原样
+func (s *MarkdownRenderService) RenderAll() (articles, docs int, err error) {
+	var articleBatch []models.Article
+	err = database.DB.Select("id, content").
+		FindInBatches(&articleBatch, markdownRenderBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, article := range articleBatch {
+				html, toc, err := renderMarkdownWithTOC(article.Content)
+				if err != nil {
+					return fmt.Errorf("渲染文章 %d 失败: %w", article.ID, err)
+				}
+				if err := database.DB.Model(&models.Article{}).Where("id = ?", article.ID).
+					UpdateColumns(map[string]interface{}{"content_html": html, "content_toc": toc}).Error; err != nil {
+					return err
+				}
+				articles++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return articles, docs, err
+	}
+	if err := database.DeleteCachePattern("article:*"); err != nil {
+		return articles, docs, err
+	}
+
+	var docBatch []models.Doc
+	err = database.DB.Select("id, content").Where("status = ?", models.DocStatusPublished).
+		FindInBatches(&docBatch, markdownRenderBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, doc := range docBatch {
+				html, toc, err := renderMarkdownWithTOC(doc.Content)
+				if err != nil {
+					return fmt.Errorf("渲染文档 %d 失败: %w", doc.ID, err)
+				}
+				if err := database.DB.Model(&models.Doc{}).Where("id = ?", doc.ID).
+					UpdateColumns(map[string]interface{}{"content_html": html, "content_toc": toc}).Error; err != nil {
+					return err
+				}
+				docs++
+			}
+			return nil
+		}).Error
+	return articles, docs, err
+}
@@ -332,6 +332,41 @@ func (s *NotificationService) CreateWorkAuditNotification(workID uint, status in
 	return nil
 }
 
+// CreateArticlePublishNotifications 文章发布后通知作者的所有关注者
+func (s *NotificationService) CreateArticlePublishNotifications(article *models.Article) error {
+	var followerIDs []uint
+	if err := database.DB.Model(&models.UserFollow{}).
+		Where("following_id = ?", article.AuthorID).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		return fmt.Errorf("查询关注者失败: %w", err)
+	}
+	if len(followerIDs) == 0 {
+		return nil
+	}
+
+	authorID := article.AuthorID
+	articleID := article.ID
+	content := fmt.Sprintf("发布了新文章《%s》", article.Title)
+	notifications := make([]models.Notification, 0, len(followerIDs))
+	for _, followerID := range followerIDs {
+		if followerID == authorID {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:     followerID,
+			FromUserID: &authorID,
+			Type:       "article_publish",
+			Content:    content,
+			ArticleID:  &articleID,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return database.DB.CreateInBatches(notifications, 500).Error
+}
+
 // GetNotificationMessage 获取通知消息内容
 func (s *NotificationService) GetNotificationMessage(notification *models.Notification) string {
 	var fromUserName string
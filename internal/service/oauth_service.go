@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/oauth"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const (
+	OAuthStateTTL  = 10 * time.Minute // 授权流程的有效期，也是浏览器绑定 Cookie 的有效期
+	oauthResultTTL = time.Minute
+
+	oauthModeLogin = "login"
+	oauthModeLink  = "link"
+)
+
+var (
+	ErrOAuthProviderNotFound = errors.New("不支持的第三方登录方式")
+	ErrOAuthStateInvalid     = errors.New("第三方登录已过期，请重新发起")
+	ErrOAuthSignupDisabled   = errors.New("该第三方账号尚未绑定本站账号，请先注册并在个人资料中绑定")
+	ErrOAuthEmailConflict    = errors.New("该邮箱已注册，请使用密码登录后在个人资料中绑定")
+	ErrOAuthIdentityLinked   = errors.New("该第三方账号已绑定其他用户")
+	ErrOAuthAlreadyLinked    = errors.New("已绑定该登录方式，请先解绑")
+	ErrOAuthLastLoginMethod  = errors.New("这是唯一的登录方式，请先通过找回密码设置密码后再解绑")
+	ErrOAuthIdentityNotFound = errors.New("绑定记录不存在")
+	ErrOAuthAccountDisabled  = errors.New("账号不存在或已被禁用")
+	ErrOAuthEmailMissing     = errors.New("第三方账号未提供邮箱，无法注册")
+)
+
+var (
+	oauthProvidersOnce sync.Once
+	oauthProviders     map[string]oauth.Provider
+	oauthProviderCfgs  map[string]config.OAuthProviderConfig
+	oauthProviderOrder []string
+)
+
+// loadOAuthProviders 按配置初始化第三方登录，配置有误的提供方跳过并记录日志
+func loadOAuthProviders() {
+	oauthProvidersOnce.Do(func() {
+		oauthProviders = make(map[string]oauth.Provider)
+		oauthProviderCfgs = make(map[string]config.OAuthProviderConfig)
+		if config.AppConfig == nil {
+			return
+		}
+		for _, cfg := range config.AppConfig.Auth.Providers {
+			if cfg.Name == "" || cfg.ClientID == "" {
+				log.Printf("第三方登录配置缺少 name 或 client_id，已跳过: %q", cfg.Name)
+				continue
+			}
+			redirectURL := cfg.RedirectURL
+			if redirectURL == "" {
+				redirectURL = publicURL("/api/auth/oauth/" + url.PathEscape(cfg.Name) + "/callback")
+			}
+			provider, err := oauth.New(cfg, redirectURL, nil)
+			if err != nil {
+				log.Printf("初始化第三方登录 %s 失败: %v", cfg.Name, err)
+				continue
+			}
+			oauthProviders[cfg.Name] = provider
+			oauthProviderCfgs[cfg.Name] = cfg
+			oauthProviderOrder = append(oauthProviderOrder, cfg.Name)
+		}
+	})
+}
+
+// OAuthService 第三方登录与账号绑定
+type OAuthService struct{}
+
+func NewOAuthService() *OAuthService {
+	loadOAuthProviders()
+	return &OAuthService{}
+}
+
+type oauthStateRecord struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Mode     string `json:"mode"`
+	UserID   uint   `json:"user_id,omitempty"`
+	Redirect string `json:"redirect,omitempty"`
+	Binding  string `json:"binding"` // 发起授权的浏览器 Cookie 的摘要
+}
+
+type oauthResultRecord struct {
+	UserID uint `json:"user_id"`
+}
+
+// Providers 已启用的第三方登录方式
+func (s *OAuthService) Providers() []*models.OAuthProviderResponse {
+	list := make([]*models.OAuthProviderResponse, 0, len(oauthProviderOrder))
+	for _, name := range oauthProviderOrder {
+		list = append(list, &models.OAuthProviderResponse{
+			Name:        name,
+			DisplayName: oauthProviders[name].DisplayName(),
+			Type:        oauthProviderCfgs[name].Type,
+		})
+	}
+	return list
+}
+
+// StartLogin 生成第三方登录授权地址，redirect 为登录成功后返回的站内路径。
+// binding 需要写入发起授权的浏览器的 Cookie，回调时必须带回
+func (s *OAuthService) StartLogin(ctx context.Context, providerName, redirect string) (authURL, binding string, err error) {
+	return s.start(ctx, providerName, &oauthStateRecord{Mode: oauthModeLogin, Redirect: safeRedirect(redirect)})
+}
+
+// StartLink 已登录用户绑定第三方账号，binding 的用法同 StartLogin
+func (s *OAuthService) StartLink(ctx context.Context, providerName string, userID uint) (authURL, binding string, err error) {
+	return s.start(ctx, providerName, &oauthStateRecord{Mode: oauthModeLink, UserID: userID})
+}
+
+// start 保存授权状态并生成授权地址。状态与发起授权的浏览器绑定，
+// 授权地址被转发给他人时，对方浏览器没有对应 Cookie，回调会被拒绝
+func (s *OAuthService) start(ctx context.Context, providerName string, record *oauthStateRecord) (string, string, error) {
+	provider, ok := oauthProviders[providerName]
+	if !ok {
+		return "", "", ErrOAuthProviderNotFound
+	}
+	state, err := oauth.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oauth.NewCodeVerifier()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oauth.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	binding, err := oauth.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	record.Provider = providerName
+	record.Verifier = verifier
+	record.Nonce = nonce
+	record.Binding = oauthBindingHash(binding)
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", "", err
+	}
+	if err := database.RDB.Set(database.Ctx, oauthStateKey(state), data, OAuthStateTTL).Err(); err != nil {
+		return "", "", err
+	}
+	authURL, err := provider.AuthCodeURL(ctx, &oauth.AuthRequest{
+		State:         state,
+		CodeChallenge: oauth.CodeChallengeS256(verifier),
+		Nonce:         nonce,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, binding, nil
+}
+
+// Callback 处理身份提供方回调，返回需要跳转的前端地址；出错时地址中带 error 参数。
+// binding 为浏览器 Cookie 中保存的值，必须与发起授权时一致
+func (s *OAuthService) Callback(ctx context.Context, providerName, code, state, binding, providerError string) string {
+	record, err := s.consumeState(providerName, state, binding)
+	if err != nil {
+		return oauthCallbackURL(url.Values{"error": {err.Error()}})
+	}
+	if providerError != "" || code == "" {
+		return oauthCallbackURL(url.Values{"error": {"已取消第三方授权"}, "mode": {record.Mode}})
+	}
+
+	identity, err := oauthProviders[providerName].Exchange(ctx, code, record.Verifier, record.Nonce)
+	if err != nil {
+		log.Printf("第三方登录 %s 换取身份失败: %v", providerName, err)
+		return oauthCallbackURL(url.Values{"error": {"第三方账号验证失败，请重试"}, "mode": {record.Mode}})
+	}
+
+	if record.Mode == oauthModeLink {
+		if err := s.link(record.UserID, providerName, identity); err != nil {
+			return oauthCallbackURL(url.Values{"error": {publicOAuthError(err)}, "mode": {oauthModeLink}})
+		}
+		return oauthCallbackURL(url.Values{"linked": {providerName}, "mode": {oauthModeLink}})
+	}
+
+	user, err := s.resolveLogin(providerName, identity)
+	if err != nil {
+		return oauthCallbackURL(url.Values{"error": {publicOAuthError(err)}})
+	}
+	// 令牌不出现在 URL 中：前端用一次性 code 调用 Exchange 换取登录结果
+	resultCode, err := oauth.RandomToken()
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(oauthResultRecord{UserID: user.ID})
+		if err == nil {
+			err = database.RDB.Set(database.Ctx, oauthResultKey(resultCode), data, oauthResultTTL).Err()
+		}
+	}
+	if err != nil {
+		log.Printf("保存第三方登录结果失败: %v", err)
+		return oauthCallbackURL(url.Values{"error": {"服务暂时不可用"}})
+	}
+	values := url.Values{"code": {resultCode}}
+	if record.Redirect != "" {
+		values.Set("redirect", record.Redirect)
+	}
+	return oauthCallbackURL(values)
+}
+
+// Exchange 使用回调页拿到的一次性 code 完成登录，需要两步验证时返回登录挑战
+func (s *OAuthService) Exchange(code string, client *models.ClientInfo) (*models.TokenPair, *models.TwoFactorChallenge, *models.User, error) {
+	key := oauthResultKey(code)
+	data, err := database.RDB.Get(database.Ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil, nil, ErrOAuthStateInvalid
+		}
+		return nil, nil, nil, err
+	}
+	// 并发兑换时只有删除成功的一方继续
+	deleted, err := database.RDB.Del(database.Ctx, key).Result()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if deleted == 0 {
+		return nil, nil, nil, ErrOAuthStateInvalid
+	}
+	var record oauthResultRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, nil, nil, ErrOAuthStateInvalid
+	}
+
+	user, err := loadActiveUser(record.UserID)
+	if err != nil {
+		return nil, nil, nil, ErrOAuthStateInvalid
+	}
+	tokens, challenge, err := NewTwoFactorService().BeginLogin(TokenScopeUser, user, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tokens, challenge, user, nil
+}
+
+// ListIdentities 当前用户已绑定的第三方账号
+func (s *OAuthService) ListIdentities(userID uint) ([]*models.UserIdentity, error) {
+	var identities []*models.UserIdentity
+	if err := database.DB.Where("user_id = ?", userID).Order("id ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// Unlink 解绑第三方账号；没有设置密码的账号不能解绑最后一个登录方式
+func (s *OAuthService) Unlink(userID, identityID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Select("id", "password").First(&user, userID).Error; err != nil {
+			return err
+		}
+		var identity models.UserIdentity
+		if err := tx.Where("id = ? AND user_id = ?", identityID, userID).First(&identity).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOAuthIdentityNotFound
+			}
+			return err
+		}
+		if user.Password == "" {
+			var count int64
+			if err := tx.Model(&models.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count <= 1 {
+				return ErrOAuthLastLoginMethod
+			}
+		}
+		return tx.Delete(&identity).Error
+	})
+}
+
+func (s *OAuthService) consumeState(providerName, state, binding string) (*oauthStateRecord, error) {
+	if state == "" || binding == "" {
+		return nil, ErrOAuthStateInvalid
+	}
+	key := oauthStateKey(state)
+	data, err := database.RDB.Get(database.Ctx, key).Bytes()
+	if err != nil {
+		return nil, ErrOAuthStateInvalid
+	}
+	if deleted, err := database.RDB.Del(database.Ctx, key).Result(); err != nil || deleted == 0 {
+		return nil, ErrOAuthStateInvalid
+	}
+	var record oauthStateRecord
+	if err := json.Unmarshal(data, &record); err != nil || record.Provider != providerName {
+		return nil, ErrOAuthStateInvalid
+	}
+	if !oauthBindingMatch(&record, binding) {
+		return nil, ErrOAuthStateInvalid
+	}
+	if _, ok := oauthProviders[providerName]; !ok {
+		return nil, ErrOAuthProviderNotFound
+	}
+	return &record, nil
+}
+
+// resolveLogin 找到第三方账号对应的本站用户：已绑定的直接登录，邮箱均已验证且一致时自动绑定，否则按配置注册新账号
+func (s *OAuthService) resolveLogin(providerName string, identity *oauth.Identity) (*models.User, error) {
+	var existing models.UserIdentity
+	err := database.DB.Where("provider = ? AND subject = ?", providerName, identity.Subject).First(&existing).Error
+	if err == nil {
+		user, err := loadActiveUser(existing.UserID)
+		if err != nil {
+			return nil, ErrOAuthAccountDisabled
+		}
+		refreshIdentity(&existing, identity)
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if identity.Email != "" {
+		var user models.User
+		err := database.DB.Where("email = ?", identity.Email).First(&user).Error
+		if err == nil {
+			// 双方都确认过邮箱归属才自动绑定，避免他人用未验证的邮箱接管账号
+			if !identity.EmailVerified || user.EmailVerifiedAt == nil {
+				return nil, ErrOAuthEmailConflict
+			}
+			if user.Status != 1 {
+				return nil, ErrOAuthAccountDisabled
+			}
+			if err := createIdentity(user.ID, providerName, identity); err != nil {
+				return nil, err
+			}
+			return &user, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	return s.signup(providerName, identity)
+}
+
+func (s *OAuthService) signup(providerName string, identity *oauth.Identity) (*models.User, error) {
+	if !oauthProviderCfgs[providerName].AllowSignup || !NewSettingService().GetBool(models.SettingRegisterEnabled, true) {
+		return nil, ErrOAuthSignupDisabled
+	}
+	if identity.Email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+	username, err := availableUsername(identity)
+	if err != nil {
+		return nil, err
+	}
+	nickname := identity.Name
+	if nickname == "" {
+		nickname = username
+	}
+
+	// 第三方注册的账号没有密码，可以通过找回密码设置
+	user := &models.User{
+		Username: username,
+		Email:    identity.Email,
+		Nickname: truncateRunes(nickname, 50),
+		Avatar:   truncateRunes(identity.AvatarURL, 255),
+		Role:     "user",
+		Status:   1,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(newIdentity(user.ID, providerName, identity)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	NewSearchService().Sync(models.SearchTypeUser, user.ID)
+	if user.EmailVerifiedAt == nil {
+		sendVerificationAsync(user)
+	}
+	return user, nil
+}
+
+func (s *OAuthService) link(userID uint, providerName string, identity *oauth.Identity) error {
+	var existing models.UserIdentity
+	err := database.DB.Where("provider = ? AND subject = ?", providerName, identity.Subject).First(&existing).Error
+	if err == nil {
+		if existing.UserID != userID {
+			return ErrOAuthIdentityLinked
+		}
+		refreshIdentity(&existing, identity)
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.UserIdentity{}).Where("user_id = ? AND provider = ?", userID, providerName).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrOAuthAlreadyLinked
+	}
+	return createIdentity(userID, providerName, identity)
+}
+
+func newIdentity(userID uint, providerName string, identity *oauth.Identity) *models.UserIdentity {
+	return &models.UserIdentity{
+		UserID:    userID,
+		Provider:  providerName,
+		Subject:   identity.Subject,
+		Email:     truncateRunes(identity.Email, 100),
+		Name:      truncateRunes(firstNonEmpty(identity.Name, identity.Username), 100),
+		AvatarURL: truncateRunes(identity.AvatarURL, 500),
+	}
+}
+
+func createIdentity(userID uint, providerName string, identity *oauth.Identity) error {
+	return database.DB.Create(newIdentity(userID, providerName, identity)).Error
+}
+
+// refreshIdentity 同步第三方账号最新的资料，失败不影响登录
+func refreshIdentity(existing *models.UserIdentity, identity *oauth.Identity) {
+	latest := newIdentity(existing.UserID, existing.Provider, identity)
+	if err := database.DB.Model(existing).Updates(map[string]interface{}{
+		"email": latest.Email, "name": latest.Name, "avatar_url": latest.AvatarURL,
+	}).Error; err != nil {
+		log.Printf("更新第三方账号资料失败: %v", err)
+	}
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// availableUsername 根据第三方用户名或邮箱前缀生成未被占用的用户名
+func availableUsername(identity *oauth.Identity) (string, error) {
+	base := identity.Username
+	if base == "" {
+		base = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+	return uniqueUsername(database.DB, base)
+}
+
+// uniqueUsername 去掉用户名中的非法字符，被占用时追加随机数字
+func uniqueUsername(tx *gorm.DB, base string) (string, error) {
+	base = usernameInvalidChars.ReplaceAllString(base, "")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	for len(base) < 3 {
+		base += "_"
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		var count int64
+		if err := tx.Unscoped().Model(&models.User{}).Where("username = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s_%04d", base, n.Int64())
+	}
+	return "", errors.New("无法生成可用的用户名，请稍后重试")
+}
+
+// safeRedirect 只允许跳回站内路径，防止开放重定向
+func safeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.Contains(redirect, "\\") {
+		return ""
+	}
+	return redirect
+}
+
+func oauthCallbackURL(values url.Values) string {
+	return publicURL("/oauth/callback?" + values.Encode())
+}
+
+// publicOAuthError 业务错误直接展示给用户，其余错误只记录日志
+func publicOAuthError(err error) string {
+	for _, known := range []error{
+		ErrOAuthSignupDisabled, ErrOAuthEmailConflict, ErrOAuthIdentityLinked,
+		ErrOAuthAlreadyLinked, ErrOAuthAccountDisabled, ErrOAuthEmailMissing,
+	} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+	log.Printf("第三方登录失败: %v", err)
+	return "第三方登录失败，请稍后重试"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// oauthBindingHash Redis 中只保存浏览器绑定值的摘要
+func oauthBindingHash(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return hex.EncodeToString(sum[:])
+}
+
+func oauthBindingMatch(record *oauthStateRecord, binding string) bool {
+	return record.Binding != "" && subtle.ConstantTimeCompare([]byte(record.Binding), []byte(oauthBindingHash(binding))) == 1
+}
+
+func oauthStateKey(state string) string {
+	return "auth:oauth:state:" + state
+}
+
+func oauthResultKey(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return "auth:oauth:result:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package service
+
+import "testing"
+
+func TestSafeRedirect(t *testing.T) {
+	tests := []struct {
+		redirect string
+		want     string
+	}{
+		{"", ""},
+		{"/blog/1", "/blog/1"},
+		{"/profile/edit?tab=security", "/profile/edit?tab=security"},
+		{"//evil.example.com", ""},
+		{"/\\evil.example.com", ""},
+		{"https://evil.example.com", ""},
+		{"javascript:alert(1)", ""},
+	}
+	for _, test := range tests {
+		if got := safeRedirect(test.redirect); got != test.want {
+			t.Errorf("safeRedirect(%q) = %q, want %q", test.redirect, got, test.want)
+		}
+	}
+}
+
+func TestOAuthBindingMatch(t *testing.T) {
+	record := &oauthStateRecord{Binding: oauthBindingHash("browser-a")}
+	if !oauthBindingMatch(record, "browser-a") {
+		t.Error("发起授权的浏览器应通过校验")
+	}
+	if oauthBindingMatch(record, "browser-b") {
+		t.Error("其他浏览器的 Cookie 不应通过校验")
+	}
+	if oauthBindingMatch(record, "") {
+		t.Error("没有 Cookie 时不应通过校验")
+	}
+	if oauthBindingMatch(&oauthStateRecord{}, "") {
+		t.Error("未绑定浏览器的旧状态不应通过校验")
+	}
+}
@@ -2,19 +2,16 @@ package service
 
 import (
 	"errors"
-	"regexp"
 	"sort"
 
 	"github.com/iceymoss/inkspace/internal/database"
 	"github.com/iceymoss/inkspace/internal/models"
-	"github.com/microcosm-cc/bluemonday"
+	"github.com/iceymoss/inkspace/pkg/markdown"
 	"gorm.io/gorm"
 )
 
 const publicWikiNodeLimit = 2000
 
-var publicWikiHTMLPolicy = newPublicWikiHTMLPolicy()
-
 type PublicWikiService struct{}
 
 func NewPublicWikiService() *PublicWikiService { return &PublicWikiService{} }
@@ -131,7 +128,7 @@ func publicWikiCatalogs(workspaceID, ownerID uint, docs []models.Doc) ([]models.
 func (s *PublicWikiService) Doc(id uint) (*models.PublicDocResponse, error) {
 	var doc models.PublicDocResponse
 	err := database.DB.Model(&models.Doc{}).
-		Select("docs.id, docs.workspace_id, docs.catalog_id, docs.title, docs.content_html, docs.view_count, docs.published_at, docs.updated_at").
+		Select("docs.id, docs.workspace_id, docs.catalog_id, docs.title, docs.content_html, docs.content_toc AS toc, docs.view_count, docs.published_at, docs.updated_at").
 		Joins("JOIN workspaces ON workspaces.id = docs.workspace_id AND workspaces.owner_id = docs.owner_id AND workspaces.is_public = ? AND workspaces.deleted_at IS NULL", true).
 		Joins("LEFT JOIN catalogs ON catalogs.id = docs.catalog_id AND catalogs.workspace_id = docs.workspace_id AND catalogs.owner_id = docs.owner_id AND catalogs.deleted_at IS NULL").
 		Where("docs.id = ? AND docs.status = ? AND docs.deleted_at IS NULL AND (docs.catalog_id IS NULL OR catalogs.id IS NOT NULL)", id, models.DocStatusPublished).Take(&doc).Error
@@ -145,6 +142,23 @@ func (s *PublicWikiService) Doc(id uint) (*models.PublicDocResponse, error) {
 	return &doc, nil
 }
 
+// Docs 分页列出所有公开空间中已发布的文档，最近更新的在前，供站点地图使用
+func (s *PublicWikiService) Docs(page, pageSize int) ([]*models.PublicDocTreeResponse, int64, error) {
+	query := database.DB.Model(&models.Doc{}).
+		Joins("JOIN workspaces ON workspaces.id = docs.workspace_id AND workspaces.owner_id = docs.owner_id AND workspaces.is_public = ? AND workspaces.deleted_at IS NULL", true).
+		Joins("LEFT JOIN catalogs ON catalogs.id = docs.catalog_id AND catalogs.workspace_id = docs.workspace_id AND catalogs.owner_id = docs.owner_id AND catalogs.deleted_at IS NULL").
+		Where("docs.status = ? AND docs.deleted_at IS NULL AND (docs.catalog_id IS NULL OR catalogs.id IS NOT NULL)", models.DocStatusPublished)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var docs []*models.PublicDocTreeResponse
+	err := query.Select("docs.id, docs.catalog_id, docs.title, docs.sort, docs.published_at, docs.updated_at").
+		Order("docs.updated_at DESC, docs.id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Scan(&docs).Error
+	return docs, total, err
+}
+
 func publicWorkspace(id uint) (*models.Workspace, error) {
 	var workspace models.Workspace
 	if err := database.DB.Where("id = ? AND is_public = ? AND deleted_at IS NULL", id, true).First(&workspace).Error; err != nil {
@@ -229,15 +243,7 @@ func countPublicCatalogs(catalogs []*models.PublicCatalogResponse) int {
 	return count
 }
 
+// sanitizePublicWikiHTML 输出前再次清洗已保存的 HTML，兼容旧版本渲染保存的内容
 func sanitizePublicWikiHTML(content string) string {
-	return publicWikiHTMLPolicy.Sanitize(content)
-}
-
-func newPublicWikiHTMLPolicy() *bluemonday.Policy {
-	policy := bluemonday.UGCPolicy()
-	policy.AllowElements("table", "thead", "tbody", "tfoot", "tr", "th", "td", "caption", "colgroup", "col")
-	policy.AllowAttrs("colspan", "rowspan", "scope").OnElements("th", "td")
-	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9_ -]{1,200}$`)).OnElements("pre", "code")
-	policy.AllowAttrs("loading").Matching(regexp.MustCompile(`^(lazy|eager)$`)).OnElements("img")
-	return policy
+	return markdown.Sanitize(content)
 }
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/search"
+
+	"gorm.io/gorm"
+)
+
+const (
+	relatedArticleKeyPrefix = "related:articles:"
+	relatedArticleTTL       = 48 * time.Hour // 调度器每小时刷新，停止运行时两天后回退到实时查询
+	relatedArticleLimit     = 10             // 每篇文章预计算的相关文章数
+	relatedVectorTerms      = 40             // 每篇文章参与相似度计算的关键词数
+	relatedBatchSize        = 200
+
+	// 各信号的权重，三项得分都在 [0, 1] 之间
+	relatedTagWeight      = 0.5
+	relatedCategoryWeight = 0.2
+	relatedContentWeight  = 0.3
+)
+
+// RelatedArticleService 相关文章：按共同标签、同分类和 TF-IDF 内容相似度打分，由调度器预计算到 Redis
+type RelatedArticleService struct{}
+
+func NewRelatedArticleService() *RelatedArticleService {
+	return &RelatedArticleService{}
+}
+
+// relatedItem 参与相关度计算的文章特征
+type relatedItem struct {
+	ID         uint
+	CategoryID uint
+	TagIDs     []uint
+	Vector     search.Vector
+}
+
+type relatedScore struct {
+	ID    uint
+	Score float64
+}
+
+// Get 获取文章的相关文章；没有预计算结果（如新发布的文章）或结果不足时按标签、分类和发布时间补足。
+// 源文章未发布时只有作者或有文章管理权限的用户可以获取，其他人视为不存在
+func (s *RelatedArticleService) Get(id, viewerID uint, viewerRole string, limit int) ([]*models.Article, error) {
+	var source models.Article
+	if err := database.DB.Select("id, category_id, author_id, status").Preload("Tags").First(&source, id).Error; err != nil {
+		return nil, err
+	}
+	if source.Status != models.ArticleStatusPublished && (viewerID == 0 || viewerID != source.AuthorID) &&
+		!NewRoleService().HasPermission(viewerRole, models.PermissionArticleManage) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	ids := make([]uint, 0, limit)
+	seen := map[uint]bool{id: true}
+	appendIDs := func(candidates []uint) {
+		for _, candidate := range candidates {
+			if len(ids) < limit && !seen[candidate] {
+				seen[candidate] = true
+				ids = append(ids, candidate)
+			}
+		}
+	}
+
+	if database.RDB != nil {
+		if data, err := database.RDB.Get(database.Ctx, relatedArticleKey(id)).Bytes(); err == nil {
+			var cached []uint
+			if json.Unmarshal(data, &cached) == nil {
+				appendIDs(cached)
+			}
+		}
+	}
+
+	articles, err := loadPublishedArticles(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(articles) >= limit {
+		return articles, nil
+	}
+
+	// 预计算结果中可能有已下线的文章，以实际查到的为准再补足
+	ids = ids[:0]
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+	fallback, err := relatedFallbackIDs(&source, limit+len(seen))
+	if err != nil {
+		return nil, err
+	}
+	appendIDs(fallback)
+	return loadPublishedArticles(ids)
+}
+
+// relatedFallbackIDs 实时查询：共同标签多的优先，其次同分类，最后是最新文章
+func relatedFallbackIDs(source *models.Article, limit int) ([]uint, error) {
+	var ids []uint
+	if len(source.Tags) > 0 {
+		tagIDs := make([]uint, len(source.Tags))
+		for i, tag := range source.Tags {
+			tagIDs[i] = tag.ID
+		}
+		if err := database.DB.Table("article_tags").
+			Joins("JOIN articles ON articles.id = article_tags.article_id").
+			Where("article_tags.tag_id IN ? AND article_tags.article_id <> ?", tagIDs, source.ID).
+			Where("articles.status = ? AND articles.deleted_at IS NULL", models.ArticleStatusPublished).
+			Group("article_tags.article_id").
+			Order("COUNT(*) DESC, MAX(articles.created_at) DESC").
+			Limit(limit).Pluck("article_tags.article_id", &ids).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(ids) >= limit {
+		return ids, nil
+	}
+
+	query := database.DB.Model(&models.Article{}).
+		Where("status = ? AND id <> ?", models.ArticleStatusPublished, source.ID).
+		Order("created_at DESC").Limit(limit)
+	if source.CategoryID > 0 {
+		var sameCategory []uint
+		if err := query.Session(&gorm.Session{}).Where("category_id = ?", source.CategoryID).
+			Pluck("id", &sameCategory).Error; err != nil {
+			return nil, err
+		}
+		ids = append(ids, sameCategory...)
+	}
+	var latest []uint
+	if err := query.Pluck("id", &latest).Error; err != nil {
+		return nil, err
+	}
+	return append(ids, latest...), nil
+}
+
+// loadPublishedArticles 按 ids 的顺序返回已发布的文章
+func loadPublishedArticles(ids []uint) ([]*models.Article, error) {
+	if len(ids) == 0 {
+		return []*models.Article{}, nil
+	}
+	var articles []*models.Article
+	if err := database.DB.Where("id IN ? AND status = ?", ids, models.ArticleStatusPublished).
+		Preload("Category").Preload("Tags").Preload("Author").
+		Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+	sorted := make([]*models.Article, 0, len(articles))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			sorted = append(sorted, article)
+		}
+	}
+	return sorted, nil
+}
+
+// Compute 重新计算全部已发布文章的相关文章并写入 Redis，返回处理的文章数；
+// 未启用 Redis 时不做预计算，Get 全部走实时查询
+func (s *RelatedArticleService) Compute() (int, error) {
+	if database.RDB == nil {
+		return 0, nil
+	}
+	items, err := loadRelatedItems()
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	related := rankRelated(items, relatedArticleLimit)
+	pipe := database.RDB.Pipeline()
+	for _, item := range items {
+		data, err := json.Marshal(related[item.ID])
+		if err != nil {
+			return 0, err
+		}
+		pipe.Set(database.Ctx, relatedArticleKey(item.ID), data, relatedArticleTTL)
+	}
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return 0, fmt.Errorf("写入相关文章缓存失败: %w", err)
+	}
+	return len(items), nil
+}
+
+// loadRelatedItems 分批读取已发布文章并提取标签和词频，正文用完即释放，不会一次性全部载入内存；
+// 但每篇文章的完整词频表会保留到 TF-IDF 计算结束，内存占用随已发布文章数和正文词汇量线性增长
+func loadRelatedItems() ([]*relatedItem, error) {
+	var articles []models.Article
+	byID := make(map[uint]*relatedItem)
+	freqs := make(map[uint]map[string]int)
+	err := database.DB.Select("id, title, summary, content, category_id").
+		Where("status = ?", models.ArticleStatusPublished).
+		FindInBatches(&articles, relatedBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, article := range articles {
+				freq := search.Frequencies(article.Summary + "\n" + article.Content)
+				// 标题更能代表主题，与搜索索引一致按 3 倍计入
+				for term, tf := range search.Frequencies(article.Title) {
+					freq[term] += tf * searchTitleBoost
+				}
+				freqs[article.ID] = freq
+				byID[article.ID] = &relatedItem{ID: article.ID, CategoryID: article.CategoryID}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var links []struct {
+		ArticleID uint
+		TagID     uint
+	}
+	if err := database.DB.Table("article_tags").Select("article_id, tag_id").Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if item, ok := byID[link.ArticleID]; ok {
+			item.TagIDs = append(item.TagIDs, link.TagID)
+		}
+	}
+
+	vectors := search.TFIDF(freqs, relatedVectorTerms)
+	items := make([]*relatedItem, 0, len(byID))
+	for id, item := range byID {
+		item.Vector = vectors[id]
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// rankRelated 为每篇文章选出得分最高的 limit 篇相关文章。
+// 候选只来自有共同标签或共同关键词的文章，同分类只作为加分项，避免两两比较全部文章
+func rankRelated(items []*relatedItem, limit int) map[uint][]uint {
+	byTag := make(map[uint][]*relatedItem)
+	byTerm := make(map[string][]*relatedItem)
+	for _, item := range items {
+		for _, tagID := range item.TagIDs {
+			byTag[tagID] = append(byTag[tagID], item)
+		}
+		for term := range item.Vector {
+			byTerm[term] = append(byTerm[term], item)
+		}
+	}
+
+	result := make(map[uint][]uint, len(items))
+	for _, item := range items {
+		candidates := make(map[uint]*relatedItem)
+		for _, tagID := range item.TagIDs {
+			for _, other := range byTag[tagID] {
+				candidates[other.ID] = other
+			}
+		}
+		for term := range item.Vector {
+			for _, other := range byTerm[term] {
+				candidates[other.ID] = other
+			}
+		}
+		delete(candidates, item.ID)
+
+		scores := make([]relatedScore, 0, len(candidates))
+		for _, other := range candidates {
+			if score := relatedSimilarity(item, other); score > 0 {
+				scores = append(scores, relatedScore{ID: other.ID, Score: score})
+			}
+		}
+		sort.Slice(scores, func(i, j int) bool {
+			if scores[i].Score != scores[j].Score {
+				return scores[i].Score > scores[j].Score
+			}
+			return scores[i].ID > scores[j].ID
+		})
+
+		ids := make([]uint, 0, min(limit, len(scores)))
+		for _, score := range scores[:min(limit, len(scores))] {
+			ids = append(ids, score.ID)
+		}
+		result[item.ID] = ids
+	}
+	return result
+}
+
+// relatedSimilarity 两篇文章的加权相关度
+func relatedSimilarity(a, b *relatedItem) float64 {
+	var score float64
+	if len(a.TagIDs) > 0 && len(b.TagIDs) > 0 {
+		shared := 0
+		for _, x := range a.TagIDs {
+			for _, y := range b.TagIDs {
+				if x == y {
+					shared++
+				}
+			}
+		}
+		score += relatedTagWeight * float64(shared) / math.Sqrt(float64(len(a.TagIDs)*len(b.TagIDs)))
+	}
+	if a.CategoryID > 0 && a.CategoryID == b.CategoryID {
+		score += relatedCategoryWeight
+	}
+	score += relatedContentWeight * a.Vector.Cosine(b.Vector)
+	return score
+}
+
+func relatedArticleKey(id uint) string {
+	return fmt.Sprintf("%s%d", relatedArticleKeyPrefix, id)
+}
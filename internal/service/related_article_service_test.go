@@ -0,0 +1,40 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iceymoss/inkspace/pkg/search"
+)
+
+func TestRankRelated(t *testing.T) {
+	docs := map[uint]map[string]int{
+		1: search.Frequencies("Go 并发 goroutine channel"),
+		2: search.Frequencies("Go 并发 goroutine 调度"),
+		3: search.Frequencies("Vue 组件 响应式"),
+		4: search.Frequencies("Vue 组件 路由"),
+		5: search.Frequencies("旅行 随笔"),
+	}
+	vectors := search.TFIDF(docs, relatedVectorTerms)
+	items := []*relatedItem{
+		{ID: 1, CategoryID: 1, TagIDs: []uint{1, 2}, Vector: vectors[1]},
+		{ID: 2, CategoryID: 1, TagIDs: []uint{1}, Vector: vectors[2]},
+		{ID: 3, CategoryID: 2, TagIDs: []uint{3}, Vector: vectors[3]},
+		{ID: 4, CategoryID: 1, TagIDs: []uint{2}, Vector: vectors[4]},
+		{ID: 5, CategoryID: 3, Vector: vectors[5]},
+	}
+
+	got := rankRelated(items, 2)
+	// 2 与 1 共享标签且内容相近；4 只共享标签和分类
+	if !reflect.DeepEqual(got[1], []uint{2, 4}) {
+		t.Fatalf("related(1) = %v", got[1])
+	}
+	// 3 与 4 只有内容相似
+	if !reflect.DeepEqual(got[3], []uint{4}) {
+		t.Fatalf("related(3) = %v", got[3])
+	}
+	// 没有任何共同点的文章不产生候选，由 Get 实时回退
+	if len(got[5]) != 0 {
+		t.Fatalf("related(5) = %v", got[5])
+	}
+}
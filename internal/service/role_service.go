@@ -0,0 +1,352 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// rolePermissionTTL 权限缓存有效期；管理后台和用户端是两个进程，修改角色后其他进程最迟在此时间后生效
+const rolePermissionTTL = 30 * time.Second
+
+var (
+	ErrRoleNotFound      = errors.New("角色不存在")
+	ErrRoleExists        = errors.New("角色已存在")
+	ErrRoleName          = errors.New("角色标识只能包含小写字母、数字、下划线和中划线")
+	ErrRoleNotEditable   = errors.New("该角色的权限不可修改")
+	ErrRoleBuiltIn       = errors.New("内置角色不能删除")
+	ErrRoleInUse         = errors.New("仍有用户使用该角色，不能删除")
+	ErrPermissionInvalid = errors.New("无效的权限")
+	ErrRoleEscalation    = errors.New("不能分配或修改超出自身权限的角色")
+	ErrRoleSelf          = errors.New("不能修改自己的角色")
+	ErrLastAdmin         = errors.New("至少需要保留一名管理员")
+	ErrUserTargetSelf    = errors.New("不能对自己的账号执行该操作")
+	ErrUserTargetAdmin   = errors.New("不能操作管理后台账号，需先调整为普通角色")
+	ErrUserTargetAbove   = errors.New("不能操作权限超出自身的账号")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// rolePermissionCache 角色 -> 权限集合，过期后整体重新加载
+var rolePermissionCache struct {
+	sync.RWMutex
+	roles    map[string]map[string]bool
+	loadedAt time.Time
+}
+
+type RoleService struct{}
+
+func NewRoleService() *RoleService {
+	return &RoleService{}
+}
+
+// HasPermission 角色是否拥有某项权限；admin 始终拥有全部权限
+func (s *RoleService) HasPermission(role, permission string) bool {
+	if role == models.RoleAdmin {
+		return true
+	}
+	return s.permissionSet(role)[permission]
+}
+
+// HasAllPermissions 角色是否拥有列表中的全部权限
+func (s *RoleService) HasAllPermissions(role string, permissions []string) bool {
+	if role == models.RoleAdmin {
+		return true
+	}
+	set := s.permissionSet(role)
+	for _, permission := range permissions {
+		if !set[permission] {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckUserTarget 检查操作者能否处置目标账号（禁用等）：
+// 不能操作自己、拥有后台访问权限的账号，以及权限超出操作者的账号
+func (s *RoleService) CheckUserTarget(actorID uint, actorRole string, targetID uint, targetRole string) error {
+	if actorID == targetID {
+		return ErrUserTargetSelf
+	}
+	if s.HasPermission(targetRole, models.PermissionAdminAccess) {
+		return ErrUserTargetAdmin
+	}
+	if !s.HasAllPermissions(actorRole, s.Permissions(targetRole)) {
+		return ErrUserTargetAbove
+	}
+	return nil
+}
+
+// Permissions 角色拥有的权限列表
+func (s *RoleService) Permissions(role string) []string {
+	if role == models.RoleAdmin {
+		return models.AllPermissions()
+	}
+	set := s.permissionSet(role)
+	list := make([]string, 0, len(set))
+	for permission := range set {
+		list = append(list, permission)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (s *RoleService) permissionSet(role string) map[string]bool {
+	rolePermissionCache.RLock()
+	roles, loadedAt := rolePermissionCache.roles, rolePermissionCache.loadedAt
+	rolePermissionCache.RUnlock()
+	if roles != nil && time.Since(loadedAt) < rolePermissionTTL {
+		return roles[role]
+	}
+
+	rolePermissionCache.Lock()
+	defer rolePermissionCache.Unlock()
+	if rolePermissionCache.roles != nil && time.Since(rolePermissionCache.loadedAt) < rolePermissionTTL {
+		return rolePermissionCache.roles[role]
+	}
+	loaded, err := loadRolePermissions()
+	if err != nil {
+		// 数据库暂时不可用时沿用旧的缓存，没有缓存则视为无权限
+		log.Printf("加载角色权限失败: %v", err)
+		return rolePermissionCache.roles[role]
+	}
+	rolePermissionCache.roles = loaded
+	rolePermissionCache.loadedAt = time.Now()
+	return loaded[role]
+}
+
+func loadRolePermissions() (map[string]map[string]bool, error) {
+	var rows []struct {
+		Name       string
+		Permission string
+	}
+	if err := database.DB.Table("roles").
+		Select("roles.name, role_permissions.permission").
+		Joins("JOIN role_permissions ON role_permissions.role_id = roles.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	roles := make(map[string]map[string]bool)
+	for _, row := range rows {
+		if roles[row.Name] == nil {
+			roles[row.Name] = make(map[string]bool)
+		}
+		roles[row.Name][row.Permission] = true
+	}
+	return roles, nil
+}
+
+// invalidateRolePermissions 本进程内立即生效
+func invalidateRolePermissions() {
+	rolePermissionCache.Lock()
+	rolePermissionCache.roles = nil
+	rolePermissionCache.Unlock()
+}
+
+// List 全部角色及使用人数
+func (s *RoleService) List() ([]*models.RoleResponse, error) {
+	var roles []models.Role
+	if err := database.DB.Preload("Permissions").Order("id ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		Role  string
+		Count int64
+	}
+	if err := database.DB.Model(&models.User{}).Select("role, COUNT(*) AS count").Group("role").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	userCounts := make(map[string]int64, len(counts))
+	for _, item := range counts {
+		userCounts[item.Role] = item.Count
+	}
+
+	list := make([]*models.RoleResponse, len(roles))
+	for i := range roles {
+		list[i] = roles[i].ToResponse(userCounts[roles[i].Name])
+	}
+	return list, nil
+}
+
+// Exists 角色是否存在
+func (s *RoleService) Exists(name string) (bool, error) {
+	var count int64
+	if err := database.DB.Model(&models.Role{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Create 新建自定义角色；actorRole 不能授予自己没有的权限
+func (s *RoleService) Create(req *models.RoleRequest, actorRole string) (*models.RoleResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, ErrRoleName
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if !s.HasAllPermissions(actorRole, permissions) {
+		return nil, ErrRoleEscalation
+	}
+	exists, err := s.Exists(name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrRoleExists
+	}
+
+	role := models.Role{
+		Name:        name,
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		Description: strings.TrimSpace(req.Description),
+	}
+	for _, permission := range permissions {
+		role.Permissions = append(role.Permissions, models.RolePermission{Permission: permission})
+	}
+	if err := database.DB.Create(&role).Error; err != nil {
+		return nil, err
+	}
+	invalidateRolePermissions()
+	return role.ToResponse(0), nil
+}
+
+// Update 修改角色名称、说明和权限；角色标识不可修改
+func (s *RoleService) Update(id uint, req *models.RoleRequest, actorRole string) (*models.RoleResponse, error) {
+	role, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if role.Editable() {
+		// 新旧权限都必须在操作者权限范围内，避免借修改角色提权或削弱更高权限的角色
+		if !s.HasAllPermissions(actorRole, permissions) || !s.HasAllPermissions(actorRole, role.PermissionList()) {
+			return nil, ErrRoleEscalation
+		}
+	} else if len(permissions) > 0 && !samePermissions(permissions, role.PermissionList()) {
+		return nil, ErrRoleNotEditable
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Updates(map[string]interface{}{
+			"display_name": strings.TrimSpace(req.DisplayName),
+			"description":  strings.TrimSpace(req.Description),
+		}).Error; err != nil {
+			return err
+		}
+		if !role.Editable() {
+			return nil
+		}
+		if err := tx.Where("role_id = ?", role.ID).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		rows := make([]models.RolePermission, len(permissions))
+		for i, permission := range permissions {
+			rows[i] = models.RolePermission{RoleID: role.ID, Permission: permission}
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateRolePermissions()
+
+	role, err = s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	database.DB.Model(&models.User{}).Where("role = ?", role.Name).Count(&count)
+	return role.ToResponse(count), nil
+}
+
+// Delete 删除自定义角色，仍有用户使用时拒绝
+func (s *RoleService) Delete(id uint, actorRole string) error {
+	role, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return ErrRoleBuiltIn
+	}
+	if !s.HasAllPermissions(actorRole, role.PermissionList()) {
+		return ErrRoleEscalation
+	}
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("role = ?", role.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := database.DB.Select("Permissions").Delete(role).Error; err != nil {
+		return err
+	}
+	invalidateRolePermissions()
+	return nil
+}
+
+func (s *RoleService) get(id uint) (*models.Role, error) {
+	var role models.Role
+	if err := database.DB.Preload("Permissions").First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// normalizePermissions 校验并去重排序
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	list := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if !models.ValidPermission(permission) {
+			return nil, ErrPermissionInvalid
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		list = append(list, permission)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+func samePermissions(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, permission := range a {
+		set[permission] = true
+	}
+	for _, permission := range b {
+		if !set[permission] {
+			return false
+		}
+	}
+	return true
+}
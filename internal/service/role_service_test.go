@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+// setRolePermissions 直接写入权限缓存，测试时无需数据库
+func setRolePermissions(t *testing.T, roles map[string][]string) {
+	t.Helper()
+	loaded := make(map[string]map[string]bool, len(roles))
+	for role, permissions := range roles {
+		loaded[role] = make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			loaded[role][permission] = true
+		}
+	}
+	rolePermissionCache.Lock()
+	rolePermissionCache.roles = loaded
+	rolePermissionCache.loadedAt = time.Now()
+	rolePermissionCache.Unlock()
+	t.Cleanup(invalidateRolePermissions)
+}
+
+func TestRoleServiceHasPermission(t *testing.T) {
+	setRolePermissions(t, map[string][]string{
+		models.RoleModerator: {models.PermissionAdminAccess, models.PermissionCommentModerate},
+	})
+	roles := NewRoleService()
+
+	tests := []struct {
+		role, permission string
+		want             bool
+	}{
+		{models.RoleAdmin, models.PermissionSettingsWrite, true},
+		{models.RoleModerator, models.PermissionCommentModerate, true},
+		{models.RoleModerator, models.PermissionAdManage, false},
+		{models.RoleUser, models.PermissionAdminAccess, false},
+		{"unknown", models.PermissionAdminAccess, false},
+	}
+	for _, test := range tests {
+		if got := roles.HasPermission(test.role, test.permission); got != test.want {
+			t.Errorf("HasPermission(%q, %q) = %v, want %v", test.role, test.permission, got, test.want)
+		}
+	}
+
+	if !roles.HasAllPermissions(models.RoleModerator, []string{models.PermissionCommentModerate}) {
+		t.Error("HasAllPermissions(moderator, subset) = false")
+	}
+	if roles.HasAllPermissions(models.RoleModerator, []string{models.PermissionCommentModerate, models.PermissionRoleManage}) {
+		t.Error("HasAllPermissions(moderator, superset) = true")
+	}
+	if got := roles.Permissions(models.RoleAdmin); !reflect.DeepEqual(got, models.AllPermissions()) {
+		t.Errorf("Permissions(admin) = %v", got)
+	}
+}
+
+func TestNormalizePermissions(t *testing.T) {
+	got, err := normalizePermissions([]string{" work.audit", models.PermissionAdminAccess, "work.audit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{models.PermissionAdminAccess, models.PermissionWorkAudit}; !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizePermissions() = %v, want %v", got, want)
+	}
+	if _, err := normalizePermissions([]string{"root"}); !errors.Is(err, ErrPermissionInvalid) {
+		t.Errorf("normalizePermissions(unknown) error = %v", err)
+	}
+}
+
+func TestBuiltinRolePermissionsAreValid(t *testing.T) {
+	for _, role := range models.BuiltinRoles {
+		for _, permission := range role.Permissions {
+			if !models.ValidPermission(permission) {
+				t.Errorf("builtin role %s has unknown permission %q", role.Name, permission)
+			}
+		}
+		if !roleNamePattern.MatchString(role.Name) {
+			t.Errorf("builtin role name %q does not match pattern", role.Name)
+		}
+	}
+}
+
+func TestRoleServiceCheckUserTarget(t *testing.T) {
+	setRolePermissions(t, map[string][]string{
+		models.RoleEditor:    {models.PermissionAdminAccess, models.PermissionArticleManage},
+		models.RoleModerator: {models.PermissionAdminAccess, models.PermissionUserRead, models.PermissionUserManage},
+		"writer":             {models.PermissionArticleManage},
+		models.RoleUser:      {},
+	})
+	roles := NewRoleService()
+
+	tests := []struct {
+		name       string
+		actorRole  string
+		targetID   uint
+		targetRole string
+		want       error
+	}{
+		{"版主禁用管理员", models.RoleModerator, 2, models.RoleAdmin, ErrUserTargetAdmin},
+		{"版主禁用编辑", models.RoleModerator, 2, models.RoleEditor, ErrUserTargetAdmin},
+		{"版主禁用权限更高的前台角色", models.RoleModerator, 2, "writer", ErrUserTargetAbove},
+		{"版主禁用普通用户", models.RoleModerator, 2, models.RoleUser, nil},
+		{"禁用自己", models.RoleModerator, 1, models.RoleModerator, ErrUserTargetSelf},
+		{"管理员禁用前台角色", models.RoleAdmin, 2, "writer", nil},
+	}
+	for _, test := range tests {
+		if err := roles.CheckUserTarget(1, test.actorRole, test.targetID, test.targetRole); !errors.Is(err, test.want) {
+			t.Errorf("%s: CheckUserTarget() error = %v, want %v", test.name, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,747 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/search"
+
+	"gorm.io/gorm"
+)
+
+const (
+	searchTitleBoost       = 3     // 标题中的词按 3 倍词频计入
+	searchMaxTermBytes     = 64    // 与 search_postings.term 的列宽一致
+	searchMaxQueryTerms    = 16    // 查询最多使用的词数
+	searchMaxCandidates    = 10000 // 单次查询最多处理的候选内容数，超出时保留得分最高的内容
+	searchSnippetRunes     = 120
+	searchFacetLimit       = 10
+	searchPostingBatchSize = 500
+)
+
+// 分面维度，计算某个维度的分面时忽略该维度自身的筛选条件
+const (
+	searchFacetType     = "type"
+	searchFacetCategory = "category"
+	searchFacetTag      = "tag"
+	searchFacetAuthor   = "author"
+	searchFacetWorkType = "work_type"
+)
+
+var (
+	ErrSearchQueryEmpty  = errors.New("搜索关键词无效")
+	ErrSearchTypeInvalid = errors.New("未知的搜索类型")
+	ErrSearchReindexing  = errors.New("搜索索引正在重建，请稍后再试")
+)
+
+// searchReindexing 是否有正在运行的全量重建任务
+var searchReindexing atomic.Bool
+
+var searchTypeLabels = map[string]string{
+	models.SearchTypeArticle: "文章",
+	models.SearchTypeWork:    "作品",
+	models.SearchTypeDoc:     "文档",
+	models.SearchTypeUser:    "用户",
+}
+
+var searchWorkTypeLabels = map[string]string{
+	"project":     "项目",
+	"photography": "摄影",
+}
+
+// SearchService 站内全文检索：倒排索引存储在 search_documents 和 search_postings 表中，
+// 中文按二元组和单字建立索引，结果按 BM25 排序。索引随内容的创建、修改、删除同步更新
+type SearchService struct{}
+
+func NewSearchService() *SearchService {
+	return &SearchService{}
+}
+
+// searchCandidate 命中全部查询词的内容及其筛选字段
+type searchCandidate struct {
+	ID         uint
+	Type       string
+	AuthorID   uint
+	CategoryID uint
+	TagIDs     []uint
+	Subtype    string
+	Score      float64
+}
+
+// Search 执行搜索：多个词之间为“且”的关系，返回分页结果和分面统计
+func (s *SearchService) Search(query *models.SearchQuery) (*models.SearchResponse, error) {
+	terms := searchTerms(query.Keyword)
+	if len(terms) == 0 {
+		return nil, ErrSearchQueryEmpty
+	}
+	resp := &models.SearchResponse{List: []*models.SearchHit{}, Page: query.Page, PageSize: query.PageSize}
+
+	var stat struct {
+		Docs      int64
+		AvgLength float64
+	}
+	if err := database.DB.Model(&models.SearchDocument{}).
+		Select("COUNT(*) AS docs, COALESCE(AVG(length), 0) AS avg_length").Scan(&stat).Error; err != nil {
+		return nil, err
+	}
+
+	var dfs []struct {
+		Term string
+		DF   int64
+	}
+	if err := database.DB.Model(&models.SearchPosting{}).Select("term, COUNT(*) AS df").
+		Where("term IN ?", terms).Group("term").Scan(&dfs).Error; err != nil {
+		return nil, err
+	}
+	if len(dfs) < len(terms) {
+		return resp, nil
+	}
+	model := search.NewBM25(stat.Docs, stat.AvgLength)
+	idf := make(map[string]float64, len(dfs))
+	for _, df := range dfs {
+		idf[df.Term] = model.IDF(df.DF)
+	}
+
+	// 在数据库中筛出包含全部查询词的内容并计算 BM25 得分，数量超出上限时只保留得分最高的部分
+	scoreSQL, scoreArgs := searchScoreSQL(model, idf)
+	var scored []struct {
+		DocumentID uint
+		Score      float64
+	}
+	if err := database.DB.Table("search_postings AS p").
+		Select("p.document_id, "+scoreSQL+" AS score", scoreArgs...).
+		Joins("JOIN search_documents d ON d.id = p.document_id").
+		Where("p.term IN ?", terms).Group("p.document_id").
+		Having("COUNT(DISTINCT p.term) = ?", len(terms)).
+		Order("score DESC, p.document_id DESC").Limit(searchMaxCandidates).
+		Scan(&scored).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*searchCandidate, len(scored))
+	candidates := make([]*searchCandidate, 0, len(scored))
+	for _, row := range scored {
+		c := &searchCandidate{ID: row.DocumentID, Score: row.Score}
+		byID[c.ID] = c
+		candidates = append(candidates, c)
+	}
+	for start := 0; start < len(candidates); start += searchPostingBatchSize {
+		batch := make([]uint, 0, searchPostingBatchSize)
+		for _, c := range candidates[start:min(start+searchPostingBatchSize, len(candidates))] {
+			batch = append(batch, c.ID)
+		}
+		var docs []models.SearchDocument
+		if err := database.DB.Select("id, type, author_id, category_id, tag_ids, subtype").
+			Where("id IN ?", batch).Find(&docs).Error; err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			c := byID[doc.ID]
+			c.Type, c.AuthorID, c.CategoryID, c.Subtype = doc.Type, doc.AuthorID, doc.CategoryID, doc.Subtype
+			c.TagIDs = parseSearchTagIDs(doc.TagIDs)
+		}
+	}
+
+	var hits []*searchCandidate
+	for _, c := range candidates {
+		if c.matches(query, "") {
+			hits = append(hits, c)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	resp.Total = int64(len(hits))
+
+	facets, err := searchFacets(candidates, query)
+	if err != nil {
+		return nil, err
+	}
+	resp.Facets = *facets
+
+	start := (query.Page - 1) * query.PageSize
+	if start >= len(hits) {
+		return resp, nil
+	}
+	end := min(start+query.PageSize, len(hits))
+	resp.List, err = searchHits(hits[start:end], terms)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// matches 判断是否满足筛选条件；skip 为计算分面时忽略的维度
+func (c *searchCandidate) matches(query *models.SearchQuery, skip string) bool {
+	if skip != searchFacetType && query.Type != "" && c.Type != query.Type {
+		return false
+	}
+	if skip != searchFacetCategory && query.CategoryID > 0 && c.CategoryID != query.CategoryID {
+		return false
+	}
+	if skip != searchFacetAuthor && query.AuthorID > 0 && c.AuthorID != query.AuthorID {
+		return false
+	}
+	if skip != searchFacetWorkType && query.WorkType != "" && c.Subtype != query.WorkType {
+		return false
+	}
+	if skip != searchFacetTag && query.TagID > 0 {
+		for _, id := range c.TagIDs {
+			if id == query.TagID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func searchFacets(candidates []*searchCandidate, query *models.SearchQuery) (*models.SearchFacets, error) {
+	types := make(map[string]int)
+	categories := make(map[uint]int)
+	tags := make(map[uint]int)
+	authors := make(map[uint]int)
+	workTypes := make(map[string]int)
+	for _, c := range candidates {
+		if c.matches(query, searchFacetType) {
+			types[c.Type]++
+		}
+		if c.CategoryID > 0 && c.matches(query, searchFacetCategory) {
+			categories[c.CategoryID]++
+		}
+		if c.matches(query, searchFacetTag) {
+			for _, id := range c.TagIDs {
+				tags[id]++
+			}
+		}
+		if c.AuthorID > 0 && c.matches(query, searchFacetAuthor) {
+			authors[c.AuthorID]++
+		}
+		if c.Subtype != "" && c.matches(query, searchFacetWorkType) {
+			workTypes[c.Subtype]++
+		}
+	}
+
+	facets := &models.SearchFacets{
+		Types:     labeledFacets(types, searchTypeLabels),
+		WorkTypes: labeledFacets(workTypes, searchWorkTypeLabels),
+	}
+	var err error
+	if facets.Categories, err = namedFacets(categories, &models.Category{}, "name"); err != nil {
+		return nil, err
+	}
+	if facets.Tags, err = namedFacets(tags, &models.Tag{}, "name"); err != nil {
+		return nil, err
+	}
+	if facets.Authors, err = namedFacets(authors, &models.User{}, "COALESCE(NULLIF(nickname, ''), username)"); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+func labeledFacets(counts map[string]int, labels map[string]string) []models.SearchFacet {
+	facets := make([]models.SearchFacet, 0, len(counts))
+	for value, count := range counts {
+		label := labels[value]
+		if label == "" {
+			label = value
+		}
+		facets = append(facets, models.SearchFacet{Value: value, Label: label, Count: count})
+	}
+	sortSearchFacets(facets)
+	return facets
+}
+
+// namedFacets 取计数最多的 searchFacetLimit 个 ID，并从 model 对应的表中查询显示名称
+func namedFacets(counts map[uint]int, model interface{}, nameColumn string) ([]models.SearchFacet, error) {
+	facets := make([]models.SearchFacet, 0, len(counts))
+	for id, count := range counts {
+		facets = append(facets, models.SearchFacet{Value: strconv.FormatUint(uint64(id), 10), Count: count})
+	}
+	sortSearchFacets(facets)
+	if len(facets) > searchFacetLimit {
+		facets = facets[:searchFacetLimit]
+	}
+	if len(facets) == 0 {
+		return facets, nil
+	}
+
+	ids := make([]string, len(facets))
+	for i := range facets {
+		ids[i] = facets[i].Value
+	}
+	var names []struct {
+		ID   uint
+		Name string
+	}
+	if err := database.DB.Model(model).Select("id, "+nameColumn+" AS name").Where("id IN ?", ids).Scan(&names).Error; err != nil {
+		return nil, err
+	}
+	nameByID := make(map[string]string, len(names))
+	for _, n := range names {
+		nameByID[strconv.FormatUint(uint64(n.ID), 10)] = n.Name
+	}
+	named := facets[:0]
+	for _, facet := range facets {
+		if name, ok := nameByID[facet.Value]; ok {
+			facet.Label = name
+			named = append(named, facet)
+		}
+	}
+	return named, nil
+}
+
+func sortSearchFacets(facets []models.SearchFacet) {
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+}
+
+// searchHits 读取当前页内容并生成高亮标题、摘要和前台地址
+func searchHits(page []*searchCandidate, terms []string) ([]*models.SearchHit, error) {
+	ids := make([]uint, len(page))
+	for i, c := range page {
+		ids[i] = c.ID
+	}
+	var docs []models.SearchDocument
+	if err := database.DB.Where("id IN ?", ids).Find(&docs).Error; err != nil {
+		return nil, err
+	}
+	docByID := make(map[uint]*models.SearchDocument, len(docs))
+	authorIDs := make([]uint, 0, len(docs))
+	for i := range docs {
+		docByID[docs[i].ID] = &docs[i]
+		if docs[i].AuthorID > 0 {
+			authorIDs = append(authorIDs, docs[i].AuthorID)
+		}
+	}
+
+	authors := make(map[uint]*models.SearchAuthor)
+	if len(authorIDs) > 0 {
+		var users []*models.SearchAuthor
+		if err := database.DB.Model(&models.User{}).Select("id, username, nickname, avatar").
+			Where("id IN ?", authorIDs).Scan(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			authors[user.ID] = user
+		}
+	}
+
+	hits := make([]*models.SearchHit, 0, len(page))
+	for _, c := range page {
+		doc := docByID[c.ID]
+		if doc == nil {
+			continue
+		}
+		hit := &models.SearchHit{
+			Type:      doc.Type,
+			ID:        doc.TargetID,
+			Title:     search.Highlight(doc.Title, terms, 0),
+			Snippet:   search.Highlight(doc.Content, terms, searchSnippetRunes),
+			Cover:     doc.Cover,
+			Author:    authors[doc.AuthorID],
+			Score:     c.Score,
+			UpdatedAt: doc.SourceUpdatedAt,
+		}
+		hit.URL = searchURL(doc, hit.Author)
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// searchURL 内容在前台的地址，文章和作品有 slug 时使用固定链接
+func searchURL(doc *models.SearchDocument, author *models.SearchAuthor) string {
+	switch doc.Type {
+	case models.SearchTypeArticle, models.SearchTypeWork:
+		prefix := "/blog/"
+		if doc.Type == models.SearchTypeWork {
+			prefix = "/works/"
+		}
+		if doc.Slug != "" && author != nil {
+			return prefix + url.PathEscape(author.Username) + "/" + url.PathEscape(doc.Slug)
+		}
+		return fmt.Sprintf("%s%d", prefix, doc.TargetID)
+	case models.SearchTypeDoc:
+		return fmt.Sprintf("/wiki/docs/%d", doc.TargetID)
+	default:
+		return fmt.Sprintf("/users/%d", doc.TargetID)
+	}
+}
+
+// Sync 内容变更后同步索引；索引失败只记录日志，不影响业务操作
+func (s *SearchService) Sync(docType string, id uint) {
+	if _, err := s.index(docType, id); err != nil {
+		log.Printf("同步搜索索引失败 (%s %d): %v", docType, id, err)
+	}
+}
+
+// SyncWorkspace 知识库公开状态变化或删除后同步其中的全部文档
+func (s *SearchService) SyncWorkspace(workspaceID uint) {
+	var ids []uint
+	if err := database.DB.Unscoped().Model(&models.Doc{}).Where("workspace_id = ?", workspaceID).Pluck("id", &ids).Error; err != nil {
+		log.Printf("同步知识库 %d 的搜索索引失败: %v", workspaceID, err)
+		return
+	}
+	for _, id := range ids {
+		s.Sync(models.SearchTypeDoc, id)
+	}
+}
+
+// RemoveAuthor 账号注销后移除该用户及其全部内容的索引
+func (s *SearchService) RemoveAuthor(userID uint) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&models.SearchDocument{}).
+			Where("author_id = ? OR (type = ? AND target_id = ?)", userID, models.SearchTypeUser, userID).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		return removeSearchDocuments(tx, ids)
+	})
+	if err != nil {
+		log.Printf("移除用户 %d 的搜索索引失败: %v", userID, err)
+	}
+}
+
+// StartReindex 在后台全量重建索引，同一时间只运行一个重建任务；types 为空时重建全部类型
+func (s *SearchService) StartReindex(types ...string) error {
+	for _, docType := range types {
+		if _, err := searchSourceModel(docType); err != nil {
+			return err
+		}
+	}
+	if !searchReindexing.CompareAndSwap(false, true) {
+		return ErrSearchReindexing
+	}
+	go func() {
+		defer searchReindexing.Store(false)
+		count, err := s.Reindex(types...)
+		if err != nil {
+			log.Printf("重建搜索索引失败（已收录 %d 条）: %v", count, err)
+			return
+		}
+		log.Printf("搜索索引重建完成，共收录 %d 条内容", count)
+	}()
+	return nil
+}
+
+// Reindex 全量重建索引，types 为空时重建全部类型；返回收录的内容数
+func (s *SearchService) Reindex(types ...string) (int, error) {
+	if len(types) == 0 {
+		types = models.SearchTypes
+	}
+	total := 0
+	for _, docType := range types {
+		model, err := searchSourceModel(docType)
+		if err != nil {
+			return total, err
+		}
+		var ids []uint
+		if err := database.DB.Model(model).Order("id ASC").Pluck("id", &ids).Error; err != nil {
+			return total, err
+		}
+
+		indexed := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			ok, err := s.index(docType, id)
+			if err != nil {
+				return total, fmt.Errorf("索引 %s %d 失败: %w", docType, id, err)
+			}
+			if ok {
+				indexed[id] = true
+				total++
+			}
+		}
+
+		// 清理源数据已删除或不再公开的内容
+		var existing []models.SearchDocument
+		if err := database.DB.Select("id, target_id").Where("type = ?", docType).Find(&existing).Error; err != nil {
+			return total, err
+		}
+		var stale []uint
+		for _, doc := range existing {
+			if !indexed[doc.TargetID] {
+				stale = append(stale, doc.ID)
+			}
+		}
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return removeSearchDocuments(tx, stale)
+		}); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// index 根据源数据重建单条内容的索引，内容不存在或不公开时从索引中移除；返回是否被收录
+func (s *SearchService) index(docType string, id uint) (bool, error) {
+	var doc *models.SearchDocument
+	var err error
+	switch docType {
+	case models.SearchTypeArticle:
+		doc, err = articleSearchDocument(id)
+	case models.SearchTypeWork:
+		doc, err = workSearchDocument(id)
+	case models.SearchTypeDoc:
+		doc, err = docSearchDocument(id)
+	case models.SearchTypeUser:
+		doc, err = userSearchDocument(id)
+	default:
+		return false, fmt.Errorf("未知的搜索类型: %s", docType)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return doc != nil, database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.SearchDocument
+		err := tx.Select("id, created_at").Where("type = ? AND target_id = ?", docType, id).Take(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if doc == nil {
+			if existing.ID == 0 {
+				return nil
+			}
+			return removeSearchDocuments(tx, []uint{existing.ID})
+		}
+
+		freq := make(map[string]int)
+		for term, n := range search.IndexFrequencies(doc.Title) {
+			freq[term] += n * searchTitleBoost
+		}
+		for term, n := range search.IndexFrequencies(doc.Content) {
+			freq[term] += n
+		}
+		for term, n := range freq {
+			if len(term) > searchMaxTermBytes {
+				delete(freq, term)
+				continue
+			}
+			doc.Length += n
+		}
+
+		doc.ID, doc.CreatedAt = existing.ID, existing.CreatedAt
+		if err := tx.Save(doc).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("document_id = ?", doc.ID).Delete(&models.SearchPosting{}).Error; err != nil {
+			return err
+		}
+		postings := make([]models.SearchPosting, 0, len(freq))
+		for term, n := range freq {
+			postings = append(postings, models.SearchPosting{Term: term, DocumentID: doc.ID, TF: n})
+		}
+		if len(postings) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(postings, searchPostingBatchSize).Error
+	})
+}
+
+func removeSearchDocuments(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("document_id IN ?", ids).Delete(&models.SearchPosting{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", ids).Delete(&models.SearchDocument{}).Error
+}
+
+// articleSearchDocument 只收录已发布的文章
+func articleSearchDocument(id uint) (*models.SearchDocument, error) {
+	var article models.Article
+	if err := database.DB.Preload("Tags").First(&article, id).Error; err != nil {
+		return nil, searchSourceError(err)
+	}
+	if article.Status != models.ArticleStatusPublished {
+		return nil, nil
+	}
+
+	contentHTML := article.ContentHTML
+	if contentHTML == "" {
+		contentHTML, _ = renderMarkdown(article.Content)
+	}
+	tagIDs := make([]uint, len(article.Tags))
+	for i, tag := range article.Tags {
+		tagIDs[i] = tag.ID
+	}
+	return &models.SearchDocument{
+		Type:            models.SearchTypeArticle,
+		TargetID:        article.ID,
+		Title:           article.Title,
+		Content:         strings.TrimSpace(article.Summary + " " + plainText(contentHTML)),
+		Cover:           article.Cover,
+		Slug:            article.Slug,
+		AuthorID:        article.AuthorID,
+		CategoryID:      article.CategoryID,
+		TagIDs:          formatSearchTagIDs(tagIDs),
+		SourceUpdatedAt: article.UpdatedAt,
+	}, nil
+}
+
+// workSearchDocument 只收录审核通过的作品
+func workSearchDocument(id uint) (*models.SearchDocument, error) {
+	var work models.Work
+	if err := database.DB.First(&work, id).Error; err != nil {
+		return nil, searchSourceError(err)
+	}
+	if work.Status != 1 {
+		return nil, nil
+	}
+	return &models.SearchDocument{
+		Type:            models.SearchTypeWork,
+		TargetID:        work.ID,
+		Title:           work.Title,
+		Content:         plainText(work.Description),
+		Cover:           work.Cover,
+		Slug:            work.Slug,
+		AuthorID:        work.AuthorID,
+		Subtype:         work.Type,
+		SourceUpdatedAt: work.UpdatedAt,
+	}, nil
+}
+
+// docSearchDocument 只收录公开知识库中已发布的文档，可见性与公开知识库页面一致
+func docSearchDocument(id uint) (*models.SearchDocument, error) {
+	public, err := NewPublicWikiService().Doc(id)
+	if err != nil {
+		if errors.Is(err, ErrKnowledgeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var doc models.Doc
+	if err := database.DB.Select("id, owner_id").First(&doc, id).Error; err != nil {
+		return nil, searchSourceError(err)
+	}
+	return &models.SearchDocument{
+		Type:            models.SearchTypeDoc,
+		TargetID:        public.ID,
+		Title:           public.Title,
+		Content:         plainText(public.ContentHTML),
+		AuthorID:        doc.OwnerID,
+		SourceUpdatedAt: public.UpdatedAt,
+	}, nil
+}
+
+// userSearchDocument 只收录正常状态的用户，按昵称、用户名和简介检索
+func userSearchDocument(id uint) (*models.SearchDocument, error) {
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		return nil, searchSourceError(err)
+	}
+	if user.Status != 1 {
+		return nil, nil
+	}
+	return &models.SearchDocument{
+		Type:            models.SearchTypeUser,
+		TargetID:        user.ID,
+		Title:           displayName(&user),
+		Content:         strings.TrimSpace(user.Username + " " + plainText(user.Bio)),
+		Cover:           user.Avatar,
+		SourceUpdatedAt: user.UpdatedAt,
+	}, nil
+}
+
+func searchSourceModel(docType string) (interface{}, error) {
+	switch docType {
+	case models.SearchTypeArticle:
+		return &models.Article{}, nil
+	case models.SearchTypeWork:
+		return &models.Work{}, nil
+	case models.SearchTypeDoc:
+		return &models.Doc{}, nil
+	case models.SearchTypeUser:
+		return &models.User{}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSearchTypeInvalid, docType)
+}
+
+// searchSourceError 源数据不存在（含已软删除）时视为需要从索引中移除
+func searchSourceError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// searchScoreSQL 按 search.BM25 的公式在数据库中计算得分的表达式，
+// 与 search_postings p、search_documents d 连接查询并按 p.document_id 分组时使用
+func searchScoreSQL(model search.BM25, idf map[string]float64) (string, []interface{}) {
+	terms := make([]string, 0, len(idf))
+	for term := range idf {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	var b strings.Builder
+	args := make([]interface{}, 0, len(terms)*2+4)
+	b.WriteString("SUM(CASE p.term")
+	for _, term := range terms {
+		b.WriteString(" WHEN ? THEN ?")
+		args = append(args, term, idf[term])
+	}
+	b.WriteString(" ELSE 0 END * p.tf * ? / (p.tf + ? * ")
+	args = append(args, model.K1+1, model.K1)
+	if model.AvgLength > 0 {
+		b.WriteString("(1 - ? + ? * d.length / ?)")
+		args = append(args, model.B, model.B, model.AvgLength)
+	} else {
+		b.WriteString("1")
+	}
+	b.WriteString("))")
+	return b.String(), args
+}
+
+// searchTerms 解析查询词，去掉超出列宽的词并限制数量
+func searchTerms(keyword string) []string {
+	var terms []string
+	for _, term := range search.Terms(keyword) {
+		if len(term) > searchMaxTermBytes {
+			continue
+		}
+		terms = append(terms, term)
+		if len(terms) == searchMaxQueryTerms {
+			break
+		}
+	}
+	return terms
+}
+
+func formatSearchTagIDs(ids []uint) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return "," + strings.Join(parts, ",") + ","
+}
+
+func parseSearchTagIDs(value string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(value, ","), ",") {
+		if id, err := strconv.ParseUint(part, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/search"
+)
+
+func TestSearchTagIDs(t *testing.T) {
+	if got := formatSearchTagIDs([]uint{1, 15}); got != ",1,15," {
+		t.Fatalf("formatSearchTagIDs() = %q", got)
+	}
+	if got := formatSearchTagIDs(nil); got != "" {
+		t.Fatalf("formatSearchTagIDs(nil) = %q", got)
+	}
+	if got := parseSearchTagIDs(",1,15,"); !reflect.DeepEqual(got, []uint{1, 15}) {
+		t.Fatalf("parseSearchTagIDs() = %v", got)
+	}
+	if got := parseSearchTagIDs(""); got != nil {
+		t.Fatalf("parseSearchTagIDs(\"\") = %v", got)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms("  全文搜索 全文  ")
+	want := []string{"全文", "文搜", "搜索"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("searchTerms() = %q, want %q", got, want)
+	}
+	if got := searchTerms("，。!?"); len(got) != 0 {
+		t.Fatalf("searchTerms(punctuation) = %q", got)
+	}
+}
+
+func TestSearchFacetCounts(t *testing.T) {
+	candidates := []*searchCandidate{
+		{ID: 1, Type: models.SearchTypeArticle, CategoryID: 1, TagIDs: []uint{1, 2}},
+		{ID: 2, Type: models.SearchTypeArticle, CategoryID: 2, TagIDs: []uint{2}},
+		{ID: 3, Type: models.SearchTypeWork, Subtype: "project"},
+		{ID: 4, Type: models.SearchTypeUser},
+	}
+	query := &models.SearchQuery{Type: models.SearchTypeArticle, TagID: 2}
+
+	var hits []uint
+	for _, c := range candidates {
+		if c.matches(query, "") {
+			hits = append(hits, c.ID)
+		}
+	}
+	if !reflect.DeepEqual(hits, []uint{1, 2}) {
+		t.Fatalf("hits = %v", hits)
+	}
+
+	// 类型分面忽略类型筛选，但仍受标签筛选约束，只剩文章
+	types := make(map[string]int)
+	for _, c := range candidates {
+		if c.matches(query, searchFacetType) {
+			types[c.Type]++
+		}
+	}
+	facets := labeledFacets(types, searchTypeLabels)
+	if len(facets) != 1 || facets[0].Value != models.SearchTypeArticle || facets[0].Count != 2 {
+		t.Fatalf("type facets = %+v", facets)
+	}
+
+	// 去掉标签筛选后，类型分面包含全部类型
+	query.TagID = 0
+	types = make(map[string]int)
+	for _, c := range candidates {
+		if c.matches(query, searchFacetType) {
+			types[c.Type]++
+		}
+	}
+	facets = labeledFacets(types, searchTypeLabels)
+	if len(facets) != 3 || facets[0].Value != models.SearchTypeArticle {
+		t.Fatalf("type facets = %+v", facets)
+	}
+}
+
+func TestSearchScoreSQL(t *testing.T) {
+	model := search.NewBM25(100, 20)
+	sql, args := searchScoreSQL(model, map[string]float64{"搜索": 1.5, "猫": 2})
+	if n := strings.Count(sql, "?"); n != len(args) {
+		t.Fatalf("placeholders = %d, args = %d: %s", n, len(args), sql)
+	}
+	// 词按字典序展开，保证生成的语句稳定
+	if args[0] != "搜索" || args[1] != 1.5 || args[2] != "猫" || args[3] != 2.0 {
+		t.Fatalf("args = %v", args)
+	}
+	if !strings.Contains(sql, "d.length") {
+		t.Fatalf("sql should normalize by document length: %s", sql)
+	}
+
+	// 语料为空时不做长度归一化
+	sql, args = searchScoreSQL(search.NewBM25(0, 0), map[string]float64{"猫": 1})
+	if strings.Contains(sql, "d.length") || strings.Count(sql, "?") != len(args) {
+		t.Fatalf("sql = %s, args = %v", sql, args)
+	}
+}
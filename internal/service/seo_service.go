@@ -0,0 +1,430 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/seo"
+
+	"github.com/microcosm-cc/bluemonday"
+	"gorm.io/gorm"
+)
+
+const (
+	seoCachePrefix       = "seo:meta:"
+	seoCacheTTL          = 5 * time.Minute
+	seoSiteTTL           = time.Minute // 站点名称、描述等配置在进程内的缓存时间
+	seoDescriptionLength = 160
+)
+
+// 可缓存的前台页面类型，缓存键为 seo:meta:<类型>:<ID>
+const (
+	seoPageHome      = "home"
+	seoPageArticle   = "article"
+	seoPageWork      = "work"
+	seoPageUser      = "user"
+	seoPageWorkspace = "wiki"
+	seoPageDoc       = "doc"
+)
+
+var errSEORouteUnknown = errors.New("未知的前台路由")
+
+var plainTextPolicy = bluemonday.StrictPolicy()
+
+// seoSiteCache 站点默认元信息用到的配置，过期后整体重新加载
+var seoSiteCache struct {
+	sync.RWMutex
+	values   map[string]string
+	loadedAt time.Time
+}
+
+// seoPage 前台路径对应的页面
+type seoPage struct {
+	Kind string
+	ID   uint
+}
+
+// SEOService 按前台路由解析页面标题、描述、封面等元信息，供服务端注入到 SPA 的 index.html
+type SEOService struct {
+	articleService *ArticleService
+	workService    *WorkService
+	slugService    *SlugService
+	wikiService    *PublicWikiService
+	shareService   *ShareService
+}
+
+func NewSEOService() *SEOService {
+	return &SEOService{
+		articleService: NewArticleService(),
+		workService:    NewWorkService(),
+		slugService:    NewSlugService(),
+		wikiService:    NewPublicWikiService(),
+		shareService:   NewShareService(),
+	}
+}
+
+// PageMeta 返回前台路径对应的元信息；requestPath 不含开头的斜杠。
+// 内容不存在或不公开时退回站点默认信息，不会返回 nil
+func (s *SEOService) PageMeta(requestPath, baseURL string) *seo.Meta {
+	baseURL = strings.TrimRight(baseURL, "/")
+	parts := strings.Split(strings.Trim(requestPath, "/"), "/")
+
+	// 分享链接可能随时被停用或过期，不做缓存
+	if len(parts) == 2 && parts[0] == "share" {
+		meta, _ := s.resolve(func(site *seo.Meta) error { return s.shareMeta(site, parts[1]) }, baseURL)
+		return meta
+	}
+
+	page, err := s.page(parts)
+	if err != nil {
+		meta, _ := s.resolve(func(*seo.Meta) error { return err }, baseURL)
+		return meta
+	}
+
+	// 只缓存解析到具体内容的页面，键按内容而不是请求路径和 Host 生成，
+	// 未配置站点地址（按请求的 Host 生成链接）时不缓存
+	cacheKey := ""
+	if database.RDB != nil && baseURL != "" && baseURL == seoBaseURL() {
+		cacheKey = seoCacheKey(page.Kind, page.ID)
+		var cached seo.Meta
+		if err := database.GetCache(cacheKey, &cached); err == nil && cached.SiteName != "" {
+			return &cached
+		}
+	}
+
+	meta, ok := s.resolve(func(site *seo.Meta) error { return s.pageMeta(site, page, baseURL) }, baseURL)
+	if ok && cacheKey != "" {
+		if err := database.SetCache(cacheKey, meta, seoCacheTTL); err != nil {
+			log.Printf("缓存页面元信息失败: %v", err)
+		}
+	}
+	return meta
+}
+
+// invalidateSEOMeta 内容状态、可见性或元信息变化后删除页面元信息缓存
+func invalidateSEOMeta(kind string, id uint) {
+	if database.RDB == nil {
+		return
+	}
+	if err := database.DeleteCache(seoCacheKey(kind, id)); err != nil {
+		log.Printf("清除页面元信息缓存失败 (%s %d): %v", kind, id, err)
+	}
+}
+
+func seoCacheKey(kind string, id uint) string {
+	return fmt.Sprintf("%s%s:%d", seoCachePrefix, kind, id)
+}
+
+// seoBaseURL 配置的站点地址，未配置时为空
+func seoBaseURL() string {
+	if config.AppConfig == nil {
+		return ""
+	}
+	return strings.TrimRight(config.AppConfig.Server.PublicURL, "/")
+}
+
+// resolve 先填充站点默认信息，再由 fill 覆盖为具体页面；fill 出错时返回站点默认信息，第二个返回值为 false
+func (s *SEOService) resolve(fill func(meta *seo.Meta) error, baseURL string) (*seo.Meta, bool) {
+	values := seoSiteValues()
+	site := func() *seo.Meta {
+		meta := &seo.Meta{
+			SiteName:    values[models.SettingSiteName],
+			Description: seoDescription(values[models.SettingSiteDescription]),
+			Locale:      "zh_CN",
+		}
+		if meta.SiteName == "" {
+			meta.SiteName = "InkSpace"
+		}
+		if keywords := values[models.SettingSiteKeywords]; keywords != "" {
+			for _, keyword := range strings.FieldsFunc(keywords, func(r rune) bool { return r == ',' || r == '，' }) {
+				if keyword = strings.TrimSpace(keyword); keyword != "" {
+					meta.Keywords = append(meta.Keywords, keyword)
+				}
+			}
+		}
+		if logo := values[models.SettingSiteLogo]; logo != "" {
+			meta.SiteLogo = absoluteURL(baseURL, logo)
+			meta.Image = meta.SiteLogo
+		}
+		return meta
+	}
+
+	meta := site()
+	if err := fill(meta); err != nil {
+		if !seoNotFound(err) {
+			log.Printf("生成页面元信息失败: %v", err)
+		}
+		return site(), false
+	}
+	return meta, true
+}
+
+// seoSiteValues 读取站点名称、描述、关键词和 Logo，在进程内缓存 seoSiteTTL
+func seoSiteValues() map[string]string {
+	seoSiteCache.RLock()
+	values, loadedAt := seoSiteCache.values, seoSiteCache.loadedAt
+	seoSiteCache.RUnlock()
+	if values != nil && time.Since(loadedAt) < seoSiteTTL {
+		return values
+	}
+
+	seoSiteCache.Lock()
+	defer seoSiteCache.Unlock()
+	if seoSiteCache.values != nil && time.Since(seoSiteCache.loadedAt) < seoSiteTTL {
+		return seoSiteCache.values
+	}
+	loaded, err := NewSettingService().Values([]string{
+		models.SettingSiteName, models.SettingSiteDescription, models.SettingSiteKeywords, models.SettingSiteLogo,
+	})
+	if err != nil {
+		// 数据库暂时不可用时沿用旧的缓存
+		log.Printf("加载站点配置失败: %v", err)
+		return seoSiteCache.values
+	}
+	seoSiteCache.values = loaded
+	seoSiteCache.loadedAt = time.Now()
+	return loaded
+}
+
+// page 解析前台路径；不是可生成元信息的路由时返回 errSEORouteUnknown
+func (s *SEOService) page(parts []string) (*seoPage, error) {
+	var kind string
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		return &seoPage{Kind: seoPageHome}, nil
+	case len(parts) == 3 && parts[0] == "blog":
+		resolved, err := s.slugService.Resolve(models.SlugTargetArticle, parts[1], parts[2])
+		if err != nil {
+			return nil, err
+		}
+		return &seoPage{Kind: seoPageArticle, ID: resolved.TargetID}, nil
+	case len(parts) == 3 && parts[0] == "works":
+		resolved, err := s.slugService.Resolve(models.SlugTargetWork, parts[1], parts[2])
+		if err != nil {
+			return nil, err
+		}
+		return &seoPage{Kind: seoPageWork, ID: resolved.TargetID}, nil
+	case len(parts) == 3 && parts[0] == "wiki" && parts[1] == "docs":
+		id, err := seoID(parts[2])
+		if err != nil {
+			return nil, err
+		}
+		return &seoPage{Kind: seoPageDoc, ID: id}, nil
+	case len(parts) == 2 && parts[0] == "blog":
+		kind = seoPageArticle
+	case len(parts) == 2 && parts[0] == "works":
+		kind = seoPageWork
+	case len(parts) == 2 && parts[0] == "users":
+		kind = seoPageUser
+	case len(parts) == 2 && parts[0] == "wiki":
+		kind = seoPageWorkspace
+	default:
+		return nil, errSEORouteUnknown
+	}
+	id, err := seoID(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return &seoPage{Kind: kind, ID: id}, nil
+}
+
+func (s *SEOService) pageMeta(meta *seo.Meta, page *seoPage, baseURL string) error {
+	switch page.Kind {
+	case seoPageHome:
+		meta.Canonical = baseURL + "/"
+		return nil
+	case seoPageArticle:
+		return s.articleMeta(meta, page.ID, baseURL)
+	case seoPageWork:
+		return s.workMeta(meta, page.ID, baseURL)
+	case seoPageUser:
+		return userMeta(meta, page.ID, baseURL)
+	case seoPageWorkspace:
+		return s.workspaceMeta(meta, page.ID, baseURL)
+	case seoPageDoc:
+		return s.docMeta(meta, page.ID, baseURL)
+	}
+	return errSEORouteUnknown
+}
+
+func (s *SEOService) articleMeta(meta *seo.Meta, id uint, baseURL string) error {
+	article, err := s.articleService.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if article.Status != models.ArticleStatusPublished {
+		return gorm.ErrRecordNotFound
+	}
+
+	meta.Type = "article"
+	meta.SchemaType = "Article"
+	meta.Title = article.Title
+	meta.Canonical = fmt.Sprintf("%s/blog/%d", baseURL, article.ID)
+	meta.PublishedTime = article.CreatedAt
+	if article.PublishAt != nil {
+		meta.PublishedTime = *article.PublishAt
+	}
+	meta.ModifiedTime = article.UpdatedAt
+	if article.Cover != "" {
+		meta.Image = absoluteURL(baseURL, article.Cover)
+	}
+	if article.Author != nil {
+		meta.AuthorName = displayName(article.Author)
+		meta.AuthorURL = fmt.Sprintf("%s/users/%d", baseURL, article.Author.ID)
+		if article.Slug != "" {
+			meta.Canonical = fmt.Sprintf("%s/blog/%s/%s", baseURL, url.PathEscape(article.Author.Username), url.PathEscape(article.Slug))
+		}
+	}
+	meta.Keywords = nil
+	if article.Category != nil {
+		meta.Keywords = append(meta.Keywords, article.Category.Name)
+	}
+	for _, tag := range article.Tags {
+		meta.Keywords = append(meta.Keywords, tag.Name)
+	}
+
+	meta.Description = seoDescription(article.Summary)
+	if meta.Description == "" {
+		contentHTML := article.ContentHTML
+		if contentHTML == "" {
+			contentHTML, _ = renderMarkdown(article.Content)
+		}
+		meta.Description = seoDescription(contentHTML)
+	}
+	return nil
+}
+
+func (s *SEOService) workMeta(meta *seo.Meta, id uint, baseURL string) error {
+	work, err := s.workService.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if work.Status != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	meta.Type = "article"
+	meta.SchemaType = "CreativeWork"
+	meta.Title = work.Title
+	meta.Description = seoDescription(work.Description)
+	meta.Canonical = fmt.Sprintf("%s/works/%d", baseURL, work.ID)
+	meta.PublishedTime = work.CreatedAt
+	meta.ModifiedTime = work.UpdatedAt
+	meta.Keywords = nil
+	if work.Cover != "" {
+		meta.Image = absoluteURL(baseURL, work.Cover)
+	}
+	if work.Author != nil {
+		meta.AuthorName = displayName(work.Author)
+		meta.AuthorURL = fmt.Sprintf("%s/users/%d", baseURL, work.Author.ID)
+		if work.Slug != "" {
+			meta.Canonical = fmt.Sprintf("%s/works/%s/%s", baseURL, url.PathEscape(work.Author.Username), url.PathEscape(work.Slug))
+		}
+	}
+	return nil
+}
+
+func userMeta(meta *seo.Meta, id uint, baseURL string) error {
+	var user models.User
+	if err := database.DB.Select("id, username, nickname, avatar, bio").Where("id = ? AND status = ?", id, 1).First(&user).Error; err != nil {
+		return err
+	}
+
+	meta.Type = "profile"
+	meta.Title = displayName(&user)
+	meta.Canonical = fmt.Sprintf("%s/users/%d", baseURL, user.ID)
+	meta.Keywords = nil
+	if bio := seoDescription(user.Bio); bio != "" {
+		meta.Description = bio
+	}
+	if user.Avatar != "" {
+		meta.Image = absoluteURL(baseURL, user.Avatar)
+	}
+	return nil
+}
+
+func (s *SEOService) workspaceMeta(meta *seo.Meta, id uint, baseURL string) error {
+	workspace, err := publicWorkspace(id)
+	if err != nil {
+		return err
+	}
+	meta.Title = workspace.Name
+	meta.Canonical = fmt.Sprintf("%s/wiki/%d", baseURL, workspace.ID)
+	meta.Keywords = nil
+	if description := seoDescription(workspace.Description); description != "" {
+		meta.Description = description
+	}
+	return nil
+}
+
+func (s *SEOService) docMeta(meta *seo.Meta, id uint, baseURL string) error {
+	doc, err := s.wikiService.Doc(id)
+	if err != nil {
+		return err
+	}
+	meta.Type = "article"
+	meta.SchemaType = "Article"
+	meta.Title = doc.Title
+	meta.Description = seoDescription(doc.ContentHTML)
+	meta.Canonical = fmt.Sprintf("%s/wiki/docs/%d", baseURL, doc.ID)
+	meta.ModifiedTime = doc.UpdatedAt
+	if doc.PublishedAt != nil {
+		meta.PublishedTime = *doc.PublishedAt
+	}
+	meta.Keywords = nil
+	return nil
+}
+
+// shareMeta 分享链接只用于链接预览，不希望被搜索引擎收录
+func (s *SEOService) shareMeta(meta *seo.Meta, token string) error {
+	doc, err := s.shareService.Preview(token, time.Now())
+	if err != nil {
+		return err
+	}
+	contentHTML, err := renderMarkdown(doc.Content)
+	if err != nil {
+		return err
+	}
+	meta.Title = doc.Title
+	meta.Description = seoDescription(contentHTML)
+	meta.NoIndex = true
+	meta.Keywords = nil
+	return nil
+}
+
+// seoDescription 将 HTML 或纯文本压缩为单行描述，过长时截断并追加省略号
+func seoDescription(content string) string {
+	text := plainText(content)
+	if len([]rune(text)) <= seoDescriptionLength {
+		return text
+	}
+	return strings.TrimSpace(truncateRunes(text, seoDescriptionLength-1)) + "…"
+}
+
+// plainText 去掉 HTML 标签并合并空白，得到单行纯文本
+func plainText(content string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(plainTextPolicy.Sanitize(content))), " ")
+}
+
+func seoID(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return uint(id), nil
+}
+
+func seoNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrSlugNotFound) || errors.Is(err, errSEORouteUnknown) ||
+		errors.Is(err, ErrKnowledgeNotFound) || errors.Is(err, ErrShareDisabled) || errors.Is(err, ErrShareExpired)
+}
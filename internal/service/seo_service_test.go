@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSEOPage(t *testing.T) {
+	s := NewSEOService()
+	tests := []struct {
+		path string
+		want *seoPage
+	}{
+		{"", &seoPage{Kind: seoPageHome}},
+		{"blog/12", &seoPage{Kind: seoPageArticle, ID: 12}},
+		{"works/3", &seoPage{Kind: seoPageWork, ID: 3}},
+		{"users/7", &seoPage{Kind: seoPageUser, ID: 7}},
+		{"wiki/2", &seoPage{Kind: seoPageWorkspace, ID: 2}},
+		{"wiki/docs/9", &seoPage{Kind: seoPageDoc, ID: 9}},
+	}
+	for _, tt := range tests {
+		got, err := s.page(strings.Split(tt.path, "/"))
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("page(%q) = %+v, %v; want %+v", tt.path, got, err, tt.want)
+		}
+	}
+
+	// 任意路径和非法 ID 不对应具体内容，不会生成缓存
+	for _, path := range []string{"wp-admin/setup.php", "blog", "blog/abc", "users/0", "a/b/c/d"} {
+		_, err := s.page(strings.Split(path, "/"))
+		if err == nil || !seoNotFound(err) {
+			t.Errorf("page(%q) error = %v, want not found", path, err)
+		}
+	}
+	if _, err := s.page([]string{"random"}); !errors.Is(err, errSEORouteUnknown) {
+		t.Errorf("page(random) error = %v", err)
+	}
+
+	if key := seoCacheKey(seoPageArticle, 12); key != "seo:meta:article:12" {
+		t.Errorf("seoCacheKey() = %q", key)
+	}
+}
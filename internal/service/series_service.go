@@ -0,0 +1,316 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeriesNotFound       = errors.New("系列不存在或无权限操作")
+	ErrSeriesArticleInvalid = errors.New("只能添加作者自己的文章，且不能重复")
+	ErrSeriesArticleTaken   = errors.New("部分文章已属于其他系列")
+)
+
+type SeriesService struct{}
+
+func NewSeriesService() *SeriesService {
+	return &SeriesService{}
+}
+
+func (s *SeriesService) Create(req *models.SeriesRequest, ownerID uint) (*models.Series, error) {
+	series := &models.Series{OwnerID: ownerID, Title: req.Title, Description: req.Description, Cover: req.Cover}
+	if err := database.DB.Create(series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *SeriesService) Update(id uint, req *models.SeriesRequest, userID uint, role string) (*models.Series, error) {
+	series, err := s.manageable(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	series.Title = req.Title
+	series.Description = req.Description
+	series.Cover = req.Cover
+	if err := database.DB.Model(series).Updates(map[string]interface{}{
+		"title": series.Title, "description": series.Description, "cover": series.Cover,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// Delete 删除系列，系列中的文章本身保留
+func (s *SeriesService) Delete(id, userID uint, role string) error {
+	series, err := s.manageable(id, userID, role)
+	if err != nil {
+		return err
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(series).Error
+	})
+}
+
+// SetArticles 按给定顺序替换系列中的文章
+func (s *SeriesService) SetArticles(id uint, articleIDs []uint, userID uint, role string) error {
+	series, err := s.manageable(id, userID, role)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[uint]bool, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if articleID == 0 || seen[articleID] {
+			return ErrSeriesArticleInvalid
+		}
+		seen[articleID] = true
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if len(articleIDs) > 0 {
+			var owned int64
+			if err := tx.Model(&models.Article{}).
+				Where("id IN ? AND author_id = ?", articleIDs, series.OwnerID).
+				Count(&owned).Error; err != nil {
+				return err
+			}
+			if int(owned) != len(articleIDs) {
+				return ErrSeriesArticleInvalid
+			}
+
+			var taken int64
+			if err := tx.Model(&models.SeriesArticle{}).
+				Where("article_id IN ? AND series_id <> ?", articleIDs, series.ID).
+				Count(&taken).Error; err != nil {
+				return err
+			}
+			if taken > 0 {
+				return ErrSeriesArticleTaken
+			}
+		}
+
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		if len(articleIDs) > 0 {
+			members := make([]models.SeriesArticle, len(articleIDs))
+			for i, articleID := range articleIDs {
+				members[i] = models.SeriesArticle{SeriesID: series.ID, ArticleID: articleID, Sort: i}
+			}
+			if err := tx.Create(&members).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(series).Update("updated_at", time.Now()).Error
+	})
+}
+
+// Get 获取系列详情；作者本人和文章管理员可以看到未发布的文章
+func (s *SeriesService) Get(id, viewerID uint, role string) (*models.SeriesResponse, error) {
+	var series models.Series
+	if err := database.DB.Preload("Owner").First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, err
+	}
+
+	publishedOnly := series.OwnerID != viewerID && !NewRoleService().HasPermission(role, models.PermissionArticleManage)
+	members, err := seriesMembers(series.ID, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.withCounts([]*models.Series{&series})
+	if err != nil {
+		return nil, err
+	}
+	resp := responses[0]
+	if publishedOnly && resp.PublishedCount == 0 {
+		return nil, ErrSeriesNotFound
+	}
+	resp.Articles = members
+	return resp, nil
+}
+
+// List 公开的系列列表，只包含至少有一篇已发布文章的系列；查看自己的系列时不做限制
+func (s *SeriesService) List(query *models.SeriesListQuery, viewerID uint) ([]*models.SeriesResponse, int64, error) {
+	db := database.DB.Model(&models.Series{})
+	if query.OwnerID > 0 {
+		db = db.Where("owner_id = ?", query.OwnerID)
+	}
+	if query.OwnerID == 0 || query.OwnerID != viewerID {
+		db = db.Where("EXISTS (SELECT 1 FROM series_articles JOIN articles ON articles.id = series_articles.article_id"+
+			" WHERE series_articles.series_id = series.id AND articles.status = ? AND articles.deleted_at IS NULL)",
+			models.ArticleStatusPublished)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var series []*models.Series
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Preload("Owner").Order("updated_at DESC").Offset(offset).Limit(query.PageSize).Find(&series).Error; err != nil {
+		return nil, 0, err
+	}
+	responses, err := s.withCounts(series)
+	return responses, total, err
+}
+
+// ArticleSeries 返回文章所属系列，withNav 为 true 时计算在系列中的位置和前后篇
+func (s *SeriesService) ArticleSeries(articleID uint, withNav bool) (*models.ArticleSeriesInfo, error) {
+	var member models.SeriesArticle
+	if err := database.DB.Where("article_id = ?", articleID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var series models.Series
+	if err := database.DB.First(&series, member.SeriesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	info := &models.ArticleSeriesInfo{ID: series.ID, Title: series.Title}
+	if !withNav {
+		return info, nil
+	}
+	published, err := seriesMembers(series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	info.Total = len(published)
+	for i, item := range published {
+		if item.ID != articleID {
+			continue
+		}
+		info.Position = i + 1
+		if i > 0 {
+			info.Prev = &models.ArticleNavItem{ID: published[i-1].ID, Title: published[i-1].Title}
+		}
+		if i+1 < len(published) {
+			info.Next = &models.ArticleNavItem{ID: published[i+1].ID, Title: published[i+1].Title}
+		}
+		break
+	}
+	return info, nil
+}
+
+// AttachSeries 为文章列表批量填充所属系列（不含前后篇）
+func (s *SeriesService) AttachSeries(articles []*models.ArticleResponse) {
+	if len(articles) == 0 {
+		return
+	}
+	ids := make([]uint, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+
+	var rows []struct {
+		ArticleID uint
+		SeriesID  uint
+		Title     string
+	}
+	if err := database.DB.Table("series_articles").
+		Select("series_articles.article_id, series.id AS series_id, series.title").
+		Joins("JOIN series ON series.id = series_articles.series_id AND series.deleted_at IS NULL").
+		Where("series_articles.article_id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		log.Printf("查询文章所属系列失败: %v", err)
+		return
+	}
+	byArticle := make(map[uint]*models.ArticleSeriesInfo, len(rows))
+	for _, row := range rows {
+		byArticle[row.ArticleID] = &models.ArticleSeriesInfo{ID: row.SeriesID, Title: row.Title}
+	}
+	for _, article := range articles {
+		article.Series = byArticle[article.ID]
+	}
+}
+
+// manageable 查询可管理的系列：作者本人或有文章管理权限
+func (s *SeriesService) manageable(id, userID uint, role string) (*models.Series, error) {
+	query := database.DB.Where("id = ?", id)
+	if !NewRoleService().HasPermission(role, models.PermissionArticleManage) {
+		query = query.Where("owner_id = ?", userID)
+	}
+	var series models.Series
+	if err := query.First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// withCounts 转换为响应并填充文章总数和已发布数
+func (s *SeriesService) withCounts(series []*models.Series) ([]*models.SeriesResponse, error) {
+	responses := make([]*models.SeriesResponse, len(series))
+	if len(series) == 0 {
+		return responses, nil
+	}
+	ids := make([]uint, len(series))
+	for i, item := range series {
+		ids[i] = item.ID
+		responses[i] = item.ToResponse()
+	}
+
+	var rows []struct {
+		SeriesID  uint
+		Total     int
+		Published int
+	}
+	if err := database.DB.Table("series_articles").
+		Select("series_articles.series_id, COUNT(*) AS total, "+
+			"SUM(CASE WHEN articles.status = ? THEN 1 ELSE 0 END) AS published", models.ArticleStatusPublished).
+		Joins("JOIN articles ON articles.id = series_articles.article_id AND articles.deleted_at IS NULL").
+		Where("series_articles.series_id IN ?", ids).
+		Group("series_articles.series_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int, len(rows))
+	published := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.SeriesID] = row.Total
+		published[row.SeriesID] = row.Published
+	}
+	for _, resp := range responses {
+		resp.ArticleCount = counts[resp.ID]
+		resp.PublishedCount = published[resp.ID]
+	}
+	return responses, nil
+}
+
+// seriesMembers 按顺序返回系列中的文章
+func seriesMembers(seriesID uint, publishedOnly bool) ([]models.SeriesArticleItem, error) {
+	db := database.DB.Model(&models.Article{}).
+		Select("articles.id, articles.title, articles.summary, articles.cover, articles.status, articles.publish_at").
+		Joins("JOIN series_articles ON series_articles.article_id = articles.id").
+		Where("series_articles.series_id = ?", seriesID)
+	if publishedOnly {
+		db = db.Where("articles.status = ?", models.ArticleStatusPublished)
+	}
+	var items []models.SeriesArticleItem
+	if err := db.Order("series_articles.sort ASC, articles.id ASC").Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Position = i + 1
+	}
+	return items, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrSessionNotFound = errors.New("会话不存在或已注销")
+
+// sessionTouchInterval 最近活跃时间的写入间隔，避免每个请求都写库
+const sessionTouchInterval = time.Minute
+
+type SessionService struct{}
+
+func NewSessionService() *SessionService { return &SessionService{} }
+
+// Create 记录一次登录并返回新会话
+func (s *SessionService) Create(userID uint, scope string, client *models.ClientInfo) (*models.UserSession, error) {
+	if client == nil {
+		client = &models.ClientInfo{}
+	}
+	now := time.Now()
+	ttl := utils.RefreshTokenTTL()
+	session := &models.UserSession{
+		SessionID:    uuid.NewString(),
+		UserID:       userID,
+		Scope:        scope,
+		Device:       describeDevice(client.UserAgent),
+		UserAgent:    truncateRunes(client.UserAgent, 500),
+		IP:           client.IP,
+		LastActiveAt: now,
+		ExpiresAt:    now.Add(ttl),
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"last_login_at": now, "last_login_ip": client.IP}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RDB.Set(database.Ctx, sessionKey(session.SessionID), userID, ttl).Err(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Extend 刷新令牌时延长会话有效期，会话已注销时返回 ErrSessionRevoked
+func (s *SessionService) Extend(sessionID string) error {
+	ttl := utils.RefreshTokenTTL()
+	alive, err := database.RDB.Expire(database.Ctx, sessionKey(sessionID), ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !alive {
+		return ErrSessionRevoked
+	}
+	now := time.Now()
+	return database.DB.Model(&models.UserSession{}).Where("session_id = ?", sessionID).
+		Updates(map[string]interface{}{"last_active_at": now, "expires_at": now.Add(ttl)}).Error
+}
+
+// List 返回用户所有未注销且未过期的会话
+func (s *SessionService) List(userID uint) ([]*models.UserSession, error) {
+	var sessions []*models.UserSession
+	err := database.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_active_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// Revoke 注销用户自己的某个会话（远程退出）
+func (s *SessionService) Revoke(userID, id uint) error {
+	var session models.UserSession
+	if err := database.DB.Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return s.revoke(database.DB.Where("id = ?", session.ID), []string{session.SessionID})
+}
+
+// RevokeBySessionID 注销指定会话，用于退出登录
+func (s *SessionService) RevokeBySessionID(sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	return s.revoke(database.DB.Where("session_id = ? AND revoked_at IS NULL", sessionID), []string{sessionID})
+}
+
+// RevokeOthers 注销除当前会话外的所有会话（在其他设备上退出）
+func (s *SessionService) RevokeOthers(userID uint, currentSessionID string) (int, error) {
+	var sessionIDs []string
+	if err := database.DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL AND session_id <> ?", userID, currentSessionID).
+		Pluck("session_id", &sessionIDs).Error; err != nil {
+		return 0, err
+	}
+	if len(sessionIDs) == 0 {
+		return 0, nil
+	}
+	return len(sessionIDs), s.revoke(database.DB.Where("session_id IN ?", sessionIDs), sessionIDs)
+}
+
+// MarkAllRevoked 将用户所有会话标记为已注销（令牌已通过代数失效，这里只同步会话列表）
+func (s *SessionService) MarkAllRevoked(userID uint) error {
+	return database.DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// Touch 记录会话最近活跃时间和IP，按 sessionTouchInterval 节流
+func (s *SessionService) Touch(sessionID, ip string) error {
+	if sessionID == "" {
+		return nil
+	}
+	first, err := database.RDB.SetNX(database.Ctx, sessionKey(sessionID)+":touch", 1, sessionTouchInterval).Result()
+	if err != nil || !first {
+		return err
+	}
+	return database.DB.Model(&models.UserSession{}).Where("session_id = ?", sessionID).
+		Updates(map[string]interface{}{"last_active_at": time.Now(), "ip": ip}).Error
+}
+
+func (s *SessionService) revoke(query *gorm.DB, sessionIDs []string) error {
+	if err := query.Model(&models.UserSession{}).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	keys := make([]string, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		keys[i] = sessionKey(sessionID)
+	}
+	return database.RDB.Del(database.Ctx, keys...).Err()
+}
+
+func sessionKey(sessionID string) string {
+	return "auth:session:" + sessionID
+}
+
+// describeDevice 从 User-Agent 中提取“浏览器 · 系统”形式的设备描述
+func describeDevice(userAgent string) string {
+	if strings.TrimSpace(userAgent) == "" {
+		return "未知设备"
+	}
+
+	browser := ""
+	switch {
+	case strings.Contains(userAgent, "MicroMessenger/"):
+		browser = "微信"
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	system := ""
+	switch {
+	case strings.Contains(userAgent, "iPhone"):
+		system = "iOS"
+	case strings.Contains(userAgent, "iPad"):
+		system = "iPadOS"
+	case strings.Contains(userAgent, "Android"):
+		system = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		system = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"):
+		system = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		system = "Linux"
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " · " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	// 命令行工具等非浏览器客户端，取产品名，例如 curl/8.0 -> curl
+	product := strings.Fields(userAgent)[0]
+	if index := strings.Index(product, "/"); index > 0 {
+		product = product[:index]
+	}
+	return truncateRunes(product, 100)
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"strings"
+
 	"github.com/iceymoss/inkspace/internal/database"
 	"github.com/iceymoss/inkspace/internal/models"
 
@@ -21,6 +23,31 @@ func (s *SettingService) Get(key string) (*models.Setting, error) {
 	return &setting, nil
 }
 
+// Values 读取一组配置的当前值，不存在的键不出现在结果中
+func (s *SettingService) Values(keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	var settings []*models.Setting
+	if err := database.DB.Where("`key` IN ?", keys).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		values[setting.Key] = setting.Value
+	}
+	return values, nil
+}
+
+// GetBool 读取开关类配置（"1" 或 "true" 为开启），配置不存在时返回默认值
+func (s *SettingService) GetBool(key string, defaultValue bool) bool {
+	setting, err := s.Get(key)
+	if err != nil {
+		return defaultValue
+	}
+	return setting.Value == "1" || setting.Value == "true"
+}
+
 func (s *SettingService) Set(req *models.SettingRequest) (*models.Setting, error) {
 	var setting models.Setting
 	err := database.DB.Where("`key` = ?", req.Key).First(&setting).Error
@@ -115,7 +142,7 @@ func (s *SettingService) BatchSet(settings map[string]string) error {
 					isPublic = true
 				} else if key == models.SettingCommentAudit || key == models.SettingRegisterEnabled ||
 					key == models.SettingArticleCommentEnabled || key == models.SettingWorkCommentEnabled ||
-					key == models.SettingWorkAudit {
+					key == models.SettingWorkAudit || key == models.SettingEmailVerifyRequired {
 					group = "feature"
 					isPublic = false
 				} else if key == models.SettingCodeTheme || key == models.SettingMarkdownTheme {
@@ -135,6 +162,12 @@ func (s *SettingService) BatchSet(settings map[string]string) error {
 					key == "holiday_primary" {
 					group = "theme"
 					isPublic = true // 节假日主题设置需要公开，前端才能使用
+				} else if key == models.SettingRobotsTxt {
+					group = "seo"
+					isPublic = false
+				} else if strings.HasPrefix(key, "hot_rank_") {
+					group = "ranking"
+					isPublic = false
 				}
 			} else {
 				// 更新现有主题记录时，确保分组和公开状态正确。
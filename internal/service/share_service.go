@@ -105,11 +105,11 @@ func (s *ShareService) Public(token string, now time.Time) (*models.Doc, error)
 			}
 			return err
 		}
-		html, err := renderMarkdown(doc.Content)
+		html, toc, err := renderMarkdownWithTOC(doc.Content)
 		if err != nil {
 			return err
 		}
-		doc.ContentHTML = html
+		doc.ContentHTML, doc.ContentTOC = html, toc
 		if err := tx.Model(&models.ShareLink{}).Where("id = ?", link.ID).
 			UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error; err != nil {
 			return err
@@ -124,6 +124,28 @@ func (s *ShareService) Public(token string, now time.Time) (*models.Doc, error)
 	return &doc, nil
 }
 
+// Preview 读取分享链接对应的文档用于生成链接预览，不增加浏览次数
+func (s *ShareService) Preview(token string, now time.Time) (*models.Doc, error) {
+	var link models.ShareLink
+	if err := database.DB.Where("token = ?", token).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrKnowledgeNotFound
+		}
+		return nil, err
+	}
+	if err := validateShareLink(&link, now); err != nil {
+		return nil, err
+	}
+	var doc models.Doc
+	if err := database.DB.Select("id, title, content, updated_at").Where("id = ?", link.DocID).First(&doc).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrKnowledgeNotFound
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
 func (s *ShareService) get(id, ownerID uint) (*models.ShareLink, error) {
 	var link models.ShareLink
 	if err := database.DB.Where("id = ? AND owner_id = ?", id, ownerID).First(&link).Error; err != nil {
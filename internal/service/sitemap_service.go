@@ -0,0 +1,330 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/sitemap"
+
+	"gorm.io/gorm"
+)
+
+const (
+	sitemapPageSize    = 10000
+	sitemapCachePrefix = "sitemap:"
+	sitemapCacheTTL    = time.Hour
+)
+
+// 站点地图分类，对应 /sitemaps/{section}-{page}.xml
+const (
+	SitemapArticles   = "articles"
+	SitemapWorks      = "works"
+	SitemapUsers      = "users"
+	SitemapCategories = "categories"
+	SitemapTags       = "tags"
+	SitemapWiki       = "wiki"
+	SitemapWikiDocs   = "docs"
+)
+
+// sitemapSections 索引中各分类的输出顺序
+var sitemapSections = []string{SitemapArticles, SitemapWorks, SitemapUsers, SitemapCategories, SitemapTags, SitemapWiki, SitemapWikiDocs}
+
+var ErrSitemapNotFound = errors.New("站点地图不存在")
+
+// defaultRobotsTxt 未配置 robots_txt 时的默认规则，屏蔽接口和个人中心等无需收录的页面
+const defaultRobotsTxt = `User-agent: *
+Allow: /
+Disallow: /api/
+Disallow: /dashboard
+Disallow: /favorites
+Disallow: /profile
+Disallow: /share/
+Disallow: /login
+Disallow: /oauth/
+Disallow: /verify-email
+Disallow: /reset-password`
+
+// SitemapDocument 生成好的站点地图
+type SitemapDocument struct {
+	Body         []byte    `json:"body"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// sitemapStat 分类的条目总数和最近更新时间
+type sitemapStat struct {
+	Total   int64
+	LastMod *time.Time
+}
+
+// SitemapService 生成站点地图索引、按分类拆分的站点地图和 robots.txt
+type SitemapService struct {
+	wikiService *PublicWikiService
+}
+
+func NewSitemapService() *SitemapService {
+	return &SitemapService{
+		wikiService: NewPublicWikiService(),
+	}
+}
+
+// Index 生成站点地图索引；每个分类按 sitemapPageSize 分页，没有内容的分类不会列出
+func (s *SitemapService) Index(baseURL string) (*SitemapDocument, error) {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return s.cached("index:"+baseURL, func() (*SitemapDocument, error) {
+		var entries []sitemap.Entry
+		var lastModified time.Time
+		for _, section := range sitemapSections {
+			stat, err := s.stat(section)
+			if err != nil {
+				return nil, err
+			}
+			if stat.Total == 0 {
+				continue
+			}
+			var lastMod time.Time
+			if stat.LastMod != nil {
+				lastMod = *stat.LastMod
+			}
+			if lastMod.After(lastModified) {
+				lastModified = lastMod
+			}
+			pages := int((stat.Total + sitemapPageSize - 1) / sitemapPageSize)
+			for page := 1; page <= pages; page++ {
+				entries = append(entries, sitemap.Entry{Loc: baseURL + SitemapPath(section, page), LastMod: lastMod})
+			}
+		}
+
+		body, err := sitemap.RenderIndex(entries)
+		if err != nil {
+			return nil, err
+		}
+		return &SitemapDocument{Body: body, LastModified: sitemapLastModified(lastModified)}, nil
+	})
+}
+
+// Section 生成某个分类第 page 页的站点地图
+func (s *SitemapService) Section(section string, page int, baseURL string) (*SitemapDocument, error) {
+	if !validSitemapSection(section) || page < 1 {
+		return nil, ErrSitemapNotFound
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	return s.cached(fmt.Sprintf("%s:%d:%s", section, page, baseURL), func() (*SitemapDocument, error) {
+		urls, err := s.urls(section, page, baseURL)
+		if err != nil {
+			return nil, err
+		}
+		if len(urls) == 0 && page > 1 {
+			return nil, ErrSitemapNotFound
+		}
+
+		var lastModified time.Time
+		for _, u := range urls {
+			if u.LastMod.After(lastModified) {
+				lastModified = u.LastMod
+			}
+		}
+		body, err := sitemap.RenderURLSet(urls)
+		if err != nil {
+			return nil, err
+		}
+		return &SitemapDocument{Body: body, LastModified: sitemapLastModified(lastModified)}, nil
+	})
+}
+
+// RobotsTxt 返回后台配置的 robots.txt；未配置时使用默认规则，缺少 Sitemap 指令时自动补充
+func (s *SitemapService) RobotsTxt(baseURL string) string {
+	content := defaultRobotsTxt
+	if setting, err := NewSettingService().Get(models.SettingRobotsTxt); err == nil && strings.TrimSpace(setting.Value) != "" {
+		content = strings.TrimSpace(strings.ReplaceAll(setting.Value, "\r\n", "\n"))
+	}
+	if !hasSitemapDirective(content) {
+		content += "\n\nSitemap: " + strings.TrimRight(baseURL, "/") + "/sitemap.xml"
+	}
+	return content + "\n"
+}
+
+// SitemapPath 分类站点地图的路径
+func SitemapPath(section string, page int) string {
+	return fmt.Sprintf("/sitemaps/%s-%d.xml", section, page)
+}
+
+func (s *SitemapService) cached(key string, build func() (*SitemapDocument, error)) (*SitemapDocument, error) {
+	cacheKey := sitemapCachePrefix + key
+	if database.RDB != nil {
+		var cached SitemapDocument
+		if err := database.GetCache(cacheKey, &cached); err == nil && len(cached.Body) > 0 {
+			return &cached, nil
+		}
+	}
+
+	doc, err := build()
+	if err != nil {
+		return nil, err
+	}
+	if database.RDB != nil {
+		if err := database.SetCache(cacheKey, doc, sitemapCacheTTL); err != nil {
+			log.Printf("缓存站点地图失败: %v", err)
+		}
+	}
+	return doc, nil
+}
+
+func (s *SitemapService) stat(section string) (*sitemapStat, error) {
+	var stat sitemapStat
+	switch section {
+	case SitemapWiki:
+		workspaces, total, err := s.wikiService.Workspaces(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		stat.Total = total
+		if len(workspaces) > 0 {
+			stat.LastMod = &workspaces[0].UpdatedAt
+		}
+		return &stat, nil
+	case SitemapWikiDocs:
+		docs, total, err := s.wikiService.Docs(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		stat.Total = total
+		if len(docs) > 0 {
+			stat.LastMod = &docs[0].UpdatedAt
+		}
+		return &stat, nil
+	}
+
+	err := sitemapQuery(section).Select("COUNT(*) AS total, MAX(updated_at) AS last_mod").Scan(&stat).Error
+	return &stat, err
+}
+
+func (s *SitemapService) urls(section string, page int, baseURL string) ([]sitemap.URL, error) {
+	switch section {
+	case SitemapArticles, SitemapWorks:
+		return s.contentURLs(section, page, baseURL)
+	case SitemapWiki:
+		workspaces, _, err := s.wikiService.Workspaces(page, sitemapPageSize)
+		if err != nil {
+			return nil, err
+		}
+		urls := make([]sitemap.URL, 0, len(workspaces))
+		for _, workspace := range workspaces {
+			urls = append(urls, sitemap.URL{Loc: fmt.Sprintf("%s/wiki/%d", baseURL, workspace.ID), LastMod: workspace.UpdatedAt})
+		}
+		return urls, nil
+	case SitemapWikiDocs:
+		docs, _, err := s.wikiService.Docs(page, sitemapPageSize)
+		if err != nil {
+			return nil, err
+		}
+		urls := make([]sitemap.URL, 0, len(docs))
+		for _, doc := range docs {
+			urls = append(urls, sitemap.URL{Loc: fmt.Sprintf("%s/wiki/docs/%d", baseURL, doc.ID), LastMod: doc.UpdatedAt})
+		}
+		return urls, nil
+	}
+
+	var rows []struct {
+		ID        uint
+		UpdatedAt time.Time
+	}
+	if err := sitemapQuery(section).Select("id, updated_at").Order("id ASC").
+		Offset((page - 1) * sitemapPageSize).Limit(sitemapPageSize).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	urls := make([]sitemap.URL, 0, len(rows))
+	for _, row := range rows {
+		var loc string
+		switch section {
+		case SitemapUsers:
+			loc = fmt.Sprintf("%s/users/%d", baseURL, row.ID)
+		case SitemapCategories:
+			loc = fmt.Sprintf("%s/blog?category_id=%d", baseURL, row.ID)
+		case SitemapTags:
+			loc = fmt.Sprintf("%s/blog?tag_id=%d", baseURL, row.ID)
+		}
+		urls = append(urls, sitemap.URL{Loc: loc, LastMod: row.UpdatedAt})
+	}
+	return urls, nil
+}
+
+// contentURLs 已发布的文章和作品，有 slug 时使用 /{prefix}/{username}/{slug} 固定链接
+func (s *SitemapService) contentURLs(section string, page int, baseURL string) ([]sitemap.URL, error) {
+	table, prefix := "articles", "blog"
+	if section == SitemapWorks {
+		table, prefix = "works", "works"
+	}
+
+	var rows []struct {
+		ID        uint
+		Slug      string
+		Username  string
+		UpdatedAt time.Time
+	}
+	err := sitemapQuery(section).
+		Select(table + ".id, " + table + ".slug, " + table + ".updated_at, COALESCE(users.username, '') AS username").
+		Joins("LEFT JOIN users ON users.id = " + table + ".author_id AND users.deleted_at IS NULL").
+		Order(table + ".id ASC").
+		Offset((page - 1) * sitemapPageSize).Limit(sitemapPageSize).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]sitemap.URL, 0, len(rows))
+	for _, row := range rows {
+		loc := fmt.Sprintf("%s/%s/%d", baseURL, prefix, row.ID)
+		if row.Slug != "" && row.Username != "" {
+			loc = fmt.Sprintf("%s/%s/%s/%s", baseURL, prefix, url.PathEscape(row.Username), url.PathEscape(row.Slug))
+		}
+		urls = append(urls, sitemap.URL{Loc: loc, LastMod: row.UpdatedAt})
+	}
+	return urls, nil
+}
+
+// sitemapQuery 各分类可公开收录的记录；公开知识库由 PublicWikiService 负责
+func sitemapQuery(section string) *gorm.DB {
+	switch section {
+	case SitemapArticles:
+		return database.DB.Model(&models.Article{}).Where("articles.status = ?", models.ArticleStatusPublished)
+	case SitemapWorks:
+		return database.DB.Model(&models.Work{}).Where("works.status = ?", 1)
+	case SitemapUsers:
+		return database.DB.Model(&models.User{}).Where("status = ?", 1)
+	case SitemapCategories:
+		return database.DB.Model(&models.Category{}).Where("article_count > 0")
+	default:
+		return database.DB.Model(&models.Tag{}).Where("user_id IS NULL AND article_count > 0")
+	}
+}
+
+func validSitemapSection(section string) bool {
+	for _, s := range sitemapSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// sitemapLastModified 没有任何条目时以当前时间作为 Last-Modified
+func sitemapLastModified(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Truncate(time.Second)
+}
+
+func hasSitemapDirective(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(line)), "sitemap:") {
+			return true
+		}
+	}
+	return false
+}
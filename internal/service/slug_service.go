@@ -0,0 +1,259 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxSlugLength = 80 // 生成 slug 的最大长度，为去重后缀预留空间
+	slugRetries   = 3  // 并发创建同名内容时唯一索引冲突的重试次数
+	// pendingSlugPrefix 标题中没有可用字符（如中文标题）时创建期间使用的临时 slug，插入后替换为 ID
+	pendingSlugPrefix = "pending-"
+)
+
+var (
+	ErrSlugInvalid  = errors.New("slug 至少需要包含一个字母或数字")
+	ErrSlugTaken    = errors.New("slug 已被你的其他内容使用")
+	ErrSlugNotFound = errors.New("内容不存在")
+)
+
+// SlugService 根据作者用户名和 slug 查找文章、作品，并处理旧 slug 跳转
+type SlugService struct{}
+
+func NewSlugService() *SlugService {
+	return &SlugService{}
+}
+
+// Resolve 查找作者下 slug 对应的内容；命中旧 slug 时返回当前 slug 并标记需要跳转
+func (s *SlugService) Resolve(targetType, username, slug string) (*models.SlugResolution, error) {
+	model, err := slugModel(targetType)
+	if err != nil {
+		return nil, err
+	}
+	var author models.User
+	if err := database.DB.Select("id", "username").Where("username = ?", username).First(&author).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSlugNotFound
+		}
+		return nil, err
+	}
+
+	var current struct {
+		ID   uint
+		Slug string
+	}
+	err = database.DB.Model(model).Select("id", "slug").
+		Where("author_id = ? AND slug = ?", author.ID, slug).Take(&current).Error
+	if err == nil {
+		return &models.SlugResolution{TargetID: current.ID, Username: author.Username, Slug: current.Slug}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var redirect models.SlugRedirect
+	if err := database.DB.Where("target_type = ? AND author_id = ? AND slug = ?", targetType, author.ID, slug).
+		First(&redirect).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSlugNotFound
+		}
+		return nil, err
+	}
+	// 内容已删除或转移给其他作者时不再跳转
+	if err := database.DB.Model(model).Select("id", "slug").
+		Where("id = ? AND author_id = ?", redirect.TargetID, author.ID).Take(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSlugNotFound
+		}
+		return nil, err
+	}
+	return &models.SlugResolution{TargetID: current.ID, Username: author.Username, Slug: current.Slug, Redirect: true}, nil
+}
+
+// assignSlug 为内容确定 slug：custom 非空时清洗后使用（冲突报错），否则根据标题生成并自动追加序号去重。
+// 标题中没有字母或数字时使用内容 ID；新建内容还没有 ID，先返回临时 slug，由 createWithSlug 在插入后替换
+func assignSlug(tx *gorm.DB, targetType string, authorID, excludeID uint, custom, title string) (string, error) {
+	model, err := slugModel(targetType)
+	if err != nil {
+		return "", err
+	}
+
+	if custom = strings.TrimSpace(custom); custom != "" {
+		slug := normalizeSlug(custom, "")
+		if slug == "" {
+			return "", ErrSlugInvalid
+		}
+		taken, err := slugTaken(tx, model, authorID, excludeID, slug)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", ErrSlugTaken
+		}
+		return slug, nil
+	}
+
+	base := normalizeSlug(title, "")
+	if base == "" {
+		if excludeID == 0 {
+			return pendingSlugPrefix + uuid.NewString(), nil
+		}
+		base = strconv.FormatUint(uint64(excludeID), 10)
+	}
+	query := tx.Unscoped().Model(model).Where("author_id = ?", authorID)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	return nextFreeSlug(query, base)
+}
+
+// createWithSlug 分配 slug 后插入内容，slug 写入 slugField 指向的字段。
+// 并发创建同名内容时唯一索引冲突，重新分配序号后重试；MySQL 中单条语句失败不会中止事务，可以在事务内直接重试
+func createWithSlug(tx *gorm.DB, targetType string, authorID uint, custom, title string, record interface{}, slugField *string) error {
+	for attempt := 0; ; attempt++ {
+		slug, err := assignSlug(tx, targetType, authorID, 0, custom, title)
+		if err != nil {
+			return err
+		}
+		*slugField = slug
+		err = tx.Create(record).Error
+		if err == nil {
+			break
+		}
+		if !isSlugConflict(err) {
+			return err
+		}
+		if custom != "" {
+			return ErrSlugTaken
+		}
+		if attempt >= slugRetries {
+			return err
+		}
+	}
+	if !strings.HasPrefix(*slugField, pendingSlugPrefix) {
+		return nil
+	}
+
+	// 临时 slug 替换为 ID，与旧数据回填的规则一致
+	model, err := slugModel(targetType)
+	if err != nil {
+		return err
+	}
+	var ids []uint
+	if err := tx.Model(model).Where("author_id = ? AND slug = ?", authorID, *slugField).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	id := ids[0]
+	base := strconv.FormatUint(uint64(id), 10)
+	slug, err := nextFreeSlug(tx.Unscoped().Model(model).Where("author_id = ? AND id <> ?", authorID, id), base)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(model).Where("id = ?", id).UpdateColumn("slug", slug).Error; err != nil {
+		return err
+	}
+	*slugField = slug
+	return nil
+}
+
+// nextFreeSlug 返回 query 范围内未被占用的 base 或 base-N，一次查询取出所有同前缀的 slug 后选择最大序号加一。
+// base 只包含字母、数字和连字符，不需要转义 LIKE 通配符
+func nextFreeSlug(query *gorm.DB, base string) (string, error) {
+	var slugs []string
+	if err := query.Where("slug = ? OR slug LIKE ?", base, base+"-%").Pluck("slug", &slugs).Error; err != nil {
+		return "", err
+	}
+	taken := false
+	suffix := 0
+	for _, slug := range slugs {
+		if slug == base {
+			taken = true
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(slug, base+"-")); err == nil && n > suffix {
+			suffix = n
+		}
+	}
+	if !taken {
+		return base, nil
+	}
+	return fmt.Sprintf("%s-%d", base, suffix+1), nil
+}
+
+// isSlugConflict 是否为 slug 唯一索引冲突（MySQL 1062）
+func isSlugConflict(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, "slug")
+}
+
+// recordSlugChange slug 变更后保留旧 slug 的跳转；新 slug 若曾是跳转记录则由当前内容接管
+func recordSlugChange(tx *gorm.DB, targetType string, authorID, targetID uint, oldSlug, newSlug string) error {
+	if newSlug != "" {
+		if err := tx.Where("target_type = ? AND author_id = ? AND slug = ?", targetType, authorID, newSlug).
+			Delete(&models.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+	}
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+	if err := tx.Where("target_type = ? AND author_id = ? AND slug = ?", targetType, authorID, oldSlug).
+		Delete(&models.SlugRedirect{}).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.SlugRedirect{
+		TargetType: targetType,
+		AuthorID:   authorID,
+		Slug:       oldSlug,
+		TargetID:   targetID,
+	}).Error
+}
+
+// normalizeSlug 清洗 slug，去掉首尾连字符并限制长度
+func normalizeSlug(text, fallback string) string {
+	slug := strings.Trim(slugify(text, ""), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		slug = fallback
+	}
+	return slug
+}
+
+// slugTaken 检查作者下 slug 是否已被占用（包含已软删除的内容，与唯一索引保持一致）
+func slugTaken(tx *gorm.DB, model interface{}, authorID, excludeID uint, slug string) (bool, error) {
+	var count int64
+	query := tx.Unscoped().Model(model).Where("author_id = ? AND slug = ?", authorID, slug)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func slugModel(targetType string) (interface{}, error) {
+	switch targetType {
+	case models.SlugTargetArticle:
+		return &models.Article{}, nil
+	case models.SlugTargetWork:
+		return &models.Work{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的 slug 类型: %s", targetType)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		fallback string
+		want     string
+	}{
+		{"英文标题", "Hello World", "article", "hello-world"},
+		{"合并连字符并去掉首尾", "  --Go_1.22 Release--  ", "article", "go-122-release"},
+		{"中文标题使用默认值", "中文标题", "article", "article"},
+		{"中英混合", "Gin 中间件 middleware", "article", "gin-middleware"},
+		{"自定义 slug 清洗后为空", "！！！", "", ""},
+		{"超长截断", strings.Repeat("a", maxSlugLength) + "-b", "work", strings.Repeat("a", maxSlugLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeSlug(tt.text, tt.fallback); got != tt.want {
+				t.Fatalf("normalizeSlug(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSlugConflict(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-hello' for key 'articles.idx_articles_author_slug'"}, true},
+		{fmt.Errorf("create: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'idx_works_author_slug'"}), true},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a' for key 'users.idx_username'"}, false},
+		{&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, false},
+		{errors.New("slug"), false},
+	}
+	for _, tt := range tests {
+		if got := isSlugConflict(tt.err); got != tt.want {
+			t.Errorf("isSlugConflict(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
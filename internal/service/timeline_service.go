@@ -0,0 +1,573 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	timelineKeyPrefix     = "feed:timeline:"
+	timelineProfilePrefix = "feed:profile:"
+	timelineSize          = 500                // 每个用户时间线保留的最新内容数
+	timelineTTL           = 7 * 24 * time.Hour // 7 天内读取过时间线的用户视为活跃用户，发布时直接推送
+	timelineProfileTTL    = time.Hour
+	timelineProfileKeep   = 2 * time.Hour // 兴趣重新计算后旧版本的保留时长，期间翻页继续使用游标中的版本
+	timelineFanoutBatch   = 500
+	timelineInterestLimit = 200 // 计算兴趣时参考最近点赞和收藏的条数
+
+	// 兴趣完全匹配的内容在排序上最多相当于提前一天发布
+	timelineAffinityBoost = 24 * 60 * 60
+	// 文章的兴趣匹配度由标签和分类加权，作品按作品类型匹配
+	timelineTagWeight      = 0.6
+	timelineCategoryWeight = 0.4
+	// 收藏比点赞更能代表兴趣
+	timelineFavoriteWeight = 2
+	timelineLikeWeight     = 1
+)
+
+var ErrTimelineCursorInvalid = errors.New("无效的分页游标")
+
+// timelineFanoutScript 只向时间线仍在缓存中的活跃用户推送，
+// 不活跃用户的时间线在下次读取时从数据库重建，避免生成缺少历史内容的时间线
+var timelineFanoutScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -tonumber(ARGV[3]) - 1)
+return 1
+`)
+
+// TimelineService 个性化首页：关注作者的新文章和作品，按发布时间和兴趣匹配度混合排序。
+// 活跃用户的时间线在发布时写入（fan-out-on-write），其他用户在读取时从数据库生成（fan-out-on-read）
+type TimelineService struct{}
+
+func NewTimelineService() *TimelineService {
+	return &TimelineService{}
+}
+
+// timelineEntry 时间线中的一条内容，Score 为发布时间戳加上兴趣加成（秒）
+type timelineEntry struct {
+	Type        string
+	ID          uint
+	PublishedAt int64
+	Score       int64
+}
+
+// timelineProfile 用户兴趣，各维度的权重已归一化到 [0, 1]。
+// Version 为生成时间（纳秒），游标记录该版本，同一次翻页始终按同一份兴趣排序
+type timelineProfile struct {
+	Version    int64              `json:"version"`
+	Tags       map[uint]float64   `json:"tags"`
+	Categories map[uint]float64   `json:"categories"`
+	WorkTypes  map[string]float64 `json:"work_types"`
+}
+
+// Get 获取用户时间线的一页
+func (s *TimelineService) Get(userID uint, query *models.TimelineQuery) (*models.TimelineResponse, error) {
+	var after *timelineEntry
+	var version int64
+	if query.Cursor != "" {
+		cursor, cursorVersion, err := parseTimelineCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after, version = cursor, cursorVersion
+	}
+
+	entries, err := s.entries(userID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.profile(userID, version)
+	if err != nil {
+		return nil, err
+	}
+	entries, err = scoreTimeline(entries, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	page, hasMore := pageTimeline(entries, after, query.PageSize)
+	resp := &models.TimelineResponse{List: []*models.TimelineItem{}, HasMore: hasMore}
+	if len(page) == 0 {
+		return resp, nil
+	}
+	if hasMore {
+		resp.NextCursor = formatTimelineCursor(profile.Version, page[len(page)-1])
+	}
+	resp.List, err = timelineItems(page)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FanOut 把新发布的内容推送到作者活跃关注者的时间线，失败只记录日志，读取时仍可从数据库重建
+func (s *TimelineService) FanOut(itemType string, id, authorID uint, publishedAt time.Time) {
+	var followerIDs []uint
+	if err := database.DB.Model(&models.UserFollow{}).
+		Where("following_id = ?", authorID).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		log.Printf("查询关注者失败，跳过时间线推送 (%s %d): %v", itemType, id, err)
+		return
+	}
+
+	member := timelineMember(itemType, id)
+	for start := 0; start < len(followerIDs); start += timelineFanoutBatch {
+		pipe := database.RDB.Pipeline()
+		for _, followerID := range followerIDs[start:min(start+timelineFanoutBatch, len(followerIDs))] {
+			timelineFanoutScript.Eval(database.Ctx, pipe, []string{timelineKey(followerID)},
+				publishedAt.Unix(), member, timelineSize)
+		}
+		if _, err := pipe.Exec(database.Ctx); err != nil {
+			log.Printf("推送时间线失败 (%s %d): %v", itemType, id, err)
+			return
+		}
+	}
+}
+
+// Invalidate 关注关系变化后丢弃缓存的时间线，下次读取时重建
+func (s *TimelineService) Invalidate(userID uint) {
+	if err := database.RDB.Del(database.Ctx, timelineKey(userID)).Err(); err != nil {
+		log.Printf("清除用户 %d 的时间线失败: %v", userID, err)
+	}
+}
+
+// entries 读取缓存的时间线，不存在时从数据库生成
+func (s *TimelineService) entries(userID uint) ([]*timelineEntry, error) {
+	key := timelineKey(userID)
+	members, err := database.RDB.ZRevRangeWithScores(database.Ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return s.rebuild(userID)
+	}
+	database.RDB.Expire(database.Ctx, key, timelineTTL)
+
+	entries := make([]*timelineEntry, 0, len(members))
+	for _, member := range members {
+		itemType, id, ok := parseTimelineMember(fmt.Sprint(member.Member))
+		if !ok {
+			continue
+		}
+		entries = append(entries, &timelineEntry{Type: itemType, ID: id, PublishedAt: int64(member.Score)})
+	}
+	return entries, nil
+}
+
+// rebuild 从数据库读取关注作者最新的文章和作品，写入缓存后该用户成为活跃用户
+func (s *TimelineService) rebuild(userID uint) ([]*timelineEntry, error) {
+	var followingIDs []uint
+	if err := database.DB.Model(&models.UserFollow{}).
+		Where("follower_id = ?", userID).
+		Pluck("following_id", &followingIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(followingIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []struct {
+		ID          uint
+		PublishedAt time.Time
+	}
+	if err := database.DB.Model(&models.Article{}).
+		Select("id, COALESCE(publish_at, created_at) AS published_at").
+		Where("author_id IN ? AND status = ?", followingIDs, models.ArticleStatusPublished).
+		Order("published_at DESC").Limit(timelineSize).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]*timelineEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, &timelineEntry{Type: models.TimelineTypeArticle, ID: row.ID, PublishedAt: row.PublishedAt.Unix()})
+	}
+
+	rows = rows[:0]
+	if err := database.DB.Model(&models.Work{}).
+		Select("id, COALESCE(published_at, created_at) AS published_at").
+		Where("author_id IN ? AND status = ?", followingIDs, 1).
+		Order("published_at DESC").Limit(timelineSize).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		entries = append(entries, &timelineEntry{Type: models.TimelineTypeWork, ID: row.ID, PublishedAt: row.PublishedAt.Unix()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].PublishedAt > entries[j].PublishedAt })
+	if len(entries) > timelineSize {
+		entries = entries[:timelineSize]
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	key := timelineKey(userID)
+	members := make([]*redis.Z, len(entries))
+	for i, entry := range entries {
+		members[i] = &redis.Z{Score: float64(entry.PublishedAt), Member: timelineMember(entry.Type, entry.ID)}
+	}
+	pipe := database.RDB.TxPipeline()
+	pipe.Del(database.Ctx, key)
+	pipe.ZAdd(database.Ctx, key, members...)
+	pipe.Expire(database.Ctx, key, timelineTTL)
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		log.Printf("缓存用户 %d 的时间线失败: %v", userID, err)
+	}
+	return entries, nil
+}
+
+// profile 根据最近点赞和收藏的内容计算用户偏好的标签、分类和作品类型。
+// version 不为 0 时读取游标记录的版本，该版本已过期则返回 ErrTimelineCursorInvalid，需要从第一页重新浏览
+func (s *TimelineService) profile(userID uint, version int64) (*timelineProfile, error) {
+	currentKey := fmt.Sprintf("%s%d", timelineProfilePrefix, userID)
+	fromCursor := version > 0
+	if !fromCursor {
+		current, err := database.RDB.Get(database.Ctx, currentKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		version = current
+	}
+	if version > 0 {
+		if data, err := database.RDB.Get(database.Ctx, timelineProfileKey(userID, version)).Bytes(); err == nil {
+			var profile timelineProfile
+			if json.Unmarshal(data, &profile) == nil {
+				return &profile, nil
+			}
+		} else if !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		if fromCursor {
+			return nil, ErrTimelineCursorInvalid
+		}
+	}
+
+	articleWeights := make(map[uint]float64)
+	workWeights := make(map[uint]float64)
+	for _, source := range []struct {
+		model  interface{}
+		weight float64
+	}{
+		{&models.Favorite{}, timelineFavoriteWeight},
+		{&models.Like{}, timelineLikeWeight},
+	} {
+		var rows []struct {
+			ArticleID *uint
+			WorkID    *uint
+		}
+		if err := database.DB.Model(source.model).Select("article_id, work_id").
+			Where("user_id = ?", userID).Order("id DESC").Limit(timelineInterestLimit).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row.ArticleID != nil {
+				articleWeights[*row.ArticleID] += source.weight
+			}
+			if row.WorkID != nil {
+				workWeights[*row.WorkID] += source.weight
+			}
+		}
+	}
+
+	profile := &timelineProfile{
+		Tags:       make(map[uint]float64),
+		Categories: make(map[uint]float64),
+		WorkTypes:  make(map[string]float64),
+	}
+	if len(articleWeights) > 0 {
+		ids := make([]uint, 0, len(articleWeights))
+		for id := range articleWeights {
+			ids = append(ids, id)
+		}
+		var articles []models.Article
+		if err := database.DB.Select("id, category_id").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+			return nil, err
+		}
+		for _, article := range articles {
+			if article.CategoryID > 0 {
+				profile.Categories[article.CategoryID] += articleWeights[article.ID]
+			}
+		}
+		tags, err := articleTagIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		for articleID, tagIDs := range tags {
+			for _, tagID := range tagIDs {
+				profile.Tags[tagID] += articleWeights[articleID]
+			}
+		}
+	}
+	if len(workWeights) > 0 {
+		ids := make([]uint, 0, len(workWeights))
+		for id := range workWeights {
+			ids = append(ids, id)
+		}
+		var works []models.Work
+		if err := database.DB.Select("id, type").Where("id IN ?", ids).Find(&works).Error; err != nil {
+			return nil, err
+		}
+		for _, work := range works {
+			profile.WorkTypes[work.Type] += workWeights[work.ID]
+		}
+	}
+	normalizeWeights(profile.Tags)
+	normalizeWeights(profile.Categories)
+	normalizeWeights(profile.WorkTypes)
+
+	profile.Version = time.Now().UnixNano()
+	if data, err := json.Marshal(profile); err == nil {
+		pipe := database.RDB.TxPipeline()
+		pipe.Set(database.Ctx, timelineProfileKey(userID, profile.Version), data, timelineProfileKeep)
+		pipe.Set(database.Ctx, currentKey, profile.Version, timelineProfileTTL)
+		if _, err := pipe.Exec(database.Ctx); err != nil {
+			log.Printf("缓存用户 %d 的兴趣失败: %v", userID, err)
+		}
+	}
+	return profile, nil
+}
+
+// scoreTimeline 按兴趣匹配度计算排序分，同时去掉已删除或不再公开的内容
+func scoreTimeline(entries []*timelineEntry, profile *timelineProfile) ([]*timelineEntry, error) {
+	var articleIDs, workIDs []uint
+	for _, entry := range entries {
+		if entry.Type == models.TimelineTypeArticle {
+			articleIDs = append(articleIDs, entry.ID)
+		} else {
+			workIDs = append(workIDs, entry.ID)
+		}
+	}
+
+	articleCategories := make(map[uint]uint)
+	var articleTags map[uint][]uint
+	if len(articleIDs) > 0 {
+		var articles []models.Article
+		if err := database.DB.Select("id, category_id").
+			Where("id IN ? AND status = ?", articleIDs, models.ArticleStatusPublished).
+			Find(&articles).Error; err != nil {
+			return nil, err
+		}
+		for _, article := range articles {
+			articleCategories[article.ID] = article.CategoryID
+		}
+		var err error
+		if articleTags, err = articleTagIDs(articleIDs); err != nil {
+			return nil, err
+		}
+	}
+	workTypes := make(map[uint]string)
+	if len(workIDs) > 0 {
+		var works []models.Work
+		if err := database.DB.Select("id, type").Where("id IN ? AND status = ?", workIDs, 1).
+			Find(&works).Error; err != nil {
+			return nil, err
+		}
+		for _, work := range works {
+			workTypes[work.ID] = work.Type
+		}
+	}
+
+	scored := make([]*timelineEntry, 0, len(entries))
+	for _, entry := range entries {
+		var affinity float64
+		switch entry.Type {
+		case models.TimelineTypeArticle:
+			categoryID, ok := articleCategories[entry.ID]
+			if !ok {
+				continue
+			}
+			affinity = profile.articleAffinity(articleTags[entry.ID], categoryID)
+		default:
+			workType, ok := workTypes[entry.ID]
+			if !ok {
+				continue
+			}
+			affinity = profile.WorkTypes[workType]
+		}
+		entry.Score = entry.PublishedAt + int64(affinity*timelineAffinityBoost)
+		scored = append(scored, entry)
+	}
+	return scored, nil
+}
+
+// articleAffinity 文章与用户兴趣的匹配度，取最匹配的标签
+func (p *timelineProfile) articleAffinity(tagIDs []uint, categoryID uint) float64 {
+	var tag float64
+	for _, id := range tagIDs {
+		tag = max(tag, p.Tags[id])
+	}
+	return timelineTagWeight*tag + timelineCategoryWeight*p.Categories[categoryID]
+}
+
+// pageTimeline 按排序分倒序排列，返回游标之后的一页
+func pageTimeline(entries []*timelineEntry, after *timelineEntry, pageSize int) ([]*timelineEntry, bool) {
+	sort.Slice(entries, func(i, j int) bool { return timelineBefore(entries[i], entries[j]) })
+	start := 0
+	if after != nil {
+		start = sort.Search(len(entries), func(i int) bool { return timelineBefore(after, entries[i]) })
+	}
+	end := min(start+pageSize, len(entries))
+	return entries[start:end], end < len(entries)
+}
+
+// timelineBefore a 是否排在 b 之前；分数相同时按 ID、类型倒序，保证顺序稳定
+func timelineBefore(a, b *timelineEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.ID != b.ID {
+		return a.ID > b.ID
+	}
+	return a.Type > b.Type
+}
+
+// timelineItems 加载一页内容的详情
+func timelineItems(page []*timelineEntry) ([]*models.TimelineItem, error) {
+	var articleIDs, workIDs []uint
+	for _, entry := range page {
+		if entry.Type == models.TimelineTypeArticle {
+			articleIDs = append(articleIDs, entry.ID)
+		} else {
+			workIDs = append(workIDs, entry.ID)
+		}
+	}
+
+	articles := make(map[uint]*models.Article)
+	if len(articleIDs) > 0 {
+		var list []*models.Article
+		if err := database.DB.Where("id IN ?", articleIDs).
+			Preload("Category").Preload("Tags").Preload("Author").
+			Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, article := range list {
+			articles[article.ID] = article
+		}
+	}
+	works := make(map[uint]*models.Work)
+	if len(workIDs) > 0 {
+		var list []*models.Work
+		if err := database.DB.Where("id IN ?", workIDs).Preload("Author").Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, work := range list {
+			works[work.ID] = work
+		}
+	}
+
+	items := make([]*models.TimelineItem, 0, len(page))
+	for _, entry := range page {
+		item := &models.TimelineItem{Type: entry.Type, PublishedAt: time.Unix(entry.PublishedAt, 0)}
+		if article, ok := articles[entry.ID]; ok && entry.Type == models.TimelineTypeArticle {
+			item.Article = article.ToResponse()
+			item.Article.Content = ""
+		} else if work, ok := works[entry.ID]; ok && entry.Type == models.TimelineTypeWork {
+			item.Work = work.ToResponse()
+		} else {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// articleTagIDs 批量查询文章的标签 ID
+func articleTagIDs(articleIDs []uint) (map[uint][]uint, error) {
+	var links []struct {
+		ArticleID uint
+		TagID     uint
+	}
+	if err := database.DB.Table("article_tags").Select("article_id, tag_id").
+		Where("article_id IN ?", articleIDs).Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	tags := make(map[uint][]uint)
+	for _, link := range links {
+		tags[link.ArticleID] = append(tags[link.ArticleID], link.TagID)
+	}
+	return tags, nil
+}
+
+// normalizeWeights 按最大值归一化到 [0, 1]
+func normalizeWeights[K comparable](weights map[K]float64) {
+	var top float64
+	for _, w := range weights {
+		top = max(top, w)
+	}
+	if top == 0 {
+		return
+	}
+	for k, w := range weights {
+		weights[k] = w / top
+	}
+}
+
+func timelineProfileKey(userID uint, version int64) string {
+	return fmt.Sprintf("%s%d:%d", timelineProfilePrefix, userID, version)
+}
+
+func timelineKey(userID uint) string {
+	return fmt.Sprintf("%s%d", timelineKeyPrefix, userID)
+}
+
+func timelineMember(itemType string, id uint) string {
+	return fmt.Sprintf("%s:%d", itemType, id)
+}
+
+func parseTimelineMember(member string) (string, uint, bool) {
+	itemType, rawID, ok := strings.Cut(member, ":")
+	if !ok || (itemType != models.TimelineTypeArticle && itemType != models.TimelineTypeWork) {
+		return "", 0, false
+	}
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return itemType, uint(id), true
+}
+
+// 游标编码计算排序时使用的兴趣版本，以及最后一条内容的排序位置：分数、类型和 ID。
+// 分数包含兴趣加成，只有在同一版本的兴趣下才能与其他内容比较
+func formatTimelineCursor(version int64, entry *timelineEntry) string {
+	raw := fmt.Sprintf("%d:%d:%s", version, entry.Score, timelineMember(entry.Type, entry.ID))
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parseTimelineCursor(cursor string) (*timelineEntry, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, 0, ErrTimelineCursorInvalid
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return nil, 0, ErrTimelineCursorInvalid
+	}
+	version, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || version <= 0 {
+		return nil, 0, ErrTimelineCursorInvalid
+	}
+	score, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, 0, ErrTimelineCursorInvalid
+	}
+	itemType, id, ok := parseTimelineMember(parts[2])
+	if !ok {
+		return nil, 0, ErrTimelineCursorInvalid
+	}
+	return &timelineEntry{Type: itemType, ID: id, Score: score}, version, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestTimelineAffinity(t *testing.T) {
+	profile := &timelineProfile{
+		Tags:       map[uint]float64{1: 1, 2: 0.5},
+		Categories: map[uint]float64{3: 1},
+	}
+	if got := profile.articleAffinity([]uint{1, 2}, 3); got != 1 {
+		t.Fatalf("full match affinity = %f", got)
+	}
+	if got := profile.articleAffinity([]uint{2}, 4); got != timelineTagWeight*0.5 {
+		t.Fatalf("partial match affinity = %f", got)
+	}
+	if got := profile.articleAffinity(nil, 0); got != 0 {
+		t.Fatalf("no match affinity = %f", got)
+	}
+}
+
+func TestNormalizeWeights(t *testing.T) {
+	weights := map[string]float64{"project": 4, "photography": 1}
+	normalizeWeights(weights)
+	if weights["project"] != 1 || weights["photography"] != 0.25 {
+		t.Fatalf("normalizeWeights() = %v", weights)
+	}
+}
+
+func TestPageTimeline(t *testing.T) {
+	entries := []*timelineEntry{
+		{Type: models.TimelineTypeArticle, ID: 1, Score: 100},
+		{Type: models.TimelineTypeWork, ID: 1, Score: 300},
+		{Type: models.TimelineTypeArticle, ID: 2, Score: 300},
+		{Type: models.TimelineTypeArticle, ID: 3, Score: 200},
+		{Type: models.TimelineTypeArticle, ID: 1, Score: 300},
+	}
+
+	var got []string
+	var after *timelineEntry
+	for {
+		page, hasMore := pageTimeline(entries, after, 2)
+		for _, entry := range page {
+			got = append(got, timelineMember(entry.Type, entry.ID))
+		}
+		if !hasMore {
+			break
+		}
+		cursor, version, err := parseTimelineCursor(formatTimelineCursor(42, page[len(page)-1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != 42 {
+			t.Fatalf("cursor version = %d, want 42", version)
+		}
+		after = cursor
+	}
+
+	want := []string{"article:2", "work:1", "article:1", "article:3", "article:1"}
+	if len(got) != len(want) {
+		t.Fatalf("pages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pages = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestParseTimelineCursor(t *testing.T) {
+	// 最后一项为不带兴趣版本的旧格式游标
+	for _, cursor := range []string{"!!", "YWJj", "MTAwOnVzZXI6MQ", "MTAwOmFydGljbGU6MQ"} {
+		if _, _, err := parseTimelineCursor(cursor); !errors.Is(err, ErrTimelineCursorInvalid) {
+			t.Fatalf("parseTimelineCursor(%q) err = %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// 令牌作用域：用户端与管理后台使用不同的签名密钥，刷新令牌也分开存放
+const (
+	TokenScopeUser  = "user"
+	TokenScopeAdmin = "admin"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
+	ErrSessionRevoked      = errors.New("登录已失效，请重新登录")
+)
+
+type TokenService struct{}
+
+func NewTokenService() *TokenService { return &TokenService{} }
+
+type refreshTokenRecord struct {
+	UserID     uint   `json:"user_id"`
+	Generation int64  `json:"gen"`
+	SessionID  string `json:"sid"`
+}
+
+// StartSession 登录成功后创建会话并签发首个令牌对
+func (s *TokenService) StartSession(scope string, user *models.User, client *models.ClientInfo) (*models.TokenPair, error) {
+	session, err := NewSessionService().Create(user.ID, scope, client)
+	if err != nil {
+		return nil, err
+	}
+	return s.Issue(scope, user, session.SessionID)
+}
+
+// Issue 在指定会话内为用户签发访问令牌和刷新令牌
+func (s *TokenService) Issue(scope string, user *models.User, sessionID string) (*models.TokenPair, error) {
+	generation, err := s.generation(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessToken string
+	if scope == TokenScopeAdmin {
+		accessToken, err = utils.GenerateAdminToken(user.ID, user.Username, user.Role, generation, sessionID)
+	} else {
+		accessToken, err = utils.GenerateToken(user.ID, user.Username, user.Role, generation, sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	record, err := json.Marshal(refreshTokenRecord{UserID: user.ID, Generation: generation, SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RDB.Set(database.Ctx, refreshTokenKey(scope, refreshToken), record, utils.RefreshTokenTTL()).Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌立即作废（轮换）
+func (s *TokenService) Refresh(scope, refreshToken string) (*models.TokenPair, *models.User, error) {
+	data, err := database.RDB.GetDel(database.Ctx, refreshTokenKey(scope, refreshToken)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil, ErrRefreshTokenInvalid
+		}
+		return nil, nil, err
+	}
+	var record refreshTokenRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, nil, ErrRefreshTokenInvalid
+	}
+
+	generation, err := s.generation(record.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.Generation != generation {
+		return nil, nil, ErrSessionRevoked
+	}
+	if record.SessionID != "" {
+		if err := NewSessionService().Extend(record.SessionID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 刷新时重新读取用户，角色和状态以数据库为准
+	var user models.User
+	if err := database.DB.First(&user, record.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrSessionRevoked
+		}
+		return nil, nil, err
+	}
+	if user.Status != 1 || (scope == TokenScopeAdmin && !NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess)) {
+		return nil, nil, ErrSessionRevoked
+	}
+
+	pair, err := s.Issue(scope, &user, record.SessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, &user, nil
+}
+
+// Revoke 退出登录：作废刷新令牌，并将当前访问令牌加入黑名单直到其自然过期
+func (s *TokenService) Revoke(scope string, claims *utils.Claims, refreshToken string) error {
+	pipe := database.RDB.TxPipeline()
+	if refreshToken != "" {
+		pipe.Del(database.Ctx, refreshTokenKey(scope, refreshToken))
+	}
+	if claims != nil && claims.ID != "" && claims.ExpiresAt != nil {
+		if ttl := time.Until(claims.ExpiresAt.Time); ttl > 0 {
+			pipe.Set(database.Ctx, revokedTokenKey(claims.ID), 1, ttl)
+		}
+	}
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return err
+	}
+	if claims != nil {
+		return NewSessionService().RevokeBySessionID(claims.SessionID)
+	}
+	return nil
+}
+
+// RevokeAll 递增用户令牌代数，使该用户已签发的所有访问令牌和刷新令牌立即失效
+func (s *TokenService) RevokeAll(userID uint) error {
+	if err := database.RDB.Incr(database.Ctx, tokenGenerationKey(userID)).Err(); err != nil {
+		return err
+	}
+	return NewSessionService().MarkAllRevoked(userID)
+}
+
+// Validate 校验访问令牌是否已被吊销
+func (s *TokenService) Validate(claims *utils.Claims) error {
+	keys := []string{tokenGenerationKey(claims.UserID), revokedTokenKey(claims.ID)}
+	if claims.SessionID != "" {
+		keys = append(keys, sessionKey(claims.SessionID))
+	}
+	values, err := database.RDB.MGet(database.Ctx, keys...).Result()
+	if err != nil {
+		return err
+	}
+	// 升级前签发的令牌没有会话ID，只校验代数和黑名单
+	sessionAlive := claims.SessionID == "" || values[2] != nil
+	return checkTokenState(claims.Generation, values[0], values[1] != nil, sessionAlive)
+}
+
+func (s *TokenService) generation(userID uint) (int64, error) {
+	value, err := database.RDB.Get(database.Ctx, tokenGenerationKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return parseTokenGeneration(value)
+}
+
+// checkTokenState 比较令牌中的代数与当前代数，stored 为 Redis MGET 的原始返回值
+func checkTokenState(tokenGeneration int64, stored interface{}, revoked, sessionAlive bool) error {
+	if revoked || !sessionAlive {
+		return ErrSessionRevoked
+	}
+	current := int64(0)
+	if value, ok := stored.(string); ok {
+		parsed, err := parseTokenGeneration(value)
+		if err != nil {
+			return err
+		}
+		current = parsed
+	}
+	if tokenGeneration != current {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+func parseTokenGeneration(value string) (int64, error) {
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("令牌代数格式错误: %w", err)
+	}
+	return generation, nil
+}
+
+func generateRefreshToken() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// refreshTokenKey Redis 中只保存刷新令牌的摘要，避免明文泄露
+func refreshTokenKey(scope, refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return fmt.Sprintf("auth:refresh:%s:%s", scope, hex.EncodeToString(sum[:]))
+}
+
+func tokenGenerationKey(userID uint) string {
+	return fmt.Sprintf("auth:gen:%d", userID)
+}
+
+func revokedTokenKey(tokenID string) string {
+	return "auth:revoked:" + tokenID
+}
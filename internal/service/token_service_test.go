@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckTokenState(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		stored     interface{}
+		revoked    bool
+		ended      bool
+		want       error
+	}{
+		{name: "no counter yet", generation: 0, stored: nil},
+		{name: "current generation", generation: 3, stored: "3"},
+		{name: "generation bumped", generation: 2, stored: "3", want: ErrSessionRevoked},
+		{name: "counter reset", generation: 3, stored: nil, want: ErrSessionRevoked},
+		{name: "token blacklisted", generation: 3, stored: "3", revoked: true, want: ErrSessionRevoked},
+		{name: "session signed out", generation: 3, stored: "3", ended: true, want: ErrSessionRevoked},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkTokenState(test.generation, test.stored, test.revoked, !test.ended)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("checkTokenState() error = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckTokenStateRejectsMalformedCounter(t *testing.T) {
+	if err := checkTokenState(0, "not-a-number", false, true); err == nil {
+		t.Fatal("checkTokenState() accepted a malformed generation counter")
+	}
+}
+
+func TestRefreshTokenKeyHidesToken(t *testing.T) {
+	token, err := generateRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userKey := refreshTokenKey(TokenScopeUser, token)
+	adminKey := refreshTokenKey(TokenScopeAdmin, token)
+	if strings.Contains(userKey, token) {
+		t.Fatalf("refreshTokenKey() stores the raw token: %s", userKey)
+	}
+	if userKey == adminKey {
+		t.Fatalf("user and admin refresh tokens share key %s", userKey)
+	}
+}
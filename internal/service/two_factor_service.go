@@ -0,0 +1,452 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const (
+	totpIssuer          = "InkSpace"
+	totpSkew            = 1 // 允许前后各一个时间步（±30秒）的时钟偏差
+	recoveryCodeCount   = 10
+	loginChallengeTTL   = 5 * time.Minute
+	loginChallengeLimit = 5 // 单个登录挑战最多尝试次数
+)
+
+var (
+	ErrTwoFactorCodeInvalid      = errors.New("验证码错误")
+	ErrTwoFactorChallengeInvalid = errors.New("登录验证已过期，请重新登录")
+	ErrTwoFactorNotEnabled       = errors.New("未开启两步验证")
+	ErrTwoFactorAlreadyEnabled   = errors.New("已开启两步验证")
+	ErrTwoFactorSetupMissing     = errors.New("请先获取验证器密钥")
+	ErrTwoFactorRequired         = errors.New("管理员账号必须开启两步验证")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService struct {
+	now func() time.Time // 测试中可替换为固定时钟
+}
+
+func NewTwoFactorService() *TwoFactorService {
+	return &TwoFactorService{now: time.Now}
+}
+
+type loginChallengeRecord struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"` // 验证码错误按用户名计入登录失败次数
+	Scope    string `json:"scope"`
+	Setup    bool   `json:"setup"`
+}
+
+// twoFactorRequired 能登录管理后台的账号必须开启两步验证
+func twoFactorRequired(user *models.User) bool {
+	return NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess)
+}
+
+// BeginLogin 密码校验通过后调用：未开启两步验证的普通用户直接签发令牌，否则返回登录挑战
+func (s *TwoFactorService) BeginLogin(scope string, user *models.User, client *models.ClientInfo) (*models.TokenPair, *models.TwoFactorChallenge, error) {
+	record, err := s.find(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	enabled := record != nil && record.Enabled
+	if !enabled && !twoFactorRequired(user) {
+		tokens, err := NewTokenService().StartSession(scope, user, client)
+		if err != nil {
+			return nil, nil, err
+		}
+		NewLoginGuardService().Succeed(user.Username)
+		return tokens, nil, nil
+	}
+
+	challengeToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(loginChallengeRecord{UserID: user.ID, Username: user.Username, Scope: scope, Setup: !enabled})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := database.RDB.Set(database.Ctx, loginChallengeKey(challengeToken), data, loginChallengeTTL).Err(); err != nil {
+		return nil, nil, err
+	}
+	return nil, &models.TwoFactorChallenge{
+		ChallengeToken: challengeToken,
+		SetupRequired:  !enabled,
+		ExpiresIn:      int64(loginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteLogin 使用验证码或恢复码完成登录挑战
+// 验证码错误计入该用户名的登录失败次数，达到阈值后退避或锁定，与密码错误共用限制
+func (s *TwoFactorService) CompleteLogin(scope, challengeToken, code string, client *models.ClientInfo) (*models.TokenPair, *models.User, error) {
+	challenge, err := s.challenge(scope, challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge.Setup {
+		return nil, nil, ErrTwoFactorRequired
+	}
+	if err := NewLoginGuardService().Blocked(challenge.Username); err != nil {
+		return nil, nil, err
+	}
+	if err := s.Verify(challenge.UserID, code); err != nil {
+		if errors.Is(err, ErrTwoFactorCodeInvalid) {
+			s.failChallenge(challengeToken, challenge, client.IP)
+		}
+		return nil, nil, err
+	}
+	return s.finishChallenge(scope, challengeToken, challenge.UserID, client)
+}
+
+// SetupForChallenge 尚未绑定验证器的管理员在登录过程中获取密钥
+func (s *TwoFactorService) SetupForChallenge(scope, challengeToken string) (*models.TwoFactorSetupResponse, error) {
+	challenge, err := s.challenge(scope, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Setup {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	user, err := loadActiveUser(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Setup(user)
+}
+
+// EnableForChallenge 登录过程中完成绑定并签发令牌，同时返回恢复码
+func (s *TwoFactorService) EnableForChallenge(scope, challengeToken, code string, client *models.ClientInfo) (*models.TokenPair, *models.User, []string, error) {
+	challenge, err := s.challenge(scope, challengeToken)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !challenge.Setup {
+		return nil, nil, nil, ErrTwoFactorAlreadyEnabled
+	}
+	if err := NewLoginGuardService().Blocked(challenge.Username); err != nil {
+		return nil, nil, nil, err
+	}
+	recoveryCodes, err := s.Enable(challenge.UserID, code)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorCodeInvalid) {
+			s.failChallenge(challengeToken, challenge, client.IP)
+		}
+		return nil, nil, nil, err
+	}
+	tokens, user, err := s.finishChallenge(scope, challengeToken, challenge.UserID, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tokens, user, recoveryCodes, nil
+}
+
+// Status 两步验证状态
+func (s *TwoFactorService) Status(user *models.User) (*models.TwoFactorStatusResponse, error) {
+	status := &models.TwoFactorStatusResponse{Required: twoFactorRequired(user)}
+	record, err := s.find(user.ID)
+	if err != nil || record == nil || !record.Enabled {
+		return status, err
+	}
+	status.Enabled = true
+	status.EnabledAt = record.EnabledAt
+	err = database.DB.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", user.ID).Count(&status.RecoveryCodesRemaining).Error
+	return status, err
+}
+
+// Setup 生成新的验证器密钥，需调用 Enable 校验一次验证码后才会生效
+func (s *TwoFactorService) Setup(user *models.User) (*models.TwoFactorSetupResponse, error) {
+	record, err := s.find(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		record = &models.UserTwoFactor{UserID: user.ID}
+	}
+	record.Secret = secret
+	record.LastUsedStep = 0
+	if err := database.DB.Save(record).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    utils.TOTPProvisioningURI(totpIssuer, user.Username, secret),
+	}, nil
+}
+
+// Enable 校验验证码后开启两步验证，返回一次性展示的恢复码
+func (s *TwoFactorService) Enable(userID uint, code string) ([]string, error) {
+	record, err := s.find(userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrTwoFactorSetupMissing
+	}
+	if record.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, err := s.matchTOTP(record.Secret, code, record.LastUsedStep)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(record).Updates(map[string]interface{}{
+			"enabled":        true,
+			"enabled_at":     now,
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, recoveryCodes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Disable 关闭两步验证，需要同时提供密码和验证码；管理员不允许关闭
+func (s *TwoFactorService) Disable(user *models.User, password, code string) error {
+	if twoFactorRequired(user) {
+		return ErrTwoFactorRequired
+	}
+	if !utils.CheckPassword(password, user.Password) {
+		return errors.New("密码错误")
+	}
+	if err := s.Verify(user.ID, code); err != nil {
+		return err
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.UserRecoveryCode{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes 使用验证器验证码重新生成恢复码，旧恢复码全部作废
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	record, err := s.find(userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || !record.Enabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err := s.verifyTOTP(record, code); err != nil {
+		return nil, err
+	}
+	recoveryCodes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryCodes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Verify 校验验证器验证码或恢复码
+func (s *TwoFactorService) Verify(userID uint, code string) error {
+	record, err := s.find(userID)
+	if err != nil {
+		return err
+	}
+	if record == nil || !record.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == utils.TOTPDigits {
+		return s.verifyTOTP(record, code)
+	}
+
+	result := database.DB.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRecoveryCode(code)).
+		Update("used_at", s.now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+// verifyTOTP 校验并记录时间步，条件更新保证同一验证码并发提交时只有一次成功
+func (s *TwoFactorService) verifyTOTP(record *models.UserTwoFactor, code string) error {
+	step, err := s.matchTOTP(record.Secret, code, record.LastUsedStep)
+	if err != nil {
+		return err
+	}
+	result := database.DB.Model(&models.UserTwoFactor{}).
+		Where("id = ? AND last_used_step < ?", record.ID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+// matchTOTP 按服务时钟校验验证码，已使用过的时间步视为无效
+func (s *TwoFactorService) matchTOTP(secret, code string, lastUsedStep int64) (int64, error) {
+	step, ok := utils.ValidateTOTP(secret, strings.TrimSpace(code), s.now(), totpSkew)
+	if !ok || step <= lastUsedStep {
+		return 0, ErrTwoFactorCodeInvalid
+	}
+	return step, nil
+}
+
+func (s *TwoFactorService) find(userID uint) (*models.UserTwoFactor, error) {
+	var record models.UserTwoFactor
+	if err := database.DB.Where("user_id = ?", userID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *TwoFactorService) challenge(scope, challengeToken string) (*loginChallengeRecord, error) {
+	data, err := database.RDB.Get(database.Ctx, loginChallengeKey(challengeToken)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrTwoFactorChallengeInvalid
+		}
+		return nil, err
+	}
+	var record loginChallengeRecord
+	if err := json.Unmarshal(data, &record); err != nil || record.Scope != scope || record.Username == "" {
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+	return &record, nil
+}
+
+// failChallenge 记录一次失败尝试，超过次数后作废挑战，需要重新输入密码；
+// 同时计入用户名的登录失败次数，避免反复获取新挑战无限尝试验证码
+func (s *TwoFactorService) failChallenge(challengeToken string, challenge *loginChallengeRecord, ip string) {
+	NewLoginGuardService().Fail(challenge.Username, ip)
+	key := loginChallengeKey(challengeToken) + ":attempts"
+	attempts, err := database.RDB.Incr(database.Ctx, key).Result()
+	if err != nil {
+		return
+	}
+	database.RDB.Expire(database.Ctx, key, loginChallengeTTL)
+	if attempts >= loginChallengeLimit {
+		database.RDB.Del(database.Ctx, loginChallengeKey(challengeToken), key)
+	}
+}
+
+func (s *TwoFactorService) finishChallenge(scope, challengeToken string, userID uint, client *models.ClientInfo) (*models.TokenPair, *models.User, error) {
+	// 先删除挑战，保证同一挑战只能换取一次令牌
+	deleted, err := database.RDB.Del(database.Ctx, loginChallengeKey(challengeToken)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	if deleted == 0 {
+		return nil, nil, ErrTwoFactorChallengeInvalid
+	}
+	user, err := loadActiveUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scope == TokenScopeAdmin && !NewRoleService().HasPermission(user.Role, models.PermissionAdminAccess) {
+		return nil, nil, ErrTwoFactorChallengeInvalid
+	}
+	tokens, err := NewTokenService().StartSession(scope, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	NewLoginGuardService().Succeed(user.Username)
+	return tokens, user, nil
+}
+
+// loadActiveUser 登录过程中重新读取用户，账号被删除或禁用时中止登录
+func loadActiveUser(userID uint) (*models.User, error) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorChallengeInvalid
+		}
+		return nil, err
+	}
+	if user.Status != 1 {
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+	return &user, nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, recoveryCodes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	records := make([]models.UserRecoveryCode, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		records[i] = models.UserRecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)}
+	}
+	return tx.Create(&records).Error
+}
+
+// generateRecoveryCodes 生成 xxxxx-xxxxx 形式的恢复码（50 位熵）
+func generateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, count)
+	for i := range codes {
+		data := make([]byte, 7)
+		if _, err := rand.Read(data); err != nil {
+			return nil, err
+		}
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(data))[:10]
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 忽略大小写、空格和连字符，用户手动输入时更宽松
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func loginChallengeKey(challengeToken string) string {
+	sum := sha256.Sum256([]byte(challengeToken))
+	return "auth:2fa:challenge:" + hex.EncodeToString(sum[:])
+}
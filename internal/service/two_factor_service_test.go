@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/internal/utils"
+)
+
+func fixedClockTwoFactorService(now time.Time) *TwoFactorService {
+	return &TwoFactorService{now: func() time.Time { return now }}
+}
+
+func TestMatchTOTPWithFixedClock(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 10, 0, time.UTC)
+	s := fixedClockTwoFactorService(now)
+	code, err := utils.TOTPCode(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, err := s.matchTOTP(secret, code, 0)
+	if err != nil {
+		t.Fatalf("matchTOTP() error = %v", err)
+	}
+	if step != utils.TOTPStep(now) {
+		t.Fatalf("matchTOTP() step = %d, want %d", step, utils.TOTPStep(now))
+	}
+
+	// 同一时间步的验证码不能重复使用
+	if _, err := s.matchTOTP(secret, code, step); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("matchTOTP() replay error = %v, want %v", err, ErrTwoFactorCodeInvalid)
+	}
+
+	// 两分钟后旧验证码失效
+	later := fixedClockTwoFactorService(now.Add(2 * time.Minute))
+	if _, err := later.matchTOTP(secret, code, 0); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("matchTOTP() expired error = %v, want %v", err, ErrTwoFactorCodeInvalid)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("generateRecoveryCodes() produced %q, want xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Fatalf("generateRecoveryCodes() produced duplicate %q", code)
+		}
+		seen[code] = true
+	}
+
+	code := codes[0]
+	typed := strings.ToUpper(strings.ReplaceAll(code, "-", " "))
+	if hashRecoveryCode(code) != hashRecoveryCode(typed) {
+		t.Fatalf("hashRecoveryCode() differs for %q and %q", code, typed)
+	}
+}
+
+func TestTwoFactorRequiredForAdmins(t *testing.T) {
+	setRolePermissions(t, map[string][]string{models.RoleEditor: {models.PermissionAdminAccess}})
+
+	if !twoFactorRequired(&models.User{Role: models.RoleAdmin}) {
+		t.Fatal("twoFactorRequired() = false for admin")
+	}
+	if !twoFactorRequired(&models.User{Role: models.RoleEditor}) {
+		t.Fatal("twoFactorRequired() = false for editor with admin access")
+	}
+	if twoFactorRequired(&models.User{Role: models.RoleUser}) {
+		t.Fatal("twoFactorRequired() = true for regular user")
+	}
+}
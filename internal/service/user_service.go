@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"log"
 
@@ -11,6 +12,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrInvalidCredentials = errors.New("用户名或密码错误")
+
 type UserService struct{}
 
 func NewUserService() *UserService {
@@ -71,32 +74,55 @@ func (s *UserService) Register(req *models.UserRegisterRequest) (*models.User, e
 		return nil, err
 	}
 
+	NewSearchService().Sync(models.SearchTypeUser, user.ID)
+	sendVerificationAsync(user)
+
 	return user, nil
 }
 
-func (s *UserService) Login(req *models.UserLoginRequest) (string, *models.User, error) {
+// Authenticate 校验用户名和密码，不签发令牌（供用户端和管理后台登录复用）
+// 失败次数受 LoginGuardService 限制，锁定期间直接拒绝，不再校验密码
+func (s *UserService) Authenticate(ctx context.Context, req *models.UserLoginRequest, ip string) (*models.User, error) {
+	guard := NewLoginGuardService()
+	if err := guard.Check(ctx, req.Username, ip, req.CaptchaToken); err != nil {
+		return nil, err
+	}
+
 	var user models.User
 	if err := database.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil, errors.New("用户名或密码错误")
+			guard.Fail(req.Username, ip)
+			return nil, ErrInvalidCredentials
 		}
-		return "", nil, err
+		return nil, err
 	}
 
 	if !utils.CheckPassword(req.Password, user.Password) {
-		return "", nil, errors.New("用户名或密码错误")
+		guard.Fail(req.Username, ip)
+		return nil, ErrInvalidCredentials
 	}
+	// 失败记录在签发令牌时才清除（见 TwoFactorService），开启两步验证的账号需要通过验证码后才算登录成功
 
 	if user.Status != 1 {
-		return "", nil, errors.New("账号已被禁用")
+		return nil, errors.New("账号已被禁用")
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Role)
+	return &user, nil
+}
+
+// Login 用户端登录，开启两步验证的账号返回登录挑战而不是令牌
+func (s *UserService) Login(ctx context.Context, req *models.UserLoginRequest, client *models.ClientInfo) (*models.TokenPair, *models.TwoFactorChallenge, *models.User, error) {
+	user, err := s.Authenticate(ctx, req, client.IP)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tokens, challenge, err := NewTwoFactorService().BeginLogin(TokenScopeUser, user, client)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, nil, err
 	}
 
-	return token, &user, nil
+	return tokens, challenge, user, nil
 }
 
 func (s *UserService) GetUserByID(id uint) (*models.User, error) {
@@ -114,14 +140,25 @@ func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 }
 
 func (s *UserService) UpdateUser(id uint, req *models.UserUpdateRequest) (*models.User, error) {
+	var current models.User
+	if err := database.DB.Select("id", "email").First(&current, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("用户不存在")
+		}
+		return nil, err
+	}
+
 	// 使用WHERE条件更新，确保只能更新自己的信息
 	updateData := make(map[string]interface{})
 
 	if req.Nickname != "" {
 		updateData["nickname"] = req.Nickname
 	}
-	if req.Email != "" {
+	emailChanged := req.Email != "" && req.Email != current.Email
+	if emailChanged {
+		// 更换邮箱后需要重新验证
 		updateData["email"] = req.Email
+		updateData["email_verified_at"] = nil
 	}
 	if req.Bio != "" {
 		updateData["bio"] = req.Bio
@@ -147,9 +184,17 @@ func (s *UserService) UpdateUser(id uint, req *models.UserUpdateRequest) (*model
 	if result.RowsAffected == 0 {
 		return nil, errors.New("用户不存在")
 	}
+	NewSearchService().Sync(models.SearchTypeUser, id)
 
 	// 重新加载用户信息
-	return s.GetUserByID(id)
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if emailChanged {
+		sendVerificationAsync(user)
+	}
+	return user, nil
 }
 
 func (s *UserService) GetUserList(query *models.UserListQuery) ([]*models.User, int64, error) {
@@ -313,46 +358,88 @@ func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword strin
 		return err
 	}
 
-	return nil
+	// 密码修改后注销该用户所有已登录的会话
+	return NewTokenService().RevokeAll(user.ID)
 }
 
-// UpdateUserStatus 更新用户状态
-func (s *UserService) UpdateUserStatus(userID uint, status int) error {
+// UpdateUserStatus 更新用户状态；不能修改自己、后台账号或权限超出操作者的账号
+func (s *UserService) UpdateUserStatus(actorID uint, actorRole string, userID uint, status int) error {
+	var user models.User
+	if err := database.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		return err
+	}
+	if err := NewRoleService().CheckUserTarget(actorID, actorRole, user.ID, user.Role); err != nil {
+		return err
+	}
+
 	if err := database.DB.Model(&models.User{}).
 		Where("id = ?", userID).
 		Update("status", status).Error; err != nil {
 		return err
 	}
+	NewSearchService().Sync(models.SearchTypeUser, userID)
 
-	return nil
+	// 状态变更（尤其是禁用）后立即注销该用户的所有会话
+	return NewTokenService().RevokeAll(userID)
 }
 
-// UpdateUserRole 更新用户角色
-func (s *UserService) UpdateUserRole(userID uint, role string) error {
-	if err := database.DB.Model(&models.User{}).
-		Where("id = ?", userID).
-		Update("role", role).Error; err != nil {
+// UpdateUserRole 更新用户角色；操作者只能在自身权限范围内调整角色，且不能修改自己的角色
+func (s *UserService) UpdateUserRole(actorID uint, actorRole string, userID uint, role string) error {
+	if actorID == userID {
+		return ErrRoleSelf
+	}
+	roleService := NewRoleService()
+	exists, err := roleService.Exists(role)
+	if err != nil {
 		return err
 	}
+	if !exists {
+		return ErrRoleNotFound
+	}
 
-	return nil
+	var user models.User
+	if err := database.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Role == role {
+		return nil
+	}
+	if !roleService.HasAllPermissions(actorRole, roleService.Permissions(role)) ||
+		!roleService.HasAllPermissions(actorRole, roleService.Permissions(user.Role)) {
+		return ErrRoleEscalation
+	}
+	if user.Role == models.RoleAdmin {
+		var admins int64
+		if err := database.DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&admins).Error; err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	if err := database.DB.Model(&user).Update("role", role).Error; err != nil {
+		return err
+	}
+
+	// 角色变更后旧令牌中的角色已过时，强制重新登录
+	return NewTokenService().RevokeAll(userID)
 }
 
-// DeleteUser 删除用户
-func (s *UserService) DeleteUser(userID uint) error {
+// DeleteUser 删除用户；与修改状态相同，不能删除自己、后台账号（需先调整为普通角色）或权限超出操作者的账号
+func (s *UserService) DeleteUser(actorID uint, actorRole string, userID uint) error {
 	user, err := s.GetUserByID(userID)
 	if err != nil {
 		return err
 	}
-
-	// 不允许删除管理员
-	if user.Role == "admin" {
-		return errors.New("不能删除管理员账号")
+	if err := NewRoleService().CheckUserTarget(actorID, actorRole, user.ID, user.Role); err != nil {
+		return err
 	}
 
 	if err := database.DB.Delete(user).Error; err != nil {
 		return err
 	}
+	NewSearchService().Sync(models.SearchTypeUser, user.ID)
 
-	return nil
+	return NewTokenService().RevokeAll(user.ID)
 }
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	viewDedupWindow  = 30 * time.Minute // 同一访客在窗口内重复访问只计一次
+	viewSeenPrefix   = "view:seen:"
+	viewPendingKey   = "view:pending"  // 待写入的浏览量，field 为 类型:ID:日期
+	viewFlushingKey  = "view:flushing" // 正在写入的批次，写入失败时保留到下次重试
+	viewBatchField   = "batch"         // 批次 ID，与计数保存在同一个 hash 中
+	viewBatchKeep    = 7 * 24 * time.Hour
+	viewDayLayout    = "20060102"
+	viewTrendDays    = 30
+	viewTrendMaxDays = 90
+)
+
+var (
+	ErrViewTargetInvalid  = errors.New("不支持的浏览统计类型")
+	ErrViewTargetNotFound = errors.New("内容不存在或无权限查看")
+
+	errViewBatchApplied = errors.New("浏览量批次已写入")
+)
+
+// ViewService 浏览量统计：Redis 去重并缓冲计数，由定时任务批量写入数据库并按天保存历史
+type ViewService struct{}
+
+func NewViewService() *ViewService {
+	return &ViewService{}
+}
+
+// viewDelta 一个内容在某一天新增的浏览量
+type viewDelta struct {
+	TargetType string
+	TargetID   uint
+	Date       time.Time
+	Count      int
+}
+
+// Record 记录一次浏览，返回是否计入浏览量。
+// 登录用户按用户去重，游客按 IP + User-Agent 去重；Redis 不可用时直接写入数据库
+func (s *ViewService) Record(targetType string, targetID, userID uint, ip, userAgent string) (bool, error) {
+	if _, err := viewTargetModel(targetType); err != nil {
+		return false, err
+	}
+	now := time.Now()
+	seenKey := fmt.Sprintf("%s%s:%d:%s", viewSeenPrefix, targetType, targetID, viewerKey(userID, ip, userAgent))
+	first, err := database.RDB.SetNX(database.Ctx, seenKey, 1, viewDedupWindow).Result()
+	if err == nil {
+		if !first {
+			return false, nil
+		}
+		err = database.RDB.HIncrBy(database.Ctx, viewPendingKey, viewField(targetType, targetID, now), 1).Err()
+		if err == nil {
+			return true, nil
+		}
+	}
+	log.Printf("记录浏览量到 Redis 失败，直接写入数据库: %v", err)
+	if err := applyViewDeltas("", []viewDelta{{TargetType: targetType, TargetID: targetID, Date: viewDate(now), Count: 1}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Flush 将 Redis 中缓冲的浏览量批量写入数据库，返回写入的浏览次数。
+// 先把待写入的计数改名为独立批次，写入期间的新浏览继续累计到新的缓冲中。
+// 批次 ID 与浏览量在同一事务中写入数据库，删除批次失败后重试时不会重复累加
+func (s *ViewService) Flush() (int, error) {
+	exists, err := database.RDB.Exists(database.Ctx, viewFlushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		if err := database.RDB.Rename(database.Ctx, viewPendingKey, viewFlushingKey).Err(); err != nil {
+			if strings.Contains(err.Error(), "no such key") {
+				return 0, nil
+			}
+			return 0, err
+		}
+	}
+	if err := database.RDB.HSetNX(database.Ctx, viewFlushingKey, viewBatchField, uuid.NewString()).Err(); err != nil {
+		return 0, err
+	}
+
+	counts, err := database.RDB.HGetAll(database.Ctx, viewFlushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	batchID := counts[viewBatchField]
+	if batchID == "" {
+		return 0, errors.New("浏览量批次缺少 ID")
+	}
+	deltas := make([]viewDelta, 0, len(counts))
+	total := 0
+	for field, value := range counts {
+		if field == viewBatchField {
+			continue
+		}
+		delta, ok := parseViewField(field)
+		count, err := strconv.Atoi(value)
+		if !ok || err != nil || count <= 0 {
+			log.Printf("跳过无效的浏览量缓冲 %s=%s", field, value)
+			continue
+		}
+		delta.Count = count
+		deltas = append(deltas, delta)
+		total += count
+	}
+	if err := applyViewDeltas(batchID, deltas); err != nil {
+		if !errors.Is(err, errViewBatchApplied) {
+			return 0, err
+		}
+		log.Printf("浏览量批次 %s 已写入，跳过", batchID)
+		total = 0
+	}
+	if err := database.RDB.Del(database.Ctx, viewFlushingKey).Err(); err != nil {
+		return total, err
+	}
+	// 批次记录只用于识别重试，保留一段时间后清理
+	if err := database.DB.Where("created_at < ?", time.Now().Add(-viewBatchKeep)).
+		Delete(&models.ViewFlushBatch{}).Error; err != nil {
+		log.Printf("清理浏览量批次记录失败: %v", err)
+	}
+	return total, nil
+}
+
+// Trend 内容最近 days 天的浏览趋势，只有作者或有对应管理权限的用户可以查看
+func (s *ViewService) Trend(targetType string, targetID, userID uint, role string, days int) (*models.ViewTrendResponse, error) {
+	model, err := viewTargetModel(targetType)
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		days = viewTrendDays
+	}
+	if days > viewTrendMaxDays {
+		days = viewTrendMaxDays
+	}
+
+	var target struct {
+		AuthorID  uint
+		ViewCount int
+	}
+	if err := database.DB.Model(model).Select("author_id, view_count").Where("id = ?", targetID).Take(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrViewTargetNotFound
+		}
+		return nil, err
+	}
+	permission := models.PermissionArticleManage
+	if targetType == models.ViewTargetWork {
+		permission = models.PermissionWorkManage
+	}
+	if target.AuthorID != userID && !NewRoleService().HasPermission(role, permission) {
+		return nil, ErrViewTargetNotFound
+	}
+
+	today := viewDate(time.Now())
+	start := today.AddDate(0, 0, -(days - 1))
+	var stats []models.ViewStat
+	if err := database.DB.Where("target_type = ? AND target_id = ? AND date >= ?", targetType, targetID, start).
+		Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]int, len(stats))
+	for _, stat := range stats {
+		byDate[stat.Date.Format("2006-01-02")] += stat.Views
+	}
+
+	resp := &models.ViewTrendResponse{TargetType: targetType, TargetID: targetID, ViewCount: target.ViewCount}
+	// 加上尚未写入数据库的缓冲计数，趋势图可以看到当天的实时数据
+	fields := make([]string, days)
+	for i := range fields {
+		fields[i] = viewField(targetType, targetID, start.AddDate(0, 0, i))
+	}
+	for _, key := range []string{viewPendingKey, viewFlushingKey} {
+		values, err := database.RDB.HMGet(database.Ctx, key, fields...).Result()
+		if err != nil {
+			log.Printf("读取浏览量缓冲失败: %v", err)
+			break
+		}
+		for i, value := range values {
+			str, _ := value.(string)
+			if count, _ := strconv.Atoi(str); count > 0 {
+				byDate[start.AddDate(0, 0, i).Format("2006-01-02")] += count
+				resp.ViewCount += count
+			}
+		}
+	}
+
+	resp.Points = make([]models.ViewTrendPoint, days)
+	for i := range resp.Points {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		resp.Points[i] = models.ViewTrendPoint{Date: date, Views: byDate[date]}
+		resp.Total += byDate[date]
+	}
+	return resp, nil
+}
+
+// applyViewDeltas 在同一事务中累加内容的浏览量和每日统计，失败时整批回滚；成功后标记内容等待重新计算热度。
+// batchID 不为空时同时记录批次，批次已记录过则不再写入并返回 errViewBatchApplied
+func applyViewDeltas(batchID string, deltas []viewDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	type target struct {
+		Type string
+		ID   uint
+	}
+	totals := make(map[target]int)
+	for _, delta := range deltas {
+		totals[target{delta.TargetType, delta.TargetID}] += delta.Count
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if batchID != "" {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ViewFlushBatch{ID: batchID})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errViewBatchApplied
+			}
+		}
+		for t, count := range totals {
+			model, err := viewTargetModel(t.Type)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(model).Where("id = ?", t.ID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error; err != nil {
+				return err
+			}
+		}
+		for _, delta := range deltas {
+			stat := models.ViewStat{TargetType: delta.TargetType, TargetID: delta.TargetID, Date: delta.Date, Views: delta.Count}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "target_type"}, {Name: "target_id"}, {Name: "date"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"views":      gorm.Expr("views + ?", delta.Count),
+					"updated_at": time.Now(),
+				}),
+			}).Create(&stat).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ids := make(map[string][]uint)
+	for t := range totals {
+		ids[t.Type] = append(ids[t.Type], t.ID)
+	}
+	for targetType, targetIDs := range ids {
+		markHot(targetType, targetIDs...)
+	}
+	return nil
+}
+
+func viewTargetModel(targetType string) (interface{}, error) {
+	switch targetType {
+	case models.ViewTargetArticle:
+		return &models.Article{}, nil
+	case models.ViewTargetWork:
+		return &models.Work{}, nil
+	}
+	return nil, ErrViewTargetInvalid
+}
+
+// viewerKey 访客标识，游客的 IP 和 User-Agent 取摘要，避免在 Redis 中保存原文
+func viewerKey(userID uint, ip, userAgent string) string {
+	if userID > 0 {
+		return "u" + strconv.FormatUint(uint64(userID), 10)
+	}
+	sum := sha1.Sum([]byte(ip + "|" + userAgent))
+	return "g" + hex.EncodeToString(sum[:10])
+}
+
+func viewField(targetType string, targetID uint, t time.Time) string {
+	return fmt.Sprintf("%s:%d:%s", targetType, targetID, t.Format(viewDayLayout))
+}
+
+func parseViewField(field string) (viewDelta, bool) {
+	parts := strings.Split(field, ":")
+	if len(parts) != 3 {
+		return viewDelta{}, false
+	}
+	if _, err := viewTargetModel(parts[0]); err != nil {
+		return viewDelta{}, false
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil || id == 0 {
+		return viewDelta{}, false
+	}
+	date, err := time.ParseInLocation(viewDayLayout, parts[2], time.Local)
+	if err != nil {
+		return viewDelta{}, false
+	}
+	return viewDelta{TargetType: parts[0], TargetID: uint(id), Date: date}, true
+}
+
+// viewDate 当天零点，每日统计按服务器时区划分
+func viewDate(t time.Time) time.Time {
+	y, m, d := t.In(time.Local).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
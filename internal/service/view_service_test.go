@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/models"
+)
+
+func TestViewFieldRoundTrip(t *testing.T) {
+	day := time.Date(2024, 2, 29, 23, 59, 0, 0, time.Local)
+	field := viewField(models.ViewTargetWork, 42, day)
+	if field != "work:42:20240229" {
+		t.Fatalf("viewField() = %q", field)
+	}
+	delta, ok := parseViewField(field)
+	if !ok || delta.TargetType != models.ViewTargetWork || delta.TargetID != 42 || !delta.Date.Equal(viewDate(day)) {
+		t.Fatalf("parseViewField(%q) = %+v, %v", field, delta, ok)
+	}
+
+	for _, invalid := range []string{"", "article:1", "doc:1:20240101", "article:0:20240101", "article:x:20240101", "article:1:2024-01-01"} {
+		if _, ok := parseViewField(invalid); ok {
+			t.Errorf("parseViewField(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestViewerKey(t *testing.T) {
+	if got := viewerKey(7, "1.2.3.4", "ua"); got != "u7" {
+		t.Fatalf("logged-in viewer = %q", got)
+	}
+	guest := viewerKey(0, "1.2.3.4", "Mozilla/5.0")
+	if guest != viewerKey(0, "1.2.3.4", "Mozilla/5.0") || len(guest) != 21 {
+		t.Fatalf("guest viewer key is not stable: %q", guest)
+	}
+	if guest == viewerKey(0, "1.2.3.4", "curl/8.0") || guest == viewerKey(0, "5.6.7.8", "Mozilla/5.0") {
+		t.Fatal("different IP or user agent should produce different viewers")
+	}
+}
+
+func TestViewDate(t *testing.T) {
+	got := viewDate(time.Date(2024, 3, 1, 15, 4, 5, 6, time.Local))
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Fatalf("viewDate() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,725 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/database"
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/wxr"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrWXRInvalid              = errors.New("无法解析 WordPress 导出文件")
+	ErrWordPressAuthorNotFound = errors.New("指定的作者映射用户不存在")
+	ErrWordPressAuthorConflict = errors.New("作者与本站已有用户的邮箱或用户名相同，请先试运行并在作者映射中指定")
+
+	// errWordPressDryRun 试运行结束后回滚事务
+	errWordPressDryRun = errors.New("wordpress dry run")
+
+	// 块编辑器写入正文的注释，如 <!-- wp:paragraph -->
+	wpBlockComment = regexp.MustCompile(`<!--\s*/?wp:[^>]*-->\n?`)
+)
+
+// WordPressImportService 导入 WordPress 导出的 WXR 文件：文章、评论（保留回复层级）、分类和标签。
+// 作者只按指定映射或之前的导入记录对应本站用户，没有时创建占位账号；
+// 与本站用户邮箱或用户名相同的作者不会自动绑定，试运行报告冲突，正式导入时需在映射中指定；
+// 导入过的作者、文章和评论记录在 import_records 中，重复导入同一站点时跳过
+type WordPressImportService struct{}
+
+func NewWordPressImportService() *WordPressImportService {
+	return &WordPressImportService{}
+}
+
+// Import 在一个事务中完成导入，任何一步失败都不会留下部分数据。
+// 试运行时执行同样的流程后回滚，报告中的数量即实际导入时将要创建的数量
+func (s *WordPressImportService) Import(r io.Reader, opts *models.WordPressImportOptions) (*models.WordPressImportReport, error) {
+	export, err := wxr.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWXRInvalid, err)
+	}
+	if opts == nil {
+		opts = &models.WordPressImportOptions{}
+	}
+
+	report := &models.WordPressImportReport{DryRun: opts.DryRun, Site: truncateRunes(export.Site(), 191)}
+	var importer *wordPressImporter
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		importer = &wordPressImporter{
+			tx:         tx,
+			export:     export,
+			opts:       opts,
+			report:     report,
+			authors:    make(map[string]uint),
+			authorIDs:  make(map[int64]uint),
+			categories: make(map[string]uint),
+			tags:       make(map[string]models.Tag),
+		}
+		if err := importer.run(); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return errWordPressDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errWordPressDryRun) {
+		return nil, err
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, id := range importer.articleIDs {
+		NewSearchService().Sync(models.SearchTypeArticle, id)
+	}
+	for _, id := range importer.userIDs {
+		NewSearchService().Sync(models.SearchTypeUser, id)
+	}
+	if len(importer.articleIDs) > 0 || report.Comments.Created > 0 {
+		if err := database.DeleteCachePattern("article:*"); err != nil {
+			log.Printf("导入 WordPress 后清理缓存失败: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// ParseWordPressAuthorMap 解析作者映射，格式为 wp登录名=本站用户名，多个用逗号分隔
+func ParseWordPressAuthorMap(value string) (map[string]string, error) {
+	authorMap := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		login, username, ok := strings.Cut(pair, "=")
+		login, username = strings.TrimSpace(login), strings.TrimSpace(username)
+		if !ok || login == "" || username == "" {
+			return nil, fmt.Errorf("作者映射格式错误: %s，应为 wp登录名=本站用户名", pair)
+		}
+		authorMap[login] = username
+	}
+	return authorMap, nil
+}
+
+// wordPressImporter 一次导入过程中的状态
+type wordPressImporter struct {
+	tx     *gorm.DB
+	export *wxr.Export
+	opts   *models.WordPressImportOptions
+	report *models.WordPressImportReport
+
+	authors    map[string]uint // WordPress 登录名 -> 用户ID
+	authorIDs  map[int64]uint  // WordPress 用户ID -> 用户ID，用于作者本人发表的评论
+	categories map[string]uint // 分类 nicename -> 分类ID
+	tags       map[string]models.Tag
+
+	articleIDs []uint // 新建的文章，提交后同步搜索索引
+	userIDs    []uint // 新建的占位账号
+}
+
+func (im *wordPressImporter) run() error {
+	if err := im.importAuthors(); err != nil {
+		return err
+	}
+	if err := im.importCategories(); err != nil {
+		return err
+	}
+
+	ignored := 0
+	for i := range im.export.Items {
+		item := &im.export.Items[i]
+		if item.PostType != "post" {
+			if item.PostType != "attachment" {
+				ignored++
+			}
+			continue
+		}
+		if err := im.importItem(item); err != nil {
+			return fmt.Errorf("导入文章《%s》失败: %w", item.Title, err)
+		}
+	}
+	if ignored > 0 {
+		im.warn("忽略了 %d 个页面、菜单等非文章内容", ignored)
+	}
+	return nil
+}
+
+func (im *wordPressImporter) warn(format string, args ...interface{}) {
+	im.report.Warnings = append(im.report.Warnings, fmt.Sprintf(format, args...))
+}
+
+// findRecord 查找之前导入时记录的本站ID，没有时返回 0
+func (im *wordPressImporter) findRecord(sourceType string, sourceID string) (uint, error) {
+	var record models.ImportRecord
+	err := im.tx.Where("source = ? AND site = ? AND source_type = ? AND source_id = ?",
+		models.ImportSourceWordPress, im.report.Site, sourceType, sourceID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return record.TargetID, err
+}
+
+// saveRecord 保存导入记录；之前的记录指向的内容已删除时更新为新的ID
+func (im *wordPressImporter) saveRecord(sourceType string, sourceID string, targetID uint) error {
+	return im.tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"target_id"}),
+	}).Create(&models.ImportRecord{
+		Source:     models.ImportSourceWordPress,
+		Site:       im.report.Site,
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		TargetID:   targetID,
+	}).Error
+}
+
+// importAuthors 为每个作者找到或创建本站用户。旧版 WXR 没有作者列表，从文章的 dc:creator 补充
+func (im *wordPressImporter) importAuthors() error {
+	authors := append([]wxr.Author(nil), im.export.Authors...)
+	known := make(map[string]bool, len(authors))
+	for _, author := range authors {
+		known[author.Login] = true
+	}
+	for _, item := range im.export.Items {
+		if item.PostType == "post" && item.Creator != "" && !known[item.Creator] {
+			known[item.Creator] = true
+			authors = append(authors, wxr.Author{Login: item.Creator})
+		}
+	}
+
+	for _, author := range authors {
+		login := strings.TrimSpace(author.Login)
+		if login == "" || im.authors[login] > 0 {
+			continue
+		}
+		mapping, err := im.resolveAuthor(login, strings.TrimSpace(author.Email), strings.TrimSpace(author.DisplayName))
+		if err != nil {
+			return err
+		}
+		im.authors[login] = mapping.UserID
+		if author.ID > 0 {
+			im.authorIDs[author.ID] = mapping.UserID
+		}
+		im.report.Authors = append(im.report.Authors, mapping)
+	}
+	return nil
+}
+
+func (im *wordPressImporter) resolveAuthor(login, email, displayName string) (*models.WordPressAuthorMapping, error) {
+	mapping := &models.WordPressAuthorMapping{Login: login}
+	var user models.User
+
+	// 指定的映射优先于之前的导入记录，方便重新指定作者
+	if username, ok := im.opts.AuthorMap[login]; ok {
+		if err := im.tx.Where("username = ?", username).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrWordPressAuthorNotFound, username)
+			}
+			return nil, err
+		}
+		im.report.Users.Existing++
+		mapping.UserID, mapping.Username = user.ID, user.Username
+		return mapping, nil
+	}
+
+	// 之前导入时绑定的用户
+	userID, err := im.findRecord(models.ImportTypeAuthor, login)
+	if err != nil {
+		return nil, err
+	}
+	if userID > 0 {
+		err := im.tx.First(&user, userID).Error
+		if err == nil {
+			im.report.Users.Existing++
+			mapping.UserID, mapping.Username = user.ID, user.Username
+			return mapping, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	// 邮箱或用户名相同不代表是同一个人（如双方都有 admin），不自动绑定，
+	// 否则导入的文章和评论会归到本站管理员名下
+	conflict, err := im.conflictUser(login, email)
+	if err != nil {
+		return nil, err
+	}
+	if conflict != nil {
+		if !im.opts.DryRun {
+			return nil, fmt.Errorf("%w: %s", ErrWordPressAuthorConflict, login)
+		}
+		mapping.Conflict = conflict.Username
+		im.warn("作者 %s 与本站用户 %s 的邮箱或用户名相同，正式导入前请在作者映射中指定（如 %s=%s）",
+			login, conflict.Username, login, conflict.Username)
+		email = "" // 试运行按占位账号统计，避免邮箱唯一约束冲突
+	}
+
+	// 创建占位账号：没有密码，作者可以通过找回密码认领
+	base := login
+	if len(usernameInvalidChars.ReplaceAllString(login, "")) < 3 {
+		base = "wordpress"
+	}
+	username, err := uniqueUsername(im.tx, base)
+	if err != nil {
+		return nil, err
+	}
+	if email == "" {
+		email = username + "@wordpress.invalid"
+	}
+	if displayName == "" {
+		displayName = login
+	}
+	user = models.User{
+		Username: username,
+		Email:    truncateRunes(email, 100),
+		Nickname: truncateRunes(displayName, 50),
+		Role:     "user",
+		Status:   1,
+	}
+	if err := im.tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+	if err := im.saveRecord(models.ImportTypeAuthor, login, user.ID); err != nil {
+		return nil, err
+	}
+	im.report.Users.Created++
+	im.userIDs = append(im.userIDs, user.ID)
+	mapping.UserID, mapping.Username, mapping.Placeholder = user.ID, user.Username, true
+	return mapping, nil
+}
+
+// conflictUser 与作者邮箱或用户名相同的本站用户，没有时返回 nil
+func (im *wordPressImporter) conflictUser(login, email string) (*models.User, error) {
+	query := im.tx.Where("username = ?", login)
+	if email != "" {
+		query = im.tx.Where("username = ? OR email = ?", login, email)
+	}
+	var user models.User
+	err := query.Select("id", "username").First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// importCategories 按父分类在前的顺序导入站点的分类列表
+func (im *wordPressImporter) importCategories() error {
+	byNicename := make(map[string]*wxr.Category, len(im.export.Categories))
+	for i := range im.export.Categories {
+		category := &im.export.Categories[i]
+		byNicename[wpUnescape(category.Nicename)] = category
+	}
+
+	var visit func(category *wxr.Category, depth int) error
+	visit = func(category *wxr.Category, depth int) error {
+		if _, ok := im.categories[wpUnescape(category.Nicename)]; ok {
+			return nil
+		}
+		var parentID *uint
+		// 分类层级有环时 depth 兜底，按顶级分类导入
+		parentName := wpUnescape(category.Parent)
+		if parent, ok := byNicename[parentName]; ok && parentName != "" && depth < len(byNicename) {
+			if err := visit(parent, depth+1); err != nil {
+				return err
+			}
+			if id := im.categories[parentName]; id > 0 {
+				parentID = &id
+			}
+		}
+		_, err := im.category(category.Nicename, category.Name, category.Description, parentID)
+		return err
+	}
+
+	for i := range im.export.Categories {
+		if err := visit(&im.export.Categories[i], 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// category 按名称查找分类，不存在时创建；WordPress 默认的“未分类”不导入
+func (im *wordPressImporter) category(nicename, name, description string, parentID *uint) (uint, error) {
+	nicename = wpUnescape(nicename)
+	if nicename == "uncategorized" {
+		return 0, nil
+	}
+	if id, ok := im.categories[nicename]; ok {
+		return id, nil
+	}
+	name = truncateRunes(strings.TrimSpace(name), 50)
+	if name == "" {
+		return 0, nil
+	}
+
+	var category models.Category
+	err := im.tx.Where("name = ?", name).First(&category).Error
+	switch {
+	case err == nil:
+		im.report.Categories.Existing++
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		slug, err := im.categorySlug(nicename, name)
+		if err != nil {
+			return 0, err
+		}
+		category = models.Category{
+			Name:        name,
+			Slug:        slug,
+			Description: truncateRunes(strings.TrimSpace(description), 200),
+			ParentID:    parentID,
+		}
+		if err := im.tx.Create(&category).Error; err != nil {
+			return 0, err
+		}
+		im.report.Categories.Created++
+	default:
+		return 0, err
+	}
+	im.categories[nicename] = category.ID
+	return category.ID, nil
+}
+
+// categorySlug 沿用 WordPress 的别名，不合法或已被占用时根据名称生成
+func (im *wordPressImporter) categorySlug(nicename, name string) (string, error) {
+	if slug := normalizeSlug(nicename, ""); slug != "" && len(slug) <= 50 {
+		var count int64
+		if err := im.tx.Model(&models.Category{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+	}
+	return uniqueCategorySlug(im.tx, name)
+}
+
+// tag WordPress 的标签是站点级的，对应本站的公共标签
+func (im *wordPressImporter) tag(name string) (models.Tag, error) {
+	name = truncateRunes(strings.TrimSpace(name), 50)
+	if tag, ok := im.tags[name]; ok {
+		return tag, nil
+	}
+
+	var tag models.Tag
+	err := im.tx.Where("name = ? AND user_id IS NULL", name).First(&tag).Error
+	switch {
+	case err == nil:
+		im.report.Tags.Existing++
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		tag = models.Tag{Name: name}
+		if err := im.tx.Create(&tag).Error; err != nil {
+			return tag, err
+		}
+		im.report.Tags.Created++
+	default:
+		return tag, err
+	}
+	im.tags[name] = tag
+	return tag, nil
+}
+
+func (im *wordPressImporter) importItem(item *wxr.Item) error {
+	status, ok := wpArticleStatus(item.Status)
+	if !ok {
+		im.report.Articles.Skipped++
+		return nil
+	}
+
+	sourceID := strconv.FormatInt(item.PostID, 10)
+	articleID, err := im.findRecord(models.ImportTypePost, sourceID)
+	if err != nil {
+		return err
+	}
+	if articleID > 0 {
+		// 之前导入后在本站删除的文章不再导入，评论也一并跳过
+		var count int64
+		if err := im.tx.Model(&models.Article{}).Where("id = ?", articleID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			im.report.Articles.Skipped++
+			return nil
+		}
+		im.report.Articles.Existing++
+		return im.importComments(item, articleID)
+	}
+
+	authorID := im.authors[item.Creator]
+	if authorID == 0 {
+		im.warn("《%s》没有作者，已跳过", item.Title)
+		im.report.Articles.Skipped++
+		return nil
+	}
+
+	var categoryID uint
+	for _, term := range item.Categories() {
+		if categoryID, err = im.category(term.Nicename, term.Name, "", nil); err != nil {
+			return err
+		}
+		if categoryID > 0 {
+			break
+		}
+	}
+	var tags []models.Tag
+	seen := make(map[uint]bool)
+	for _, term := range item.Tags() {
+		tag, err := im.tag(term.Name)
+		if err != nil {
+			return err
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	title := truncateRunes(item.Title, 200)
+	if title == "" {
+		title = "无标题"
+	}
+	if item.PostPassword != "" {
+		// 本站没有密码保护，按私有文章导入
+		status = models.ArticleStatusPrivate
+		im.warn("《%s》设置了访问密码，已导入为私有文章", title)
+	}
+
+	now := time.Now()
+	published := item.Published()
+	if published.IsZero() {
+		published = now
+	}
+	updated := item.Updated()
+	if updated.IsZero() {
+		updated = published
+	}
+	var publishAt *time.Time
+	if status != models.ArticleStatusDraft {
+		publishAt = &published
+	}
+
+	article := &models.Article{
+		CreatedAt:  published,
+		UpdatedAt:  updated,
+		Title:      title,
+		Content:    wpContent(item.Content()),
+		Summary:    truncateRunes(plainText(item.Excerpt()), 500),
+		Cover:      truncateRunes(im.export.Thumbnail(item), 255),
+		CategoryID: categoryID,
+		AuthorID:   authorID,
+		Status:     status,
+		IsTop:      item.IsSticky == 1,
+		PublishAt:  publishAt,
+	}
+	if err := createImportedArticle(im.tx, article, wpUnescape(item.PostName), tags, "从 WordPress 导入"); err != nil {
+		return err
+	}
+	if err := im.saveRecord(models.ImportTypePost, sourceID, article.ID); err != nil {
+		return err
+	}
+	im.report.Articles.Created++
+	im.articleIDs = append(im.articleIDs, article.ID)
+	return im.importComments(item, article.ID)
+}
+
+// importComments 按父评论在前的顺序导入评论，回复的 RootID 指向所在楼层的顶级评论
+func (im *wordPressImporter) importComments(item *wxr.Item, articleID uint) error {
+	byID := make(map[int64]*wxr.Comment, len(item.Comments))
+	for i := range item.Comments {
+		byID[item.Comments[i].ID] = &item.Comments[i]
+	}
+	imported := make(map[int64]*models.Comment, len(item.Comments))
+	skipped := make(map[int64]bool)
+
+	var visit func(c *wxr.Comment, depth int) error
+	visit = func(c *wxr.Comment, depth int) error {
+		if imported[c.ID] != nil || skipped[c.ID] {
+			return nil
+		}
+		var parent *models.Comment
+		if p, ok := byID[c.Parent]; ok && c.Parent != c.ID && depth < len(byID) {
+			if err := visit(p, depth+1); err != nil {
+				return err
+			}
+			parent = imported[p.ID]
+		}
+		comment, err := im.importComment(c, articleID, parent)
+		if err != nil {
+			return err
+		}
+		if comment == nil {
+			skipped[c.ID] = true
+		} else {
+			imported[c.ID] = comment
+		}
+		return nil
+	}
+
+	ordered := make([]*wxr.Comment, 0, len(item.Comments))
+	for i := range item.Comments {
+		ordered = append(ordered, &item.Comments[i])
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+	for _, c := range ordered {
+		if err := visit(c, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importComment 导入一条评论，返回 nil 表示跳过。父评论被跳过（如垃圾评论）时作为顶级评论导入
+func (im *wordPressImporter) importComment(c *wxr.Comment, articleID uint, parent *models.Comment) (*models.Comment, error) {
+	status, ok := wpCommentStatus(c)
+	if !ok {
+		im.report.Comments.Skipped++
+		return nil, nil
+	}
+
+	sourceID := strconv.FormatInt(c.ID, 10)
+	commentID, err := im.findRecord(models.ImportTypeComment, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if commentID > 0 {
+		var existing models.Comment
+		if err := im.tx.Select("id", "root_id").First(&existing, commentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				im.report.Comments.Skipped++
+				return nil, nil
+			}
+			return nil, err
+		}
+		im.report.Comments.Existing++
+		return &existing, nil
+	}
+
+	content := plainText(c.Content)
+	if content == "" {
+		im.report.Comments.Skipped++
+		return nil, nil
+	}
+	createdAt := c.Time()
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	comment := &models.Comment{
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		ArticleID: &articleID,
+		UserID:    im.authorIDs[c.UserID],
+		Content:   content,
+		Nickname:  truncateRunes(strings.TrimSpace(c.Author), 50),
+		Email:     truncateRunes(strings.TrimSpace(c.AuthorEmail), 100),
+		Website:   truncateRunes(strings.TrimSpace(c.AuthorURL), 200),
+		IP:        truncateRunes(strings.TrimSpace(c.AuthorIP), 50),
+		Status:    status,
+	}
+	if parent != nil {
+		comment.ParentID = &parent.ID
+		// 父评论有 root_id 时沿用，否则父评论就是顶级评论
+		if parent.RootID != nil {
+			comment.RootID = parent.RootID
+		} else {
+			comment.RootID = &parent.ID
+		}
+	}
+
+	// 访客评论没有对应的用户，user_id 保持为 NULL（与注销账号后匿名保留的评论一致）
+	query := im.tx
+	if comment.UserID == 0 {
+		query = query.Omit("user_id")
+	}
+	if err := query.Create(comment).Error; err != nil {
+		return nil, err
+	}
+	// Status 带有 default:1 标签，待审核的评论需要单独更新
+	if status == 0 {
+		if err := im.tx.Model(comment).UpdateColumn("status", 0).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := im.saveRecord(models.ImportTypeComment, sourceID, comment.ID); err != nil {
+		return nil, err
+	}
+
+	// 与发表评论一致，只有审核通过的评论计入评论数
+	if status == 1 {
+		if err := im.tx.Model(&models.Article{}).Where("id = ?", articleID).
+			UpdateColumn("comment_count", gorm.Expr("comment_count + ?", 1)).Error; err != nil {
+			return nil, err
+		}
+		if comment.UserID > 0 {
+			if err := im.tx.Model(&models.User{}).Where("id = ?", comment.UserID).
+				UpdateColumn("comment_count", gorm.Expr("comment_count + ?", 1)).Error; err != nil {
+				return nil, err
+			}
+		}
+		if comment.ParentID != nil {
+			if err := im.tx.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+				UpdateColumn("reply_count", gorm.Expr("reply_count + ?", 1)).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	im.report.Comments.Created++
+	return comment, nil
+}
+
+// wpArticleStatus WordPress 文章状态对应的本站状态；回收站、自动草稿等不导入
+func wpArticleStatus(status string) (int, bool) {
+	switch status {
+	case "publish":
+		return models.ArticleStatusPublished, true
+	case "private":
+		return models.ArticleStatusPrivate, true
+	case "future":
+		return models.ArticleStatusScheduled, true
+	case "draft", "pending":
+		return models.ArticleStatusDraft, true
+	default:
+		return 0, false
+	}
+}
+
+// wpCommentStatus 只导入普通评论：通过的为 1，待审核的为 0；垃圾评论、回收站和 pingback/trackback 不导入
+func wpCommentStatus(c *wxr.Comment) (int, bool) {
+	if c.Type != "" && c.Type != "comment" {
+		return 0, false
+	}
+	switch c.Approved {
+	case "1":
+		return 1, true
+	case "0":
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// wpContent 去掉块编辑器的注释；正文的 HTML 在 Markdown 中原样保留
+func wpContent(content string) string {
+	return strings.TrimSpace(wpBlockComment.ReplaceAllString(content, ""))
+}
+
+// wpUnescape WordPress 的别名中非 ASCII 字符是 URL 编码的
+func wpUnescape(value string) string {
+	value = strings.TrimSpace(value)
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
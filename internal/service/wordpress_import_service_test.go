@@ -0,0 +1,73 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/models"
+	"github.com/iceymoss/inkspace/pkg/wxr"
+)
+
+func TestWPArticleStatus(t *testing.T) {
+	tests := map[string]int{
+		"publish": models.ArticleStatusPublished,
+		"private": models.ArticleStatusPrivate,
+		"future":  models.ArticleStatusScheduled,
+		"draft":   models.ArticleStatusDraft,
+		"pending": models.ArticleStatusDraft,
+	}
+	for status, want := range tests {
+		if got, ok := wpArticleStatus(status); !ok || got != want {
+			t.Errorf("wpArticleStatus(%q) = %d, %v", status, got, ok)
+		}
+	}
+	for _, status := range []string{"trash", "auto-draft", "inherit"} {
+		if _, ok := wpArticleStatus(status); ok {
+			t.Errorf("wpArticleStatus(%q) should be skipped", status)
+		}
+	}
+}
+
+func TestWPCommentStatus(t *testing.T) {
+	tests := []struct {
+		comment wxr.Comment
+		status  int
+		ok      bool
+	}{
+		{wxr.Comment{Approved: "1"}, 1, true},
+		{wxr.Comment{Approved: "0", Type: "comment"}, 0, true},
+		{wxr.Comment{Approved: "spam"}, 0, false},
+		{wxr.Comment{Approved: "trash"}, 0, false},
+		{wxr.Comment{Approved: "1", Type: "pingback"}, 0, false},
+	}
+	for _, tt := range tests {
+		status, ok := wpCommentStatus(&tt.comment)
+		if status != tt.status || ok != tt.ok {
+			t.Errorf("wpCommentStatus(%+v) = %d, %v", tt.comment, status, ok)
+		}
+	}
+}
+
+func TestWPContent(t *testing.T) {
+	got := wpContent("<!-- wp:paragraph -->\n<p>正文</p>\n<!-- /wp:paragraph -->\n\n<!-- wp:image {\"id\":3} -->\n<figure><img src=\"a.png\"/></figure>\n<!-- /wp:image -->\n<!--more-->")
+	want := "<p>正文</p>\n\n<figure><img src=\"a.png\"/></figure>\n<!--more-->"
+	if got != want {
+		t.Fatalf("wpContent = %q, want %q", got, want)
+	}
+	if got := wpUnescape("%e5%b9%b6%e5%8f%91"); got != "并发" {
+		t.Fatalf("wpUnescape = %q", got)
+	}
+}
+
+func TestParseWordPressAuthorMap(t *testing.T) {
+	got, err := ParseWordPressAuthorMap(" alice = admin ,bob=bob2,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"alice": "admin", "bob": "bob2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("author map = %v, want %v", got, want)
+	}
+	if _, err := ParseWordPressAuthorMap("alice"); err == nil {
+		t.Fatal("expected error for missing username")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/iceymoss/inkspace/internal/database"
 	"github.com/iceymoss/inkspace/internal/models"
@@ -21,6 +22,10 @@ func NewWorkService() *WorkService {
 }
 
 func (s *WorkService) Create(req *models.WorkRequest, authorID uint, role string) (*models.Work, error) {
+	if err := EnsureEmailVerified(authorID); err != nil {
+		return nil, err
+	}
+
 	// 验证照片数量限制
 	if req.Type == "photography" {
 		maxPhotos := s.GetPhotoLimit(role)
@@ -98,9 +103,13 @@ func (s *WorkService) Create(req *models.WorkRequest, authorID uint, role string
 		Status:      workStatus, // 根据审核配置设置状态
 		IsRecommend: req.IsRecommend,
 	}
+	if workStatus == 1 {
+		now := time.Now()
+		work.PublishedAt = &now
+	}
 
 	// 创建作品
-	if err := database.DB.Create(work).Error; err != nil {
+	if err := createWithSlug(database.DB, models.SlugTargetWork, authorID, req.Slug, req.Title, work, &work.Slug); err != nil {
 		return nil, err
 	}
 
@@ -125,6 +134,10 @@ func (s *WorkService) Create(req *models.WorkRequest, authorID uint, role string
 		}
 	}
 
+	NewSearchService().Sync(models.SearchTypeWork, work.ID)
+	if workStatus == 1 {
+		NewTimelineService().FanOut(models.TimelineTypeWork, work.ID, work.AuthorID, *work.PublishedAt)
+	}
 	return work, nil
 }
 
@@ -133,8 +146,8 @@ func (s *WorkService) Update(id uint, req *models.WorkRequest, userID uint, role
 	var work models.Work
 	query := database.DB.Where("id = ?", id)
 
-	// 非管理员只能更新自己的作品
-	if role != "admin" {
+	// 没有作品管理权限时只能更新自己的作品
+	if !NewRoleService().HasPermission(role, models.PermissionWorkManage) {
 		query = query.Where("author_id = ?", userID)
 	}
 
@@ -171,8 +184,8 @@ func (s *WorkService) Update(id uint, req *models.WorkRequest, userID uint, role
 	oldStatus := work.Status
 
 	// 如果用户尝试将作品状态设置为已发布（status=1），需要检查审核配置
-	// 管理员可以绕过审核，直接设置为已发布
-	if workStatus == 1 && role != "admin" {
+	// 有作品审核权限的用户可以绕过审核，直接设置为已发布
+	if workStatus == 1 && !NewRoleService().HasPermission(role, models.PermissionWorkAudit) {
 		settingService := NewSettingService()
 		workAuditSetting, err := settingService.Get(models.SettingWorkAudit)
 		if err != nil {
@@ -198,8 +211,19 @@ func (s *WorkService) Update(id uint, req *models.WorkRequest, userID uint, role
 		}
 	}
 
+	// 只有显式修改 slug 时才变更，旧 slug 保留跳转
+	slug := work.Slug
+	if req.Slug != "" || work.Slug == "" {
+		newSlug, err := assignSlug(database.DB, models.SlugTargetWork, work.AuthorID, work.ID, req.Slug, req.Title)
+		if err != nil {
+			return nil, err
+		}
+		slug = newSlug
+	}
+
 	// 使用WHERE条件更新，确保权限（非管理员只能更新自己的作品）
 	updateData := map[string]interface{}{
+		"slug":         slug,
 		"title":        req.Title,
 		"type":         req.Type,
 		"metadata":     string(metadataJSON),
@@ -215,14 +239,26 @@ func (s *WorkService) Update(id uint, req *models.WorkRequest, userID uint, role
 		"status":       workStatus, // 使用处理后的状态
 		"is_recommend": req.IsRecommend,
 	}
-
-	updateQuery := database.DB.Model(&models.Work{}).Where("id = ?", id)
-	// 非管理员只能更新自己的作品
-	if role != "admin" {
-		updateQuery = updateQuery.Where("author_id = ?", userID)
+	if workStatus == 1 && work.PublishedAt == nil {
+		updateData["published_at"] = time.Now()
 	}
 
-	if err := updateQuery.Updates(updateData).Error; err != nil {
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		updateQuery := tx.Model(&models.Work{}).Where("id = ?", id)
+		// 没有作品管理权限时只能更新自己的作品
+		if !NewRoleService().HasPermission(role, models.PermissionWorkManage) {
+			updateQuery = updateQuery.Where("author_id = ?", userID)
+		}
+
+		if err := updateQuery.Updates(updateData).Error; err != nil {
+			// 检查和写入之间 slug 被并发占用
+			if isSlugConflict(err) {
+				return ErrSlugTaken
+			}
+			return err
+		}
+		return recordSlugChange(tx, models.SlugTargetWork, work.AuthorID, work.ID, work.Slug, slug)
+	}); err != nil {
 		return nil, err
 	}
 
@@ -253,6 +289,9 @@ func (s *WorkService) Update(id uint, req *models.WorkRequest, userID uint, role
 		}
 	}
 
+	NewSearchService().Sync(models.SearchTypeWork, id)
+	invalidateSEOMeta(seoPageWork, id)
+
 	// 重新加载作品以获取最新数据
 	if err := database.DB.Preload("Author").First(&work, id).Error; err != nil {
 		return nil, err
@@ -266,8 +305,8 @@ func (s *WorkService) Delete(id uint, userID uint, role string) error {
 	var work models.Work
 	query := database.DB.Where("id = ?", id)
 
-	// 非管理员只能删除自己的作品
-	if role != "admin" {
+	// 没有作品管理权限时只能删除自己的作品
+	if !NewRoleService().HasPermission(role, models.PermissionWorkManage) {
 		query = query.Where("author_id = ?", userID)
 	}
 
@@ -297,6 +336,8 @@ func (s *WorkService) Delete(id uint, userID uint, role string) error {
 		}
 	}
 
+	NewSearchService().Sync(models.SearchTypeWork, id)
+	invalidateSEOMeta(seoPageWork, id)
 	return nil
 }
 
@@ -453,10 +494,6 @@ func (s *WorkService) GetUserWorks(authorID uint, page, pageSize int, workType s
 	return works, total, nil
 }
 
-func (s *WorkService) IncrementViewCount(id uint) error {
-	return database.DB.Model(&models.Work{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
-}
-
 // GetRecommended 获取推荐作品
 // 只返回已发布（status=1）且被推荐（is_recommend=true）的作品
 func (s *WorkService) GetRecommended(limit int) ([]*models.Work, error) {
@@ -599,8 +636,8 @@ func (s *WorkService) GetTodayQuotaUsage(userID uint) (int, error) {
 
 // GetPhotoLimit 获取用户的照片数量限制
 func (s *WorkService) GetPhotoLimit(role string) int {
-	if role == "admin" {
-		return 50 // 管理员50张
+	if NewRoleService().HasPermission(role, models.PermissionWorkManage) {
+		return 50 // 作品管理员50张
 	}
 	return 10 // 普通用户10张
 }
@@ -623,6 +660,12 @@ func (s *WorkService) UpdateWorkStatus(id uint, status int, auditMessage string)
 			"status":        status,
 			"audit_message": auditMessage, // 保存审核消息
 		}
+		// 首次审核通过时记录发布时间，时间线按审核通过的时间排序，避免审核较久的作品排在后面
+		if status == 1 && work.PublishedAt == nil {
+			now := time.Now()
+			work.PublishedAt = &now
+			updateData["published_at"] = now
+		}
 		if err := tx.Model(&models.Work{}).Where("id = ?", id).Updates(updateData).Error; err != nil {
 			return err
 		}
@@ -665,6 +708,12 @@ func (s *WorkService) UpdateWorkStatus(id uint, status int, auditMessage string)
 		return err
 	}
 
+	NewSearchService().Sync(models.SearchTypeWork, id)
+	invalidateSEOMeta(seoPageWork, id)
+	if oldStatus != 1 && status == 1 {
+		NewTimelineService().FanOut(models.TimelineTypeWork, id, work.AuthorID, *work.PublishedAt)
+	}
+
 	// 发送审核通知（异步，不阻塞主流程）
 	// 注意：只有在状态真正改变时才发送通知
 	if oldStatus != status && (status == 1 || status == 3) {
@@ -67,6 +67,9 @@ func (s *WorkspaceService) Update(id, ownerID uint, req *models.WorkspaceRequest
 		}
 	}
 	s.deleteCache(id)
+	if req.IsPublic != nil {
+		NewSearchService().SyncWorkspace(id)
+	}
 	return s.Get(id, ownerID)
 }
 
@@ -101,6 +104,7 @@ func (s *WorkspaceService) Delete(id, ownerID uint) error {
 	})
 	if err == nil {
 		s.deleteCache(id)
+		NewSearchService().SyncWorkspace(id)
 	}
 	return err
 }
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 一次性操作令牌的用途，不同用途的令牌不能混用
+const (
+	ActionVerifyEmail   = "verify_email"
+	ActionResetPassword = "reset_password"
+)
+
+var ErrActionTokenInvalid = errors.New("链接无效或已过期")
+
+// ActionClaims 邮件链接中携带的签名令牌
+// Fingerprint 绑定签发时的账号状态（邮箱或密码摘要），状态变化后令牌自动失效，从而实现一次性使用
+type ActionClaims struct {
+	UserID      uint   `json:"uid"`
+	Purpose     string `json:"purpose"`
+	Fingerprint string `json:"fp"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken 签发一次性操作令牌
+func GenerateActionToken(purpose string, userID uint, state string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := ActionClaims{
+		UserID:      userID,
+		Purpose:     purpose,
+		Fingerprint: ActionFingerprint(state),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "action",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(actionSecret())
+}
+
+// ParseActionToken 校验签名、有效期和用途
+func ParseActionToken(tokenString, purpose string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return actionSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("action"))
+	if err != nil {
+		return nil, ErrActionTokenInvalid
+	}
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid || claims.Purpose != purpose {
+		return nil, ErrActionTokenInvalid
+	}
+	return claims, nil
+}
+
+// ActionFingerprint 账号状态摘要，只取前 16 字节，避免在链接中暴露完整的密码哈希
+func ActionFingerprint(state string) string {
+	mac := hmac.New(sha256.New, actionSecret())
+	mac.Write([]byte(state))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// actionSecret 由用户端 JWT 密钥派生，与登录令牌的签名密钥隔离
+func actionSecret() []byte {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWT.Secret))
+	mac.Write([]byte("inkspace-action-token"))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+func withTestJWTSecret(t *testing.T) {
+	previous := config.AppConfig
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{Secret: "test-secret"}}
+	t.Cleanup(func() { config.AppConfig = previous })
+}
+
+func TestActionTokenRoundTrip(t *testing.T) {
+	withTestJWTSecret(t)
+
+	token, err := GenerateActionToken(ActionResetPassword, 7, "password-hash", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ParseActionToken(token, ActionResetPassword)
+	if err != nil {
+		t.Fatalf("ParseActionToken() error = %v", err)
+	}
+	if claims.UserID != 7 || claims.Fingerprint != ActionFingerprint("password-hash") {
+		t.Fatalf("ParseActionToken() = %+v", claims)
+	}
+	if claims.Fingerprint == ActionFingerprint("new-password-hash") {
+		t.Fatal("fingerprint does not change with account state")
+	}
+}
+
+func TestActionTokenRejectsMisuse(t *testing.T) {
+	withTestJWTSecret(t)
+
+	token, _ := GenerateActionToken(ActionVerifyEmail, 1, "a@example.com", time.Hour)
+	if _, err := ParseActionToken(token, ActionResetPassword); !errors.Is(err, ErrActionTokenInvalid) {
+		t.Fatalf("wrong purpose error = %v, want %v", err, ErrActionTokenInvalid)
+	}
+
+	expired, _ := GenerateActionToken(ActionVerifyEmail, 1, "a@example.com", -time.Minute)
+	if _, err := ParseActionToken(expired, ActionVerifyEmail); !errors.Is(err, ErrActionTokenInvalid) {
+		t.Fatalf("expired error = %v, want %v", err, ErrActionTokenInvalid)
+	}
+
+	// 登录令牌不能当作操作令牌使用
+	loginToken, _ := GenerateToken(1, "alice", "user", 0, "")
+	if _, err := ParseActionToken(loginToken, ActionVerifyEmail); !errors.Is(err, ErrActionTokenInvalid) {
+		t.Fatalf("login token error = %v, want %v", err, ErrActionTokenInvalid)
+	}
+}
@@ -7,26 +7,44 @@ import (
 	"github.com/iceymoss/inkspace/internal/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAccessExpireMinutes = 30
+	defaultRefreshExpireHours  = 168
 )
 
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID     uint   `json:"user_id"`
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	Generation int64  `json:"gen"` // 签发时的用户令牌代数，代数递增后旧令牌全部失效
+	SessionID  string `json:"sid"` // 所属登录会话，会话被注销后令牌失效
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, username, role string) (string, error) {
-	cfg := config.AppConfig.JWT
-	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.ExpireHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// AccessTokenTTL 访问令牌有效期
+func AccessTokenTTL() time.Duration {
+	minutes := config.AppConfig.JWT.AccessExpireMinutes
+	if minutes <= 0 {
+		minutes = defaultAccessExpireMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// RefreshTokenTTL 刷新令牌有效期（沿用 expireHours 配置）
+func RefreshTokenTTL() time.Duration {
+	hours := config.AppConfig.JWT.ExpireHours
+	if hours <= 0 {
+		hours = defaultRefreshExpireHours
 	}
+	return time.Duration(hours) * time.Hour
+}
+
+func GenerateToken(userID uint, username, role string, generation int64, sessionID string) (string, error) {
+	cfg := config.AppConfig.JWT
+	claims := newClaims(userID, username, role, generation, sessionID, "")
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(cfg.Secret))
@@ -51,42 +69,18 @@ func ParseToken(tokenString string) (*Claims, error) {
 // ========== 管理员专用Token函数 ==========
 
 // GenerateAdminToken 生成管理员Token（使用独立的secret）
-func GenerateAdminToken(userID uint, username, role string) (string, error) {
-	cfg := config.AppConfig.JWT
-
-	// 使用管理员专用secret，如果未配置则使用默认secret+后缀
-	adminSecret := cfg.AdminSecret
-	if adminSecret == "" {
-		adminSecret = cfg.Secret + "-admin-secret"
-	}
-
-	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.ExpireHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "admin-service", // 标识为管理服务签发
-		},
-	}
+func GenerateAdminToken(userID uint, username, role string, generation int64, sessionID string) (string, error) {
+	// 标识为管理服务签发
+	claims := newClaims(userID, username, role, generation, sessionID, "admin-service")
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(adminSecret))
+	return token.SignedString([]byte(adminSecret()))
 }
 
 // ParseAdminToken 解析管理员Token（使用独立的secret）
 func ParseAdminToken(tokenString string) (*Claims, error) {
-	cfg := config.AppConfig.JWT
-
-	// 使用管理员专用secret
-	adminSecret := cfg.AdminSecret
-	if adminSecret == "" {
-		adminSecret = cfg.Secret + "-admin-secret"
-	}
-
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(adminSecret), nil
+		return []byte(adminSecret()), nil
 	})
 
 	if err != nil {
@@ -103,3 +97,29 @@ func ParseAdminToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid admin token")
 }
+
+func newClaims(userID uint, username, role string, generation int64, sessionID, issuer string) Claims {
+	now := time.Now()
+	return Claims{
+		UserID:     userID,
+		Username:   username,
+		Role:       role,
+		Generation: generation,
+		SessionID:  sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(), // 用于退出登录时精确吊销单个令牌
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    issuer,
+		},
+	}
+}
+
+// adminSecret 使用管理员专用secret，如果未配置则使用默认secret+后缀
+func adminSecret() string {
+	cfg := config.AppConfig.JWT
+	if cfg.AdminSecret == "" {
+		return cfg.Secret + "-admin-secret"
+	}
+	return cfg.AdminSecret
+}
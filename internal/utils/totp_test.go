@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量（取后 6 位）
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, test := range tests {
+		got, err := TOTPCode(secret, time.Unix(test.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("TOTPCode(%d) = %s, want %s", test.unix, got, test.want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	previous, _ := TOTPCode(secret, now.Add(-TOTPPeriod*time.Second))
+	stale, _ := TOTPCode(secret, now.Add(-2*TOTPPeriod*time.Second))
+
+	step, ok := ValidateTOTP(secret, previous, now, 1)
+	if !ok || step != TOTPStep(now)-1 {
+		t.Fatalf("ValidateTOTP(previous) = %d, %v; want step %d", step, ok, TOTPStep(now)-1)
+	}
+	if _, ok := ValidateTOTP(secret, stale, now, 1); ok {
+		t.Fatal("ValidateTOTP accepted a code outside the skew window")
+	}
+	if _, ok := ValidateTOTP(secret, "12345", now, 1); ok {
+		t.Fatal("ValidateTOTP accepted a short code")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("InkSpace", "alice", "JBSWY3DPEHPK3PXP")
+	for _, part := range []string{"otpauth://totp/InkSpace:alice?", "secret=JBSWY3DPEHPK3PXP", "issuer=InkSpace", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("TOTPProvisioningURI() = %s, missing %s", uri, part)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+var (
+	ErrMissingResponse = errors.New("captcha: missing response")
+	ErrInvalid         = errors.New("captcha: verification failed")
+)
+
+// 各服务商的服务端校验地址，三者都遵循 secret + response + remoteip 的表单协议
+var verifyURLs = map[string]string{
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+}
+
+// Verifier 人机验证校验器
+type Verifier interface {
+	// Provider 服务商名称，前端据此加载对应的组件
+	Provider() string
+	// SiteKey 前端渲染组件使用的公开 key
+	SiteKey() string
+	// Verify 校验前端提交的验证结果
+	Verify(ctx context.Context, response, remoteIP string) error
+}
+
+// New 根据配置创建校验器，未配置服务商时返回 nil
+func New(cfg config.CaptchaConfig, client *http.Client) (Verifier, error) {
+	if cfg.Provider == "" {
+		return nil, nil
+	}
+	endpoint := cfg.VerifyURL
+	if endpoint == "" {
+		endpoint = verifyURLs[cfg.Provider]
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("captcha: unknown provider %q", cfg.Provider)
+	}
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("captcha: %s secret key is required", cfg.Provider)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &siteVerifier{provider: cfg.Provider, siteKey: cfg.SiteKey, secret: cfg.SecretKey, endpoint: endpoint, client: client}, nil
+}
+
+type siteVerifier struct {
+	provider string
+	siteKey  string
+	secret   string
+	endpoint string
+	client   *http.Client
+}
+
+func (v *siteVerifier) Provider() string { return v.provider }
+
+func (v *siteVerifier) SiteKey() string { return v.siteKey }
+
+func (v *siteVerifier) Verify(ctx context.Context, response, remoteIP string) error {
+	if strings.TrimSpace(response) == "" {
+		return ErrMissingResponse
+	}
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", response)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err != nil {
+		return fmt.Errorf("captcha: decode response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(result.ErrorCodes, ","))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+func TestSiteVerifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok := r.PostFormValue("secret") == "secret" && r.PostFormValue("response") == "good" && r.PostFormValue("remoteip") == "10.0.0.1"
+		result := map[string]interface{}{"success": ok}
+		if !ok {
+			result["error-codes"] = []string{"invalid-input-response"}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	verifier, err := New(config.CaptchaConfig{Provider: "turnstile", SiteKey: "site", SecretKey: "secret", VerifyURL: server.URL}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifier.Provider() != "turnstile" || verifier.SiteKey() != "site" {
+		t.Fatalf("verifier = %s/%s", verifier.Provider(), verifier.SiteKey())
+	}
+
+	ctx := context.Background()
+	if err := verifier.Verify(ctx, "good", "10.0.0.1"); err != nil {
+		t.Fatalf("Verify(good) error = %v", err)
+	}
+	if err := verifier.Verify(ctx, "bad", "10.0.0.1"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Verify(bad) error = %v, want %v", err, ErrInvalid)
+	}
+	if err := verifier.Verify(ctx, " ", "10.0.0.1"); !errors.Is(err, ErrMissingResponse) {
+		t.Fatalf("Verify(empty) error = %v, want %v", err, ErrMissingResponse)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if verifier, err := New(config.CaptchaConfig{}, nil); verifier != nil || err != nil {
+		t.Fatalf("New(disabled) = %v, %v", verifier, err)
+	}
+	if _, err := New(config.CaptchaConfig{Provider: "unknown", SecretKey: "s"}, nil); err == nil {
+		t.Fatal("New(unknown provider) succeeded")
+	}
+	if _, err := New(config.CaptchaConfig{Provider: "hcaptcha"}, nil); err == nil {
+		t.Fatal("New(without secret) succeeded")
+	}
+}
@@ -0,0 +1,239 @@
+// Package diff 提供基于 Myers 算法的文本差异比较，支持行级和词级粒度
+package diff
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op 差异操作类型
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// String 返回操作类型名称
+func (o Op) String() string {
+	switch o {
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	default:
+		return "equal"
+	}
+}
+
+// Edit 单个差异片段
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// maxEditDistance 编辑距离上限，超过后直接视为整体替换，避免差异极大时占用过多内存
+const maxEditDistance = 2000
+
+// Lines 按行比较两段文本，每个片段为一整行（保留行尾换行符）
+func Lines(a, b string) []Edit {
+	return Tokens(splitLines(a), splitLines(b))
+}
+
+// Words 先按行比较，再对改动的行做词级比较，相邻同类片段会被合并
+func Words(a, b string) []Edit {
+	var result []Edit
+	var deleted, inserted strings.Builder
+	flush := func() {
+		if deleted.Len() == 0 && inserted.Len() == 0 {
+			return
+		}
+		result = append(result, Tokens(Tokenize(deleted.String()), Tokenize(inserted.String()))...)
+		deleted.Reset()
+		inserted.Reset()
+	}
+
+	for _, edit := range Lines(a, b) {
+		switch edit.Op {
+		case Delete:
+			deleted.WriteString(edit.Text)
+		case Insert:
+			inserted.WriteString(edit.Text)
+		default:
+			flush()
+			result = append(result, edit)
+		}
+	}
+	flush()
+	return Merge(result)
+}
+
+// Tokens 比较两个字符串序列，返回逐项的差异
+func Tokens(a, b []string) []Edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	edits = appendAll(edits, Equal, a[:prefix])
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	edits = appendAll(edits, Equal, a[len(a)-suffix:])
+	return edits
+}
+
+// Merge 合并相邻的同类片段
+func Merge(edits []Edit) []Edit {
+	merged := make([]Edit, 0, len(edits))
+	for _, edit := range edits {
+		if edit.Text == "" {
+			continue
+		}
+		if n := len(merged); n > 0 && merged[n-1].Op == edit.Op {
+			merged[n-1].Text += edit.Text
+			continue
+		}
+		merged = append(merged, edit)
+	}
+	return merged
+}
+
+// Tokenize 将文本切分为词、连续空白、标点和单个 CJK 字符
+func Tokenize(s string) []string {
+	var tokens []string
+	start := -1
+	kind := 0
+	for i, r := range s {
+		k := runeKind(r)
+		if start >= 0 && (k != kind || k == kindSingle) {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start = i
+			kind = k
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+const (
+	kindWord = iota
+	kindSpace
+	kindSingle
+)
+
+func runeKind(r rune) int {
+	switch {
+	case r == '\n':
+		return kindSingle
+	case unicode.IsSpace(r):
+		return kindSpace
+	case r == '_' || (r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))):
+		return kindWord
+	case unicode.IsLetter(r) && !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return kindWord
+	default:
+		return kindSingle
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func appendAll(edits []Edit, op Op, items []string) []Edit {
+	for _, item := range items {
+		edits = append(edits, Edit{Op: op, Text: item})
+	}
+	return edits
+}
+
+// myers 使用 Myers 贪心算法计算最短编辑脚本
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return appendAll(appendAll(nil, Delete, a), Insert, b)
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] 保存第 d 轮开始前 k ∈ [-d-1, d+1] 的状态
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		if d > maxEditDistance {
+			return appendAll(appendAll(nil, Delete, a), Insert, b)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(trace [][]int, a, b []string) []Edit {
+	var reversed []Edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Edit{Op: Equal, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Edit{Op: Insert, Text: b[y-1]})
+			} else {
+				reversed = append(reversed, Edit{Op: Delete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]Edit, len(reversed))
+	for i := range reversed {
+		edits[i] = reversed[len(reversed)-1-i]
+	}
+	return edits
+}
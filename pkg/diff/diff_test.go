@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rebuild 从差异片段还原比较前后的文本
+func rebuild(edits []Edit) (string, string) {
+	var before, after strings.Builder
+	for _, edit := range edits {
+		if edit.Op != Insert {
+			before.WriteString(edit.Text)
+		}
+		if edit.Op != Delete {
+			after.WriteString(edit.Text)
+		}
+	}
+	return before.String(), after.String()
+}
+
+func TestLines(t *testing.T) {
+	a := "标题\n第一段\n第二段\n结尾\n"
+	b := "标题\n第一段（修改）\n第二段\n新增段落\n结尾\n"
+
+	got := Lines(a, b)
+	want := []Edit{
+		{Equal, "标题\n"},
+		{Delete, "第一段\n"},
+		{Insert, "第一段（修改）\n"},
+		{Equal, "第二段\n"},
+		{Insert, "新增段落\n"},
+		{Equal, "结尾\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Lines() = %#v, want %#v", got, want)
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("hello old world\n不变\n", "hello new world\n不变\n")
+	want := []Edit{
+		{Equal, "hello "},
+		{Delete, "old"},
+		{Insert, "new"},
+		{Equal, " world\n不变\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Words() = %#v, want %#v", got, want)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Go 语言, v1.26\n")
+	want := []string{"Go", " ", "语", "言", ",", " ", "v1", ".", "26", "\n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize() = %q, want %q", got, want)
+	}
+}
+
+func TestTokensRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+	random := func() []string {
+		items := make([]string, rng.Intn(30))
+		for i := range items {
+			items[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return items
+	}
+
+	for i := 0; i < 200; i++ {
+		a, b := random(), random()
+		before, after := rebuild(Tokens(a, b))
+		if before != strings.Join(a, "") || after != strings.Join(b, "") {
+			t.Fatalf("Tokens(%q, %q) does not rebuild inputs: %q, %q", a, b, before, after)
+		}
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	if edits := Lines("", ""); len(edits) != 0 {
+		t.Fatalf("Lines(\"\", \"\") = %#v, want no edits", edits)
+	}
+	before, after := rebuild(Words("", "新内容"))
+	if before != "" || after != "新内容" {
+		t.Fatalf("Words() rebuild = %q, %q", before, after)
+	}
+}
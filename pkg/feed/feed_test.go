@@ -0,0 +1,125 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleFeed() *Feed {
+	published := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	return &Feed{
+		Title:       "InkSpace",
+		Description: "写作与分享",
+		Link:        "https://blog.example.com/",
+		FeedURL:     "https://blog.example.com/feed.xml",
+		Updated:     published.Add(time.Hour),
+		Items: []Item{{
+			ID:          "tag:blog.example.com,2026-03-01:article:1",
+			Title:       "Go & 泛型",
+			Link:        "https://blog.example.com/blog/alice/go-generics",
+			Summary:     "摘要 <b>",
+			ContentHTML: "<p>正文</p>]]><p>后续</p>",
+			AuthorName:  "Alice",
+			Categories:  []string{"Go"},
+			Published:   published,
+			Updated:     published.Add(time.Hour),
+		}},
+	}
+}
+
+func TestRenderRSS(t *testing.T) {
+	body, err := Render(sampleFeed(), RSS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Channel struct {
+			Items []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+	if len(doc.Channel.Items) != 1 {
+		t.Fatalf("items = %d, want 1", len(doc.Channel.Items))
+	}
+	item := doc.Channel.Items[0]
+	if item.Title != "Go & 泛型" || item.GUID != "tag:blog.example.com,2026-03-01:article:1" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+	if item.PubDate != "Sun, 01 Mar 2026 08:00:00 +0000" {
+		t.Fatalf("pubDate = %q", item.PubDate)
+	}
+	if item.Content != "<p>正文</p>]]><p>后续</p>" {
+		t.Fatalf("content = %q", item.Content)
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	body, err := Render(sampleFeed(), Atom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		ID      string `xml:"id"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+	if !strings.Contains(string(body), `xmlns="http://www.w3.org/2005/Atom"`) {
+		t.Fatalf("missing atom namespace:\n%s", body)
+	}
+	if doc.ID != "https://blog.example.com/feed.xml" {
+		t.Fatalf("feed id = %q", doc.ID)
+	}
+	if len(doc.Entries) != 1 || doc.Entries[0].Content.Type != "html" || doc.Entries[0].Updated != "2026-03-01T09:00:00Z" {
+		t.Fatalf("unexpected entries: %+v", doc.Entries)
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	body, err := Render(sampleFeed(), JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ID      string `json:"id"`
+			Authors []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "https://jsonfeed.org/version/1.1" || len(doc.Items) != 1 || doc.Items[0].Authors[0].Name != "Alice" {
+		t.Fatalf("unexpected json feed: %s", body)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, ok := ParseFormat("atom"); !ok || f != Atom {
+		t.Fatalf("ParseFormat(atom) = %q, %v", f, ok)
+	}
+	if _, ok := ParseFormat("xml"); ok {
+		t.Fatal("ParseFormat(xml) should fail")
+	}
+}
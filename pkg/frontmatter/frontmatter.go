@@ -0,0 +1,259 @@
+// Package frontmatter 解析和生成带元数据头的 Markdown 文件，兼容 Hugo、Hexo、Jekyll 的常用写法。
+// 元数据头支持 YAML（以 --- 包围）和 TOML（以 +++ 包围）
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Post 一篇 Markdown 文章
+type Post struct {
+	Title      string
+	Date       time.Time // 发布时间，元数据中没有时为零值
+	Updated    time.Time
+	Slug       string
+	Tags       []string
+	Categories []string
+	Draft      bool
+	Summary    string
+	Cover      string
+	Body       string
+}
+
+// 各字段可能使用的键名，按优先级排列
+var (
+	titleKeys   = []string{"title"}
+	dateKeys    = []string{"date", "publishdate", "pubdate", "published_at"}
+	updatedKeys = []string{"lastmod", "updated", "last_modified_at", "modified"}
+	slugKeys    = []string{"slug"}
+	tagKeys     = []string{"tags", "tag"}
+	categoryKey = []string{"categories", "category"}
+	summaryKeys = []string{"summary", "description", "excerpt"}
+	coverKeys   = []string{"cover", "image", "featured_image", "featureimage", "thumbnail", "banner", "cover_image"}
+)
+
+// 常见的日期写法；不带时区的按本地时间解析
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+// Parse 解析 Markdown 文件；没有元数据头时整个文件作为正文
+func Parse(data []byte) (*Post, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	meta, body, delimiter := split(data)
+	post := &Post{Body: string(body)}
+	if delimiter == "" {
+		return post, nil
+	}
+
+	values := make(map[string]interface{})
+	var err error
+	if delimiter == "+++" {
+		err = toml.Unmarshal(meta, &values)
+	} else {
+		err = yaml.Unmarshal(meta, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析元数据失败: %w", err)
+	}
+	fields := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		fields[strings.ToLower(key)] = value
+	}
+
+	post.Title = stringValue(lookup(fields, titleKeys))
+	post.Slug = stringValue(lookup(fields, slugKeys))
+	post.Summary = stringValue(lookup(fields, summaryKeys))
+	post.Cover = coverValue(lookup(fields, coverKeys))
+	post.Tags = listValue(lookup(fields, tagKeys))
+	post.Categories = listValue(lookup(fields, categoryKey))
+	if post.Date, err = timeValue(lookup(fields, dateKeys)); err != nil {
+		return nil, err
+	}
+	if post.Updated, err = timeValue(lookup(fields, updatedKeys)); err != nil {
+		return nil, err
+	}
+	// Hugo 使用 draft: true，Hexo 和 Jekyll 使用 published: false
+	if draft, ok := fields["draft"].(bool); ok {
+		post.Draft = draft
+	}
+	if published, ok := fields["published"].(bool); ok && !published {
+		post.Draft = true
+	}
+	return post, nil
+}
+
+// split 拆分元数据头和正文，返回使用的分隔符；没有元数据头时分隔符为空
+func split(data []byte) ([]byte, []byte, string) {
+	for _, delimiter := range []string{"---", "+++"} {
+		first, rest, ok := cutLine(data)
+		if !ok || strings.TrimSpace(string(first)) != delimiter {
+			continue
+		}
+		var meta []byte
+		for len(rest) > 0 {
+			line, next, _ := cutLine(rest)
+			if strings.TrimSpace(string(line)) == delimiter {
+				return meta, bytes.TrimLeft(next, "\r\n"), delimiter
+			}
+			meta = append(meta, line...)
+			meta = append(meta, '\n')
+			rest = next
+		}
+	}
+	return nil, data, ""
+}
+
+func cutLine(data []byte) ([]byte, []byte, bool) {
+	if len(data) == 0 {
+		return nil, nil, false
+	}
+	line, rest, found := bytes.Cut(data, []byte("\n"))
+	if !found {
+		rest = nil
+	}
+	return bytes.TrimSuffix(line, []byte("\r")), rest, true
+}
+
+func lookup(fields map[string]interface{}, keys []string) interface{} {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok && value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// coverValue 封面可能是字符串，也可能是 Hugo 主题常用的 {image: ...} 结构
+func coverValue(value interface{}) string {
+	if m, ok := value.(map[string]interface{}); ok {
+		return stringValue(m["image"])
+	}
+	return stringValue(value)
+}
+
+// listValue 读取标签或分类：数组、嵌套数组（Hexo 多级分类）或字符串。
+// 字符串中有逗号时按逗号拆分，否则按空白拆分（Jekyll 的写法）
+func listValue(value interface{}) []string {
+	var items []string
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				collect(item)
+			}
+		case string:
+			sep := strings.Fields
+			if strings.Contains(v, ",") {
+				sep = func(s string) []string { return strings.Split(s, ",") }
+			}
+			for _, item := range sep(v) {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		case nil:
+		default:
+			items = append(items, fmt.Sprint(v))
+		}
+	}
+	collect(value)
+
+	seen := make(map[string]bool, len(items))
+	unique := items[:0]
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			unique = append(unique, item)
+		}
+	}
+	return unique
+}
+
+// timeValue 解析日期：YAML 中可能是字符串，TOML 中可能是日期时间类型
+func timeValue(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	case toml.LocalDate:
+		return v.AsTime(time.Local), nil
+	case toml.LocalDateTime:
+		return v.AsTime(time.Local), nil
+	}
+	text := stringValue(value)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法识别的日期格式: %s", text)
+}
+
+// yamlPost 导出时的 YAML 元数据，字段顺序即输出顺序
+type yamlPost struct {
+	Title      string    `yaml:"title"`
+	Date       time.Time `yaml:"date,omitempty"`
+	Lastmod    time.Time `yaml:"lastmod,omitempty"`
+	Slug       string    `yaml:"slug,omitempty"`
+	Tags       []string  `yaml:"tags,omitempty"`
+	Categories []string  `yaml:"categories,omitempty"`
+	Draft      bool      `yaml:"draft,omitempty"`
+	Summary    string    `yaml:"summary,omitempty"`
+	Cover      string    `yaml:"cover,omitempty"`
+}
+
+// Marshal 生成带 YAML 元数据头的 Markdown，使用 Hugo 的键名，Parse 可以原样读回
+func (p *Post) Marshal() ([]byte, error) {
+	meta, err := yaml.Marshal(&yamlPost{
+		Title:      p.Title,
+		Date:       p.Date,
+		Lastmod:    p.Updated,
+		Slug:       p.Slug,
+		Tags:       p.Tags,
+		Categories: p.Categories,
+		Draft:      p.Draft,
+		Summary:    p.Summary,
+		Cover:      p.Cover,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(meta)
+	buf.WriteString("---\n\n")
+	buf.WriteString(p.Body)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,133 @@
+package frontmatter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHugoYAML(t *testing.T) {
+	post, err := Parse([]byte(`---
+title: "Go 并发入门"
+date: 2021-03-04T10:20:30+08:00
+lastmod: 2021-05-06
+slug: go-concurrency
+tags: [Go, 并发]
+categories:
+  - 后端
+draft: true
+summary: 一篇入门
+cover:
+  image: images/cover.png
+---
+
+正文内容
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if post.Title != "Go 并发入门" || post.Slug != "go-concurrency" || post.Summary != "一篇入门" {
+		t.Fatalf("post = %+v", post)
+	}
+	if !post.Draft || post.Cover != "images/cover.png" || post.Body != "正文内容\n" {
+		t.Fatalf("post = %+v", post)
+	}
+	if !reflect.DeepEqual(post.Tags, []string{"Go", "并发"}) || !reflect.DeepEqual(post.Categories, []string{"后端"}) {
+		t.Fatalf("tags = %q categories = %q", post.Tags, post.Categories)
+	}
+	want := time.Date(2021, 3, 4, 10, 20, 30, 0, time.FixedZone("", 8*3600))
+	if !post.Date.Equal(want) {
+		t.Fatalf("date = %v, want %v", post.Date, want)
+	}
+	if post.Updated.Format("2006-01-02") != "2021-05-06" {
+		t.Fatalf("updated = %v", post.Updated)
+	}
+}
+
+func TestParseHugoTOML(t *testing.T) {
+	post, err := Parse([]byte("+++\r\ntitle = \"TOML 文章\"\r\ndate = 2020-01-02T03:04:05\r\ntags = [\"a\", \"b\"]\r\n+++\r\nbody"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Title != "TOML 文章" || post.Body != "body" || !reflect.DeepEqual(post.Tags, []string{"a", "b"}) {
+		t.Fatalf("post = %+v", post)
+	}
+	if got := post.Date.Format("2006-01-02 15:04:05"); got != "2020-01-02 03:04:05" {
+		t.Fatalf("date = %s", got)
+	}
+}
+
+func TestParseHexoAndJekyll(t *testing.T) {
+	post, err := Parse([]byte(`---
+title: Hexo
+date: 2019-08-01 12:30:00
+categories:
+  - [生活, 旅行]
+  - 随笔
+tags: 游记
+published: false
+---
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !post.Draft || !reflect.DeepEqual(post.Categories, []string{"生活", "旅行", "随笔"}) || !reflect.DeepEqual(post.Tags, []string{"游记"}) {
+		t.Fatalf("post = %+v", post)
+	}
+
+	post, err = Parse([]byte("---\nlayout: post\ntitle: Jekyll\ndate: 2018-02-03 04:05:06 +0800\ntags: ruby jekyll\ncategory: blog\n---\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(post.Tags, []string{"ruby", "jekyll"}) || !reflect.DeepEqual(post.Categories, []string{"blog"}) {
+		t.Fatalf("post = %+v", post)
+	}
+	if _, offset := post.Date.Zone(); offset != 8*3600 {
+		t.Fatalf("date = %v", post.Date)
+	}
+}
+
+func TestParseWithoutFrontMatter(t *testing.T) {
+	post, err := Parse([]byte("# 标题\n\n---\n正文"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Title != "" || post.Body != "# 标题\n\n---\n正文" {
+		t.Fatalf("post = %+v", post)
+	}
+}
+
+func TestParseInvalidDate(t *testing.T) {
+	if _, err := Parse([]byte("---\ndate: yesterday\n---\n")); err == nil {
+		t.Fatal("expected error for invalid date")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	original := &Post{
+		Title:      "标题: 带冒号",
+		Date:       time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Slug:       "title",
+		Tags:       []string{"Go"},
+		Categories: []string{"后端"},
+		Draft:      true,
+		Summary:    "摘要",
+		Body:       "正文\n",
+	}
+	data, err := original.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !post.Date.Equal(original.Date) || !post.Updated.IsZero() {
+		t.Fatalf("dates = %v %v", post.Date, post.Updated)
+	}
+	post.Date, post.Updated = original.Date, original.Updated
+	if !reflect.DeepEqual(post, original) {
+		t.Fatalf("round trip = %+v, want %+v", post, original)
+	}
+}
@@ -0,0 +1,135 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+// ErrNoRecipient 邮件缺少收件人
+var ErrNoRecipient = errors.New("mailer: no recipient")
+
+// Message 待发送的邮件，Text 和 HTML 至少提供一个
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// NewFromConfig 根据配置创建 Mailer，未配置发送方式时返回丢弃邮件的实现
+func NewFromConfig() (Mailer, error) {
+	cfg := config.AppConfig.Mail
+	from := mail.Address{Name: cfg.FromName, Address: cfg.From}
+
+	switch cfg.Transport {
+	case "smtp":
+		return NewSMTPMailer(cfg.SMTP, from), nil
+	case "file":
+		return NewFileMailer(cfg.FileDir, from)
+	case "memory":
+		return NewMemoryMailer(), nil
+	case "":
+		return discardMailer{}, nil
+	default:
+		return nil, fmt.Errorf("mailer: unknown transport %q", cfg.Transport)
+	}
+}
+
+type discardMailer struct{}
+
+func (discardMailer) Send(msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipient
+	}
+	return nil
+}
+
+// Build 生成符合 RFC 5322 的 MIME 邮件内容
+func Build(from mail.Address, msg *Message) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, ErrNoRecipient
+	}
+	to := make([]string, len(msg.To))
+	for i, address := range msg.To {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("mailer: invalid recipient %q: %w", address, err)
+		}
+		to[i] = parsed.String()
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", from.String())
+	writeHeader(&buf, "To", strings.Join(to, ", "))
+	writeHeader(&buf, "Subject", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(from.Address))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	switch {
+	case msg.Text != "" && msg.HTML != "":
+		boundary := randomHex(12)
+		writeHeader(&buf, "Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+		buf.WriteString("\r\n")
+		writePart(&buf, boundary, "text/plain", msg.Text)
+		writePart(&buf, boundary, "text/html", msg.HTML)
+		buf.WriteString("--" + boundary + "--\r\n")
+	case msg.HTML != "":
+		writeBody(&buf, "text/html", msg.HTML)
+	default:
+		writeBody(&buf, "text/plain", msg.Text)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key + ": " + value + "\r\n")
+}
+
+func writePart(buf *bytes.Buffer, boundary, contentType, body string) {
+	buf.WriteString("--" + boundary + "\r\n")
+	writeBody(buf, contentType, body)
+	buf.WriteString("\r\n")
+}
+
+func writeBody(buf *bytes.Buffer, contentType, body string) {
+	writeHeader(buf, "Content-Type", contentType+"; charset=UTF-8")
+	writeHeader(buf, "Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+	writer := quotedprintable.NewWriter(buf)
+	writer.Write([]byte(body))
+	writer.Close()
+	buf.WriteString("\r\n")
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if index := strings.LastIndex(from, "@"); index >= 0 {
+		domain = from[index+1:]
+	}
+	return "<" + randomHex(16) + "@" + domain + ">"
+}
+
+func randomHex(n int) string {
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		return base64.RawURLEncoding.EncodeToString([]byte(time.Now().String()))
+	}
+	return hex.EncodeToString(data)
+}
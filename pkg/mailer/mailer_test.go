@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"errors"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildMultipartMessage(t *testing.T) {
+	from := mail.Address{Name: "InkSpace", Address: "no-reply@example.com"}
+	data, err := Build(from, &Message{
+		To:      []string{"alice@example.com"},
+		Subject: "验证邮箱",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Build() produced an unparsable message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != "验证邮箱" {
+		t.Fatalf("Subject = %q (%v), want 验证邮箱", subject, err)
+	}
+	if !strings.HasPrefix(parsed.Header.Get("Content-Type"), "multipart/alternative") {
+		t.Fatalf("Content-Type = %s, want multipart/alternative", parsed.Header.Get("Content-Type"))
+	}
+	for _, part := range []string{"plain body", "<p>html body</p>"} {
+		if !strings.Contains(string(data), part) {
+			t.Errorf("Build() output missing %q", part)
+		}
+	}
+}
+
+func TestBuildRejectsBadRecipients(t *testing.T) {
+	from := mail.Address{Address: "no-reply@example.com"}
+	if _, err := Build(from, &Message{Subject: "x", Text: "x"}); !errors.Is(err, ErrNoRecipient) {
+		t.Fatalf("Build() error = %v, want %v", err, ErrNoRecipient)
+	}
+	if _, err := Build(from, &Message{To: []string{"not an address"}, Text: "x"}); err == nil {
+		t.Fatal("Build() accepted an invalid recipient")
+	}
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	if err := m.Send(&Message{To: []string{"bob@example.com"}, Subject: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	last, ok := m.Last()
+	if !ok || last.Subject != "hello" {
+		t.Fatalf("Last() = %+v, %v", last, ok)
+	}
+	m.Reset()
+	if len(m.Messages()) != 0 {
+		t.Fatal("Reset() kept messages")
+	}
+}
+
+func TestFileMailerWritesEML(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewFileMailer(dir, mail.Address{Address: "no-reply@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(&Message{To: []string{"carol@example.com"}, Subject: "hi", Text: "body"}); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("FileMailer wrote %d files, want 1", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(data), "carol@example.com") {
+		t.Fatalf("eml file missing recipient: %s", data)
+	}
+}
@@ -0,0 +1,128 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// hljsClasses chroma 词法类型对应的 highlight.js 类名，
+// 服务端高亮的代码因此可以直接使用站点配置的 highlight.js 代码主题
+var hljsClasses = map[chroma.TokenType]string{
+	chroma.Keyword:            "hljs-keyword",
+	chroma.KeywordConstant:    "hljs-literal",
+	chroma.KeywordType:        "hljs-type",
+	chroma.NameAttribute:      "hljs-attr",
+	chroma.NameBuiltin:        "hljs-built_in",
+	chroma.NameClass:          "hljs-title class_",
+	chroma.NameConstant:       "hljs-variable constant_",
+	chroma.NameDecorator:      "hljs-meta",
+	chroma.NameFunction:       "hljs-title function_",
+	chroma.NameTag:            "hljs-name",
+	chroma.NameVariable:       "hljs-variable",
+	chroma.LiteralString:      "hljs-string",
+	chroma.LiteralStringRegex: "hljs-regexp",
+	chroma.LiteralNumber:      "hljs-number",
+	chroma.OperatorWord:       "hljs-keyword",
+	chroma.Comment:            "hljs-comment",
+	chroma.CommentPreproc:     "hljs-meta",
+	chroma.GenericDeleted:     "hljs-deletion",
+	chroma.GenericInserted:    "hljs-addition",
+	chroma.GenericHeading:     "hljs-section",
+	chroma.GenericSubheading:  "hljs-section",
+	chroma.GenericEmph:        "hljs-emphasis",
+	chroma.GenericStrong:      "hljs-strong",
+}
+
+// hljsClass 按 子类型 -> 分类 逐级查找，没有对应样式时返回空字符串
+func hljsClass(t chroma.TokenType) string {
+	for ; t != 0; t = t.Parent() {
+		if class, ok := hljsClasses[t]; ok {
+			return class
+		}
+	}
+	return ""
+}
+
+// codeBlockRenderer 替换默认的围栏代码块渲染，```math 输出为公式块
+type codeBlockRenderer struct{}
+
+func (codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, renderFencedCodeBlock)
+}
+
+func renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.FencedCodeBlock)
+	var code bytes.Buffer
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		code.Write(segment.Value(source))
+	}
+
+	language := codeLanguage(string(n.Language(source)))
+	if language == "math" || language == "latex" || language == "katex" {
+		writeMath(w, "div", bytes.TrimSpace(code.Bytes()))
+		_ = w.WriteByte('\n')
+		return ast.WalkSkipChildren, nil
+	}
+
+	if language == "" {
+		_, _ = w.WriteString("<pre><code>")
+	} else {
+		_, _ = w.WriteString(`<pre><code class="hljs language-` + language + `">`)
+	}
+	highlight(w, language, code.String())
+	_, _ = w.WriteString("</code></pre>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+// codeLanguage 代码块语言转为可用作 class 的名称，如 c++ 转为 cpp
+func codeLanguage(info string) string {
+	info = strings.ToLower(strings.TrimSpace(info))
+	info = strings.ReplaceAll(info, "++", "pp")
+	info = strings.ReplaceAll(info, "#", "sharp")
+	var b strings.Builder
+	for _, r := range info {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// highlight 按语言着色，不支持的语言只转义输出
+func highlight(w util.BufWriter, language, code string) {
+	var lexer chroma.Lexer
+	if language != "" {
+		lexer = lexers.Get(language)
+	}
+	if lexer == nil {
+		_, _ = w.Write(util.EscapeHTML([]byte(code)))
+		return
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		_, _ = w.Write(util.EscapeHTML([]byte(code)))
+		return
+	}
+	for token := iterator(); token != chroma.EOF; token = iterator() {
+		value := util.EscapeHTML([]byte(token.Value))
+		class := hljsClass(token.Type)
+		if class == "" {
+			_, _ = w.Write(value)
+			continue
+		}
+		_, _ = w.WriteString(`<span class="` + class + `">`)
+		_, _ = w.Write(value)
+		_, _ = w.WriteString("</span>")
+	}
+}
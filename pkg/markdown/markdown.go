@@ -0,0 +1,140 @@
+// Package markdown 服务端 Markdown 渲染，文章、知识库文档、公开知识库和分享页共用。
+// 支持 GFM 表格和任务列表、脚注、标题锚点与目录、代码高亮和 KaTeX 数学公式，输出经过 bluemonday 清洗
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Heading 目录中的一个标题，ID 与正文中标题的 id 属性一致
+type Heading struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Level    int        `json:"level"`
+	Children []*Heading `json:"children,omitempty"`
+}
+
+// Result 渲染结果
+type Result struct {
+	HTML string     // 清洗后的 HTML
+	TOC  []*Heading // 按层级嵌套的目录，没有标题时为空
+}
+
+// 在 GFM 的基础上增加脚注、中文换行和数学公式；表格对齐使用 align 属性，避免被清洗掉。
+// 原始 HTML 原样输出，统一由 Sanitize 清洗
+var md = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Linkify,
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.TaskList,
+		extension.Footnote,
+		extension.CJK,
+		mathExtension{},
+	),
+	goldmark.WithRendererOptions(
+		html.WithUnsafe(),
+		renderer.WithNodeRenderers(util.Prioritized(codeBlockRenderer{}, 100)),
+	),
+)
+
+// Render 渲染 Markdown，为标题生成锚点并提取目录
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+	toc := assignHeadingIDs(doc, src)
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+	return &Result{HTML: Sanitize(buf.String()), TOC: toc}, nil
+}
+
+// assignHeadingIDs 按标题纯文本生成 id，重复时追加序号，同时按层级组装目录
+func assignHeadingIDs(doc ast.Node, source []byte) []*Heading {
+	var toc, stack []*Heading
+	used := make(map[string]bool)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		var buf bytes.Buffer
+		nodeText(heading, source, &buf)
+		title := strings.TrimSpace(buf.String())
+
+		base := headingID(title)
+		id := base
+		for i := 1; used[id]; i++ {
+			id = fmt.Sprintf("%s-%d", base, i)
+		}
+		used[id] = true
+		heading.SetAttributeString("id", []byte(id))
+
+		item := &Heading{ID: id, Title: title, Level: heading.Level}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc = append(toc, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// nodeText 节点的纯文本，忽略强调、链接等标记
+func nodeText(n ast.Node, source []byte, buf *bytes.Buffer) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			buf.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(c.Value)
+		case *Math:
+			buf.Write(c.Literal)
+		default:
+			nodeText(c, source, buf)
+		}
+	}
+}
+
+// headingID 保留字母（含中文）、数字、下划线和连字符，空白替换为连字符
+func headingID(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_':
+			b.WriteRune(r)
+			dash = false
+		case (unicode.IsSpace(r) || r == '-') && !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	id := strings.TrimRight(b.String(), "-")
+	if id == "" {
+		return "heading"
+	}
+	return id
+}
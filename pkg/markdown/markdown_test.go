@@ -0,0 +1,113 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustRender(t *testing.T, source string) *Result {
+	t.Helper()
+	result, err := Render(source)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return result
+}
+
+func TestRenderTOC(t *testing.T) {
+	result := mustRender(t, "# 入门 指南\n\n## 安装 `go`\n\n### 下载\n\n## 安装 `go`\n\n# FAQ\n")
+	for _, want := range []string{`<h1 id="入门-指南">`, `<h2 id="安装-go">`, `<h3 id="下载">`, `<h2 id="安装-go-1">`, `<h1 id="faq">`} {
+		if !strings.Contains(result.HTML, want) {
+			t.Errorf("HTML missing %s: %s", want, result.HTML)
+		}
+	}
+
+	want := []*Heading{
+		{ID: "入门-指南", Title: "入门 指南", Level: 1, Children: []*Heading{
+			{ID: "安装-go", Title: "安装 go", Level: 2, Children: []*Heading{
+				{ID: "下载", Title: "下载", Level: 3},
+			}},
+			{ID: "安装-go-1", Title: "安装 go", Level: 2},
+		}},
+		{ID: "faq", Title: "FAQ", Level: 1},
+	}
+	if !reflect.DeepEqual(result.TOC, want) {
+		t.Fatalf("TOC = %+v, want %+v", result.TOC, want)
+	}
+}
+
+func TestRenderExtensions(t *testing.T) {
+	result := mustRender(t, "| a | b |\n|:-|-:|\n| 1 | 2 |\n\n- [x] done\n- [ ] todo\n\n正文[^1]\n\n[^1]: 脚注\n")
+	for _, want := range []string{
+		`<th align="left">a</th>`,
+		`<td align="right">2</td>`,
+		`<input checked="" disabled="" type="checkbox"> done`,
+		`<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref"`,
+		`<li id="fn:1">`,
+		`<div class="footnotes" role="doc-endnotes">`,
+	} {
+		if !strings.Contains(result.HTML, want) {
+			t.Errorf("HTML missing %s: %s", want, result.HTML)
+		}
+	}
+}
+
+func TestRenderMath(t *testing.T) {
+	tests := []struct {
+		source, want string
+	}{
+		{"公式 $a^2+b<c$ 结束", `公式 <span class="language-math">a^2+b&lt;c</span> 结束`},
+		{"价格 $5 和 $10", "价格 $5 和 $10"},
+		{`转义 \$x$`, "转义 $x$"},
+		{"$$\nE=mc^2\n$$\n", `<div class="language-math">E=mc^2</div>`},
+		{"$$x_1$$\n", `<div class="language-math">x_1</div>`},
+		{"```math\n\\sum_i x_i\n```\n", `<div class="language-math">\sum_i x_i</div>`},
+	}
+	for _, tt := range tests {
+		if got := mustRender(t, tt.source).HTML; !strings.Contains(got, tt.want) {
+			t.Errorf("Render(%q) = %s, want %s", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestRenderHighlight(t *testing.T) {
+	html := mustRender(t, "```go\nfunc main() {} // 注释\n```\n\n```c++\nint x;\n```\n\n```\n<b>\n```\n").HTML
+	for _, want := range []string{
+		`<pre><code class="hljs language-go"><span class="hljs-keyword">func</span> <span class="hljs-title function_">main</span>`,
+		`<span class="hljs-comment">// 注释`,
+		`<code class="hljs language-cpp"><span class="hljs-type">int</span>`,
+		"<pre><code>&lt;b&gt;\n</code></pre>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML missing %s: %s", want, html)
+		}
+	}
+}
+
+func TestRenderSanitizesRawHTML(t *testing.T) {
+	html := mustRender(t, "<script>alert(1)</script>\n\n<b onclick=\"x()\">粗体</b> <a href=\"javascript:alert(1)\">链接</a>\n\n<h2 id=\"x\" style=\"color:red\">标题</h2>\n").HTML
+	for _, bad := range []string{"<script", "onclick", "javascript:", "style="} {
+		if strings.Contains(html, bad) {
+			t.Errorf("HTML contains %s: %s", bad, html)
+		}
+	}
+	if !strings.Contains(html, "<b>粗体</b>") {
+		t.Fatalf("safe HTML was removed: %s", html)
+	}
+}
+
+func TestHeadingID(t *testing.T) {
+	tests := map[string]string{
+		"Hello, World!":  "hello-world",
+		"Go 1.22 新特性":    "go-122-新特性",
+		"  a -- b  ":     "a-b",
+		"？！":             "heading",
+		"snake_case 标识符": "snake_case-标识符",
+	}
+	for title, want := range tests {
+		if got := headingID(title); got != want {
+			t.Errorf("headingID(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 数学公式输出为 class="language-math" 的 span（行内）或 div（独立成行），
+// 内容为转义后的 TeX 源码，与 Vditor.mathRender 和 KaTeX 按元素渲染的约定一致
+var (
+	KindMath      = ast.NewNodeKind("Math")
+	KindMathBlock = ast.NewNodeKind("MathBlock")
+
+	mathDelimiter = []byte("$$")
+)
+
+// Math 行内公式 $...$，$$...$$ 写在段落中时为独立公式
+type Math struct {
+	ast.BaseInline
+	Literal []byte
+	Display bool
+}
+
+func (n *Math) Kind() ast.NodeKind { return KindMath }
+
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Literal": string(n.Literal)}, nil)
+}
+
+// MathBlock 独立成行的 $$...$$ 公式块
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool // 开始和结束标记在同一行
+}
+
+func (n *MathBlock) Kind() ast.NodeKind { return KindMathBlock }
+
+func (n *MathBlock) IsRaw() bool { return true }
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type mathExtension struct{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(mathBlockParser{}, 150)),
+		parser.WithInlineParsers(util.Prioritized(mathInlineParser{}, 150)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(mathRenderer{}, 150)))
+}
+
+type mathInlineParser struct{}
+
+func (mathInlineParser) Trigger() []byte { return []byte{'$'} }
+
+// Parse 公式不跨行；单个 $ 之后和结束的 $ 之前不能是空白，结束的 $ 之后不能紧跟数字，
+// 否则不作为公式，避免把“$5 和 $10”这样的金额识别为公式
+func (mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	delim := 1
+	if len(line) > 1 && line[1] == '$' {
+		delim = 2
+	}
+	if len(line) <= delim || (delim == 1 && util.IsSpace(line[1])) {
+		return nil
+	}
+	rest := line[delim:]
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '\n', '\r':
+			return nil
+		case '$':
+			if delim == 2 {
+				if i == 0 || i+1 >= len(rest) || rest[i+1] != '$' {
+					continue
+				}
+			} else if util.IsSpace(rest[i-1]) || (i+1 < len(rest) && rest[i+1] >= '0' && rest[i+1] <= '9') {
+				return nil
+			}
+			node := &Math{Literal: append([]byte(nil), rest[:i]...), Display: delim == 2}
+			block.Advance(delim + i + delim)
+			return node
+		}
+	}
+	return nil
+}
+
+type mathBlockParser struct{}
+
+func (mathBlockParser) Trigger() []byte { return []byte{'$'} }
+
+func (mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], mathDelimiter) {
+		return nil, parser.NoChildren
+	}
+	start := pos + len(mathDelimiter)
+	rest := line[start:]
+	node := &MathBlock{}
+	if i := bytes.Index(rest, mathDelimiter); i >= 0 {
+		// $$...$$ 写在一行，结束标记之后只能是空白
+		if i == 0 || !util.IsBlank(rest[i+len(mathDelimiter):]) {
+			return nil, parser.NoChildren
+		}
+		offset := segment.Start - segment.Padding + start
+		node.Lines().Append(text.NewSegment(offset, offset+i))
+		node.closed = true
+	} else if !util.IsBlank(rest) {
+		node.Lines().Append(text.NewSegment(segment.Start-segment.Padding+start, segment.Stop))
+	}
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+func (mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	if i := bytes.Index(line, mathDelimiter); i >= 0 && util.IsBlank(line[i+len(mathDelimiter):]) {
+		if !util.IsBlank(line[:i]) {
+			node.Lines().Append(text.NewSegment(segment.Start, segment.Start+i))
+		}
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+func (mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (mathBlockParser) CanInterruptParagraph() bool { return true }
+
+func (mathBlockParser) CanAcceptIndentedLine() bool { return false }
+
+type mathRenderer struct{}
+
+func (mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, renderMath)
+	reg.Register(KindMathBlock, renderMathBlock)
+}
+
+func renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*Math)
+	tag := "span"
+	if n.Display {
+		tag = "div"
+	}
+	writeMath(w, tag, n.Literal)
+	return ast.WalkSkipChildren, nil
+}
+
+func renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var tex bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		tex.Write(segment.Value(source))
+	}
+	writeMath(w, "div", bytes.TrimSpace(tex.Bytes()))
+	_ = w.WriteByte('\n')
+	return ast.WalkSkipChildren, nil
+}
+
+func writeMath(w util.BufWriter, tag string, tex []byte) {
+	_, _ = w.WriteString("<" + tag + ` class="language-math">`)
+	_, _ = w.Write(util.EscapeHTML(tex))
+	_, _ = w.WriteString("</" + tag + ">")
+}
@@ -0,0 +1,30 @@
+package markdown
+
+import (
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var policy = NewPolicy()
+
+// NewPolicy 在 UGCPolicy 的基础上放行表格、代码高亮、公式、脚注、任务列表和中文标题锚点
+func NewPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowElements("table", "thead", "tbody", "tfoot", "tr", "th", "td", "caption", "colgroup", "col")
+	p.AllowAttrs("colspan", "rowspan", "scope").OnElements("th", "td")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9_ -]{1,200}$`)).
+		OnElements("pre", "code", "span", "div", "a", "sup", "li", "ul", "ol")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]{1,200}$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-[a-z]{1,20}$`)).OnElements("a", "div", "section", "sup")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^(lazy|eager)$`)).OnElements("img")
+	return p
+}
+
+// Sanitize 按 NewPolicy 清洗 HTML，也用于清洗数据库中已保存的渲染结果
+func Sanitize(html string) string {
+	return policy.Sanitize(html)
+}
@@ -0,0 +1,83 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	githubAuthURL  = "https://github.com/login/oauth/authorize"
+	githubTokenURL = "https://github.com/login/oauth/access_token"
+	githubAPIURL   = "https://api.github.com"
+)
+
+// githubProvider GitHub OAuth App，GitHub 不支持 OIDC，通过 REST API 读取用户信息
+type githubProvider struct {
+	baseProvider
+}
+
+func newGitHubProvider(base baseProvider) *githubProvider {
+	return &githubProvider{baseProvider: base}
+}
+
+func (p *githubProvider) AuthCodeURL(ctx context.Context, req *AuthRequest) (string, error) {
+	return p.authCodeURL(orDefault(p.cfg.AuthURL, githubAuthURL), p.scopes("read:user", "user:email"), req)
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	token, err := p.exchangeCode(ctx, orDefault(p.cfg.TokenURL, githubTokenURL), code, codeVerifier, false)
+	if err != nil {
+		return nil, err
+	}
+	apiURL := strings.TrimRight(orDefault(p.cfg.APIURL, githubAPIURL), "/")
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := p.getJSON(ctx, apiURL+"/user", token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, errors.New("oauth: github user id missing")
+	}
+
+	identity := &Identity{
+		Subject:   strconv.FormatInt(user.ID, 10), // login 可以修改，使用数字 ID 作为标识
+		Username:  user.Login,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+	}
+
+	// 公开资料中的邮箱未必已验证，以 /user/emails 中的主邮箱为准
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(ctx, apiURL+"/user/emails", token.AccessToken, &emails); err == nil {
+		for _, email := range emails {
+			if email.Primary {
+				identity.Email = email.Email
+				identity.EmailVerified = email.Verified
+				break
+			}
+		}
+	}
+	if identity.Email == "" {
+		identity.Email = user.Email
+	}
+	return identity, nil
+}
+
+func orDefault(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
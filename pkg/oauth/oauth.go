@@ -0,0 +1,214 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+var (
+	ErrUnknownProviderType = errors.New("oauth: unknown provider type")
+	ErrInvalidIDToken      = errors.New("oauth: invalid id token")
+)
+
+// Identity 第三方账号信息
+type Identity struct {
+	Subject       string // 在身份提供方内唯一且不变的用户标识
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+	AvatarURL     string
+}
+
+// AuthRequest 发起授权时需要携带的一次性参数
+type AuthRequest struct {
+	State         string
+	CodeChallenge string // PKCE S256 challenge
+	Nonce         string // OIDC 防重放
+}
+
+// Provider 身份提供方，实现授权码 + PKCE 流程
+type Provider interface {
+	Name() string
+	DisplayName() string
+	// AuthCodeURL 返回跳转到身份提供方的授权地址
+	AuthCodeURL(ctx context.Context, req *AuthRequest) (string, error)
+	// Exchange 使用授权码换取令牌并读取第三方账号信息
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
+
+// New 根据配置创建身份提供方
+func New(cfg config.OAuthProviderConfig, redirectURL string, client *http.Client) (Provider, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	base := baseProvider{cfg: cfg, redirectURL: redirectURL, client: client}
+	switch cfg.Type {
+	case "github":
+		return newGitHubProvider(base), nil
+	case "oidc":
+		if cfg.Issuer == "" {
+			return nil, fmt.Errorf("oauth: provider %s: issuer is required", cfg.Name)
+		}
+		return newOIDCProvider(base), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProviderType, cfg.Type)
+	}
+}
+
+// NewCodeVerifier 生成 PKCE code_verifier（RFC 7636，43 个字符）
+func NewCodeVerifier() (string, error) {
+	return randomString(32)
+}
+
+// CodeChallengeS256 计算 code_verifier 对应的 S256 challenge
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomToken 生成 state / nonce 等随机字符串
+func RandomToken() (string, error) {
+	return randomString(24)
+}
+
+func randomString(n int) (string, error) {
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+type baseProvider struct {
+	cfg         config.OAuthProviderConfig
+	redirectURL string
+	client      *http.Client
+}
+
+func (p *baseProvider) Name() string { return p.cfg.Name }
+
+func (p *baseProvider) DisplayName() string {
+	if p.cfg.DisplayName != "" {
+		return p.cfg.DisplayName
+	}
+	return p.cfg.Name
+}
+
+func (p *baseProvider) scopes(defaults ...string) string {
+	if len(p.cfg.Scopes) > 0 {
+		return strings.Join(p.cfg.Scopes, " ")
+	}
+	return strings.Join(defaults, " ")
+}
+
+func (p *baseProvider) authCodeURL(endpoint, scope string, req *AuthRequest) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", scope)
+	query.Set("state", req.State)
+	query.Set("code_challenge", req.CodeChallenge)
+	query.Set("code_challenge_method", "S256")
+	if req.Nonce != "" {
+		query.Set("nonce", req.Nonce)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchangeCode 以授权码换取令牌；basicAuth 为 true 时使用 client_secret_basic，否则使用 client_secret_post
+func (p *baseProvider) exchangeCode(ctx context.Context, endpoint, code, codeVerifier string, basicAuth bool) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if !basicAuth {
+		form.Set("client_id", p.cfg.ClientID)
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var token tokenResponse
+	status, err := p.doJSON(req, &token)
+	if err != nil {
+		return nil, err
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("oauth: token endpoint: %s %s", token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK || token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth: token endpoint returned status %d", status)
+	}
+	return &token, nil
+}
+
+func (p *baseProvider) getJSON(ctx context.Context, endpoint, accessToken string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	status, err := p.doJSON(req, out)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("oauth: GET %s returned status %d", endpoint, status)
+	}
+	return nil
+}
+
+func (p *baseProvider) doJSON(req *http.Request, out interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("oauth: decode %s: %w", req.URL.Path, err)
+	}
+	return resp.StatusCode, nil
+}
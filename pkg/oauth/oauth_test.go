@@ -0,0 +1,249 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iceymoss/inkspace/internal/config"
+)
+
+const testRedirectURL = "http://localhost:8081/api/auth/oauth/test/callback"
+
+// fakeOIDCServer 本地模拟的 OIDC 身份提供方，校验 PKCE 并签发 RS256 ID Token
+type fakeOIDCServer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+func newFakeOIDCServer(t *testing.T) *fakeOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeOIDCServer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"userinfo_endpoint":      f.URL + "/userinfo",
+			"jwks_uri":               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.PostFormValue("code") != "good-code" || CodeChallengeS256(r.PostFormValue("code_verifier")) != f.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":            f.URL,
+			"sub":            "user-42",
+			"aud":            "client",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          f.nonce,
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"name":           "Alice",
+		}
+		for k, v := range f.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		writeJSON(w, map[string]string{"access_token": "access", "token_type": "Bearer", "id_token": signed})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// authorize 模拟浏览器跳转到授权页，记录 PKCE challenge 与 nonce
+func (f *fakeOIDCServer) authorize(t *testing.T, provider Provider) (verifier, nonce string) {
+	verifier, _ = NewCodeVerifier()
+	nonce, _ = RandomToken()
+	authURL, err := provider.AuthCodeURL(context.Background(), &AuthRequest{
+		State:         "state",
+		CodeChallenge: CodeChallengeS256(verifier),
+		Nonce:         nonce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(authURL)
+	query := u.Query()
+	if u.Path != "/authorize" || query.Get("code_challenge_method") != "S256" || query.Get("redirect_uri") != testRedirectURL {
+		t.Fatalf("AuthCodeURL() = %s", authURL)
+	}
+	f.challenge = query.Get("code_challenge")
+	f.nonce = query.Get("nonce")
+	return verifier, nonce
+}
+
+func newTestOIDCProvider(t *testing.T, f *fakeOIDCServer) Provider {
+	provider, err := New(config.OAuthProviderConfig{
+		Name: "test", Type: "oidc", Issuer: f.URL, ClientID: "client", ClientSecret: "secret",
+	}, testRedirectURL, f.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestOIDCExchange(t *testing.T) {
+	f := newFakeOIDCServer(t)
+	provider := newTestOIDCProvider(t, f)
+
+	verifier, nonce := f.authorize(t, provider)
+	identity, err := provider.Exchange(context.Background(), "good-code", verifier, nonce)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if identity.Subject != "user-42" || identity.Email != "alice@example.com" || !identity.EmailVerified || identity.Name != "Alice" {
+		t.Fatalf("Exchange() = %+v", identity)
+	}
+}
+
+func TestOIDCExchangeRejectsWrongVerifier(t *testing.T) {
+	f := newFakeOIDCServer(t)
+	provider := newTestOIDCProvider(t, f)
+
+	_, nonce := f.authorize(t, provider)
+	other, _ := NewCodeVerifier()
+	if _, err := provider.Exchange(context.Background(), "good-code", other, nonce); err == nil {
+		t.Fatal("Exchange() with wrong code_verifier succeeded")
+	}
+}
+
+func TestOIDCExchangeRejectsBadIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		nonce  string
+	}{
+		{name: "nonce mismatch", nonce: "other"},
+		{name: "wrong audience", claims: jwt.MapClaims{"aud": "someone-else"}},
+		{name: "wrong issuer", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "expired", claims: jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeOIDCServer(t)
+			provider := newTestOIDCProvider(t, f)
+			verifier, nonce := f.authorize(t, provider)
+			f.claims = tt.claims
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			_, err := provider.Exchange(context.Background(), "good-code", verifier, nonce)
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("Exchange() error = %v, want %v", err, ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestGitHubExchange(t *testing.T) {
+	var challenge string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("client_secret") != "secret" || CodeChallengeS256(r.PostFormValue("code_verifier")) != challenge {
+			writeJSON(w, map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		writeJSON(w, map[string]string{"access_token": "gho_test", "token_type": "bearer"})
+	})
+	mux.HandleFunc("/api/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gho_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{})
+			return
+		}
+		writeJSON(w, map[string]interface{}{"id": 1001, "login": "octocat", "name": "The Octocat", "email": "public@example.com"})
+	})
+	mux.HandleFunc("/api/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]interface{}{
+			{"email": "old@example.com", "primary": false, "verified": true},
+			{"email": "octocat@example.com", "primary": true, "verified": true},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider, err := New(config.OAuthProviderConfig{
+		Name: "github", Type: "github", ClientID: "client", ClientSecret: "secret",
+		AuthURL: server.URL + "/login/oauth/authorize", TokenURL: server.URL + "/login/oauth/access_token", APIURL: server.URL + "/api",
+	}, testRedirectURL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, _ := NewCodeVerifier()
+	challenge = CodeChallengeS256(verifier)
+
+	identity, err := provider.Exchange(context.Background(), "code", verifier, "")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if identity.Subject != "1001" || identity.Username != "octocat" || identity.Email != "octocat@example.com" || !identity.EmailVerified {
+		t.Fatalf("Exchange() = %+v", identity)
+	}
+}
+
+func TestPKCEHelpers(t *testing.T) {
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RFC 7636 要求 43~128 个字符
+	if len(verifier) != 43 {
+		t.Fatalf("NewCodeVerifier() length = %d, want 43", len(verifier))
+	}
+	challenge := CodeChallengeS256(verifier)
+	if challenge != CodeChallengeS256(verifier) || len(challenge) != 43 {
+		t.Fatalf("CodeChallengeS256() = %s", challenge)
+	}
+	other, _ := NewCodeVerifier()
+	if other == verifier || CodeChallengeS256(other) == challenge {
+		t.Fatal("verifiers are not random")
+	}
+}
@@ -0,0 +1,271 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// discoveryTTL 发现文档和签名公钥的缓存时间，公钥轮换时遇到未知 kid 会立即刷新
+const discoveryTTL = time.Hour
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcProvider 通用 OpenID Connect 提供方，端点通过 issuer 的发现文档获取
+type oidcProvider struct {
+	baseProvider
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func newOIDCProvider(base baseProvider) *oidcProvider {
+	return &oidcProvider{baseProvider: base}
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, req *AuthRequest) (string, error) {
+	discovery, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	endpoint := orDefault(p.cfg.AuthURL, discovery.AuthorizationEndpoint)
+	return p.authCodeURL(endpoint, p.scopes("openid", "profile", "email"), req)
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	discovery, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.exchangeCode(ctx, orDefault(p.cfg.TokenURL, discovery.TokenEndpoint), code, codeVerifier, true)
+	if err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: missing id_token", ErrInvalidIDToken)
+	}
+
+	claims, err := p.verifyIDToken(ctx, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	identity := claims.identity()
+
+	// ID Token 中缺少邮箱时从 userinfo 补全，sub 必须一致
+	if identity.Email == "" && discovery.UserinfoEndpoint != "" {
+		var info idTokenClaims
+		if err := p.getJSON(ctx, discovery.UserinfoEndpoint, token.AccessToken, &info); err == nil && info.Subject == claims.Subject {
+			identity.Email = info.Email
+			identity.EmailVerified = bool(info.EmailVerified)
+			if identity.Name == "" {
+				identity.Name = info.Name
+			}
+		}
+	}
+	return identity, nil
+}
+
+type idTokenClaims struct {
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     flexBool `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Picture           string   `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+func (c *idTokenClaims) identity() *Identity {
+	return &Identity{
+		Subject:       c.Subject,
+		Email:         c.Email,
+		EmailVerified: bool(c.EmailVerified),
+		Name:          c.Name,
+		Username:      c.PreferredUsername,
+		AvatarURL:     c.Picture,
+	}
+}
+
+// flexBool 部分提供方把 email_verified 返回为字符串 "true"
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	*b = flexBool(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+func (p *oidcProvider) verifyIDToken(ctx context.Context, raw, nonce string) (*idTokenClaims, error) {
+	discovery, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+func (p *oidcProvider) metadata(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil && time.Since(p.fetchedAt) < discoveryTTL {
+		return p.discovery, nil
+	}
+	if err := p.refreshLocked(ctx); err != nil {
+		return nil, err
+	}
+	return p.discovery, nil
+}
+
+func (p *oidcProvider) key(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupLocked(kid); ok {
+		return key, nil
+	}
+	// 未知 kid 可能是提供方轮换了公钥，刷新一次
+	if err := p.refreshLocked(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := p.lookupLocked(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oauth: unknown signing key %q", kid)
+}
+
+func (p *oidcProvider) lookupLocked(kid string) (interface{}, bool) {
+	if kid != "" {
+		key, ok := p.keys[kid]
+		return key, ok
+	}
+	// 未指定 kid 时只接受唯一公钥
+	if len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (p *oidcProvider) refreshLocked(ctx context.Context) error {
+	issuer := strings.TrimRight(p.cfg.Issuer, "/")
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", "", &discovery); err != nil {
+		return err
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != issuer {
+		return fmt.Errorf("oauth: issuer mismatch: %s != %s", discovery.Issuer, p.cfg.Issuer)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, discovery.JWKSURI, "", &jwks); err != nil {
+		return err
+	}
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	p.discovery = &discovery
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oauth: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("oauth: unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
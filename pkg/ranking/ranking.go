@@ -0,0 +1,139 @@
+// Package ranking 热度排序：按互动权重把计数折算成积分，再用时间衰减公式计算热度分。
+//
+// 支持两种公式：
+//   - Hacker News：积分 / (发布小时数 + 2)^重力，分数随时间持续下降，旧内容自然退出热门
+//   - Reddit：log10(积分) + 发布时间 / 时间尺度，分数只在积分变化时改变，新内容天然靠前
+package ranking
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// 公式名称
+const (
+	FormulaHackerNews = "hackernews"
+	FormulaReddit     = "reddit"
+)
+
+// 默认参数
+const (
+	DefaultGravity   = 1.8
+	DefaultTimeScale = 12*time.Hour + 30*time.Minute // Reddit 使用的 45000 秒
+)
+
+// redditEpoch Reddit 公式的时间起点，只影响分数的绝对值，不影响排序
+var redditEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Counters 内容的互动计数
+type Counters struct {
+	Views     int
+	Comments  int
+	Likes     int
+	Favorites int
+}
+
+// Weights 每种互动折算成积分的权重
+type Weights struct {
+	View     float64
+	Comment  float64
+	Like     float64
+	Favorite float64
+}
+
+// DefaultWeights 默认权重：一次浏览记 1 分，收藏、评论、点赞代表更强的兴趣
+func DefaultWeights() Weights {
+	return Weights{View: 1, Comment: 6, Like: 4, Favorite: 8}
+}
+
+// Points 按权重累计积分，负数计数按 0 处理
+func (w Weights) Points(c Counters) float64 {
+	return w.View*nonNegative(c.Views) +
+		w.Comment*nonNegative(c.Comments) +
+		w.Like*nonNegative(c.Likes) +
+		w.Favorite*nonNegative(c.Favorites)
+}
+
+// Formula 时间衰减公式
+type Formula interface {
+	Score(points float64, publishedAt, now time.Time) float64
+}
+
+// HackerNews 积分 / (发布小时数 + 2)^Gravity，Gravity 越大衰减越快
+type HackerNews struct {
+	Gravity float64
+}
+
+func (f HackerNews) Score(points float64, publishedAt, now time.Time) float64 {
+	hours := now.Sub(publishedAt).Hours()
+	if hours < 0 {
+		hours = 0
+	}
+	return points / math.Pow(hours+2, f.Gravity)
+}
+
+// Reddit log10(积分) + 发布秒数 / TimeScale，积分每增长 10 倍相当于晚发布一个 TimeScale
+type Reddit struct {
+	TimeScale time.Duration
+}
+
+func (f Reddit) Score(points float64, publishedAt, _ time.Time) float64 {
+	return math.Log10(math.Max(points, 1)) + publishedAt.Sub(redditEpoch).Seconds()/f.TimeScale.Seconds()
+}
+
+// Config 热度排序配置
+type Config struct {
+	Formula   string
+	Gravity   float64
+	TimeScale time.Duration
+	Weights   Weights
+}
+
+// DefaultConfig 默认使用 Hacker News 公式
+func DefaultConfig() Config {
+	return Config{
+		Formula:   FormulaHackerNews,
+		Gravity:   DefaultGravity,
+		TimeScale: DefaultTimeScale,
+		Weights:   DefaultWeights(),
+	}
+}
+
+// Ranker 根据配置创建排序器，无效的参数回退为默认值
+func (c Config) Ranker() Ranker {
+	ranker := Ranker{Weights: c.Weights}
+	switch strings.ToLower(strings.TrimSpace(c.Formula)) {
+	case FormulaReddit:
+		scale := c.TimeScale
+		if scale <= 0 {
+			scale = DefaultTimeScale
+		}
+		ranker.Formula = Reddit{TimeScale: scale}
+	default:
+		gravity := c.Gravity
+		if gravity <= 0 {
+			gravity = DefaultGravity
+		}
+		ranker.Formula = HackerNews{Gravity: gravity}
+	}
+	return ranker
+}
+
+// Ranker 组合互动权重和衰减公式
+type Ranker struct {
+	Formula Formula
+	Weights Weights
+}
+
+// Score 计算内容在 now 时刻的热度分
+func (r Ranker) Score(c Counters, publishedAt, now time.Time) float64 {
+	return r.Formula.Score(r.Weights.Points(c), publishedAt, now)
+}
+
+func nonNegative(n int) float64 {
+	if n < 0 {
+		return 0
+	}
+	return float64(n)
+}
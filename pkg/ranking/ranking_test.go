@@ -0,0 +1,83 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestWeightsPoints(t *testing.T) {
+	w := Weights{View: 1, Comment: 6, Like: 4, Favorite: 8}
+	got := w.Points(Counters{Views: 10, Comments: 2, Likes: 3, Favorites: 1})
+	if got != 10+12+12+8 {
+		t.Fatalf("Points = %v", got)
+	}
+	if got := w.Points(Counters{Views: -5, Likes: 1}); got != 4 {
+		t.Fatalf("负数计数应按 0 处理，得到 %v", got)
+	}
+}
+
+func TestHackerNewsDecay(t *testing.T) {
+	f := HackerNews{Gravity: 1.8}
+	if got, want := f.Score(100, now, now), 100/math.Pow(2, 1.8); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("刚发布的分数 = %v, want %v", got, want)
+	}
+	if f.Score(100, now.Add(time.Hour), now) != f.Score(100, now, now) {
+		t.Fatal("发布时间晚于当前时间时应按 0 小时计算")
+	}
+
+	// 一个月前的爆款积分是新内容的 50 倍，仍然排在后面
+	viral := f.Score(5000, now.Add(-30*24*time.Hour), now)
+	fresh := f.Score(100, now.Add(-3*time.Hour), now)
+	if viral >= fresh {
+		t.Fatalf("旧爆款 %v 不应高于新内容 %v", viral, fresh)
+	}
+
+	// 同一内容的分数随时间下降
+	published := now.Add(-24 * time.Hour)
+	if f.Score(100, published, now.Add(time.Hour)) >= f.Score(100, published, now) {
+		t.Fatal("Hacker News 分数应随时间衰减")
+	}
+}
+
+func TestRedditScore(t *testing.T) {
+	f := Reddit{TimeScale: DefaultTimeScale}
+	published := now.Add(-48 * time.Hour)
+
+	// 分数与当前时间无关
+	if f.Score(100, published, now) != f.Score(100, published, now.Add(240*time.Hour)) {
+		t.Fatal("Reddit 分数不应随当前时间变化")
+	}
+	// 积分增长 10 倍等于晚发布一个时间尺度
+	later := f.Score(10, published.Add(DefaultTimeScale), now)
+	if got := f.Score(100, published, now); math.Abs(got-later) > 1e-9 {
+		t.Fatalf("Score(100) = %v, 晚发布一个时间尺度的 Score(10) = %v", got, later)
+	}
+	// 积分不足 1 时按 1 计算
+	if f.Score(0, published, now) != f.Score(1, published, now) {
+		t.Fatal("积分为 0 时应与 1 相同")
+	}
+}
+
+func TestConfigRanker(t *testing.T) {
+	r := Config{Formula: " Reddit ", Weights: DefaultWeights()}.Ranker()
+	reddit, ok := r.Formula.(Reddit)
+	if !ok || reddit.TimeScale != DefaultTimeScale {
+		t.Fatalf("Formula = %#v", r.Formula)
+	}
+
+	r = Config{Formula: "unknown", Gravity: -1}.Ranker()
+	hn, ok := r.Formula.(HackerNews)
+	if !ok || hn.Gravity != DefaultGravity {
+		t.Fatalf("未知公式应回退为默认的 Hacker News，得到 %#v", r.Formula)
+	}
+
+	r = DefaultConfig().Ranker()
+	c := Counters{Views: 20, Likes: 2}
+	want := HackerNews{Gravity: DefaultGravity}.Score(DefaultWeights().Points(c), now, now)
+	if got := r.Score(c, now, now); got != want {
+		t.Fatalf("Score = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package search
+
+import "math"
+
+// BM25 Okapi BM25 打分参数和语料统计
+type BM25 struct {
+	K1        float64 // 词频饱和度，通常取 1.2
+	B         float64 // 文档长度归一化程度，通常取 0.75
+	Docs      int64   // 语料中的文档总数
+	AvgLength float64 // 文档平均长度（词数）
+}
+
+// NewBM25 使用常用参数 k1=1.2、b=0.75
+func NewBM25(docs int64, avgLength float64) BM25 {
+	return BM25{K1: 1.2, B: 0.75, Docs: docs, AvgLength: avgLength}
+}
+
+// IDF 逆文档频率，df 为包含该词的文档数；使用 +1 的变体保证结果非负
+func (m BM25) IDF(df int64) float64 {
+	return math.Log(1 + (float64(m.Docs-df)+0.5)/(float64(df)+0.5))
+}
+
+// Score 单个词对文档的得分
+func (m BM25) Score(tf, length int, idf float64) float64 {
+	if tf <= 0 {
+		return 0
+	}
+	norm := 1.0
+	if m.AvgLength > 0 {
+		norm = 1 - m.B + m.B*float64(length)/m.AvgLength
+	}
+	f := float64(tf)
+	return idf * f * (m.K1 + 1) / (f + m.K1*norm)
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const ellipsis = "…"
+
+// Highlight 用 <mark> 标出 text 中命中 terms 的部分，其余内容做 HTML 转义。
+// maxRunes 大于 0 且原文更长时，截取从第一个命中位置附近开始的片段
+func Highlight(text string, terms []string, maxRunes int) string {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	// 命中区间按起点有序，二元组之间相互重叠，合并后整体标记
+	var ranges [][2]int
+	for _, token := range Tokenize(text) {
+		if !wanted[token.Term] {
+			continue
+		}
+		if n := len(ranges); n > 0 && token.Start <= ranges[n-1][1] {
+			if token.End > ranges[n-1][1] {
+				ranges[n-1][1] = token.End
+			}
+			continue
+		}
+		ranges = append(ranges, [2]int{token.Start, token.End})
+	}
+
+	start, end := 0, len(text)
+	if maxRunes > 0 && utf8.RuneCountInString(text) > maxRunes {
+		if len(ranges) > 0 {
+			start = backRunes(text, ranges[0][0], maxRunes/5)
+		}
+		end = forwardRunes(text, start, maxRunes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	pos := start
+	for _, r := range ranges {
+		if r[1] <= pos {
+			continue
+		}
+		if r[0] >= end {
+			break
+		}
+		from, to := max(r[0], pos), min(r[1], end)
+		b.WriteString(html.EscapeString(text[pos:from]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[from:to]))
+		b.WriteString("</mark>")
+		pos = to
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// backRunes 从字节偏移 offset 向前移动 n 个字符
+func backRunes(text string, offset, n int) int {
+	for ; n > 0 && offset > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(text[:offset])
+		offset -= size
+	}
+	return offset
+}
+
+// forwardRunes 从字节偏移 offset 向后移动 n 个字符
+func forwardRunes(text string, offset, n int) int {
+	for ; n > 0 && offset < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset
+}
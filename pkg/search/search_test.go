@@ -0,0 +1,130 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"搜索引擎", []string{"搜索", "索引", "引擎"}},
+		{"Go语言 BM25排序", []string{"go", "语言", "bm25", "排序"}},
+		{"猫 and 狗", []string{"猫", "and", "狗"}},
+		{"Hello, World! hello", []string{"hello", "world", "hello"}},
+		{"ひらがなカタカナ", []string{"ひら", "らが", "がな", "なカ", "カタ", "タカ", "カナ"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, token := range Tokenize(tt.text) {
+			got = append(got, token.Term)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	text := "用Go写搜索"
+	for _, token := range Tokenize(text) {
+		term := text[token.Start:token.End]
+		if term != token.Term && term != "Go" {
+			t.Errorf("token %q has offsets %d-%d (%q)", token.Term, token.Start, token.End, term)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("搜索 搜索 Search")
+	want := []string{"搜索", "search"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Terms() = %q, want %q", got, want)
+	}
+}
+
+func TestIndexFrequencies(t *testing.T) {
+	got := IndexFrequencies("猫咪和猫 cat")
+	want := map[string]int{"猫咪": 1, "咪和": 1, "和猫": 1, "猫": 2, "咪": 1, "和": 1, "cat": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("IndexFrequencies() = %v, want %v", got, want)
+	}
+	// 查询仍只按二元组切分，单字查询得到一个单字词
+	if got := Terms("猫"); !reflect.DeepEqual(got, []string{"猫"}) {
+		t.Fatalf("Terms(猫) = %q", got)
+	}
+}
+
+func TestBM25(t *testing.T) {
+	m := NewBM25(100, 50)
+	rare, common := m.IDF(1), m.IDF(90)
+	if rare <= common || common <= 0 {
+		t.Fatalf("idf rare=%f common=%f", rare, common)
+	}
+	if m.Score(2, 50, rare) <= m.Score(1, 50, rare) {
+		t.Fatal("higher tf should score higher")
+	}
+	if m.Score(1, 20, rare) <= m.Score(1, 200, rare) {
+		t.Fatal("shorter document should score higher")
+	}
+	if m.Score(0, 50, rare) != 0 {
+		t.Fatal("zero tf should score zero")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		terms    []string
+		maxRunes int
+		want     string
+	}{
+		{"合并重叠的二元组", "全文搜索引擎", Terms("搜索引擎"), 0, "全文<mark>搜索引擎</mark>"},
+		{"转义", "<b>Go</b> & go", Terms("go"), 0, "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; <mark>go</mark>"},
+		{"截取片段", "开头的一些无关内容，然后才提到搜索功能，后面还有很多很多文字", Terms("搜索"), 10, "…提到<mark>搜索</mark>功能，后面还…"},
+		{"无命中时从开头截取", "一二三四五六", Terms("七八"), 3, "一二三…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, tt.terms, tt.maxRunes); got != tt.want {
+				t.Fatalf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTFIDF(t *testing.T) {
+	vectors := TFIDF(map[uint]map[string]int{
+		1: Frequencies("Go 语言 并发 编程"),
+		2: Frequencies("Go 并发 模型"),
+		3: Frequencies("摄影 构图 技巧"),
+		4: Frequencies("摄影 器材"),
+	}, 0)
+
+	if got := vectors[1].Cosine(vectors[1]); got < 0.999 || got > 1.001 {
+		t.Fatalf("self similarity = %f", got)
+	}
+	if vectors[1].Cosine(vectors[2]) <= vectors[1].Cosine(vectors[3]) {
+		t.Fatal("articles about go should be more similar to each other")
+	}
+	if vectors[1].Cosine(vectors[3]) != 0 {
+		t.Fatal("unrelated articles should have zero similarity")
+	}
+	if _, ok := vectors[1]["编程"]; ok {
+		t.Fatal("terms appearing in only one document should be dropped")
+	}
+
+	limited := TFIDF(map[uint]map[string]int{
+		1: Frequencies("a b c"),
+		2: Frequencies("a b c"),
+	}, 2)
+	if len(limited[1]) != 2 {
+		t.Fatalf("limited vector has %d terms", len(limited[1]))
+	}
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"math"
+	"sort"
+)
+
+// Vector 稀疏的词权重向量，由 TFIDF 生成时已归一化为单位长度
+type Vector map[string]float64
+
+// Cosine 余弦相似度；两个向量都是单位向量时即为点积
+func (v Vector) Cosine(other Vector) float64 {
+	if len(other) < len(v) {
+		v, other = other, v
+	}
+	var dot float64
+	for term, w := range v {
+		dot += w * other[term]
+	}
+	return dot
+}
+
+// TFIDF 根据各文档的词频计算 TF-IDF 向量。
+// maxTerms 大于 0 时每个文档只保留权重最高的 maxTerms 个词，只出现在一个文档中的词对相似度没有贡献，直接丢弃
+func TFIDF(docs map[uint]map[string]int, maxTerms int) map[uint]Vector {
+	df := make(map[string]int)
+	for _, freq := range docs {
+		for term := range freq {
+			df[term]++
+		}
+	}
+
+	n := float64(len(docs))
+	vectors := make(map[uint]Vector, len(docs))
+	for id, freq := range docs {
+		type weighted struct {
+			term   string
+			weight float64
+		}
+		terms := make([]weighted, 0, len(freq))
+		for term, tf := range freq {
+			if df[term] < 2 {
+				continue
+			}
+			// 对数词频抑制长文反复出现的词，平滑的 IDF 保证权重为正
+			w := (1 + math.Log(float64(tf))) * math.Log(1+n/float64(df[term]))
+			terms = append(terms, weighted{term, w})
+		}
+		sort.Slice(terms, func(i, j int) bool {
+			if terms[i].weight != terms[j].weight {
+				return terms[i].weight > terms[j].weight
+			}
+			return terms[i].term < terms[j].term
+		})
+		if maxTerms > 0 && len(terms) > maxTerms {
+			terms = terms[:maxTerms]
+		}
+
+		var norm float64
+		for _, t := range terms {
+			norm += t.weight * t.weight
+		}
+		vector := make(Vector, len(terms))
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for _, t := range terms {
+				vector[t.term] = t.weight / norm
+			}
+		}
+		vectors[id] = vector
+	}
+	return vectors
+}
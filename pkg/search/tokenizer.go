@@ -0,0 +1,115 @@
+// Package search 提供全文检索的基础能力：中英文混合分词、BM25 打分和命中高亮。
+// 索引的存储由调用方负责。
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTermRunes 单个词的最大字符数，超长的英文单词和数字串不进入索引
+const MaxTermRunes = 32
+
+// Token 分词结果；Start、End 为词在原文中的字节偏移
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize 对文本分词：连续的中日韩字符按二元组（bigram）切分，单独出现的汉字作为一个词；
+// 其他语言按字母和数字组成的单词切分并转为小写
+func Tokenize(text string) []Token {
+	return tokenize(text, false)
+}
+
+// tokenize 分词；unigrams 为 true 时连续的中日韩字符除二元组外每个字也作为一个词
+func tokenize(text string, unigrams bool) []Token {
+	var tokens []Token
+	var cjk []Token // 当前连续的中日韩字符，每个元素对应一个字
+	wordStart := -1
+
+	flushWord := func(end int) {
+		if wordStart < 0 {
+			return
+		}
+		word := text[wordStart:end]
+		if utf8.RuneCountInString(word) <= MaxTermRunes {
+			tokens = append(tokens, Token{Term: strings.ToLower(word), Start: wordStart, End: end})
+		}
+		wordStart = -1
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+			return
+		case 1:
+			tokens = append(tokens, cjk[0])
+		default:
+			for i := 0; i+1 < len(cjk); i++ {
+				tokens = append(tokens, Token{Term: cjk[i].Term + cjk[i+1].Term, Start: cjk[i].Start, End: cjk[i+1].End})
+			}
+			if unigrams {
+				tokens = append(tokens, cjk...)
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for i, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord(i)
+			cjk = append(cjk, Token{Term: string(r), Start: i, End: i + utf8.RuneLen(r)})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			if wordStart < 0 {
+				wordStart = i
+			}
+		default:
+			flushWord(i)
+			flushCJK()
+		}
+	}
+	flushWord(len(text))
+	flushCJK()
+	return tokens
+}
+
+// Terms 返回去重后的词，保持首次出现的顺序，用于解析查询
+func Terms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, token := range Tokenize(text) {
+		if !seen[token.Term] {
+			seen[token.Term] = true
+			terms = append(terms, token.Term)
+		}
+	}
+	return terms
+}
+
+// Frequencies 统计每个词出现的次数
+func Frequencies(text string) map[string]int {
+	freq := make(map[string]int)
+	for _, token := range Tokenize(text) {
+		freq[token.Term]++
+	}
+	return freq
+}
+
+// IndexFrequencies 统计建立倒排索引用的词频：在 Frequencies 的基础上，
+// 连续的中日韩字符中每个字也单独计数，使“猫”这样的单字查询能命中“猫咪”
+func IndexFrequencies(text string) map[string]int {
+	freq := make(map[string]int)
+	for _, token := range tokenize(text, true) {
+		freq[token.Term]++
+	}
+	return freq
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
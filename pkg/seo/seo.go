@@ -0,0 +1,198 @@
+// Package seo 生成页面 <head> 中的标题、描述、Open Graph、Twitter Card 和 JSON-LD 结构化数据
+package seo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// Meta 单个页面的 SEO 元信息
+type Meta struct {
+	Title       string   // 页面标题，不含站点名称
+	Description string   // 纯文本描述
+	Canonical   string   // 规范地址
+	Image       string   // 分享预览图，绝对地址
+	Type        string   // og:type，如 website、article、profile
+	Keywords    []string // 关键词，文章类页面同时输出为 article:tag
+	NoIndex     bool     // 不希望被搜索引擎收录的页面，如分享链接
+
+	SiteName string
+	SiteLogo string
+	Locale   string
+
+	// 以下字段用于 article:* 标签和 JSON-LD
+	SchemaType    string // JSON-LD 的 @type，如 Article、CreativeWork；为空时不输出结构化数据
+	AuthorName    string
+	AuthorURL     string
+	PublishedTime time.Time
+	ModifiedTime  time.Time
+}
+
+// FullTitle <title> 使用的标题：页面标题 - 站点名称
+func (m *Meta) FullTitle() string {
+	switch {
+	case m.Title == "":
+		return m.SiteName
+	case m.SiteName == "" || m.Title == m.SiteName:
+		return m.Title
+	default:
+		return m.Title + " - " + m.SiteName
+	}
+}
+
+// Tags 输出 <title> 以及各类 meta、link 和 JSON-LD 标签
+func (m *Meta) Tags() string {
+	var b strings.Builder
+	element := func(format string, values ...string) {
+		args := make([]interface{}, len(values))
+		for i, value := range values {
+			args[i] = html.EscapeString(value)
+		}
+		b.WriteString(fmt.Sprintf(format, args...))
+		b.WriteString("\n    ")
+	}
+	meta := func(attr, key, value string) {
+		if value != "" {
+			element(`<meta `+attr+`="%s" content="%s">`, key, value)
+		}
+	}
+
+	element("<title>%s</title>", m.FullTitle())
+	meta("name", "description", m.Description)
+	if len(m.Keywords) > 0 {
+		meta("name", "keywords", strings.Join(m.Keywords, ","))
+	}
+	if m.NoIndex {
+		meta("name", "robots", "noindex")
+	}
+	if m.Canonical != "" {
+		element(`<link rel="canonical" href="%s">`, m.Canonical)
+	}
+
+	ogType := m.Type
+	if ogType == "" {
+		ogType = "website"
+	}
+	title := m.Title
+	if title == "" {
+		title = m.SiteName
+	}
+	meta("property", "og:type", ogType)
+	meta("property", "og:title", title)
+	meta("property", "og:description", m.Description)
+	meta("property", "og:url", m.Canonical)
+	meta("property", "og:image", m.Image)
+	meta("property", "og:site_name", m.SiteName)
+	meta("property", "og:locale", m.Locale)
+	if ogType == "article" {
+		meta("property", "article:published_time", formatTime(m.PublishedTime))
+		meta("property", "article:modified_time", formatTime(m.ModifiedTime))
+		meta("property", "article:author", m.AuthorURL)
+		for _, keyword := range m.Keywords {
+			meta("property", "article:tag", keyword)
+		}
+	}
+
+	card := "summary"
+	if m.Image != "" {
+		card = "summary_large_image"
+	}
+	meta("name", "twitter:card", card)
+	meta("name", "twitter:title", title)
+	meta("name", "twitter:description", m.Description)
+	meta("name", "twitter:image", m.Image)
+
+	if data := m.structuredData(); data != nil {
+		// json.Marshal 默认转义 <、> 和 &，内容无法提前闭合 script 标签
+		if body, err := json.Marshal(data); err == nil {
+			b.WriteString(`<script type="application/ld+json">`)
+			b.Write(body)
+			b.WriteString("</script>\n    ")
+		}
+	}
+	return strings.TrimRight(b.String(), " \n")
+}
+
+func (m *Meta) structuredData() map[string]interface{} {
+	if m.SchemaType == "" {
+		return nil
+	}
+	data := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    m.SchemaType,
+		"headline": truncateRunes(m.Title, 110),
+		"name":     m.Title,
+	}
+	if m.Description != "" {
+		data["description"] = m.Description
+	}
+	if m.Canonical != "" {
+		data["url"] = m.Canonical
+		data["mainEntityOfPage"] = map[string]interface{}{"@type": "WebPage", "@id": m.Canonical}
+	}
+	if m.Image != "" {
+		data["image"] = []string{m.Image}
+	}
+	if len(m.Keywords) > 0 {
+		data["keywords"] = strings.Join(m.Keywords, ",")
+	}
+	if published := formatTime(m.PublishedTime); published != "" {
+		data["datePublished"] = published
+	}
+	if modified := formatTime(m.ModifiedTime); modified != "" {
+		data["dateModified"] = modified
+	}
+	if m.AuthorName != "" {
+		author := map[string]interface{}{"@type": "Person", "name": m.AuthorName}
+		if m.AuthorURL != "" {
+			author["url"] = m.AuthorURL
+		}
+		data["author"] = author
+	}
+	if m.SiteName != "" {
+		publisher := map[string]interface{}{"@type": "Organization", "name": m.SiteName}
+		if m.SiteLogo != "" {
+			publisher["logo"] = map[string]interface{}{"@type": "ImageObject", "url": m.SiteLogo}
+		}
+		data["publisher"] = publisher
+	}
+	return data
+}
+
+// Inject 将 meta 标签写入页面：替换原有的 <title>，没有 <title> 时插入到 </head> 之前
+func Inject(page []byte, m *Meta) []byte {
+	tags := []byte(m.Tags())
+	if start := bytes.Index(page, []byte("<title>")); start >= 0 {
+		if end := bytes.Index(page[start:], []byte("</title>")); end >= 0 {
+			end += start + len("</title>")
+			return concat(page[:start], tags, page[end:])
+		}
+	}
+	if i := bytes.Index(page, []byte("</head>")); i >= 0 {
+		return concat(page[:i], append(tags, '\n'), page[i:])
+	}
+	return page
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
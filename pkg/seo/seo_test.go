@@ -0,0 +1,86 @@
+package seo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleMeta() *Meta {
+	return &Meta{
+		Title:         `Go & "泛型"`,
+		Description:   "摘要</script><script>alert(1)</script>",
+		Canonical:     "https://blog.example.com/blog/alice/go-generics",
+		Image:         "https://blog.example.com/uploads/cover.png",
+		Type:          "article",
+		Keywords:      []string{"Go", "泛型"},
+		SiteName:      "InkSpace",
+		Locale:        "zh_CN",
+		SchemaType:    "Article",
+		AuthorName:    "Alice",
+		AuthorURL:     "https://blog.example.com/users/1",
+		PublishedTime: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		ModifiedTime:  time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestTags(t *testing.T) {
+	tags := sampleMeta().Tags()
+
+	for _, want := range []string{
+		`<title>Go &amp; &#34;泛型&#34; - InkSpace</title>`,
+		`<link rel="canonical" href="https://blog.example.com/blog/alice/go-generics">`,
+		`<meta property="og:type" content="article">`,
+		`<meta property="og:image" content="https://blog.example.com/uploads/cover.png">`,
+		`<meta property="article:published_time" content="2026-03-01T08:00:00Z">`,
+		`<meta property="article:tag" content="泛型">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+	} {
+		if !strings.Contains(tags, want) {
+			t.Errorf("missing %s in:\n%s", want, tags)
+		}
+	}
+	if strings.Contains(tags, "</script><script>") {
+		t.Fatalf("description not escaped:\n%s", tags)
+	}
+
+	start := strings.Index(tags, `<script type="application/ld+json">`)
+	if start < 0 {
+		t.Fatalf("missing JSON-LD:\n%s", tags)
+	}
+	body := tags[start+len(`<script type="application/ld+json">`):]
+	body = body[:strings.Index(body, "</script>")]
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		t.Fatalf("invalid JSON-LD %q: %v", body, err)
+	}
+	if data["@type"] != "Article" || data["datePublished"] != "2026-03-01T08:00:00Z" || data["author"].(map[string]interface{})["name"] != "Alice" {
+		t.Fatalf("unexpected JSON-LD: %v", data)
+	}
+}
+
+func TestTagsWithoutImage(t *testing.T) {
+	tags := (&Meta{SiteName: "InkSpace", NoIndex: true}).Tags()
+	if !strings.Contains(tags, "<title>InkSpace</title>") || !strings.Contains(tags, `content="summary"`) ||
+		!strings.Contains(tags, `<meta name="robots" content="noindex">`) {
+		t.Fatalf("unexpected tags:\n%s", tags)
+	}
+	if strings.Contains(tags, "ld+json") || strings.Contains(tags, "article:") {
+		t.Fatalf("website page should not have article data:\n%s", tags)
+	}
+}
+
+func TestInject(t *testing.T) {
+	meta := &Meta{Title: "文章", SiteName: "InkSpace"}
+
+	page := Inject([]byte("<html><head><meta charset=\"UTF-8\"><title>InkSpace - 个人网站</title></head></html>"), meta)
+	if strings.Count(string(page), "<title>") != 1 || !strings.Contains(string(page), "<title>文章 - InkSpace</title>") {
+		t.Fatalf("title not replaced: %s", page)
+	}
+
+	page = Inject([]byte("<html><head></head></html>"), meta)
+	if !strings.HasPrefix(string(page), "<html><head><title>文章 - InkSpace</title>") || !strings.HasSuffix(string(page), "\n</head></html>") {
+		t.Fatalf("tags not inserted before </head>: %s", page)
+	}
+}
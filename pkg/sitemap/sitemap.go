@@ -0,0 +1,82 @@
+// Package sitemap 生成符合 sitemaps.org 协议的 urlset 和 sitemapindex 文档
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// MaxURLs 单个 sitemap 文件允许的最大 URL 数量
+	MaxURLs = 50000
+	// ContentType sitemap 和 sitemap 索引的 HTTP Content-Type
+	ContentType = "application/xml; charset=utf-8"
+
+	xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// URL sitemap 中的一个页面
+type URL struct {
+	Loc     string
+	LastMod time.Time // 为零值时不输出 lastmod
+}
+
+// Entry sitemap 索引中的一个子 sitemap
+type Entry struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	URLs    []xmlItem `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	Sitemaps []xmlItem `xml:"sitemap"`
+}
+
+type xmlItem struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// RenderURLSet 输出 urlset 文档，超出 MaxURLs 的部分会被截断
+func RenderURLSet(urls []URL) ([]byte, error) {
+	if len(urls) > MaxURLs {
+		urls = urls[:MaxURLs]
+	}
+	doc := urlSet{Xmlns: xmlns, URLs: make([]xmlItem, 0, len(urls))}
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, xmlItem{Loc: u.Loc, LastMod: formatLastMod(u.LastMod)})
+	}
+	return marshalXML(doc)
+}
+
+// RenderIndex 输出 sitemapindex 文档
+func RenderIndex(entries []Entry) ([]byte, error) {
+	doc := sitemapIndex{Xmlns: xmlns, Sitemaps: make([]xmlItem, 0, len(entries))}
+	for _, e := range entries {
+		doc.Sitemaps = append(doc.Sitemaps, xmlItem{Loc: e.Loc, LastMod: formatLastMod(e.LastMod)})
+	}
+	return marshalXML(doc)
+}
+
+// formatLastMod 使用 W3C Datetime 格式
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
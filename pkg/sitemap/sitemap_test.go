@@ -0,0 +1,60 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderURLSet(t *testing.T) {
+	modified := time.Date(2026, 3, 1, 16, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	body, err := RenderURLSet([]URL{
+		{Loc: "https://blog.example.com/blog?category_id=1&page=2", LastMod: modified},
+		{Loc: "https://blog.example.com/users/1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		URLs []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+	if !strings.Contains(string(body), `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`) {
+		t.Fatalf("missing namespace:\n%s", body)
+	}
+	if len(doc.URLs) != 2 {
+		t.Fatalf("urls = %d, want 2", len(doc.URLs))
+	}
+	if doc.URLs[0].Loc != "https://blog.example.com/blog?category_id=1&page=2" || doc.URLs[0].LastMod != "2026-03-01T08:00:00Z" {
+		t.Fatalf("unexpected first url: %+v", doc.URLs[0])
+	}
+	if doc.URLs[1].LastMod != "" || strings.Count(string(body), "<lastmod>") != 1 {
+		t.Fatalf("zero lastmod should be omitted:\n%s", body)
+	}
+}
+
+func TestRenderIndex(t *testing.T) {
+	body, err := RenderIndex([]Entry{{Loc: "https://blog.example.com/sitemaps/articles-1.xml", LastMod: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		XMLName  xml.Name
+		Sitemaps []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+	if doc.XMLName.Local != "sitemapindex" || len(doc.Sitemaps) != 1 || doc.Sitemaps[0].LastMod != "2026-03-01T08:00:00Z" {
+		t.Fatalf("unexpected index:\n%s", body)
+	}
+}
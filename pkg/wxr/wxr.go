@@ -0,0 +1,225 @@
+// Package wxr 解析 WordPress 后台“工具 - 导出”生成的 WXR（WordPress eXtended RSS）文件。
+// 字段按元素本地名匹配，兼容 WXR 1.0 到 1.2 不同的命名空间地址
+package wxr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WordPress 中的时间格式，未设置时为 0000-00-00 00:00:00
+const dateLayout = "2006-01-02 15:04:05"
+
+// Export 一个 WordPress 站点的导出内容
+type Export struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	BaseSiteURL string     `xml:"base_site_url"`
+	BaseBlogURL string     `xml:"base_blog_url"`
+	Authors     []Author   `xml:"author"`
+	Categories  []Category `xml:"category"`
+	Tags        []Tag      `xml:"tag"`
+	Items       []Item     `xml:"item"`
+}
+
+// Author 站点作者（wp:author）
+type Author struct {
+	ID          int64  `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+// Category 分类（wp:category），Parent 为父分类的 nicename
+type Category struct {
+	TermID      int64  `xml:"term_id"`
+	Nicename    string `xml:"category_nicename"`
+	Parent      string `xml:"category_parent"`
+	Name        string `xml:"cat_name"`
+	Description string `xml:"category_description"`
+}
+
+// Tag 标签（wp:tag）
+type Tag struct {
+	TermID int64  `xml:"term_id"`
+	Slug   string `xml:"tag_slug"`
+	Name   string `xml:"tag_name"`
+}
+
+// Item 文章、页面、附件等内容，由 PostType 区分
+type Item struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Creator       string    `xml:"creator"` // 作者登录名
+	Encoded       []encoded `xml:"encoded"`
+	PostID        int64     `xml:"post_id"`
+	PostDate      string    `xml:"post_date"`
+	PostDateGMT   string    `xml:"post_date_gmt"`
+	Modified      string    `xml:"post_modified"`
+	ModifiedGMT   string    `xml:"post_modified_gmt"`
+	PostName      string    `xml:"post_name"`
+	Status        string    `xml:"status"`
+	PostParent    int64     `xml:"post_parent"`
+	PostType      string    `xml:"post_type"`
+	PostPassword  string    `xml:"post_password"`
+	IsSticky      int       `xml:"is_sticky"`
+	AttachmentURL string    `xml:"attachment_url"`
+	Terms         []Term    `xml:"category"`
+	Meta          []Meta    `xml:"postmeta"`
+	Comments      []Comment `xml:"comment"`
+}
+
+// encoded content:encoded 和 excerpt:encoded 本地名相同，按命名空间区分
+type encoded struct {
+	XMLName xml.Name
+	Text    string `xml:",chardata"`
+}
+
+// Term 内容所属的分类或标签，Domain 为 category 或 post_tag
+type Term struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// Meta 自定义字段（wp:postmeta）
+type Meta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+// Comment 评论，Parent 为父评论的 ID，0 表示顶级评论
+type Comment struct {
+	ID          int64  `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	AuthorURL   string `xml:"comment_author_url"`
+	AuthorIP    string `xml:"comment_author_IP"`
+	Date        string `xml:"comment_date"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	Approved    string `xml:"comment_approved"` // 1、0、spam、trash
+	Type        string `xml:"comment_type"`     // 空或 comment 为普通评论，另有 pingback、trackback
+	Parent      int64  `xml:"comment_parent"`
+	UserID      int64  `xml:"comment_user_id"`
+}
+
+// Parse 解析 WXR 文件
+func Parse(r io.Reader) (*Export, error) {
+	var doc struct {
+		Channel Export `xml:"channel"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	export := &doc.Channel
+	for i := range export.Items {
+		item := &export.Items[i]
+		item.Title = strings.TrimSpace(item.Title)
+		item.Creator = strings.TrimSpace(item.Creator)
+	}
+	return export, nil
+}
+
+// Site 来源站点地址，用于区分不同站点的导入记录
+func (e *Export) Site() string {
+	for _, site := range []string{e.BaseBlogURL, e.Link, e.BaseSiteURL} {
+		if site = strings.TrimRight(strings.TrimSpace(site), "/"); site != "" {
+			return site
+		}
+	}
+	return strings.TrimSpace(e.Title)
+}
+
+// Thumbnail 文章的特色图片地址，没有时返回空字符串
+func (e *Export) Thumbnail(item *Item) string {
+	id := item.MetaValue("_thumbnail_id")
+	if id == "" {
+		return ""
+	}
+	for i := range e.Items {
+		if attachment := &e.Items[i]; attachment.PostType == "attachment" && fmt.Sprint(attachment.PostID) == id {
+			return strings.TrimSpace(attachment.AttachmentURL)
+		}
+	}
+	return ""
+}
+
+// Content 正文 HTML
+func (i *Item) Content() string {
+	return i.encoded("content")
+}
+
+// Excerpt 手动填写的摘要
+func (i *Item) Excerpt() string {
+	return strings.TrimSpace(i.encoded("excerpt"))
+}
+
+func (i *Item) encoded(kind string) string {
+	for _, e := range i.Encoded {
+		if strings.Contains(e.XMLName.Space, kind) {
+			return e.Text
+		}
+	}
+	return ""
+}
+
+// Categories 内容所属的分类
+func (i *Item) Categories() []Term {
+	return i.terms("category")
+}
+
+// Tags 内容的标签
+func (i *Item) Tags() []Term {
+	return i.terms("post_tag")
+}
+
+func (i *Item) terms(domain string) []Term {
+	var terms []Term
+	for _, term := range i.Terms {
+		if term.Domain == domain && strings.TrimSpace(term.Name) != "" {
+			term.Name = strings.TrimSpace(term.Name)
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// MetaValue 自定义字段的值
+func (i *Item) MetaValue(key string) string {
+	for _, meta := range i.Meta {
+		if meta.Key == key {
+			return meta.Value
+		}
+	}
+	return ""
+}
+
+// Published 发布时间，优先使用 GMT 时间；没有时间（如草稿）时返回零值
+func (i *Item) Published() time.Time {
+	return parseDate(i.PostDateGMT, i.PostDate)
+}
+
+// Updated 最后修改时间
+func (i *Item) Updated() time.Time {
+	return parseDate(i.ModifiedGMT, i.Modified)
+}
+
+// Time 评论时间
+func (c *Comment) Time() time.Time {
+	return parseDate(c.DateGMT, c.Date)
+}
+
+// parseDate GMT 时间按 UTC 解析，站点本地时间按服务器时区解析
+func parseDate(gmt, local string) time.Time {
+	if t, err := time.Parse(dateLayout, strings.TrimSpace(gmt)); err == nil && t.Year() > 1 {
+		return t
+	}
+	if t, err := time.ParseInLocation(dateLayout, strings.TrimSpace(local), time.Local); err == nil && t.Year() > 1 {
+		return t
+	}
+	return time.Time{}
+}
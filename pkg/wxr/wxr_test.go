@@ -0,0 +1,68 @@
+package wxr
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	export, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Site() != "https://old.example.com" {
+		t.Fatalf("site = %q", export.Site())
+	}
+	if len(export.Authors) != 1 || export.Authors[0].Login != "alice" || export.Authors[0].ID != 2 {
+		t.Fatalf("authors = %+v", export.Authors)
+	}
+	if len(export.Categories) != 2 || export.Categories[1].Parent != "backend" || export.Categories[1].Name != "Go" {
+		t.Fatalf("categories = %+v", export.Categories)
+	}
+	if len(export.Tags) != 1 || export.Tags[0].Name != "并发" {
+		t.Fatalf("tags = %+v", export.Tags)
+	}
+	if len(export.Items) != 2 {
+		t.Fatalf("items = %d", len(export.Items))
+	}
+
+	post := &export.Items[0]
+	if post.Title != "Go 并发入门" || post.Creator != "alice" || post.PostType != "post" || post.Status != "publish" {
+		t.Fatalf("post = %+v", post)
+	}
+	if !strings.Contains(post.Content(), "<p>正文</p>") || post.Excerpt() != "摘要" {
+		t.Fatalf("content = %q excerpt = %q", post.Content(), post.Excerpt())
+	}
+	if !post.Published().Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("published = %v", post.Published())
+	}
+	if !post.Updated().IsZero() {
+		t.Fatalf("updated = %v", post.Updated())
+	}
+	if c := post.Categories(); len(c) != 1 || c[0].Nicename != "go" {
+		t.Fatalf("categories = %+v", c)
+	}
+	if tags := post.Tags(); len(tags) != 1 || tags[0].Name != "并发" {
+		t.Fatalf("tags = %+v", tags)
+	}
+	if got := export.Thumbnail(post); got != "https://old.example.com/wp-content/uploads/cover.png" {
+		t.Fatalf("thumbnail = %q", got)
+	}
+
+	if len(post.Comments) != 2 || post.Comments[1].Parent != 7 || post.Comments[1].UserID != 2 {
+		t.Fatalf("comments = %+v", post.Comments)
+	}
+	// 没有 GMT 时间时按本地时间解析
+	want := time.Date(2020, 1, 3, 8, 0, 0, 0, time.Local)
+	if got := post.Comments[0].Time(); !got.Equal(want) {
+		t.Fatalf("comment time = %v, want %v", got, want)
+	}
+}
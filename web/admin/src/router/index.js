@@ -72,6 +72,16 @@ const routes = [
         name: 'Users',
         component: () => import('@/views/admin/Users.vue')
       },
+      {
+        path: 'roles',
+        name: 'Roles',
+        component: () => import('@/views/admin/Roles.vue')
+      },
+      {
+        path: 'audit-logs',
+        name: 'AuditLogs',
+        component: () => import('@/views/admin/AuditLogs.vue')
+      },
       {
         path: 'ads',
         name: 'Ads',
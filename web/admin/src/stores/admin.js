@@ -7,6 +7,12 @@ export const useAdminStore = defineStore('admin', () => {
   const admin = ref(null)
 
   const isLoggedIn = computed(() => !!token.value)
+  // 当前账号的后台权限，来自 /admin/auth/profile
+  const permissions = computed(() => admin.value?.permissions || [])
+
+  function can(permission) {
+    return permissions.value.includes(permission)
+  }
 
   function setToken(newToken) {
     token.value = newToken
@@ -17,26 +23,51 @@ export const useAdminStore = defineStore('admin', () => {
     admin.value = adminData
   }
 
-  async function login(credentials) {
-    try {
-      // 调用管理员登录API
-      const response = await adminApi.post('/admin/auth/login', credentials)
-      
-      // adminApi的响应拦截器已经返回了response.data
-      // 所以response实际上就是后端返回的数据 { code: 0, data: { token, user } }
-      const { token: authToken, user } = response.data
-      
-      // 检查是否是管理员
-      if (user.role !== 'admin') {
-        throw new Error('该账号不是管理员')
-      }
-      
-      setToken(authToken)
-      setAdmin(user)
-      return response.data
-    } catch (error) {
+  // 业务错误以 HTTP 200 + 非零 code 返回，需要手动转为异常
+  function unwrap(response) {
+    if (response.code !== 0) {
+      const error = new Error(response.message || '请求失败')
+      error.code = response.code
+      error.data = response.data
       throw error
     }
+    return response.data
+  }
+
+  // 保存登录成功后的令牌，并加载管理员信息和权限（服务端已拒绝无后台权限的账号）
+  async function applyLogin(data) {
+    const { token: authToken, refresh_token: refreshToken, user } = data
+    setToken(authToken)
+    localStorage.setItem('admin_refresh_token', refreshToken)
+    setAdmin(user)
+    await fetchProfile()
+  }
+
+  // 密码校验通过后返回 { two_factor: { challenge_token, setup_required } }，需要继续完成两步验证
+  async function login(credentials) {
+    // adminApi的响应拦截器已经返回了response.data
+    const data = unwrap(await adminApi.post('/admin/auth/login', credentials))
+    if (!data.two_factor) {
+      await applyLogin(data)
+    }
+    return data
+  }
+
+  async function verifyTwoFactor(challengeToken, code) {
+    const data = unwrap(await adminApi.post('/admin/auth/2fa/verify', { challenge_token: challengeToken, code }))
+    await applyLogin(data)
+    return data
+  }
+
+  async function setupTwoFactor(challengeToken) {
+    return unwrap(await adminApi.post('/admin/auth/2fa/setup', { challenge_token: challengeToken }))
+  }
+
+  // 绑定成功后返回恢复码，只展示这一次
+  async function enableTwoFactor(challengeToken, code) {
+    const data = unwrap(await adminApi.post('/admin/auth/2fa/enable', { challenge_token: challengeToken, code }))
+    await applyLogin(data)
+    return data
   }
 
   async function fetchProfile() {
@@ -55,17 +86,38 @@ export const useAdminStore = defineStore('admin', () => {
     token.value = ''
     admin.value = null
     localStorage.removeItem('admin_token')
+    localStorage.removeItem('admin_refresh_token')
+  }
+
+  // 通知服务端吊销令牌后再清理本地登录状态
+  async function signOut() {
+    if (token.value) {
+      try {
+        await adminApi.post('/admin/auth/logout', {
+          refresh_token: localStorage.getItem('admin_refresh_token') || ''
+        })
+      } catch (error) {
+        // 令牌已失效时服务端会拒绝，忽略即可
+      }
+    }
+    logout()
   }
 
   return {
     token,
     admin,
     isLoggedIn,
+    permissions,
+    can,
     setToken,
     setAdmin,
     login,
+    verifyTwoFactor,
+    setupTwoFactor,
+    enableTwoFactor,
     fetchProfile,
-    logout
+    logout,
+    signOut
   }
 })
 
@@ -20,10 +20,60 @@ adminApi.interceptors.request.use(
   }
 )
 
+// 管理员访问令牌过期时使用刷新令牌续期，并发请求共享同一次刷新
+let refreshPromise = null
+
+function refreshAdminToken() {
+  const refreshToken = localStorage.getItem('admin_refresh_token')
+  if (!refreshToken) {
+    return Promise.reject(new Error('no refresh token'))
+  }
+  if (!refreshPromise) {
+    refreshPromise = axios
+      .post('/api/admin/auth/refresh', { refresh_token: refreshToken })
+      .then((response) => {
+        if (response.data?.code !== 0) {
+          throw new Error(response.data?.message || '刷新登录状态失败')
+        }
+        const tokens = response.data.data
+        localStorage.setItem('admin_token', tokens.token)
+        localStorage.setItem('admin_refresh_token', tokens.refresh_token)
+        return tokens.token
+      })
+      .finally(() => {
+        refreshPromise = null
+      })
+  }
+  return refreshPromise
+}
+
+async function retryWithRefreshedToken(config) {
+  config._retried = true
+  const token = await refreshAdminToken()
+  config.headers.Authorization = `Bearer ${token}`
+  return adminApi(config)
+}
+
+function isRefreshable(config) {
+  return config && !config._retried &&
+    !config.url?.startsWith('/admin/auth/login') && !config.url?.startsWith('/admin/auth/2fa')
+}
+
 // Response interceptor
 adminApi.interceptors.response.use(
-  (response) => {
+  async (response) => {
     const data = response.data
+    // 管理端认证中间件以 code=401 表示令牌失效，尝试刷新后重放请求
+    if (data?.code === 401 && isRefreshable(response.config)) {
+      try {
+        return await retryWithRefreshedToken(response.config)
+      } catch (refreshError) {
+        localStorage.removeItem('admin_token')
+        localStorage.removeItem('admin_refresh_token')
+        window.location.href = '/admin/login'
+        return Promise.reject(new Error(data.message || '登录已过期'))
+      }
+    }
     if (data.code === 0 || response.status === 200) {
       return data
     } else {
@@ -31,13 +81,22 @@ adminApi.interceptors.response.use(
       return Promise.reject(new Error(data.message || '请求失败'))
     }
   },
-  (error) => {
+  async (error) => {
+    if (error.response?.status === 401 && isRefreshable(error.config)) {
+      try {
+        return await retryWithRefreshedToken(error.config)
+      } catch (refreshError) {
+        // 刷新失败，按登录过期处理
+      }
+    }
+
     if (error.response) {
       const status = error.response.status
       switch (status) {
         case 401:
           ElMessage.error('未登录或登录已过期')
           localStorage.removeItem('admin_token')
+          localStorage.removeItem('admin_refresh_token')
           window.location.href = '/admin/login'
           break
         case 403:
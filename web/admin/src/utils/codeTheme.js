@@ -12,16 +12,14 @@ export const loadCodeTheme = async () => {
   }
   
   try {
-    // 管理后台可以直接获取所有设置
-    const response = await adminApi.get('/admin/settings')
-    const settings = response.data || []
-    const codeThemeSetting = settings.find(s => s.key === 'code_theme')
-    if (codeThemeSetting && codeThemeSetting.value) {
-      codeTheme = codeThemeSetting.value
+    // 公开配置对所有后台账号开放
+    const response = await adminApi.get('/admin/settings/public')
+    const settings = response.data || {}
+    if (settings.code_theme) {
+      codeTheme = settings.code_theme
     }
-    const markdownThemeSetting = settings.find(s => s.key === 'markdown_theme')
-    if (markdownThemeSetting && markdownThemeSetting.value) {
-      markdownTheme = markdownThemeSetting.value
+    if (settings.markdown_theme) {
+      markdownTheme = settings.markdown_theme
     }
     themeLoaded = true
     return codeTheme
@@ -21,6 +21,21 @@ const routes = [
         name: 'BlogDetail',
         component: () => import('@/views/BlogDetail.vue')
       },
+      {
+        path: 'blog/:username/:slug',
+        name: 'BlogPermalink',
+        component: () => import('@/views/BlogDetail.vue')
+      },
+      {
+        path: 'series',
+        name: 'Series',
+        component: () => import('@/views/Series.vue')
+      },
+      {
+        path: 'series/:id',
+        name: 'SeriesDetail',
+        component: () => import('@/views/SeriesDetail.vue')
+      },
       {
         path: 'works',
         name: 'Works',
@@ -31,6 +46,11 @@ const routes = [
         name: 'WorkDetail',
         component: () => import('@/views/WorkDetail.vue')
       },
+      {
+        path: 'works/:username/:slug',
+        name: 'WorkPermalink',
+        component: () => import('@/views/WorkDetail.vue')
+      },
       {
         path: 'photos',
         name: 'Photos',
@@ -51,6 +71,17 @@ const routes = [
         name: 'WikiDoc',
         component: () => import('@/views/wiki/WikiDoc.vue')
       },
+      {
+        path: 'following',
+        name: 'Following',
+        component: () => import('@/views/Following.vue'),
+        meta: { requiresAuth: true }
+      },
+      {
+        path: 'search',
+        name: 'Search',
+        component: () => import('@/views/Search.vue')
+      },
       {
         path: 'user-search',
         name: 'UserSearch',
@@ -82,6 +113,21 @@ const routes = [
     path: '/login',
     name: 'Login',
     component: () => import('@/views/Login.vue')
+  },
+  {
+    path: '/oauth/callback',
+    name: 'OAuthCallback',
+    component: () => import('@/views/Login.vue')
+  },
+  {
+    path: '/verify-email',
+    name: 'VerifyEmail',
+    component: () => import('@/views/VerifyEmail.vue')
+  },
+  {
+    path: '/reset-password',
+    name: 'ResetPassword',
+    component: () => import('@/views/ResetPassword.vue')
   },
     {
       path: '/dashboard',
@@ -108,6 +154,11 @@ const routes = [
           name: 'EditArticle',
           component: () => import('@/views/user/ArticleEdit.vue')
         },
+        {
+          path: 'series',
+          name: 'MySeries',
+          component: () => import('@/views/user/MySeries.vue')
+        },
       {
         path: 'works',
         name: 'MyWorks',
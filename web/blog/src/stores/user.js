@@ -4,23 +4,65 @@ import api from '@/utils/api'
 
 export const useUserStore = defineStore('user', () => {
   const token = ref(localStorage.getItem('token') || '')
+  const refreshToken = ref(localStorage.getItem('refresh_token') || '')
   const user = ref(null)
 
   const isLoggedIn = computed(() => !!token.value)
-  const isAdmin = computed(() => user.value?.role === 'admin')
+  // 除普通用户外的角色（管理员、编辑、版主等）都可以进入管理后台，具体权限由后台校验
+  const isAdmin = computed(() => !!user.value?.role && user.value.role !== 'user')
 
   function setToken(newToken) {
     token.value = newToken
     localStorage.setItem('token', newToken)
   }
 
+  function setTokens(tokens) {
+    setToken(tokens.token)
+    if (tokens.refresh_token) {
+      refreshToken.value = tokens.refresh_token
+      localStorage.setItem('refresh_token', tokens.refresh_token)
+    }
+  }
+
   function setUser(userData) {
     user.value = userData
   }
 
+  // 开启两步验证的账号返回 { two_factor: { challenge_token, setup_required } }，需要继续验证
   async function login(credentials) {
     const response = await api.post('/login', credentials)
-    setToken(response.data.token)
+    if (!response.data.two_factor) {
+      setTokens(response.data)
+      setUser(response.data.user)
+    }
+    return response.data
+  }
+
+  // 第三方登录回调页用一次性 code 换取登录结果，返回格式与 login 相同
+  async function exchangeOAuth(code) {
+    const response = await api.post('/auth/oauth/exchange', { code }, { skipAuth: true })
+    if (!response.data.two_factor) {
+      setTokens(response.data)
+      setUser(response.data.user)
+    }
+    return response.data
+  }
+
+  async function verifyTwoFactor(challengeToken, code) {
+    const response = await api.post('/auth/2fa/verify', { challenge_token: challengeToken, code }, { skipAuth: true })
+    setTokens(response.data)
+    setUser(response.data.user)
+    return response.data
+  }
+
+  async function setupTwoFactor(challengeToken) {
+    const response = await api.post('/auth/2fa/setup', { challenge_token: challengeToken }, { skipAuth: true })
+    return response.data
+  }
+
+  async function enableTwoFactor(challengeToken, code) {
+    const response = await api.post('/auth/2fa/enable', { challenge_token: challengeToken, code }, { skipAuth: true })
+    setTokens(response.data)
     setUser(response.data.user)
     return response.data
   }
@@ -42,21 +84,42 @@ export const useUserStore = defineStore('user', () => {
 
   function logout() {
     token.value = ''
+    refreshToken.value = ''
     user.value = null
     localStorage.removeItem('token')
+    localStorage.removeItem('refresh_token')
+  }
+
+  // 通知服务端吊销令牌后再清理本地登录状态
+  async function signOut() {
+    if (token.value) {
+      try {
+        await api.post('/logout', { refresh_token: refreshToken.value }, { silentError: true })
+      } catch (error) {
+        // 令牌已失效时服务端会拒绝，忽略即可
+      }
+    }
+    logout()
   }
 
   return {
     token,
+    refreshToken,
     user,
     isLoggedIn,
     isAdmin,
     setToken,
+    setTokens,
     setUser,
     login,
+    exchangeOAuth,
+    verifyTwoFactor,
+    setupTwoFactor,
+    enableTwoFactor,
     register,
     fetchProfile,
-    logout
+    logout,
+    signOut
   }
 })
 
@@ -21,9 +21,38 @@ api.interceptors.request.use(
   }
 )
 
+// 访问令牌过期时使用刷新令牌换取新令牌，并发请求共享同一次刷新
+let refreshPromise = null
+
+function refreshAccessToken() {
+  const userStore = useUserStore()
+  if (!userStore.refreshToken) {
+    return Promise.reject(new Error('no refresh token'))
+  }
+  if (!refreshPromise) {
+    refreshPromise = axios
+      .post('/api/auth/refresh', { refresh_token: userStore.refreshToken })
+      .then((response) => {
+        if (response.data?.code !== 0) {
+          throw new Error(response.data?.message || '刷新登录状态失败')
+        }
+        userStore.setTokens(response.data.data)
+        return response.data.data.token
+      })
+      .finally(() => {
+        refreshPromise = null
+      })
+  }
+  return refreshPromise
+}
+
 // Response interceptor
 api.interceptors.response.use(
   (response) => {
+    // 文件下载直接返回内容，出错时服务端返回的 JSON 由调用方处理
+    if (response.config?.responseType === 'blob') {
+      return response.data
+    }
     const data = response.data
     // 检查响应体中的 code 字段，只有 code === 0 才是成功
     if (data.code === 0) {
@@ -32,10 +61,26 @@ api.interceptors.response.use(
     } else {
       // 即使 HTTP 状态码是 200，如果 code 不是 0，也是错误
       if (!response.config?.silentError) ElMessage.error(data.message || '请求失败')
-      return Promise.reject(new Error(data.message || '请求失败'))
+      const error = new Error(data.message || '请求失败')
+      // 保留业务错误码和附加数据，例如登录需要人机验证时返回的组件信息
+      error.code = data.code
+      error.data = data.data
+      return Promise.reject(error)
     }
   },
-  (error) => {
+  async (error) => {
+    const original = error.config
+    if (error.response?.status === 401 && original && !original.skipAuth && !original._retried) {
+      original._retried = true
+      try {
+        const token = await refreshAccessToken()
+        original.headers.Authorization = `Bearer ${token}`
+        return api(original)
+      } catch (refreshError) {
+        // 刷新失败，按登录过期处理
+      }
+    }
+
     if (error.response) {
       const status = error.response.status
       const data = error.response.data
@@ -0,0 +1,33 @@
+// 服务端渲染正文（content_html）的辅助函数
+
+/**
+ * 将接口返回的嵌套目录展开为带缩进层级的列表
+ * @param {Array} toc - 目录，每项包含 id、title、level、children
+ * @param {number} depth - 当前缩进层级
+ */
+export function flattenToc(toc = [], depth = 0) {
+  return (toc || []).flatMap(item => [
+    { id: item.id, title: item.title, level: item.level, depth },
+    ...flattenToc(item.children, depth + 1)
+  ])
+}
+
+/**
+ * 渲染正文中的数学公式，服务端输出为 .language-math 元素，交给 Vditor 内置的 KaTeX 处理
+ * @param {HTMLElement} element - 正文容器
+ */
+export async function renderMath(element) {
+  if (!element?.querySelector('.language-math')) {
+    return
+  }
+  const { default: Vditor } = await import('vditor')
+  Vditor.mathRender(element)
+}
+
+/**
+ * 平滑滚动到目录对应的标题
+ * @param {string} id - 标题 id
+ */
+export function scrollToHeading(id) {
+  document.getElementById(id)?.scrollIntoView({ behavior: 'smooth', block: 'start' })
+}
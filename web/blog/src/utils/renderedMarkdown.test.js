@@ -0,0 +1,21 @@
+import { describe, expect, it } from 'vitest'
+import { flattenToc } from './renderedMarkdown'
+
+describe('rendered markdown helpers', () => {
+  it('flattens nested headings with depth', () => {
+    const toc = [
+      { id: '入门', title: '入门', level: 1, children: [{ id: '安装', title: '安装', level: 3 }] },
+      { id: 'faq', title: 'FAQ', level: 1 }
+    ]
+    expect(flattenToc(toc)).toEqual([
+      { id: '入门', title: '入门', level: 1, depth: 0 },
+      { id: '安装', title: '安装', level: 3, depth: 1 },
+      { id: 'faq', title: 'FAQ', level: 1, depth: 0 }
+    ])
+  })
+
+  it('treats a missing toc as empty', () => {
+    expect(flattenToc(undefined)).toEqual([])
+    expect(flattenToc(null)).toEqual([])
+  })
+})
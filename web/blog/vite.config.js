@@ -39,6 +39,14 @@ export default defineConfig({
       '/uploads': {
         target: 'http://localhost:8081',  // 静态文件服务
         changeOrigin: true
+      },
+      '^/(feed\\.xml|atom\\.xml|feed\\.json)$': {
+        target: 'http://localhost:8081',  // 订阅源
+        changeOrigin: true
+      },
+      '^/(sitemap\\.xml|sitemaps/.*|robots\\.txt)$': {
+        target: 'http://localhost:8081',  // 站点地图和 robots.txt
+        changeOrigin: true
       }
     }
   }